@@ -0,0 +1,49 @@
+package genetics
+
+// MutationController adapts an Evolver's MutationRate between generations based on
+// how the last generation performed. Fixed rates are rarely right for both the
+// exploration and convergence phases of a run.
+type MutationController interface {
+	AdjustMutationRate(rate float32, s Stats) float32
+}
+
+// OneFifthRule is Rechenberg's 1/5 success rule applied to MutationRate: when more
+// than a fifth of offspring improve on their parents the search is exploiting a
+// promising region and MutationRate grows by Factor to explore more; when fewer
+// succeed it shrinks by Factor to converge.
+type OneFifthRule struct {
+	// Factor by which MutationRate changes; 0 defaults to 1.5.
+	Factor float32
+	// Min and Max bound MutationRate; a Max of 0 defaults to 1.
+	Min float32
+	Max float32
+}
+
+// AdjustMutationRate implements MutationController
+func (o OneFifthRule) AdjustMutationRate(rate float32, s Stats) float32 {
+	if s.Offspring == 0 {
+		return rate
+	}
+	factor, max := o.Factor, o.Max
+	if factor == 0 {
+		factor = 1.5
+	}
+	if max == 0 {
+		max = 1
+	}
+
+	switch success := s.SuccessRate(); {
+	case success > 0.2:
+		rate *= factor
+	case success < 0.2:
+		rate /= factor
+	}
+
+	if rate < o.Min {
+		rate = o.Min
+	}
+	if rate > max {
+		rate = max
+	}
+	return rate
+}
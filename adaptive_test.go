@@ -0,0 +1,94 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/rand"
+)
+
+func TestOneFifthRule(t *testing.T) {
+	for _, test := range []struct {
+		tag   string
+		rule  genetics.OneFifthRule
+		rate  float32
+		stats genetics.Stats
+		want  float32
+	}{
+		{
+			tag:   "no offspring",
+			rate:  0.1,
+			stats: genetics.Stats{},
+			want:  0.1,
+		}, {
+			tag:   "successful",
+			rule:  genetics.OneFifthRule{Factor: 2},
+			rate:  0.1,
+			stats: genetics.Stats{Offspring: 10, Improvements: 5},
+			want:  0.2,
+		}, {
+			tag:   "unsuccessful",
+			rule:  genetics.OneFifthRule{Factor: 2},
+			rate:  0.1,
+			stats: genetics.Stats{Offspring: 10, Improvements: 1},
+			want:  0.05,
+		}, {
+			tag:   "balanced",
+			rule:  genetics.OneFifthRule{Factor: 2},
+			rate:  0.1,
+			stats: genetics.Stats{Offspring: 10, Improvements: 2},
+			want:  0.1,
+		}, {
+			tag:   "clamped max",
+			rule:  genetics.OneFifthRule{Factor: 2, Max: 0.15},
+			rate:  0.1,
+			stats: genetics.Stats{Offspring: 10, Improvements: 10},
+			want:  0.15,
+		}, {
+			tag:   "clamped min",
+			rule:  genetics.OneFifthRule{Factor: 2, Min: 0.08},
+			rate:  0.1,
+			stats: genetics.Stats{Offspring: 10},
+			want:  0.08,
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			if got := test.rule.AdjustMutationRate(test.rate, test.stats); got != test.want {
+				t.Errorf("AdjustMutationRate(%f, %+v)=%f; want %f", test.rate, test.stats, got, test.want)
+			}
+		})
+	}
+}
+
+func TestEngineStats(t *testing.T) {
+	rng := rand.New()
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 6,
+			CrossoverRate:    1,
+			MutationRate:     0.5,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator:          genetics.RandomResettingMutation{},
+		},
+		Evaluator:       genetics.FitnessFunc(oneMax),
+		MutationControl: genetics.OneFifthRule{Min: 0.01},
+	}
+	pop := newBinaryPopulation(t, rng, 10, 12)
+	if err := engine.Run(rng, pop, 8); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+
+	stats := engine.Stats()
+	_, best := pop.Best()
+	_, worst := pop.Worst()
+	if stats.Generation != 8 || stats.Evaluations != 6 || stats.Offspring != 6 {
+		t.Errorf("Stats()=%+v; want generation 8 with 6 evaluated offspring", stats)
+	}
+	if stats.Best != best || stats.Worst != worst || stats.Mean < float64(worst) || stats.Mean > float64(best) {
+		t.Errorf("Stats()=%+v does not summarize population fitness %v", stats, pop.Fitness)
+	}
+	if rate := engine.Evolver.MutationRate; rate < 0.01 || rate > 1 {
+		t.Errorf("MutationControl left MutationRate %f outside of [0.01, 1]", rate)
+	}
+}
@@ -0,0 +1,37 @@
+package genetics
+
+import "math"
+
+// LocusStats summarizes the alleles which a population holds at one locus. A locus
+// whose Frequency is 1 and Entropy 0 has converged: every member holds Allele, so
+// only mutation can explore it further.
+type LocusStats struct {
+	// Allele is the most common allele at the locus. Ties go to the lowest allele.
+	Allele Gene `json:"allele"`
+	// Frequency is the fraction of the population which holds Allele.
+	Frequency float64 `json:"frequency"`
+	// Entropy is the Shannon entropy, in bits, of the alleles at the locus: 0 once
+	// it has converged and log2 of the number of alleles while they are evenly
+	// mixed.
+	Entropy float64 `json:"entropy"`
+	// Alleles is the number of distinct alleles at the locus.
+	Alleles int `json:"alleles"`
+}
+
+// AnalyzePopulation returns the LocusStats of each locus of pop, showing which
+// genes have converged and which are still being searched. pop must not be empty.
+func AnalyzePopulation(pop *Population) []LocusStats {
+	loci := make([]LocusStats, pop.Species.NumGenes)
+	for i := range loci {
+		freq := alleleFrequencies(pop.Chromosomes, i)
+		l := LocusStats{Alleles: len(freq)}
+		l.Allele, l.Frequency = modalAllele(freq)
+		for _, f := range freq {
+			l.Entropy -= f * math.Log2(f)
+		}
+		// Avoid -0 for converged loci
+		l.Entropy = math.Abs(l.Entropy)
+		loci[i] = l
+	}
+	return loci
+}
@@ -0,0 +1,86 @@
+package genetics_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestAnalyzePopulation(t *testing.T) {
+	s := genetics.NewSpecies(3, 3)
+	pop := &genetics.Population{Species: s}
+	for _, g := range [][]genetics.Gene{{0, 2, 3}, {1, 2, 3}, {2, 2, 1}, {3, 2, 1}} {
+		pop.Chromosomes = append(pop.Chromosomes, s.New(g...))
+	}
+	want := []genetics.LocusStats{
+		{Allele: 0, Frequency: 0.25, Entropy: 2, Alleles: 4},
+		{Allele: 2, Frequency: 1, Entropy: 0, Alleles: 1},
+		{Allele: 1, Frequency: 0.5, Entropy: 1, Alleles: 2},
+	}
+	if diff := cmp.Diff(want, genetics.AnalyzePopulation(pop)); diff != "" {
+		t.Errorf("AnalyzePopulation() diff=%s", diff)
+	}
+}
+
+func TestEngineAnalyzeLoci(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	s := genetics.NewSpecies(10, 1)
+	pop, err := s.NewRandPopulation(rng, 30)
+	if err != nil {
+		t.Fatalf("NewRandPopulation(); err=%s", err)
+	}
+	recorder := &struct {
+		genetics.NopObserver
+		statsRecorder
+	}{}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 20,
+			CrossoverRate:    0.9,
+			Selector:         genetics.TournamentSelection{Size: 3},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+		},
+		// OneMax
+		Evaluator: genetics.FitnessFunc(func(c genetics.Chromosome) genetics.Fitness {
+			sum := 0
+			for _, g := range c.Genes {
+				sum += g
+			}
+			return genetics.Fitness(sum)
+		}),
+		AnalyzeLoci: true,
+		Observers:   []genetics.Observer{recorder},
+	}
+	if err := engine.Run(rng, pop, 30); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	entropy := func(ls []genetics.LocusStats) float64 {
+		total := 0.0
+		for _, l := range ls {
+			total += l.Entropy
+		}
+		return total
+	}
+	stats := recorder.stats
+	first, last := stats[0].Loci, stats[len(stats)-1].Loci
+	if len(first) != s.NumGenes || len(last) != s.NumGenes {
+		t.Fatalf("Stats.Loci has %d and %d loci; want %d", len(first), len(last), s.NumGenes)
+	}
+	// Without mutation, selection drives the loci to converge
+	if !(entropy(last) < entropy(first)/2) || math.IsNaN(entropy(last)) {
+		t.Errorf("total entropy fell from %g to %g; want it at least halved", entropy(first), entropy(last))
+	}
+
+	engine.AnalyzeLoci = false
+	if err := engine.Run(rng, pop, 1); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if got := engine.Stats().Loci; got != nil {
+		t.Errorf("Stats().Loci=%v without AnalyzeLoci; want nil", got)
+	}
+}
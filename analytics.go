@@ -0,0 +1,65 @@
+package genetics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+// OperatorAnalytics accumulates Stats.Operators over a run, so that crossover and
+// mutation strategies can be compared on more children than one generation
+// produces. Add it to an Engine's Observers; it restarts with each run.
+type OperatorAnalytics struct {
+	NopObserver
+	totals map[string]OperatorStats
+}
+
+// OnStats implements StatsObserver
+func (a *OperatorAnalytics) OnStats(s Stats) {
+	if s.Generation == 0 || a.totals == nil {
+		a.totals = map[string]OperatorStats{}
+	}
+	for op, o := range s.Operators {
+		t := a.totals[op]
+		t.Offspring += o.Offspring
+		t.Improvements += o.Improvements
+		t.Gain += o.Gain
+		a.totals[op] = t
+	}
+}
+
+// Totals returns the accumulated OperatorStats of each operator.
+func (a *OperatorAnalytics) Totals() map[string]OperatorStats {
+	totals := make(map[string]OperatorStats, len(a.totals))
+	for op, o := range a.totals {
+		totals[op] = o
+	}
+	return totals
+}
+
+// Operators returns the names of the operators, from the highest success rate to
+// the lowest. Operators with equal success rates are ordered by name.
+func (a *OperatorAnalytics) Operators() []string {
+	ops := make([]string, 0, len(a.totals))
+	for op := range a.totals {
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		ri, rj := a.totals[ops[i]].SuccessRate(), a.totals[ops[j]].SuccessRate()
+		return ri > rj || (ri == rj && ops[i] < ops[j])
+	})
+	return ops
+}
+
+// WriteTable writes the accumulated OperatorStats as an aligned text table, in
+// the order of Operators.
+func (a *OperatorAnalytics) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "Operator\tOffspring\tImprovements\tSuccessRate\tMeanGain")
+	for _, op := range a.Operators() {
+		o := a.totals[op]
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.3f\t%.3g\n", op, o.Offspring, o.Improvements, o.SuccessRate(), o.MeanGain())
+	}
+	return tw.Flush()
+}
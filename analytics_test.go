@@ -0,0 +1,84 @@
+package genetics_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestStatsOperators(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	pop := newBinaryPopulation(t, rng, 30, 20)
+	recorder := &statsObserver{}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 10,
+			CrossoverRate:    0.5,
+			MutationRate:     0.5,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 2},
+			Mutator:          genetics.RandomResettingMutation{},
+		},
+		Evaluator: genetics.FitnessFunc(oneMax),
+		Observers: []genetics.Observer{recorder},
+	}
+	if err := engine.Run(rng, pop, 10); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	stats := recorder.stats
+	if stats[0].Operators != nil {
+		t.Errorf("the initial generation has Operators %v", stats[0].Operators)
+	}
+	for _, s := range stats[1:] {
+		crossover, clone := s.Operators["MultiPointCrossover(2)"], s.Operators["Clone"]
+		if crossover.Offspring+clone.Offspring != s.Offspring || crossover.Improvements+clone.Improvements != s.Improvements {
+			t.Errorf("generation %d: every child is either a crossover or a clone; Stats=%+v", s.Generation, s)
+		}
+		if m := s.Operators["RandomResettingMutation"]; m.Offspring > s.Offspring || m.Improvements > m.Offspring {
+			t.Errorf("generation %d: mutation %+v exceeds the offspring", s.Generation, m)
+		}
+		for op, o := range s.Operators {
+			if (o.Improvements == 0) != (o.Gain == 0) {
+				t.Errorf("generation %d: %s improved %d children by %g", s.Generation, op, o.Improvements, o.Gain)
+			}
+		}
+	}
+}
+
+func TestOperatorAnalytics(t *testing.T) {
+	a := &genetics.OperatorAnalytics{}
+	a.OnStats(genetics.Stats{Generation: 0})
+	a.OnStats(genetics.Stats{Generation: 1, Operators: map[string]genetics.OperatorStats{
+		"Swap":  {Offspring: 4, Improvements: 1, Gain: 2},
+		"Clone": {Offspring: 4, Improvements: 1, Gain: 2},
+	}})
+	a.OnStats(genetics.Stats{Generation: 2, Operators: map[string]genetics.OperatorStats{
+		"Swap":      {Offspring: 4, Improvements: 3, Gain: 6},
+		"Inversion": {Offspring: 2},
+	}})
+	swap := a.Totals()["Swap"]
+	if swap.Offspring != 8 || swap.Improvements != 4 || swap.Gain != 8 || swap.SuccessRate() != 0.5 || swap.MeanGain() != 1 {
+		t.Errorf("Totals()[Swap]=%+v; want 8 offspring of which 4 improved by 8", swap)
+	}
+	if got, want := strings.Join(a.Operators(), ","), "Swap,Clone,Inversion"; got != want {
+		t.Errorf("Operators()=%s; want %s", got, want)
+	}
+	var buf bytes.Buffer
+	if err := a.WriteTable(&buf); err != nil {
+		t.Fatalf("WriteTable(); err=%s", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[1], "Swap") || !strings.Contains(lines[1], "0.500") {
+		t.Errorf("WriteTable() wrote:\n%s", buf.String())
+	}
+
+	a.OnStats(genetics.Stats{Generation: 0})
+	if len(a.Totals()) != 0 {
+		t.Errorf("Totals()=%v after a new run started", a.Totals())
+	}
+}
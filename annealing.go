@@ -0,0 +1,153 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/inlined/rand"
+)
+
+// SimulatedAnnealing is a single-solution baseline which shares Evaluators and
+// Mutators with Engine. Each step mutates a copy of the current Chromosome; fitter
+// or equally fit candidates always replace it, while a candidate which is worse by
+// d replaces it with probability exp(-d/T). The temperature T starts at Temperature
+// and is multiplied by Cooling after every step, so Temperature should be on the
+// scale of the problem's Fitness. Like Engine, SimulatedAnnealing is not goroutine
+// safe.
+type SimulatedAnnealing struct {
+	Evaluator Evaluator
+	Mutator   Mutator
+	// Objective is the direction in which fitness improves. Defaults to Maximize.
+	Objective Objective
+
+	Temperature float64
+	// Cooling is the geometric cooling rate in (0, 1). Defaults to 0.995.
+	Cooling float64
+
+	// Terminate, if set, ends a Run early once a step satisfies it.
+	Terminate Termination
+	// Observers are notified of the Stats of each step.
+	Observers []StatsObserver
+}
+
+// Run anneals start for up to steps steps and returns the fittest Chromosome seen.
+// Each step is reported as a generation whose Best is the best fitness seen so far
+// and whose Worst and Mean are the fitness of the current Chromosome.
+func (s SimulatedAnnealing) Run(r rand.Rand, start Chromosome, steps int) (Chromosome, Fitness, error) {
+	if s.Temperature <= 0 {
+		return start, 0, fmt.Errorf("SimulatedAnnealing.Run(); Temperature %g must be positive", s.Temperature)
+	}
+	cooling := s.Cooling
+	if cooling == 0 {
+		cooling = 0.995
+	}
+	if cooling <= 0 || cooling >= 1 {
+		return start, 0, fmt.Errorf("SimulatedAnnealing.Run(); Cooling %g must be in (0, 1)", cooling)
+	}
+
+	t := s.Temperature
+	return singleSolution{
+		name:      "SimulatedAnnealing",
+		objective: s.Objective,
+		evaluator: s.Evaluator,
+		mutator:   s.Mutator,
+		terminate: s.Terminate,
+		observers: s.Observers,
+		accept: func(delta Fitness) bool {
+			ok := delta >= 0 || r.Float64() < math.Exp(float64(delta)/t)
+			t *= cooling
+			return ok
+		},
+	}.run(r, start, steps)
+}
+
+// StochasticHillClimbing is the simplest single-solution baseline: each step
+// mutates a copy of the current Chromosome and keeps it unless it is less fit.
+// Accepting equally fit candidates lets the climber drift across plateaus. Unlike
+// the HillClimbing LocalSearch, it is a complete driver which reports Stats and
+// honors Terminate.
+type StochasticHillClimbing struct {
+	Evaluator Evaluator
+	Mutator   Mutator
+	// Objective is the direction in which fitness improves. Defaults to Maximize.
+	Objective Objective
+
+	// Terminate, if set, ends a Run early once a step satisfies it.
+	Terminate Termination
+	// Observers are notified of the Stats of each step.
+	Observers []StatsObserver
+}
+
+// Run climbs from start for up to steps steps and returns the fittest Chromosome
+// seen. Steps are reported as in SimulatedAnnealing.Run.
+func (h StochasticHillClimbing) Run(r rand.Rand, start Chromosome, steps int) (Chromosome, Fitness, error) {
+	return singleSolution{
+		name:      "StochasticHillClimbing",
+		objective: h.Objective,
+		evaluator: h.Evaluator,
+		mutator:   h.Mutator,
+		terminate: h.Terminate,
+		observers: h.Observers,
+		accept: func(delta Fitness) bool {
+			return delta >= 0
+		},
+	}.run(r, start, steps)
+}
+
+// singleSolution is the loop shared by single-solution drivers, which differ only
+// in whether they accept a candidate that changes fitness by delta. delta is a
+// difference of scores, so it is positive for an improvement under objective.
+type singleSolution struct {
+	name      string
+	objective Objective
+	evaluator Evaluator
+	mutator   Mutator
+	terminate Termination
+	observers []StatsObserver
+	accept    func(delta Fitness) bool
+}
+
+func (s singleSolution) run(r rand.Rand, start Chromosome, steps int) (Chromosome, Fitness, error) {
+	current := start.Clone()
+	fitness, err := s.evaluator.Evaluate(current)
+	if err != nil {
+		return start, 0, fmt.Errorf("%s.Run(); cannot evaluate start: %s", s.name, err)
+	}
+	best, bestFitness := current, fitness
+
+	for step := 0; step < steps; step++ {
+		candidate := current.Clone()
+		s.mutator.Mutate(r, &candidate)
+		f, err := s.evaluator.Evaluate(candidate)
+		if err != nil {
+			return best, bestFitness, fmt.Errorf("%s.Run(); step %d: %s", s.name, step, err)
+		}
+		improved := s.objective.Better(f, fitness)
+		if s.accept(s.objective.score(f) - s.objective.score(fitness)) {
+			current, fitness = candidate, f
+		}
+		if s.objective.Better(fitness, bestFitness) {
+			best, bestFitness = current, fitness
+		}
+
+		stats := Stats{
+			Generation:  step,
+			Objective:   s.objective,
+			Best:        bestFitness,
+			Worst:       fitness,
+			Mean:        float64(fitness),
+			Evaluations: 1,
+			Offspring:   1,
+		}
+		if improved {
+			stats.Improvements = 1
+		}
+		for _, o := range s.observers {
+			o.OnStats(stats)
+		}
+		if s.terminate != nil && s.terminate(stats) {
+			break
+		}
+	}
+	return best, bestFitness, nil
+}
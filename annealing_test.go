@@ -0,0 +1,178 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+// inPlace scores a permutation by the number of genes at their own index; the
+// identity permutation is optimal.
+func inPlace(c genetics.Chromosome) genetics.Fitness {
+	f := genetics.Fitness(0)
+	for i, g := range c.Genes {
+		if int(g) == i {
+			f++
+		}
+	}
+	return f
+}
+
+func newPerm(t *testing.T, rng rand.Rand, numGenes int) genetics.Chromosome {
+	c, err := genetics.NewSpecies(numGenes, genetics.Gene(numGenes-1)).NewPerm(rng)
+	if err != nil {
+		t.Fatalf("NewPerm(); err=%s", err)
+	}
+	return c
+}
+
+func TestSingleSolutionBaselines(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		run  func(r rand.Rand, start genetics.Chromosome, steps int) (genetics.Chromosome, genetics.Fitness, error)
+	}{
+		{
+			name: "SimulatedAnnealing",
+			run: genetics.SimulatedAnnealing{
+				Evaluator:   genetics.FitnessFunc(inPlace),
+				Mutator:     genetics.SwapMutation{},
+				Temperature: 2,
+				Cooling:     0.99,
+			}.Run,
+		}, {
+			name: "StochasticHillClimbing",
+			run: genetics.StochasticHillClimbing{
+				Evaluator: genetics.FitnessFunc(inPlace),
+				Mutator:   genetics.SwapMutation{},
+			}.Run,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			rng := rand.New()
+			rng.Seed(42)
+			start := newPerm(t, rng, 20)
+			startGenes := append([]genetics.Gene(nil), start.Genes...)
+			best, f, err := test.run(rng, start, 5000)
+			if err != nil {
+				t.Fatalf("Run(); err=%s", err)
+			}
+			if f != 20 || inPlace(best) != 20 {
+				t.Errorf("Run() found %v scoring %g; want the identity permutation", best.Genes, f)
+			}
+			for i, g := range startGenes {
+				if start.Genes[i] != g {
+					t.Fatalf("Run() modified its start %v", start.Genes)
+				}
+			}
+		})
+	}
+}
+
+func TestSingleSolutionMinimize(t *testing.T) {
+	misplaced := genetics.FitnessFunc(func(c genetics.Chromosome) genetics.Fitness {
+		return genetics.Fitness(len(c.Genes)) - inPlace(c)
+	})
+	for _, test := range []struct {
+		name string
+		run  func(r rand.Rand, start genetics.Chromosome, steps int) (genetics.Chromosome, genetics.Fitness, error)
+	}{
+		{
+			name: "SimulatedAnnealing",
+			run: genetics.SimulatedAnnealing{
+				Evaluator:   misplaced,
+				Mutator:     genetics.SwapMutation{},
+				Objective:   genetics.Minimize,
+				Temperature: 2,
+				Cooling:     0.99,
+			}.Run,
+		}, {
+			name: "StochasticHillClimbing",
+			run: genetics.StochasticHillClimbing{
+				Evaluator: misplaced,
+				Mutator:   genetics.SwapMutation{},
+				Objective: genetics.Minimize,
+				Terminate: genetics.TargetFitness(0),
+			}.Run,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			rng := rand.New()
+			rng.Seed(42)
+			best, f, err := test.run(rng, newPerm(t, rng, 20), 5000)
+			if err != nil {
+				t.Fatalf("Run(); err=%s", err)
+			}
+			if f != 0 || inPlace(best) != 20 {
+				t.Errorf("Run() found %v with %g misplaced; want the identity permutation", best.Genes, f)
+			}
+		})
+	}
+}
+
+func TestSimulatedAnnealingAcceptsWorse(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	hot, cold := &statsRecorder{}, &statsRecorder{}
+	start := newPerm(t, rng, 20)
+	sa := genetics.SimulatedAnnealing{
+		Evaluator:   genetics.FitnessFunc(inPlace),
+		Mutator:     genetics.SwapMutation{},
+		Temperature: 1000,
+		Observers:   []genetics.StatsObserver{hot},
+	}
+	if _, _, err := sa.Run(rng, start, 100); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	climber := genetics.StochasticHillClimbing{
+		Evaluator: sa.Evaluator,
+		Mutator:   sa.Mutator,
+		Observers: []genetics.StatsObserver{cold},
+	}
+	if _, _, err := climber.Run(rng, start, 100); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+
+	regressions := func(stats []genetics.Stats) int {
+		n := 0
+		for i := 1; i < len(stats); i++ {
+			if stats[i].Mean < stats[i-1].Mean {
+				n++
+			}
+		}
+		return n
+	}
+	if regressions(hot.stats) == 0 {
+		t.Error("SimulatedAnnealing never accepted a worse candidate at a high temperature")
+	}
+	if n := regressions(cold.stats); n != 0 {
+		t.Errorf("StochasticHillClimbing accepted %d worse candidates", n)
+	}
+
+	if _, _, err := (genetics.SimulatedAnnealing{Evaluator: sa.Evaluator, Mutator: sa.Mutator}).Run(rng, start, 1); err == nil {
+		t.Error("Run() should reject a Temperature of 0")
+	}
+}
+
+func TestStochasticHillClimbingTerminate(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	recorder := &statsRecorder{}
+	climber := genetics.StochasticHillClimbing{
+		Evaluator: genetics.FitnessFunc(inPlace),
+		Mutator:   genetics.SwapMutation{},
+		Terminate: genetics.TargetFitness(10),
+		Observers: []genetics.StatsObserver{recorder},
+	}
+	_, f, err := climber.Run(rng, newPerm(t, rng, 20), 5000)
+	if err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if f < 10 {
+		t.Errorf("Run() stopped at %g before reaching the target", f)
+	}
+	if n := len(recorder.stats); n == 5000 || recorder.stats[n-1].Best < 10 {
+		t.Errorf("Run() did not stop when it reached the target; steps=%d", n)
+	}
+}
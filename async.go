@@ -0,0 +1,160 @@
+package genetics
+
+import (
+	"context"
+	"sync"
+)
+
+// AsyncEvaluator starts evaluations whose Fitness arrives later, e.g. from people
+// scoring designs in an interactive GA or from a slow external service. Submit
+// must not wait for the evaluation to finish.
+//
+// An Engine whose Evaluator also implements AsyncEvaluator submits every
+// unevaluated member of a generation before waiting for any of their scores,
+// which may arrive in any order, and stops waiting once its Context is done. Use
+// AsyncFunc to adapt a function, or an EvaluationQueue to score Chromosomes
+// elsewhere.
+type AsyncEvaluator interface {
+	Submit(c Chromosome) *Future
+}
+
+// Future is a Fitness which is not known yet. Create Futures with NewFuture; they
+// are safe for concurrent use.
+type Future struct {
+	done    chan struct{}
+	once    sync.Once
+	fitness Fitness
+	err     error
+}
+
+// NewFuture creates an unresolved Future.
+func NewFuture() *Future {
+	return &Future{done: make(chan struct{})}
+}
+
+// Resolve sets the outcome of an evaluation. Only the first call has any effect.
+func (f *Future) Resolve(fitness Fitness, err error) {
+	f.once.Do(func() {
+		f.fitness, f.err = fitness, err
+		close(f.done)
+	})
+}
+
+// Done is closed once f is resolved.
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait waits until f is resolved and returns its outcome, or returns ctx.Err() if
+// ctx is done first.
+func (f *Future) Wait(ctx context.Context) (Fitness, error) {
+	select {
+	case <-f.done:
+		return f.fitness, f.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// resolved reports whether f has been resolved.
+func (f *Future) resolved() bool {
+	select {
+	case <-f.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// AsyncFunc adapts a function which starts an evaluation into an Evaluator and
+// AsyncEvaluator.
+type AsyncFunc func(c Chromosome) *Future
+
+// Evaluate implements Evaluator by waiting for the evaluation of c.
+func (f AsyncFunc) Evaluate(c Chromosome) (Fitness, error) {
+	return f(c).Wait(context.Background())
+}
+
+// Submit implements AsyncEvaluator
+func (f AsyncFunc) Submit(c Chromosome) *Future {
+	return f(c)
+}
+
+// Evaluation is a Chromosome awaiting the Fitness which will resolve its Future.
+type Evaluation struct {
+	Chromosome Chromosome
+	*Future
+}
+
+// EvaluationQueue is an Evaluator and AsyncEvaluator which queues Chromosomes to
+// be scored elsewhere, e.g. by people using a UI which polls Pending and resolves
+// each Evaluation as it is scored. The zero value is an empty queue; it is safe
+// for concurrent use.
+type EvaluationQueue struct {
+	mu      sync.Mutex
+	pending []*Evaluation
+}
+
+// Submit implements AsyncEvaluator
+func (q *EvaluationQueue) Submit(c Chromosome) *Future {
+	ev := &Evaluation{Chromosome: c, Future: NewFuture()}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending = append(q.pending, ev)
+	return ev.Future
+}
+
+// Evaluate implements Evaluator by queueing c and waiting for its score.
+func (q *EvaluationQueue) Evaluate(c Chromosome) (Fitness, error) {
+	return q.Submit(c).Wait(context.Background())
+}
+
+// Pending returns the Evaluations which have not been resolved, oldest first.
+func (q *EvaluationQueue) Pending() []*Evaluation {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	pending := q.pending[:0]
+	for _, ev := range q.pending {
+		if !ev.resolved() {
+			pending = append(pending, ev)
+		}
+	}
+	for i := len(pending); i < len(q.pending); i++ {
+		q.pending[i] = nil
+	}
+	q.pending = pending
+	return append([]*Evaluation(nil), pending...)
+}
+
+// submit starts evaluating every pending member of the population which is not
+// cached, so that slow evaluations proceed together.
+func (e *Engine) submit(a AsyncEvaluator) []*Future {
+	futures := make([]*Future, len(e.pending))
+	for n, i := range e.pending {
+		c := e.pop.Chromosomes[i]
+		if e.Cache != nil {
+			e.cacheLookups++
+			if f, ok := e.Cache.Get(c); ok {
+				e.cacheHits++
+				futures[n] = NewFuture()
+				futures[n].Resolve(f, nil)
+				continue
+			}
+		}
+		futures[n] = a.Submit(c)
+	}
+	return futures
+}
+
+// await waits for the score of pending member i of the population, adding it to
+// the Cache.
+func (e *Engine) await(ctx context.Context, future *Future, i int) (Fitness, error) {
+	f, err := future.Wait(ctx)
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+	if err == nil && e.Cache != nil {
+		e.Cache.Add(e.pop.Chromosomes[i], f)
+	}
+	return f, err
+}
@@ -0,0 +1,118 @@
+package genetics_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+// scoreQueue plays a person scoring every Evaluation of q with oneMax, most
+// recent first, once n(batches scored so far) are pending. It sends the sizes of
+// the batches it scored once stop is closed.
+func scoreQueue(q *genetics.EvaluationQueue, n func(batch int) int, stop <-chan struct{}) <-chan []int {
+	sizes := make(chan []int, 1)
+	go func() {
+		var scored []int
+		for {
+			select {
+			case <-stop:
+				sizes <- scored
+				return
+			case <-time.After(time.Millisecond):
+			}
+			pending := q.Pending()
+			if len(pending) == 0 || len(pending) < n(len(scored)) {
+				continue
+			}
+			for k := len(pending) - 1; k >= 0; k-- {
+				pending[k].Resolve(oneMax(pending[k].Chromosome), nil)
+			}
+			scored = append(scored, len(pending))
+		}
+	}()
+	return sizes
+}
+
+func TestEngineAsyncEvaluator(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	pop := newBinaryPopulation(t, rng, 30, 20)
+	q := &genetics.EvaluationQueue{}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	engine := newStreamEngine()
+	engine.Evaluator = q
+	engine.Context = ctx
+
+	// Batches are only scored once complete, so the Engine must submit each
+	// generation before waiting for its scores.
+	stop := make(chan struct{})
+	sizes := scoreQueue(q, func(batch int) int {
+		if batch == 0 {
+			return 20
+		}
+		return 10
+	}, stop)
+	err := engine.Run(rng, pop, 5)
+	close(stop)
+	if err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if got := <-sizes; len(got) != 6 || got[0] != 20 || got[5] != 10 {
+		t.Errorf("expected an initial batch of 20 and 5 batches of 10; got %v", got)
+	}
+	for i, c := range pop.Chromosomes {
+		if pop.Fitness[i] != oneMax(c) {
+			t.Errorf("Run() left score %g for chromosome %d; want %g", pop.Fitness[i], i, oneMax(c))
+		}
+	}
+}
+
+func TestEngineAsyncEvaluatorFailures(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	pop := newBinaryPopulation(t, rng, 30, 20)
+	engine := newStreamEngine()
+	submitted := 0
+	engine.Evaluator = genetics.AsyncFunc(func(c genetics.Chromosome) *genetics.Future {
+		f := genetics.NewFuture()
+		if submitted++; submitted == 3 {
+			f.Resolve(0, errors.New("declined to score"))
+		} else {
+			f.Resolve(oneMax(c), nil)
+		}
+		return f
+	})
+	var budgetErr *genetics.EvaluationBudgetError
+	if err := engine.Run(rng, pop, 5); !errors.As(err, &budgetErr) || budgetErr.Evaluations != 3 {
+		t.Errorf("Run()=%v; want an EvaluationBudgetError after 3 evaluations", err)
+	}
+
+	// Nobody scores the queue
+	ctx, cancel := context.WithCancel(context.Background())
+	engine.Evaluator = &genetics.EvaluationQueue{}
+	engine.Context = ctx
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if err := engine.Run(rng, pop, 5); err == nil {
+		t.Error("Run() should fail once its Context is done")
+	}
+}
+
+func TestFuture(t *testing.T) {
+	f := genetics.NewFuture()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := f.Wait(ctx); err != context.Canceled {
+		t.Errorf("Wait() of an unresolved Future=%v; want %s", err, context.Canceled)
+	}
+	f.Resolve(3, nil)
+	f.Resolve(4, errors.New("ignored"))
+	if got, err := f.Wait(context.Background()); got != 3 || err != nil {
+		t.Errorf("Wait()=%g, %v; want the first resolution 3, nil", got, err)
+	}
+}
@@ -0,0 +1,38 @@
+package genetics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// AuditRecord describes a single fitness evaluation.
+type AuditRecord struct {
+	Generation int     `json:"generation"`
+	Genes      []Gene  `json:"genes"`
+	Fitness    Fitness `json:"fitness"`
+	// Operator names the operators which produced the chromosome, e.g.
+	// "MultiPointCrossover(2)+SwapMutation", or "Initial" for the first generation.
+	Operator string `json:"operator"`
+	// Error is set if the evaluation failed
+	Error string `json:"error,omitempty"`
+}
+
+// AuditLog appends one JSON object per evaluation (JSON lines) to a writer so
+// that users can audit exactly what a search explored.
+type AuditLog struct {
+	enc *json.Encoder
+}
+
+// NewAuditLog creates an AuditLog which appends to w.
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{enc: json.NewEncoder(w)}
+}
+
+// Record appends rec to the log.
+func (a *AuditLog) Record(rec AuditRecord) error {
+	if err := a.enc.Encode(rec); err != nil {
+		return fmt.Errorf("AuditLog.Record(); err=%s", err)
+	}
+	return nil
+}
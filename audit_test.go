@@ -0,0 +1,60 @@
+package genetics_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/rand"
+)
+
+func TestAuditLog(t *testing.T) {
+	rng := rand.New()
+	var buf bytes.Buffer
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 4,
+			CrossoverRate:    1,
+			MutationRate:     0.5,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator:          genetics.RandomResettingMutation{},
+		},
+		Evaluator: genetics.FitnessFunc(oneMax),
+		Audit:     genetics.NewAuditLog(&buf),
+	}
+	pop := newBinaryPopulation(t, rng, 8, 10)
+	if err := engine.Run(rng, pop, 3); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+
+	dec := json.NewDecoder(&buf)
+	var records []genetics.AuditRecord
+	for dec.More() {
+		var rec genetics.AuditRecord
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatalf("audit log is not JSON lines; err=%s", err)
+		}
+		records = append(records, rec)
+	}
+	if want := 10 + 3*4; len(records) != want {
+		t.Fatalf("audit log has %d records; want %d", len(records), want)
+	}
+	for n, rec := range records {
+		initial := n < 10
+		if initial && (rec.Generation != 0 || rec.Operator != "Initial") {
+			t.Errorf("record %d = %+v; want an initial record from generation 0", n, rec)
+		}
+		if !initial && !strings.HasPrefix(rec.Operator, "MultiPointCrossover(1)") {
+			t.Errorf("record %d = %+v; want a record produced by crossover", n, rec)
+		}
+		if len(rec.Genes) != 8 {
+			t.Errorf("record %d has %d genes; want 8", n, len(rec.Genes))
+		}
+	}
+	if last := records[len(records)-1]; last.Generation != 3 {
+		t.Errorf("last record is from generation %d; want 3", last.Generation)
+	}
+}
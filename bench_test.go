@@ -0,0 +1,251 @@
+package genetics_test
+
+// The benchmarks in this file cover every selector, crossover, and mutator at
+// several genome lengths and population sizes. Measure a change by running
+//
+//	go test -run XXX -bench . -benchmem -count 10 > new.txt
+//
+// on both the change and its base and comparing the two with benchstat. Add
+// -cpuprofile or -memprofile to profile a single benchmark (selected with -bench).
+//
+// Performance regression budget: a change must not slow any benchmark by more
+// than 5% (where benchstat reports p < 0.05) or increase its allocs/op unless
+// its description justifies the cost. TestAllocationBudget enforces the
+// allocations of the core operators, which do not vary between machines.
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+var (
+	benchGenomeLengths   = []int{10, 1000, 100000}
+	benchPopulationSizes = []int{100, 10000}
+	benchSelectors       = []genetics.NaturalSelection{
+		genetics.StochasticUniversalSampling{},
+		genetics.RouletteWheelSelection{},
+		genetics.RankedSelection{},
+		genetics.ExponentialRankedSelection{Base: 0.9},
+		genetics.TournamentSelection{Size: 3},
+		genetics.TournamentSelection{Size: 3, P: 0.8},
+	}
+	benchCrossovers = []genetics.Crossover{
+		genetics.MultiPointCrossover{Points: 1},
+		genetics.MultiPointCrossover{Points: 8},
+		genetics.WholeArithmeticRecombination{},
+		genetics.DavisOrderCrossover{},
+		genetics.PartiallyMappedCrossover{},
+		genetics.CompositeCrossover{Crossovers: []genetics.Crossover{genetics.DavisOrderCrossover{}, genetics.PartiallyMappedCrossover{}}},
+	}
+	benchMutators = []genetics.Mutator{
+		genetics.RandomResettingMutation{},
+		genetics.SwapMutation{},
+		genetics.ScrambleMutation{},
+		genetics.InversionMutation{},
+		genetics.InsertionMutation{},
+		genetics.DisplacementMutation{},
+		genetics.InvertedDisplacementMutation{},
+		genetics.CompositeMutator{Mutators: []genetics.Mutator{genetics.SwapMutation{}, genetics.InversionMutation{}}},
+	}
+)
+
+// benchParents returns two permutations of numGenes genes, which every
+// crossover and mutator accepts.
+func benchParents(numGenes int) (a, b genetics.Chromosome) {
+	rng := rand.New()
+	rng.Seed(42)
+	s := genetics.NewSpecies(numGenes, genetics.Gene(numGenes-1))
+	a, _ = s.NewPerm(rng)
+	b, _ = s.NewPerm(rng)
+	return a, b
+}
+
+// benchScores returns size random scores.
+func benchScores(size int) []genetics.Fitness {
+	rng := rand.New()
+	rng.Seed(42)
+	scores := make([]genetics.Fitness, size)
+	for i := range scores {
+		scores[i] = genetics.Fitness(rng.Float64())
+	}
+	return scores
+}
+
+func BenchmarkSelectors(b *testing.B) {
+	for _, s := range benchSelectors {
+		for _, size := range benchPopulationSizes {
+			scores := benchScores(size)
+			b.Run(fmt.Sprintf("%s/%d", s, size), func(b *testing.B) {
+				rng := rand.New()
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					s.SelectParents(rng, size/2, scores)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkCrossovers(b *testing.B) {
+	for _, c := range benchCrossovers {
+		for _, numGenes := range benchGenomeLengths {
+			pa, pb := benchParents(numGenes)
+			b.Run(fmt.Sprintf("%s/%d", c, numGenes), func(b *testing.B) {
+				rng := rand.New()
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					c.Crossover(rng, pa, pb)
+				}
+			})
+			into, ok := c.(genetics.CrossoverInto)
+			if !ok {
+				continue
+			}
+			x, y := pa.Species.New(), pa.Species.New()
+			b.Run(fmt.Sprintf("%s/%d/Into", c, numGenes), func(b *testing.B) {
+				rng := rand.New()
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					into.CrossoverInto(rng, pa, pb, x, y)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkMutators(b *testing.B) {
+	for _, m := range benchMutators {
+		for _, numGenes := range benchGenomeLengths {
+			c, _ := benchParents(numGenes)
+			b.Run(fmt.Sprintf("%s/%d", m, numGenes), func(b *testing.B) {
+				rng := rand.New()
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					m.Mutate(rng, &c)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkRealOperators(b *testing.B) {
+	for _, numGenes := range benchGenomeLengths {
+		rng := rand.New()
+		rng.Seed(42)
+		s := genetics.NewUniformRealSpecies(numGenes, -5, 5)
+		pa, pb := s.NewRand(rng), s.NewRand(rng)
+		for _, c := range []genetics.RealCrossover{
+			genetics.ArithmeticCrossover{},
+			genetics.BlendCrossover{Alpha: 0.5},
+		} {
+			b.Run(fmt.Sprintf("%s/%d", c, numGenes), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					c.Crossover(rng, pa, pb)
+				}
+			})
+		}
+		for _, m := range []genetics.RealMutator{
+			genetics.GaussianMutation{Sigma: 0.1},
+			genetics.PolynomialMutation{Eta: 20},
+		} {
+			c := s.NewRand(rng)
+			b.Run(fmt.Sprintf("%s/%d", m, numGenes), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					m.Mutate(rng, &c)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkBinaryOperators(b *testing.B) {
+	for _, numGenes := range benchGenomeLengths {
+		rng := rand.New()
+		rng.Seed(42)
+		s := genetics.NewBinarySpecies(numGenes)
+		pa, _ := s.NewRand(rng)
+		pb, _ := s.NewRand(rng)
+		for _, c := range []genetics.BinaryCrossover{
+			genetics.UniformMaskCrossover{},
+			genetics.PointMaskCrossover{Points: 2},
+		} {
+			b.Run(fmt.Sprintf("%s/%d", c, numGenes), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					c.Crossover(rng, pa, pb)
+				}
+			})
+		}
+		m := genetics.BitFlipMutation{}
+		c, _ := s.NewRand(rng)
+		b.Run(fmt.Sprintf("%s/%d", m, numGenes), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				m.Mutate(rng, &c)
+			}
+		})
+	}
+}
+
+func BenchmarkEvolve(b *testing.B) {
+	for _, size := range benchPopulationSizes {
+		for _, numGenes := range benchGenomeLengths[:2] {
+			rng := rand.New()
+			rng.Seed(42)
+			pop, err := genetics.NewSpecies(numGenes, 1).NewRandPopulation(rng, size)
+			if err != nil {
+				b.Fatalf("NewRandPopulation(); err=%s", err)
+			}
+			scores := benchScores(size)
+			e := genetics.NewEvolver(genetics.WithReplacementCount(size / 2))
+			b.Run(fmt.Sprintf("%d/%d", size, numGenes), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					e.Evolve(rng, pop.Chromosomes, scores)
+				}
+			})
+		}
+	}
+}
+
+// TestAllocationBudget enforces the allocations per call of the core operators;
+// see the performance regression budget above.
+func TestAllocationBudget(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector adds allocations")
+	}
+	rng := rand.New()
+	rng.Seed(42)
+	pa, pb := benchParents(100)
+	x, y := pa.Species.New(), pa.Species.New()
+	scores := benchScores(100)
+	for _, test := range []struct {
+		tag    string
+		budget float64
+		fn     func()
+	}{
+		{"MultiPoint", 3, func() { genetics.MultiPointCrossover{Points: 2}.Crossover(rng, pa, pb) }},
+		{"MultiPoint/Into", 1, func() { genetics.MultiPointCrossover{Points: 2}.CrossoverInto(rng, pa, pb, x, y) }},
+		{"WholeArithmetic/Into", 0, func() { genetics.WholeArithmeticRecombination{}.CrossoverInto(rng, pa, pb, x, y) }},
+		{"DavisOrder/Into", 2, func() { genetics.DavisOrderCrossover{}.CrossoverInto(rng, pa, pb, x, y) }},
+		{"PMX/Into", 2, func() { genetics.PartiallyMappedCrossover{}.CrossoverInto(rng, pa, pb, x, y) }},
+		{"Swap", 0, func() { genetics.SwapMutation{}.Mutate(rng, &x) }},
+		{"Scramble", 0, func() { genetics.ScrambleMutation{}.Mutate(rng, &x) }},
+		{"Inversion", 0, func() { genetics.InversionMutation{}.Mutate(rng, &x) }},
+		{"Insertion", 0, func() { genetics.InsertionMutation{}.Mutate(rng, &x) }},
+		{"Displacement", 0, func() { genetics.DisplacementMutation{}.Mutate(rng, &x) }},
+		{"InvertedDisplacement", 0, func() { genetics.InvertedDisplacementMutation{}.Mutate(rng, &x) }},
+		{"StochasticUniversalSampling", 2, func() { genetics.StochasticUniversalSampling{}.SelectParents(rng, 50, scores) }},
+		{"TournamentSelection", 51, func() { genetics.TournamentSelection{Size: 3}.SelectParents(rng, 50, scores) }},
+	} {
+		if got := testing.AllocsPerRun(100, test.fn); got > test.budget {
+			t.Errorf("%s made %g allocations per call; budget is %g", test.tag, got, test.budget)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+package genetics
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+
+	"github.com/inlined/rand"
+)
+
+const (
+	bitFlipMutation      = "BitFlipMutation"
+	maskFlipMutation     = "MaskFlipMutation"
+	uniformMaskCrossover = "UniformMaskCrossover"
+	pointMaskCrossover   = "PointMaskCrossover"
+)
+
+// BinarySpecies is a factory for bit-packed binary Chromosomes. Binary encodings
+// such as knapsack problems need one bit per gene, so packing 64 genes into each
+// word saves 64x memory over a Species with MaxAllele 1 and lets operators work a
+// word at a time.
+type BinarySpecies struct {
+	NumGenes int
+}
+
+// NewBinarySpecies initializes a BinarySpecies
+func NewBinarySpecies(numGenes int) *BinarySpecies {
+	return &BinarySpecies{NumGenes: numGenes}
+}
+
+// numWords is the number of uint64s needed to hold NumGenes bits.
+func (s *BinarySpecies) numWords() int {
+	return (s.NumGenes + 63) / 64
+}
+
+// tailMask masks off the unused bits of the last word.
+func (s *BinarySpecies) tailMask() uint64 {
+	if s.NumGenes%64 == 0 {
+		return math.MaxUint64
+	}
+	return 1<<uint(s.NumGenes%64) - 1
+}
+
+// BinaryChromosome is a bit-packed Chromosome of a BinarySpecies. Gene i is bit
+// i%64 of Words[i/64]; unused bits of the last word are always 0.
+type BinaryChromosome struct {
+	Species *BinarySpecies
+	Words   []uint64
+}
+
+// New creates a BinaryChromosome with every gene unset.
+func (s *BinarySpecies) New() BinaryChromosome {
+	return BinaryChromosome{
+		Species: s,
+		Words:   make([]uint64, s.numWords()),
+	}
+}
+
+// NewRand creates a BinaryChromosome with each gene independently randomized.
+func (s *BinarySpecies) NewRand(rng rand.Rand) (BinaryChromosome, error) {
+	c := s.New()
+	b := make([]byte, 8*len(c.Words))
+	if n, err := rng.Read(b); n != len(b) || err != nil {
+		return BinaryChromosome{}, fmt.Errorf("rand.Read(); wanted %d bytes; got %d bytes; err=%s", len(b), n, err)
+	}
+	for i := range c.Words {
+		c.Words[i] = binary.LittleEndian.Uint64(b[8*i:])
+	}
+	if len(c.Words) != 0 {
+		c.Words[len(c.Words)-1] &= s.tailMask()
+	}
+	return c, nil
+}
+
+// Get returns gene i.
+func (c BinaryChromosome) Get(i int) bool {
+	return c.Words[i/64]&(1<<uint(i%64)) != 0
+}
+
+// Set sets gene i to v.
+func (c BinaryChromosome) Set(i int, v bool) {
+	if v {
+		c.Words[i/64] |= 1 << uint(i%64)
+	} else {
+		c.Words[i/64] &^= 1 << uint(i%64)
+	}
+}
+
+// Flip inverts gene i.
+func (c BinaryChromosome) Flip(i int) {
+	c.Words[i/64] ^= 1 << uint(i%64)
+}
+
+// Count returns the number of set genes.
+func (c BinaryChromosome) Count() int {
+	n := 0
+	for _, w := range c.Words {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// BinaryCrossover is a strategy for generating two bit-packed children from two parents.
+type BinaryCrossover interface {
+	fmt.Stringer
+	Crossover(r rand.Rand, a, b BinaryChromosome) (x, y BinaryChromosome)
+}
+
+// BinaryMutator introduces randomness to a bit-packed population.
+type BinaryMutator interface {
+	fmt.Stringer
+	Mutate(r rand.Rand, c *BinaryChromosome)
+}
+
+// BitFlipMutation flips each gene independently with probability Rate. A Rate of 0
+// flips exactly one random gene. Rather than drawing a random number per gene,
+// the distance to the next flipped gene is drawn from a geometric distribution.
+type BitFlipMutation struct {
+	Rate float64
+}
+
+func (m BitFlipMutation) String() string {
+	return fmt.Sprintf("%s(%g)", bitFlipMutation, m.Rate)
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (BitFlipMutation) ConcurrentSafe() bool {
+	return true
+}
+
+// Mutate implements BinaryMutator
+func (m BitFlipMutation) Mutate(r rand.Rand, c *BinaryChromosome) {
+	n := c.Species.NumGenes
+	if m.Rate == 0 {
+		c.Flip(int(r.Int63n(int64(n))))
+		return
+	}
+	if m.Rate >= 1 {
+		for i := range c.Words {
+			c.Words[i] = ^c.Words[i]
+		}
+		c.Words[len(c.Words)-1] &= c.Species.tailMask()
+		return
+	}
+	logq := math.Log(1 - m.Rate)
+	for i := -1; ; {
+		u := r.Float64()
+		for u == 0 {
+			u = r.Float64()
+		}
+		i += 1 + int(math.Log(u)/logq)
+		if i >= n || i < 0 {
+			return
+		}
+		c.Flip(i)
+	}
+}
+
+// MaskFlipMutation flips each gene independently with probability Rate, rounded to
+// a multiple of 1/256, a word at a time: it XORs in random masks whose bits are each
+// set with probability Rate. It draws the same randomness for any Rate, so it is
+// much faster than BitFlipMutation unless Rate is small. A Rate of 0 flips exactly
+// one random gene.
+type MaskFlipMutation struct {
+	Rate float64
+}
+
+func (m MaskFlipMutation) String() string {
+	return fmt.Sprintf("%s(%g)", maskFlipMutation, m.Rate)
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (MaskFlipMutation) ConcurrentSafe() bool {
+	return true
+}
+
+// Mutate implements BinaryMutator
+func (m MaskFlipMutation) Mutate(r rand.Rand, c *BinaryChromosome) {
+	if m.Rate == 0 {
+		c.Flip(int(r.Int63n(int64(c.Species.NumGenes))))
+		return
+	}
+	mask := make([]uint64, len(c.Words))
+	if err := randomMask(r, m.Rate, mask); err != nil {
+		panic(fmt.Sprintf("MaskFlipMutation: %s", err))
+	}
+	for i, w := range mask {
+		c.Words[i] ^= w
+	}
+	c.Words[len(c.Words)-1] &= c.Species.tailMask()
+}
+
+// maskBits is the precision, in bits, of the rates of mask-based mutations.
+const maskBits = 8
+
+// randomMask fills mask with random bits which are each set with probability rate,
+// rounded to a multiple of 1/2^maskBits. Working up from the least significant set
+// bit of the rounded rate, each bit ORs in a random word if it is set and ANDs one
+// in if it is not, which sets each bit of mask with the probability of the binary
+// fraction.
+func randomMask(r rand.Rand, rate float64, mask []uint64) error {
+	p := uint(math.Round(rate * (1 << maskBits)))
+	if p == 0 || p >= 1<<maskBits {
+		fill := uint64(0)
+		if p != 0 {
+			fill = math.MaxUint64
+		}
+		for i := range mask {
+			mask[i] = fill
+		}
+		return nil
+	}
+	b := make([]byte, 8*len(mask))
+	lowest := bits.TrailingZeros(p)
+	for k := lowest; k < maskBits; k++ {
+		if n, err := r.Read(b); n != len(b) || err != nil {
+			return fmt.Errorf("rand.Read(); wanted %d bytes; got %d bytes; err=%s", len(b), n, err)
+		}
+		for i := range mask {
+			w := binary.LittleEndian.Uint64(b[8*i:])
+			switch {
+			case k == lowest:
+				mask[i] = w
+			case p&(1<<uint(k)) != 0:
+				mask[i] |= w
+			default:
+				mask[i] &= w
+			}
+		}
+	}
+	return nil
+}
+
+// UniformMaskCrossover draws a random mask and builds each child from one parent's
+// genes where the mask is set and the other's where it is not, 64 genes at a time.
+type UniformMaskCrossover struct{}
+
+func (UniformMaskCrossover) String() string {
+	return uniformMaskCrossover
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (UniformMaskCrossover) ConcurrentSafe() bool {
+	return true
+}
+
+// Crossover implements BinaryCrossover
+func (UniformMaskCrossover) Crossover(r rand.Rand, a, b BinaryChromosome) (x, y BinaryChromosome) {
+	s := a.Species
+	mask, err := s.NewRand(r)
+	if err != nil {
+		panic(fmt.Sprintf("UniformMaskCrossover: %s", err))
+	}
+	return crossMasked(a, b, mask.Words)
+}
+
+// PointMaskCrossover is MultiPointCrossover for bit-packed chromosomes. The
+// alternating segments are converted to a mask so that whole words are exchanged
+// at once.
+type PointMaskCrossover struct {
+	Points int
+}
+
+func (c PointMaskCrossover) String() string {
+	return fmt.Sprintf("%s(%d)", pointMaskCrossover, c.Points)
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (PointMaskCrossover) ConcurrentSafe() bool {
+	return true
+}
+
+// Crossover implements BinaryCrossover
+func (c PointMaskCrossover) Crossover(r rand.Rand, a, b BinaryChromosome) (x, y BinaryChromosome) {
+	s := a.Species
+	indexes := rand.Deal(r, s.NumGenes, c.Points)
+	sort.Ints(indexes)
+	indexes = append(indexes, s.NumGenes)
+	mask := make([]uint64, s.numWords())
+	for n := 0; n+1 < len(indexes); n += 2 {
+		setBits(mask, indexes[n], indexes[n+1])
+	}
+	return crossMasked(a, b, mask)
+}
+
+// setBits sets bits [lo, hi) of words.
+func setBits(words []uint64, lo, hi int) {
+	for lo < hi {
+		w, bit := lo/64, uint(lo%64)
+		n := 64 - int(bit)
+		if hi-lo < n {
+			n = hi - lo
+		}
+		words[w] |= (math.MaxUint64 >> uint(64-n)) << bit
+		lo += n
+	}
+}
+
+// crossMasked exchanges the genes of a and b where mask is set.
+func crossMasked(a, b BinaryChromosome, mask []uint64) (x, y BinaryChromosome) {
+	x, y = a.Species.New(), a.Species.New()
+	for i, m := range mask {
+		x.Words[i] = a.Words[i]&^m | b.Words[i]&m
+		y.Words[i] = b.Words[i]&^m | a.Words[i]&m
+	}
+	return x, y
+}
+
+// BinaryEvolver is the Evolver for bit-packed populations. It shares selection
+// and replacement with Evolver.
+type BinaryEvolver struct {
+	ReplacementCount int
+	CrossoverRate    float32
+	MutationRate     float32
+	Selector         NaturalSelection
+	Crossover        BinaryCrossover
+	Mutator          BinaryMutator
+}
+
+// Validate reports the configuration errors that Evolver.Validate does, other
+// than those of a MatingRestriction.
+func (e BinaryEvolver) Validate(populationSize int) error {
+	if err := validateEvolver("BinaryEvolver", populationSize, e.ReplacementCount, e.CrossoverRate, e.MutationRate, e.Selector); err != nil {
+		return err
+	}
+	switch {
+	case e.Crossover == nil && e.CrossoverRate > 0:
+		return fmt.Errorf("BinaryEvolver.Validate(); Crossover is nil but CrossoverRate is %g", e.CrossoverRate)
+	case e.Mutator == nil && e.MutationRate > 0:
+		return fmt.Errorf("BinaryEvolver.Validate(); Mutator is nil but MutationRate is %g", e.MutationRate)
+	}
+	return nil
+}
+
+// Evolve replaces a handful of the population with the next generation. It
+// panics if the BinaryEvolver is invalid for pop; see Validate.
+func (e BinaryEvolver) Evolve(rand rand.Rand, pop []BinaryChromosome, scores []Fitness) {
+	if err := e.Validate(len(pop)); err != nil {
+		panic(err.Error())
+	}
+	breed(rand, pop, scores, e.ReplacementCount, e.Selector, func(a, b BinaryChromosome) (x, y BinaryChromosome) {
+		if rand.Float32() < e.CrossoverRate {
+			x, y = e.Crossover.Crossover(rand, a, b)
+		} else {
+			x = BinaryChromosome{Species: a.Species, Words: append([]uint64(nil), a.Words...)}
+			y = BinaryChromosome{Species: b.Species, Words: append([]uint64(nil), b.Words...)}
+		}
+		if rand.Float32() < e.MutationRate {
+			e.Mutator.Mutate(rand, &x)
+		}
+		if rand.Float32() < e.MutationRate {
+			e.Mutator.Mutate(rand, &y)
+		}
+		return x, y
+	})
+}
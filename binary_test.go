@@ -0,0 +1,315 @@
+package genetics_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/inlined/rand"
+	"github.com/inlined/xkcd"
+
+	"github.com/inlined/genetics"
+)
+
+func TestBinaryChromosome(t *testing.T) {
+	s := genetics.NewBinarySpecies(70)
+	c := s.New()
+	if len(c.Words) != 2 {
+		t.Fatalf("70 genes should pack into 2 words; got %d", len(c.Words))
+	}
+	c.Set(0, true)
+	c.Set(64, true)
+	c.Flip(69)
+	c.Flip(0)
+	if c.Get(0) || !c.Get(64) || !c.Get(69) || c.Count() != 2 {
+		t.Errorf("unexpected genes %x", c.Words)
+	}
+
+	rng := rand.New()
+	for run := 0; run < 20; run++ {
+		c, err := s.NewRand(rng)
+		if err != nil {
+			t.Fatalf("NewRand(); err=%s", err)
+		}
+		if c.Words[1]>>6 != 0 {
+			t.Fatalf("NewRand() set unused bits: %x", c.Words[1])
+		}
+	}
+}
+
+func TestPointMaskCrossover(t *testing.T) {
+	s := genetics.NewBinarySpecies(130)
+	a, b := s.New(), s.New()
+	for i := 0; i < s.NumGenes; i++ {
+		a.Set(i, true)
+	}
+	// Deal points 10 and 100; genes [10, 100) come from the other parent
+	x, y := genetics.PointMaskCrossover{Points: 2}.Crossover(xkcd.Rand(10, 100), a, b)
+	for i := 0; i < s.NumGenes; i++ {
+		fromB := i >= 10 && i < 100
+		if x.Get(i) == fromB || y.Get(i) != fromB {
+			t.Fatalf("gene %d crossed incorrectly; x=%t y=%t", i, x.Get(i), y.Get(i))
+		}
+	}
+}
+
+func TestUniformMaskCrossover(t *testing.T) {
+	s := genetics.NewBinarySpecies(100)
+	a, b := s.New(), s.New()
+	for i := 0; i < s.NumGenes; i++ {
+		a.Set(i, true)
+	}
+	x, y := genetics.UniformMaskCrossover{}.Crossover(rand.New(), a, b)
+	for i := 0; i < s.NumGenes; i++ {
+		if x.Get(i) == y.Get(i) {
+			t.Fatalf("gene %d was not inherited from exactly one parent by each child", i)
+		}
+	}
+}
+
+func TestBitFlipMutation(t *testing.T) {
+	s := genetics.NewBinarySpecies(1000)
+	rng := rand.New()
+
+	c := s.New()
+	genetics.BitFlipMutation{}.Mutate(rng, &c)
+	if c.Count() != 1 {
+		t.Errorf("BitFlipMutation{} flipped %d genes; want 1", c.Count())
+	}
+
+	c = s.New()
+	genetics.BitFlipMutation{Rate: 1}.Mutate(rng, &c)
+	if c.Count() != 1000 {
+		t.Errorf("BitFlipMutation{1} flipped %d genes; want 1000", c.Count())
+	}
+
+	c = s.New()
+	genetics.BitFlipMutation{Rate: 0.1}.Mutate(rng, &c)
+	if n := c.Count(); n < 50 || n > 150 {
+		t.Errorf("BitFlipMutation{0.1} flipped %d of 1000 genes; want about 100", n)
+	}
+}
+
+func TestMaskFlipMutation(t *testing.T) {
+	s := genetics.NewBinarySpecies(100000)
+	rng := rand.New()
+	rng.Seed(42)
+	for _, test := range []struct {
+		rate     float64
+		min, max int
+	}{
+		{0, 1, 1},
+		{1, 100000, 100000},
+		{0.5, 49000, 51000},
+		{0.25, 24000, 26000},
+		// Rounded to 77/256
+		{0.3, 29000, 31000},
+		// Rounded to 3/256
+		{0.01, 1000, 1350},
+	} {
+		c := s.New()
+		genetics.MaskFlipMutation{Rate: test.rate}.Mutate(rng, &c)
+		if n := c.Count(); n < test.min || n > test.max {
+			t.Errorf("MaskFlipMutation{%g} flipped %d of 100000 genes; want [%d, %d]", test.rate, n, test.min, test.max)
+		}
+	}
+
+	c := genetics.NewBinarySpecies(70).New()
+	genetics.MaskFlipMutation{Rate: 1}.Mutate(rng, &c)
+	if c.Count() != 70 || c.Words[1]>>6 != 0 {
+		t.Errorf("MaskFlipMutation{1} set unused bits: %x", c.Words[1])
+	}
+}
+
+func TestBinaryEvolverOneMax(t *testing.T) {
+	rng := rand.New()
+	s := genetics.NewBinarySpecies(200)
+	pop := make([]genetics.BinaryChromosome, 30)
+	scores := make([]genetics.Fitness, len(pop))
+	for i := range pop {
+		pop[i], _ = s.NewRand(rng)
+	}
+	evolver := genetics.BinaryEvolver{
+		ReplacementCount: 14,
+		CrossoverRate:    1,
+		MutationRate:     0.5,
+		Selector:         genetics.TournamentSelection{Size: 3},
+		Crossover:        genetics.UniformMaskCrossover{},
+		Mutator:          genetics.BitFlipMutation{},
+	}
+	initial, best := 0, 0
+	for gen := 0; gen < 50; gen++ {
+		for i, c := range pop {
+			scores[i] = genetics.Fitness(c.Count())
+			if c.Count() > best {
+				best = c.Count()
+			}
+		}
+		if gen == 0 {
+			initial = best
+		}
+		evolver.Evolve(rng, pop, scores)
+	}
+	if best <= initial {
+		t.Errorf("BinaryEvolver did not improve OneMax; initial=%d best=%d", initial, best)
+	}
+}
+
+func TestBinaryEvolverValidate(t *testing.T) {
+	valid := genetics.BinaryEvolver{
+		ReplacementCount: 2,
+		CrossoverRate:    0.9,
+		MutationRate:     0.1,
+		Selector:         genetics.TournamentSelection{Size: 2},
+		Crossover:        genetics.UniformMaskCrossover{},
+		Mutator:          genetics.BitFlipMutation{},
+	}
+	for _, test := range []struct {
+		tag    string
+		modify func(e *genetics.BinaryEvolver)
+		valid  bool
+	}{
+		{tag: "valid", modify: func(e *genetics.BinaryEvolver) {}, valid: true},
+		{tag: "mutation only", modify: func(e *genetics.BinaryEvolver) { e.Crossover, e.CrossoverRate = nil, 0 }, valid: true},
+		{tag: "nil Selector", modify: func(e *genetics.BinaryEvolver) { e.Selector = nil }},
+		{tag: "nil Crossover", modify: func(e *genetics.BinaryEvolver) { e.Crossover = nil }},
+		{tag: "nil Mutator", modify: func(e *genetics.BinaryEvolver) { e.Mutator = nil }},
+		{tag: "odd ReplacementCount", modify: func(e *genetics.BinaryEvolver) { e.ReplacementCount = 3 }},
+		{tag: "ReplacementCount too large", modify: func(e *genetics.BinaryEvolver) { e.ReplacementCount = 6 }},
+		{tag: "MutationRate above 1", modify: func(e *genetics.BinaryEvolver) { e.MutationRate = 1.5 }},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			e := valid
+			test.modify(&e)
+			if err := e.Validate(4); test.valid != (err == nil) {
+				t.Errorf("Validate(4); valid=%t err=%v", test.valid, err)
+			}
+		})
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Evolve() should panic with an odd ReplacementCount")
+		}
+	}()
+	rng := rand.New()
+	rng.Seed(42)
+	s := genetics.NewBinarySpecies(8)
+	pop := make([]genetics.BinaryChromosome, 4)
+	for i := range pop {
+		pop[i], _ = s.NewRand(rng)
+	}
+	e := valid
+	e.ReplacementCount = 3
+	e.Evolve(rng, pop, make([]genetics.Fitness, len(pop)))
+}
+
+// The bit-packed benchmarks are compared against their []Gene counterparts on
+// large genomes; see -benchmem for the memory savings.
+func BenchmarkCrossover(b *testing.B) {
+	for _, numGenes := range []int{10000, 100000} {
+		rng := rand.New()
+		s := genetics.NewSpecies(numGenes, 1)
+		ga, _ := s.NewRand(rng)
+		gb, _ := s.NewRand(rng)
+		b.Run(fmt.Sprintf("Gene/%d", numGenes), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				genetics.MultiPointCrossover{Points: 2}.Crossover(rng, ga, gb)
+			}
+		})
+
+		bs := genetics.NewBinarySpecies(numGenes)
+		ba, _ := bs.NewRand(rng)
+		bb, _ := bs.NewRand(rng)
+		b.Run(fmt.Sprintf("Binary/%d", numGenes), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				genetics.PointMaskCrossover{Points: 2}.Crossover(rng, ba, bb)
+			}
+		})
+	}
+}
+
+func BenchmarkNewRand(b *testing.B) {
+	for _, numGenes := range []int{10000, 100000} {
+		rng := rand.New()
+		s := genetics.NewSpecies(numGenes, 1)
+		b.Run(fmt.Sprintf("Gene/%d", numGenes), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				s.NewRand(rng)
+			}
+		})
+		bs := genetics.NewBinarySpecies(numGenes)
+		b.Run(fmt.Sprintf("Binary/%d", numGenes), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				bs.NewRand(rng)
+			}
+		})
+	}
+}
+
+// uniformGeneByGene is a uniform crossover which flips a coin per gene, the
+// baseline for UniformMaskCrossover.
+func uniformGeneByGene(r rand.Rand, a, b genetics.BinaryChromosome) (x, y genetics.BinaryChromosome) {
+	x, y = a.Species.New(), a.Species.New()
+	for i := 0; i < a.Species.NumGenes; i++ {
+		if r.Float64() < 0.5 {
+			x.Set(i, b.Get(i))
+			y.Set(i, a.Get(i))
+		} else {
+			x.Set(i, a.Get(i))
+			y.Set(i, b.Get(i))
+		}
+	}
+	return x, y
+}
+
+// flipGeneByGene flips each gene with probability rate, the baseline for
+// BitFlipMutation and MaskFlipMutation.
+func flipGeneByGene(r rand.Rand, rate float64, c genetics.BinaryChromosome) {
+	for i := 0; i < c.Species.NumGenes; i++ {
+		if r.Float64() < rate {
+			c.Flip(i)
+		}
+	}
+}
+
+// The word-level operators are compared against gene-by-gene loops on the same
+// bit-packed chromosomes.
+func BenchmarkWordLevelBinary(b *testing.B) {
+	for _, numGenes := range []int{10000, 100000} {
+		rng := rand.New()
+		s := genetics.NewBinarySpecies(numGenes)
+		pa, _ := s.NewRand(rng)
+		pb, _ := s.NewRand(rng)
+		b.Run(fmt.Sprintf("Uniform/GeneByGene/%d", numGenes), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				uniformGeneByGene(rng, pa, pb)
+			}
+		})
+		b.Run(fmt.Sprintf("Uniform/Mask/%d", numGenes), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				genetics.UniformMaskCrossover{}.Crossover(rng, pa, pb)
+			}
+		})
+		for _, rate := range []float64{0.01, 0.1} {
+			b.Run(fmt.Sprintf("Flip(%g)/GeneByGene/%d", rate, numGenes), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					flipGeneByGene(rng, rate, pa)
+				}
+			})
+			b.Run(fmt.Sprintf("Flip(%g)/BitFlip/%d", rate, numGenes), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					genetics.BitFlipMutation{Rate: rate}.Mutate(rng, &pa)
+				}
+			})
+			b.Run(fmt.Sprintf("Flip(%g)/MaskFlip/%d", rate, numGenes), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					genetics.MaskFlipMutation{Rate: rate}.Mutate(rng, &pa)
+				}
+			})
+		}
+	}
+}
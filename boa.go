@@ -0,0 +1,251 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/inlined/rand"
+)
+
+// BOA is the Bayesian optimization algorithm of Pelikan, Goldberg, and Cantú-Paz,
+// for binary problems whose genes interact so strongly that crossover disrupts the
+// building blocks it should combine. Each generation it learns a Bayesian network,
+// in which each gene depends on up to MaxParents others, from the Selected fittest
+// of a population of PopulationSize, and replaces the rest of the population with
+// Chromosomes sampled from the network. The network is built greedily, adding the
+// dependency that most improves its Bayesian information criterion until none
+// does. Probabilities reports the frequency of 1 in each gene of the population,
+// and a run ends early once every gene has converged.
+type BOA struct {
+	distribution
+
+	Species   *Species
+	Evaluator Evaluator
+
+	// PopulationSize defaults to 200.
+	PopulationSize int
+	// Selected is the number of fittest Chromosomes the network is learned from and
+	// which survive to the next generation. Defaults to half of PopulationSize.
+	Selected int
+	// MaxParents is the most genes on which a gene may depend, in [1, 16]. Defaults
+	// to 2.
+	MaxParents int
+
+	// Terminate, if set, ends a Run early once a generation satisfies it.
+	Terminate Termination
+	// Observers are notified of the Stats of the population after each generation.
+	Observers []StatsObserver
+
+	parents [][]int
+}
+
+// Parents returns, for each gene, the genes it depended on in the most recently
+// learned network.
+func (b *BOA) Parents() [][]int {
+	return b.parents
+}
+
+// Run evolves the population for up to the given number of generations and
+// returns the fittest Chromosome evaluated.
+func (b *BOA) Run(r rand.Rand, generations int) (Chromosome, Fitness, error) {
+	if err := b.reset("BOA", b.Species); err != nil {
+		return Chromosome{}, 0, err
+	}
+	n := b.PopulationSize
+	if n == 0 {
+		n = 200
+	}
+	selected := b.Selected
+	if selected == 0 {
+		selected = n / 2
+	}
+	maxParents := b.MaxParents
+	if maxParents == 0 {
+		maxParents = 2
+	}
+	if selected < 1 || selected >= n || maxParents < 1 || maxParents > 16 {
+		return Chromosome{}, 0, fmt.Errorf("BOA.Run(); Selected %d must be in [1, PopulationSize %d) and MaxParents %d in [1, 16]", selected, n, maxParents)
+	}
+	pop := make([]Chromosome, n)
+	for k := range pop {
+		pop[k] = b.Species.New()
+	}
+	scores := make([]Fitness, n)
+	b.parents = nil
+	return b.run("BOA", generations, b.Terminate, b.Observers, func() ([]Fitness, int, error) {
+		// The first generation is sampled uniformly; later ones replace all but the
+		// Selected fittest with samples of a network learned from them.
+		order, start := rankIndexes(scores), 0
+		var net *bayesianNetwork
+		if b.generation > 0 {
+			start = selected
+			parents := make([]Chromosome, selected)
+			for k, i := range order[:selected] {
+				parents[k] = pop[i]
+			}
+			net = learnNetwork(parents, maxParents)
+			b.parents = net.parents
+		}
+		for _, k := range order[start:] {
+			if net == nil {
+				b.sample(r, pop[k])
+			} else {
+				net.sample(r, pop[k])
+			}
+			f, err := b.evaluate(b.Evaluator, pop[k])
+			if err != nil {
+				return nil, 0, err
+			}
+			scores[k] = f
+		}
+		for i := range b.p {
+			ones := 0
+			for _, c := range pop {
+				ones += c.Genes[i]
+			}
+			b.p[i] = float64(ones) / float64(n)
+		}
+		return scores, n - start, nil
+	})
+}
+
+// bayesianNetwork is a Bayesian network over the genes of a binary Species.
+type bayesianNetwork struct {
+	// parents[i] are the genes on which gene i depends.
+	parents [][]int
+	// order lists every gene after its parents.
+	order []int
+	// ones[i][config] is the probability that gene i is 1 given that its parents
+	// have the values of the bits of config.
+	ones [][]float64
+}
+
+// learnNetwork greedily builds the network with at most maxParents parents per gene
+// that best fits xs by the Bayesian information criterion.
+func learnNetwork(xs []Chromosome, maxParents int) *bayesianNetwork {
+	n := len(xs[0].Genes)
+	penalty := math.Log(float64(len(xs))) / 2
+	net := &bayesianNetwork{parents: make([][]int, n)}
+
+	// score is the BIC of gene i given parents: the log-likelihood of xs less a
+	// penalty for each free parameter of the conditional probability table.
+	score := func(i int, parents []int) float64 {
+		counts := conditionalCounts(xs, i, parents)
+		ll := 0.0
+		for _, c := range counts {
+			total := c[0] + c[1]
+			for _, k := range c {
+				if k > 0 {
+					ll += k * math.Log(k/total)
+				}
+			}
+		}
+		return ll - penalty*float64(len(counts))
+	}
+
+	// gain[i][j] is the improvement of adding j to the parents of i.
+	// ancestor[a][d] reports whether a path leads from gene a to gene d, to keep the
+	// network acyclic.
+	current := make([]float64, n)
+	gain := make([][]float64, n)
+	ancestor := make([][]bool, n)
+	update := func(i int) {
+		for j := range gain[i] {
+			gain[i][j] = math.Inf(-1)
+			if j != i && len(net.parents[i]) < maxParents && !containsInt(net.parents[i], j) {
+				gain[i][j] = score(i, append(net.parents[i][:len(net.parents[i]):len(net.parents[i])], j)) - current[i]
+			}
+		}
+	}
+	for i := 0; i < n; i++ {
+		current[i] = score(i, nil)
+		gain[i] = make([]float64, n)
+		ancestor[i] = make([]bool, n)
+		ancestor[i][i] = true
+		update(i)
+	}
+	for {
+		child, parent, best := -1, -1, 0.0
+		for i := range gain {
+			for j, g := range gain[i] {
+				if g > best && !ancestor[i][j] {
+					child, parent, best = i, j, g
+				}
+			}
+		}
+		if child < 0 {
+			break
+		}
+		net.parents[child] = append(net.parents[child], parent)
+		current[child] += best
+		for a := range ancestor {
+			if ancestor[a][parent] {
+				for d, below := range ancestor[child] {
+					ancestor[a][d] = ancestor[a][d] || below
+				}
+			}
+		}
+		update(child)
+	}
+
+	visited := make([]bool, n)
+	var visit func(i int)
+	visit = func(i int) {
+		if visited[i] {
+			return
+		}
+		visited[i] = true
+		for _, j := range net.parents[i] {
+			visit(j)
+		}
+		net.order = append(net.order, i)
+	}
+	net.ones = make([][]float64, n)
+	for i := 0; i < n; i++ {
+		visit(i)
+		counts := conditionalCounts(xs, i, net.parents[i])
+		marginal := 0.0
+		for _, c := range counts {
+			marginal += c[1]
+		}
+		marginal /= float64(len(xs))
+		net.ones[i] = make([]float64, len(counts))
+		for config, c := range counts {
+			// Configurations absent from xs fall back to the marginal frequency
+			net.ones[i][config] = marginal
+			if total := c[0] + c[1]; total > 0 {
+				net.ones[i][config] = c[1] / total
+			}
+		}
+	}
+	return net
+}
+
+// conditionalCounts counts, for each configuration of parents in xs, how often gene
+// i is 0 and 1.
+func conditionalCounts(xs []Chromosome, i int, parents []int) [][2]float64 {
+	counts := make([][2]float64, 1<<uint(len(parents)))
+	for _, x := range xs {
+		counts[parentConfig(x, parents)][x.Genes[i]]++
+	}
+	return counts
+}
+
+// parentConfig packs the values of parents in c into the bits of an index.
+func parentConfig(c Chromosome, parents []int) int {
+	config := 0
+	for b, j := range parents {
+		config |= c.Genes[j] << uint(b)
+	}
+	return config
+}
+
+// sample overwrites c with genes drawn from the network.
+func (net *bayesianNetwork) sample(r rand.Rand, c Chromosome) {
+	for _, i := range net.order {
+		c.Genes[i] = 0
+		if r.Float64() < net.ones[i][parentConfig(c, net.parents[i])] {
+			c.Genes[i] = 1
+		}
+	}
+}
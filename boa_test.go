@@ -0,0 +1,101 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+const (
+	// trapSize is the number of genes in each block of trap.
+	trapSize = 4
+	// trapBlocks is the number of blocks in trap.
+	trapBlocks = 6
+)
+
+// trap is a sum of deceptive traps interleaved so that gene i belongs to block
+// i%trapBlocks. A block scores trapSize when all its genes are 1 and otherwise
+// scores more for fewer 1s, leading searches that treat genes independently away
+// from the optimum of trapSize*trapBlocks.
+func trap(c genetics.Chromosome) genetics.Fitness {
+	f := genetics.Fitness(0)
+	for b := 0; b < trapBlocks; b++ {
+		ones := 0
+		for i := b; i < len(c.Genes); i += trapBlocks {
+			ones += c.Genes[i]
+		}
+		if ones == trapSize {
+			f += trapSize
+		} else {
+			f += genetics.Fitness(trapSize - 1 - ones)
+		}
+	}
+	return f
+}
+
+func TestBOA(t *testing.T) {
+	s := genetics.NewSpecies(trapSize*trapBlocks, 1)
+	rng := rand.New()
+	rng.Seed(42)
+	recorder := &statsRecorder{}
+	boa := &genetics.BOA{
+		Species:        s,
+		Evaluator:      genetics.FitnessFunc(trap),
+		PopulationSize: 800,
+		MaxParents:     3,
+		Terminate:      genetics.TargetFitness(trapSize * trapBlocks),
+		Observers:      []genetics.StatsObserver{recorder},
+	}
+	best, f, err := boa.Run(rng, 50)
+	if err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if f != trapSize*trapBlocks {
+		t.Errorf("BOA.Run() found %v scoring %g; want %d", best.Genes, f, trapSize*trapBlocks)
+	}
+	if len(recorder.stats) < 2 || recorder.stats[0].Evaluations != 800 || recorder.stats[1].Evaluations != 400 {
+		t.Errorf("expected Stats for an initial population of 800 and generations of 400 offspring; got %+v", recorder.stats)
+	}
+
+	// The first network, learned before selection fixes any genes, should often
+	// link genes of the same block, which chance would do for 1 in 8 dependencies
+	rng.Seed(42)
+	boa.Terminate, boa.Observers = nil, nil
+	if _, _, err := boa.Run(rng, 2); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	linked, edges := 0, 0
+	for i, parents := range boa.Parents() {
+		for _, j := range parents {
+			edges++
+			if i%trapBlocks == j%trapBlocks {
+				linked++
+			}
+		}
+	}
+	if edges == 0 || 3*linked < edges {
+		t.Errorf("only %d of %d dependencies are within a block; got %v", linked, edges, boa.Parents())
+	}
+
+	// Independent genes are deceived even with more evaluations
+	rng.Seed(42)
+	umda := &genetics.UMDA{Species: s, Evaluator: genetics.FitnessFunc(trap), Samples: 800}
+	if _, f, err := umda.Run(rng, 50); err != nil || f == trapSize*trapBlocks {
+		t.Errorf("UMDA.Run()=%g, %v; expected the trap to deceive it", f, err)
+	}
+}
+
+func TestBOAValidation(t *testing.T) {
+	rng := rand.New()
+	for _, boa := range []*genetics.BOA{
+		{Species: genetics.NewSpecies(10, 2), Evaluator: genetics.FitnessFunc(oneMax)},
+		{Species: genetics.NewSpecies(10, 1), Evaluator: genetics.FitnessFunc(oneMax), PopulationSize: 10, Selected: 10},
+		{Species: genetics.NewSpecies(10, 1), Evaluator: genetics.FitnessFunc(oneMax), MaxParents: 17},
+	} {
+		if _, _, err := boa.Run(rng, 10); err == nil {
+			t.Errorf("%+v.Run() should have failed", boa)
+		}
+	}
+}
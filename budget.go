@@ -0,0 +1,55 @@
+package genetics
+
+import (
+	"time"
+
+	"github.com/inlined/rand"
+)
+
+// RunFor evolves pop in place until budget has elapsed and scores the final
+// population, so that a run powering an interactive feature answers on time with
+// the fittest Chromosome it found. Generations are sized to the budget: from the
+// average time each evaluation has taken so far, the final generations replace
+// fewer than Evolver.ReplacementCount Chromosomes so that their children can be
+// evaluated before time runs out. The initial population is always evaluated in
+// full, and a slow Evaluator may still overrun the budget. Terminate may end the
+// run early. Evolver.ReplacementCount is restored before RunFor returns.
+func (e *Engine) RunFor(r rand.Rand, pop *Population, budget time.Duration) error {
+	start := time.Now()
+	deadline := start.Add(budget)
+	replacements := e.Evolver.ReplacementCount
+	defer func() {
+		e.Evolver.ReplacementCount = replacements
+	}()
+	e.steeredReset(pop)
+	evaluations := 0
+	size := func() bool {
+		if e.generation == 0 {
+			return true
+		}
+		// Leave time to evaluate both the pending children of the last generation
+		// and the children of this one.
+		evaluations += e.stats.Evaluations
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return false
+		}
+		perEvaluation := time.Since(start) / time.Duration(evaluations)
+		n := replacements
+		if perEvaluation > 0 {
+			if fit := int(remaining/perEvaluation) - len(e.pending); fit < n {
+				n = fit &^ 1
+			}
+		}
+		if n < 2 {
+			return false
+		}
+		e.Evolver.ReplacementCount = n
+		return true
+	}
+	var err error
+	for stop := false; err == nil && !stop; {
+		stop, err = e.steeredStep(r, size)
+	}
+	return e.finish(r, err)
+}
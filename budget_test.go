@@ -0,0 +1,58 @@
+package genetics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestEngineRunFor(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	pop := newBinaryPopulation(t, rng, 30, 20)
+	recorder := &statsRecorder{}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 10,
+			CrossoverRate:    1,
+			MutationRate:     0.1,
+			Selector:         genetics.TournamentSelection{Size: 3},
+			Crossover:        genetics.MultiPointCrossover{Points: 2},
+			Mutator:          genetics.RandomResettingMutation{},
+		},
+		Evaluator: genetics.FitnessFunc(func(c genetics.Chromosome) genetics.Fitness {
+			time.Sleep(time.Millisecond)
+			return oneMax(c)
+		}),
+		Observers: []genetics.Observer{struct {
+			genetics.NopObserver
+			*statsRecorder
+		}{statsRecorder: recorder}},
+	}
+	start := time.Now()
+	if err := engine.RunFor(rng, pop, 200*time.Millisecond); err != nil {
+		t.Fatalf("RunFor(); err=%s", err)
+	}
+	if elapsed := time.Since(start); elapsed > 300*time.Millisecond {
+		t.Errorf("RunFor() took %s; want about 200ms", elapsed)
+	}
+	if engine.Generation() < 2 {
+		t.Errorf("RunFor() evolved %d generations; want several", engine.Generation())
+	}
+	if engine.Evolver.ReplacementCount != 10 {
+		t.Errorf("RunFor() left ReplacementCount %d; want 10", engine.Evolver.ReplacementCount)
+	}
+	for n, s := range recorder.stats[1:] {
+		if s.Evaluations > 10 || s.Evaluations%2 != 0 {
+			t.Errorf("generation %d evaluated %d children; want an even number up to 10", n+1, s.Evaluations)
+		}
+	}
+	for i, c := range pop.Chromosomes {
+		if pop.Fitness[i] != oneMax(c) {
+			t.Errorf("RunFor() left stale score %g for chromosome %d; want %g", pop.Fitness[i], i, oneMax(c))
+		}
+	}
+}
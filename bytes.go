@@ -0,0 +1,216 @@
+package genetics
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/inlined/rand"
+)
+
+const (
+	byteUniformCrossover = "ByteUniformCrossover"
+	bytePointCrossover   = "BytePointCrossover"
+	byteMaskMutation     = "ByteMaskMutation"
+)
+
+// byteMasks[m] has byte i set to 0xFF where bit i of m is set, so that 8 bits of a
+// crossover mask select 8 byte genes at once.
+var byteMasks = func() (masks [256]uint64) {
+	for m := range masks {
+		for i := uint(0); i < 8; i++ {
+			if m&(1<<i) != 0 {
+				masks[m] |= 0xFF << (8 * i)
+			}
+		}
+	}
+	return masks
+}()
+
+// ByteSpecies is a factory for ByteChromosomes, which hold one byte per gene, e.g.
+// for alleles in [0, 255] or genes of 8 bits apiece. Bytes use an eighth of the
+// memory of Genes, and operators work on 8 genes per word. Evolve ByteChromosomes
+// with a GenomeEvolver[ByteChromosome] and ByteOperators.
+type ByteSpecies struct {
+	NumGenes int
+}
+
+// NewByteSpecies initializes a ByteSpecies
+func NewByteSpecies(numGenes int) *ByteSpecies {
+	return &ByteSpecies{NumGenes: numGenes}
+}
+
+// ByteChromosome is a Chromosome of a ByteSpecies.
+type ByteChromosome struct {
+	Species *ByteSpecies
+	Genes   []byte
+}
+
+// New creates a ByteChromosome with every gene 0.
+func (s *ByteSpecies) New() ByteChromosome {
+	return ByteChromosome{
+		Species: s,
+		Genes:   make([]byte, s.NumGenes),
+	}
+}
+
+// NewRand creates a ByteChromosome with each gene independently randomized.
+func (s *ByteSpecies) NewRand(rng rand.Rand) (ByteChromosome, error) {
+	c := s.New()
+	if n, err := rng.Read(c.Genes); n != len(c.Genes) || err != nil {
+		return ByteChromosome{}, fmt.Errorf("rand.Read(); wanted %d bytes; got %d bytes; err=%s", len(c.Genes), n, err)
+	}
+	return c, nil
+}
+
+// clone returns a copy of c which does not share Genes.
+func (c ByteChromosome) clone() ByteChromosome {
+	return ByteChromosome{Species: c.Species, Genes: append([]byte(nil), c.Genes...)}
+}
+
+// ByteCrossover is a strategy for generating two ByteChromosomes from two parents.
+type ByteCrossover interface {
+	fmt.Stringer
+	Crossover(r rand.Rand, a, b ByteChromosome) (x, y ByteChromosome)
+}
+
+// ByteMutator introduces randomness to a population of ByteChromosomes.
+type ByteMutator interface {
+	fmt.Stringer
+	Mutate(r rand.Rand, c *ByteChromosome)
+}
+
+// ByteUniformCrossover draws a random bit per gene and builds each child from one
+// parent's genes where the bit is set and the other's where it is not. Each byte
+// of the mask expands to select 8 genes, which are exchanged a word at a time.
+type ByteUniformCrossover struct{}
+
+func (ByteUniformCrossover) String() string {
+	return byteUniformCrossover
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (ByteUniformCrossover) ConcurrentSafe() bool {
+	return true
+}
+
+// Crossover implements ByteCrossover
+func (ByteUniformCrossover) Crossover(r rand.Rand, a, b ByteChromosome) (x, y ByteChromosome) {
+	s := a.Species
+	mask := make([]byte, (s.NumGenes+7)/8)
+	if n, err := r.Read(mask); n != len(mask) || err != nil {
+		panic(fmt.Sprintf("ByteUniformCrossover: rand.Read(); wanted %d bytes; got %d bytes; err=%v", len(mask), n, err))
+	}
+	x, y = s.New(), s.New()
+	words := s.NumGenes &^ 7
+	for i := 0; i < words; i += 8 {
+		m := byteMasks[mask[i/8]]
+		ga, gb := binary.LittleEndian.Uint64(a.Genes[i:]), binary.LittleEndian.Uint64(b.Genes[i:])
+		binary.LittleEndian.PutUint64(x.Genes[i:], ga&^m|gb&m)
+		binary.LittleEndian.PutUint64(y.Genes[i:], gb&^m|ga&m)
+	}
+	for i := words; i < s.NumGenes; i++ {
+		if mask[i/8]&(1<<uint(i%8)) != 0 {
+			x.Genes[i], y.Genes[i] = b.Genes[i], a.Genes[i]
+		} else {
+			x.Genes[i], y.Genes[i] = a.Genes[i], b.Genes[i]
+		}
+	}
+	return x, y
+}
+
+// BytePointCrossover is MultiPointCrossover for ByteChromosomes. Segments are
+// copied rather than exchanged gene by gene.
+type BytePointCrossover struct {
+	Points int
+}
+
+func (c BytePointCrossover) String() string {
+	return fmt.Sprintf("%s(%d)", bytePointCrossover, c.Points)
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (BytePointCrossover) ConcurrentSafe() bool {
+	return true
+}
+
+// Crossover implements ByteCrossover
+func (c BytePointCrossover) Crossover(r rand.Rand, a, b ByteChromosome) (x, y ByteChromosome) {
+	s := a.Species
+	indexes := rand.Deal(r, s.NumGenes, c.Points)
+	sort.Ints(indexes)
+	x, y = s.New(), s.New()
+	from, to := a.Genes, b.Genes
+	start := 0
+	for _, end := range append(indexes, s.NumGenes) {
+		copy(x.Genes[start:end], from[start:end])
+		copy(y.Genes[start:end], to[start:end])
+		from, to = to, from
+		start = end
+	}
+	return x, y
+}
+
+// ByteMaskMutation flips each bit of every gene independently with probability
+// Rate, rounded to a multiple of 1/256, by XORing in random masks 8 genes at a
+// time. A Rate of 0 flips exactly one random bit.
+type ByteMaskMutation struct {
+	Rate float64
+}
+
+func (m ByteMaskMutation) String() string {
+	return fmt.Sprintf("%s(%g)", byteMaskMutation, m.Rate)
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (ByteMaskMutation) ConcurrentSafe() bool {
+	return true
+}
+
+// Mutate implements ByteMutator
+func (m ByteMaskMutation) Mutate(r rand.Rand, c *ByteChromosome) {
+	if m.Rate == 0 {
+		bit := r.Int63n(8 * int64(len(c.Genes)))
+		c.Genes[bit/8] ^= 1 << uint(bit%8)
+		return
+	}
+	mask := make([]uint64, (len(c.Genes)+7)/8)
+	if err := randomMask(r, m.Rate, mask); err != nil {
+		panic(fmt.Sprintf("ByteMaskMutation: %s", err))
+	}
+	words := len(c.Genes) &^ 7
+	for i := 0; i < words; i += 8 {
+		binary.LittleEndian.PutUint64(c.Genes[i:], binary.LittleEndian.Uint64(c.Genes[i:])^mask[i/8])
+	}
+	for i := words; i < len(c.Genes); i++ {
+		c.Genes[i] ^= byte(mask[i/8] >> (8 * uint(i%8)))
+	}
+}
+
+// ByteOperators adapts a ByteCrossover and ByteMutator to GenomeOperators so that a
+// GenomeEvolver[ByteChromosome] can evolve ByteChromosomes.
+type ByteOperators struct {
+	ByteCrossover ByteCrossover
+	ByteMutator   ByteMutator
+}
+
+func (o ByteOperators) String() string {
+	return fmt.Sprintf("%s+%s", o.ByteCrossover, o.ByteMutator)
+}
+
+// Crossover implements GenomeOperators
+func (o ByteOperators) Crossover(r rand.Rand, a, b ByteChromosome) (x, y ByteChromosome) {
+	return o.ByteCrossover.Crossover(r, a, b)
+}
+
+// Mutate implements GenomeOperators
+func (o ByteOperators) Mutate(r rand.Rand, g ByteChromosome) ByteChromosome {
+	c := g.clone()
+	o.ByteMutator.Mutate(r, &c)
+	return c
+}
+
+// Clone implements GenomeOperators
+func (o ByteOperators) Clone(g ByteChromosome) ByteChromosome {
+	return g.clone()
+}
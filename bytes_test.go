@@ -0,0 +1,175 @@
+package genetics_test
+
+import (
+	"fmt"
+	"math/bits"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+	"github.com/inlined/xkcd"
+
+	"github.com/inlined/genetics"
+)
+
+func TestByteUniformCrossover(t *testing.T) {
+	// 21 genes exercise both whole words and the tail.
+	s := genetics.NewByteSpecies(21)
+	rng := rand.New()
+	rng.Seed(42)
+	a, _ := s.NewRand(rng)
+	b, _ := s.NewRand(rng)
+	for run := 0; run < 20; run++ {
+		x, y := genetics.ByteUniformCrossover{}.Crossover(rng, a, b)
+		for i := range x.Genes {
+			fromA := x.Genes[i] == a.Genes[i] && y.Genes[i] == b.Genes[i]
+			fromB := x.Genes[i] == b.Genes[i] && y.Genes[i] == a.Genes[i]
+			if !fromA && !fromB {
+				t.Fatalf("gene %d was not inherited from exactly one parent by each child; a=%d b=%d x=%d y=%d", i, a.Genes[i], b.Genes[i], x.Genes[i], y.Genes[i])
+			}
+		}
+	}
+}
+
+func TestBytePointCrossover(t *testing.T) {
+	s := genetics.NewByteSpecies(130)
+	a, b := s.New(), s.New()
+	for i := range a.Genes {
+		a.Genes[i] = 1
+	}
+	// Deal points 10 and 100; genes [10, 100) come from the other parent
+	x, y := genetics.BytePointCrossover{Points: 2}.Crossover(xkcd.Rand(10, 100), a, b)
+	for i := range x.Genes {
+		want := byte(1)
+		if i >= 10 && i < 100 {
+			want = 0
+		}
+		if x.Genes[i] != want || y.Genes[i] != 1-want {
+			t.Fatalf("gene %d crossed incorrectly; x=%d y=%d", i, x.Genes[i], y.Genes[i])
+		}
+	}
+}
+
+func TestByteMaskMutation(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	flipped := func(c genetics.ByteChromosome) int {
+		n := 0
+		for _, g := range c.Genes {
+			n += bits.OnesCount8(g)
+		}
+		return n
+	}
+	s := genetics.NewByteSpecies(10003)
+	for _, test := range []struct {
+		rate     float64
+		min, max int
+	}{
+		{0, 1, 1},
+		{1, 80024, 80024},
+		{0.5, 39000, 41000},
+		{0.05, 3600, 4400},
+	} {
+		c := s.New()
+		genetics.ByteMaskMutation{Rate: test.rate}.Mutate(rng, &c)
+		if n := flipped(c); n < test.min || n > test.max {
+			t.Errorf("ByteMaskMutation{%g} flipped %d of 80024 bits; want [%d, %d]", test.rate, n, test.min, test.max)
+		}
+	}
+}
+
+func TestByteOperatorsEvolve(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	s := genetics.NewByteSpecies(64)
+	ones := func(c genetics.ByteChromosome) genetics.Fitness {
+		n := 0
+		for _, b := range c.Genes {
+			n += bits.OnesCount8(b)
+		}
+		return genetics.Fitness(n)
+	}
+	pop := make([]genetics.ByteChromosome, 30)
+	scores := make([]genetics.Fitness, len(pop))
+	for i := range pop {
+		pop[i], _ = s.NewRand(rng)
+	}
+	ops := genetics.ByteOperators{ByteCrossover: genetics.ByteUniformCrossover{}, ByteMutator: genetics.ByteMaskMutation{}}
+	evolver := genetics.GenomeEvolver[genetics.ByteChromosome]{
+		ReplacementCount: 14,
+		CrossoverRate:    1,
+		MutationRate:     0.5,
+		Selector:         genetics.TournamentSelection{Size: 3},
+		Operators:        ops,
+	}
+	initial, best := genetics.Fitness(0), genetics.Fitness(0)
+	for gen := 0; gen < 50; gen++ {
+		for i, g := range pop {
+			if scores[i] = ones(g); scores[i] > best {
+				best = scores[i]
+			}
+		}
+		if gen == 0 {
+			initial = best
+		}
+		evolver.Evolve(rng, pop, scores)
+	}
+	if best <= initial {
+		t.Errorf("GenomeEvolver did not improve ByteChromosomes; initial=%g best=%g", initial, best)
+	}
+
+	c := pop[0]
+	before := append([]byte(nil), c.Genes...)
+	ops.Mutate(rng, c)
+	if diff := cmp.Diff(before, c.Genes); diff != "" {
+		t.Errorf("ByteOperators.Mutate() modified its argument; diff=%s", diff)
+	}
+}
+
+// uniformByteByByte is a uniform crossover which flips a coin per gene, the
+// baseline for ByteUniformCrossover.
+func uniformByteByByte(r rand.Rand, a, b genetics.ByteChromosome) (x, y genetics.ByteChromosome) {
+	x, y = a.Species.New(), a.Species.New()
+	for i := range a.Genes {
+		if r.Float64() < 0.5 {
+			x.Genes[i], y.Genes[i] = b.Genes[i], a.Genes[i]
+		} else {
+			x.Genes[i], y.Genes[i] = a.Genes[i], b.Genes[i]
+		}
+	}
+	return x, y
+}
+
+// The word-level operators are compared against gene-by-gene loops over bytes and
+// against Chromosomes of Genes.
+func BenchmarkWordLevelBytes(b *testing.B) {
+	for _, numGenes := range []int{10000, 100000} {
+		rng := rand.New()
+		s := genetics.NewByteSpecies(numGenes)
+		pa, _ := s.NewRand(rng)
+		pb, _ := s.NewRand(rng)
+		b.Run(fmt.Sprintf("Uniform/GeneByGene/%d", numGenes), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				uniformByteByByte(rng, pa, pb)
+			}
+		})
+		b.Run(fmt.Sprintf("Uniform/Mask/%d", numGenes), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				genetics.ByteUniformCrossover{}.Crossover(rng, pa, pb)
+			}
+		})
+		gs := genetics.NewSpecies(numGenes, 255)
+		ga, _ := gs.NewRand(rng)
+		gb, _ := gs.NewRand(rng)
+		b.Run(fmt.Sprintf("Point/Gene/%d", numGenes), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				genetics.MultiPointCrossover{Points: 2}.Crossover(rng, ga, gb)
+			}
+		})
+		b.Run(fmt.Sprintf("Point/Byte/%d", numGenes), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				genetics.BytePointCrossover{Points: 2}.Crossover(rng, pa, pb)
+			}
+		})
+	}
+}
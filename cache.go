@@ -0,0 +1,84 @@
+package genetics
+
+import "container/list"
+
+// FitnessCache memoizes scores by chromosome contents so that duplicate
+// individuals, which are common once a population converges, are not evaluated
+// again. When full, the least recently used score is evicted. Like Engine, a
+// FitnessCache is not goroutine safe.
+type FitnessCache struct {
+	maxSize int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type cacheEntry struct {
+	key     string
+	fitness Fitness
+}
+
+// NewFitnessCache creates a FitnessCache holding up to maxSize scores.
+func NewFitnessCache(maxSize int) *FitnessCache {
+	return &FitnessCache{
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get returns the cached score of c, if any.
+func (fc *FitnessCache) Get(c Chromosome) (Fitness, bool) {
+	elem, ok := fc.entries[genesKey(c.Genes)]
+	if !ok {
+		return 0, false
+	}
+	fc.order.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).fitness, true
+}
+
+// Add caches the score of c.
+func (fc *FitnessCache) Add(c Chromosome, f Fitness) {
+	key := genesKey(c.Genes)
+	if elem, ok := fc.entries[key]; ok {
+		elem.Value.(*cacheEntry).fitness = f
+		fc.order.MoveToFront(elem)
+		return
+	}
+	fc.entries[key] = fc.order.PushFront(&cacheEntry{key: key, fitness: f})
+	for fc.order.Len() > fc.maxSize {
+		oldest := fc.order.Back()
+		fc.order.Remove(oldest)
+		delete(fc.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// Clear removes every cached score, e.g. after the fitness function changes.
+func (fc *FitnessCache) Clear() {
+	fc.entries = make(map[string]*list.Element)
+	fc.order.Init()
+}
+
+// Len returns the number of cached scores.
+func (fc *FitnessCache) Len() int {
+	return fc.order.Len()
+}
+
+// cachedEvaluator consults an Engine's Cache before its Evaluator and counts
+// cache hits for the Engine's Stats. Failed evaluations are not cached.
+type cachedEvaluator struct {
+	e *Engine
+}
+
+// Evaluate implements Evaluator
+func (c cachedEvaluator) Evaluate(ch Chromosome) (Fitness, error) {
+	c.e.cacheLookups++
+	if f, ok := c.e.Cache.Get(ch); ok {
+		c.e.cacheHits++
+		return f, nil
+	}
+	f, err := c.e.Evaluator.Evaluate(ch)
+	if err == nil {
+		c.e.Cache.Add(ch, f)
+	}
+	return f, err
+}
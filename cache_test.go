@@ -0,0 +1,106 @@
+package genetics_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestFitnessCacheEviction(t *testing.T) {
+	s := genetics.NewSpecies(2, 9)
+	cache := genetics.NewFitnessCache(2)
+	cache.Add(s.New(1, 1), 1)
+	cache.Add(s.New(2, 2), 2)
+	// Touching {1, 1} makes {2, 2} the least recently used
+	if f, ok := cache.Get(s.New(1, 1)); !ok || f != 1 {
+		t.Errorf("Get({1, 1}) = %g, %t; want 1, true", f, ok)
+	}
+	cache.Add(s.New(3, 3), 3)
+	if cache.Len() != 2 {
+		t.Errorf("Len() = %d; want 2", cache.Len())
+	}
+	if _, ok := cache.Get(s.New(2, 2)); ok {
+		t.Error("least recently used score should have been evicted")
+	}
+	for _, c := range []genetics.Chromosome{s.New(1, 1), s.New(3, 3)} {
+		if _, ok := cache.Get(c); !ok {
+			t.Errorf("Get(%v) missed", c.Genes)
+		}
+	}
+}
+
+func TestEngineCache(t *testing.T) {
+	rng := rand.New()
+	s := genetics.NewSpecies(8, 1)
+	pop := &genetics.Population{Species: s}
+	for i := 0; i < 10; i++ {
+		// Only two distinct chromosomes
+		pop.Chromosomes = append(pop.Chromosomes, s.New(i%2, 1, 0, 1))
+	}
+	eval := &countingEvaluator{fn: oneMax}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 4,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator:          genetics.SwapMutation{},
+		},
+		Evaluator: eval,
+		Cache:     genetics.NewFitnessCache(100),
+	}
+	engine.Reset(pop)
+	if err := engine.Step(rng); err != nil {
+		t.Fatalf("Step(); err=%s", err)
+	}
+	stats := engine.Stats()
+	if eval.count != 2 || stats.CacheLookups != 10 || stats.CacheHits != 8 {
+		t.Errorf("evaluated %d times with stats %+v; want 2 evaluations and 8 of 10 cache hits", eval.count, stats)
+	}
+	if rate := stats.CacheHitRate(); rate != 0.8 {
+		t.Errorf("CacheHitRate() = %f; want 0.8", rate)
+	}
+	if stats.Best != 3 || stats.Worst != 2 {
+		t.Errorf("Stats() = %+v; cached scores should range from 2 to 3", stats)
+	}
+}
+
+func TestEngineCacheBatch(t *testing.T) {
+	rng := rand.New()
+	s := genetics.NewSpecies(8, 1)
+	pop := &genetics.Population{Species: s}
+	for i := 0; i < 10; i++ {
+		pop.Chromosomes = append(pop.Chromosomes, s.New(i%2, 1, 0, 1))
+	}
+	var scored []int
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 4,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator:          genetics.SwapMutation{},
+		},
+		Evaluator: genetics.BatchFunc(func(ctx context.Context, cs []genetics.Chromosome) []genetics.Fitness {
+			scored = append(scored, len(cs))
+			f := make([]genetics.Fitness, len(cs))
+			for i, c := range cs {
+				f[i] = oneMax(c)
+			}
+			return f
+		}),
+		Cache: genetics.NewFitnessCache(100),
+	}
+	engine.Reset(pop)
+	if err := engine.Step(rng); err != nil {
+		t.Fatalf("Step(); err=%s", err)
+	}
+	stats := engine.Stats()
+	if len(scored) != 1 || scored[0] != 2 || stats.CacheLookups != 10 || stats.CacheHits != 8 {
+		t.Errorf("scored batches of %v with stats %+v; want one batch of 2 and 8 of 10 cache hits", scored, stats)
+	}
+	if stats.Best != 3 || stats.Worst != 2 {
+		t.Errorf("Stats() = %+v; scores should range from 2 to 3", stats)
+	}
+}
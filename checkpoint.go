@@ -0,0 +1,184 @@
+package genetics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// manifestFile is the name of the manifest which a Checkpointer keeps in its Dir
+const manifestFile = "manifest.json"
+
+// Checkpoint describes a single saved Island Population.
+type Checkpoint struct {
+	Generation int `json:"generation"`
+	// File is relative to the checkpoint directory
+	File string `json:"file"`
+	// Best is the best Fitness of the evaluated members.
+	Best Fitness `json:"best"`
+	// Pending lists the members which were not yet evaluated: children bred in
+	// the checkpointed generation, whose saved Fitness is that of the member they
+	// replaced.
+	Pending []int `json:"pending,omitempty"`
+}
+
+// IslandCheckpoints lists the retained Checkpoints of a single Island.
+type IslandCheckpoints struct {
+	// Checkpoints are ordered from oldest to newest
+	Checkpoints []Checkpoint `json:"checkpoints"`
+	// Best is the checkpoint with the highest Best fitness ever saved
+	Best *Checkpoint `json:"best,omitempty"`
+}
+
+// Manifest indexes every retained Checkpoint of an Archipelago by Island name.
+type Manifest struct {
+	Islands map[string]*IslandCheckpoints `json:"islands"`
+}
+
+// Checkpointer periodically saves the Populations of an Archipelago's Islands to
+// Dir/<island>/gen-<generation>.json and indexes them in Dir/manifest.json so that
+// long runs can be resumed or inspected per island.
+type Checkpointer struct {
+	Dir string
+	// Interval is the number of generations between checkpoints of an Island.
+	// Islands are staggered across the interval so that they do not all write
+	// checkpoints in the same generation.
+	Interval int
+	// KeepLast is the number of recent checkpoints retained per Island; 0 retains all.
+	KeepLast int
+	// KeepBest retains each Island's best-ever checkpoint even after it is no
+	// longer among the last KeepLast.
+	KeepBest bool
+
+	manifest *Manifest
+}
+
+// Save checkpoints island, which is the nth of total islands, if it is due in
+// the island's current generation.
+func (c *Checkpointer) Save(n, total int, island *Island) error {
+	gen := island.Engine.Generation()
+	if c.Interval <= 0 || (gen+n*c.Interval/total)%c.Interval != 0 {
+		return nil
+	}
+	if c.manifest == nil {
+		m, err := LoadManifest(c.Dir)
+		if os.IsNotExist(err) {
+			m, err = &Manifest{Islands: map[string]*IslandCheckpoints{}}, nil
+		}
+		if err != nil {
+			return err
+		}
+		c.manifest = m
+	}
+
+	pop := island.Population
+	cp := Checkpoint{
+		Generation: gen,
+		File:       filepath.Join(island.Name, fmt.Sprintf("gen-%06d.json", gen)),
+		Pending:    append([]int(nil), island.Engine.pending...),
+	}
+	sort.Ints(cp.Pending)
+	pending := make(map[int]bool, len(cp.Pending))
+	for _, i := range cp.Pending {
+		pending[i] = true
+	}
+	evaluated := false
+	for i, f := range pop.Fitness {
+		if !pending[i] && (!evaluated || pop.Objective.Better(f, cp.Best)) {
+			cp.Best, evaluated = f, true
+		}
+	}
+	if err := writeJSON(filepath.Join(c.Dir, cp.File), pop); err != nil {
+		return err
+	}
+
+	ic := c.manifest.Islands[island.Name]
+	if ic == nil {
+		ic = &IslandCheckpoints{}
+		c.manifest.Islands[island.Name] = ic
+	}
+	ic.Checkpoints = append(ic.Checkpoints, cp)
+	var expired []Checkpoint
+	if c.KeepBest && evaluated && (ic.Best == nil || pop.Objective.Better(cp.Best, ic.Best.Best)) {
+		if ic.Best != nil {
+			expired = append(expired, *ic.Best)
+		}
+		best := cp
+		ic.Best = &best
+	}
+	if c.KeepLast > 0 && len(ic.Checkpoints) > c.KeepLast {
+		expired = append(expired, ic.Checkpoints[:len(ic.Checkpoints)-c.KeepLast]...)
+		ic.Checkpoints = ic.Checkpoints[len(ic.Checkpoints)-c.KeepLast:]
+	}
+	for _, old := range expired {
+		if ic.retains(old) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.Dir, old.File)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return writeJSON(filepath.Join(c.Dir, manifestFile), c.manifest)
+}
+
+// retains reports whether cp is still listed by ic.
+func (ic *IslandCheckpoints) retains(cp Checkpoint) bool {
+	if ic.Best != nil && ic.Best.File == cp.File {
+		return true
+	}
+	for _, kept := range ic.Checkpoints {
+		if kept.File == cp.File {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadManifest reads the Manifest written by a Checkpointer into dir.
+func LoadManifest(dir string) (*Manifest, error) {
+	b, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("LoadManifest(%s); err=%s", dir, err)
+	}
+	if m.Islands == nil {
+		m.Islands = map[string]*IslandCheckpoints{}
+	}
+	return m, nil
+}
+
+// LoadCheckpoint reads the Population saved in cp, which was listed in the Manifest
+// of dir. The Fitness of the members in cp.Pending is stale; Engine.Reset
+// evaluates every member again.
+func LoadCheckpoint(dir string, cp Checkpoint) (*Population, error) {
+	b, err := os.ReadFile(filepath.Join(dir, cp.File))
+	if err != nil {
+		return nil, err
+	}
+	pop := &Population{}
+	if err := json.Unmarshal(b, pop); err != nil {
+		return nil, fmt.Errorf("LoadCheckpoint(%s); err=%s", cp.File, err)
+	}
+	return pop, nil
+}
+
+// writeJSON atomically replaces path with the JSON encoding of v.
+func writeJSON(path string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,290 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/inlined/rand"
+)
+
+// CMAES is the covariance matrix adaptation evolution strategy, an alternative to
+// RealEvolver for continuous problems. Each generation samples Lambda candidates
+// from a multivariate normal distribution, then moves its mean toward the fittest
+// half and adapts its covariance and step size to the shape of the landscape.
+// CMAES shares the RealSpecies, RealEvaluator, Termination, Stats, and
+// StatsObserver APIs with the genetic algorithm drivers so the two can be compared
+// on the same problem. Candidates are clamped to the species' bounds before they
+// are evaluated and recombined. CMAES always maximizes Fitness; to minimize, have
+// the Evaluator return the negated cost. Like Engine, a CMAES is not goroutine
+// safe.
+type CMAES struct {
+	Species   *RealSpecies
+	Evaluator RealEvaluator
+
+	// Lambda is the number of candidates sampled per generation. Defaults to
+	// 4 + floor(3 ln n) for n genes.
+	Lambda int
+	// Sigma is the initial step size. Defaults to a third of the mean gene range.
+	Sigma float64
+	// Mean is the initial mean. Defaults to the center of the species' bounds.
+	Mean []float64
+	// TolSigma ends a run once the step size falls below it. Defaults to 1e-12.
+	TolSigma float64
+
+	// Terminate, if set, ends a Run early once a generation satisfies it.
+	Terminate Termination
+	// Observers are notified of the Stats of each generation.
+	Observers []StatsObserver
+
+	generation  int
+	mean        []float64
+	sigma       float64
+	c           [][]float64
+	ps, pc      []float64
+	best        RealChromosome
+	bestFitness Fitness
+	stats       Stats
+}
+
+// Best returns the fittest candidate evaluated since the run began.
+func (c *CMAES) Best() (RealChromosome, Fitness) {
+	return c.best, c.bestFitness
+}
+
+// Stats summarizes the most recent generation.
+func (c *CMAES) Stats() Stats {
+	return c.stats
+}
+
+// StepSize returns the current step size sigma.
+func (c *CMAES) StepSize() float64 {
+	return c.sigma
+}
+
+// Run evolves the distribution for up to the given number of generations and
+// returns the fittest candidate evaluated.
+func (c *CMAES) Run(r rand.Rand, generations int) (RealChromosome, Fitness, error) {
+	n := c.Species.NumGenes()
+	lambda := c.Lambda
+	if lambda == 0 {
+		lambda = 4 + int(3*math.Log(float64(n)))
+	}
+	if lambda < 2 {
+		return RealChromosome{}, 0, fmt.Errorf("CMAES.Run(); Lambda %d must be at least 2", lambda)
+	}
+	c.reset()
+
+	// Strategy parameters; see Hansen, "The CMA Evolution Strategy: A Tutorial"
+	mu := lambda / 2
+	weights := make([]float64, mu)
+	sum, sumSq := 0.0, 0.0
+	for i := range weights {
+		weights[i] = math.Log(float64(lambda+1)/2) - math.Log(float64(i+1))
+		sum += weights[i]
+	}
+	for i := range weights {
+		weights[i] /= sum
+		sumSq += weights[i] * weights[i]
+	}
+	mueff := 1 / sumSq
+	nf := float64(n)
+	cc := (4 + mueff/nf) / (nf + 4 + 2*mueff/nf)
+	cs := (mueff + 2) / (nf + mueff + 5)
+	c1 := 2 / ((nf+1.3)*(nf+1.3) + mueff)
+	cmu := math.Min(1-c1, 2*(mueff-2+1/mueff)/((nf+2)*(nf+2)+mueff))
+	damps := 1 + 2*math.Max(0, math.Sqrt((mueff-1)/(nf+1))-1) + cs
+	chiN := math.Sqrt(nf) * (1 - 1/(4*nf) + 1/(21*nf*nf))
+	tolSigma := c.TolSigma
+	if tolSigma == 0 {
+		tolSigma = 1e-12
+	}
+
+	xs := make([][]float64, lambda)
+	ys := make([][]float64, lambda)
+	scores := make([]Fitness, lambda)
+	for ; c.generation < generations && c.sigma > tolSigma; c.generation++ {
+		values, b := symmetricEigen(c.c)
+		d := make([]float64, n)
+		for i, v := range values {
+			d[i] = math.Sqrt(math.Max(v, 1e-20))
+		}
+
+		for k := range xs {
+			z := make([]float64, n)
+			for i := range z {
+				z[i] = d[i] * normFloat64(r)
+			}
+			ys[k] = make([]float64, n)
+			xs[k] = make([]float64, n)
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					ys[k][i] += b[i][j] * z[j]
+				}
+				xs[k][i] = c.mean[i] + c.sigma*ys[k][i]
+			}
+			// Recombine the repaired candidate so that the mean stays within bounds
+			cand := c.Species.New(xs[k]...)
+			for i, g := range cand.Genes {
+				ys[k][i] = (g - c.mean[i]) / c.sigma
+			}
+			f, err := c.Evaluator.Evaluate(cand)
+			if err != nil {
+				return c.best, c.bestFitness, fmt.Errorf("CMAES.Run(); generation %d: %s", c.generation, err)
+			}
+			scores[k] = f
+			if (c.generation == 0 && k == 0) || f > c.bestFitness {
+				c.best, c.bestFitness = cand, f
+			}
+		}
+		c.stats = Stats{Generation: c.generation, Evaluations: lambda}
+		c.stats.summarize(scores)
+		for _, o := range c.Observers {
+			o.OnStats(c.stats)
+		}
+
+		// Recombine the fittest mu steps into the new mean
+		order := rankIndexes(scores)
+		step := make([]float64, n)
+		for w, k := range order[:mu] {
+			for i := range step {
+				step[i] += weights[w] * ys[k][i]
+			}
+		}
+		for i := range c.mean {
+			c.mean[i] += c.sigma * step[i]
+		}
+
+		// Update evolution paths. C^-1/2 = B D^-1 B^T
+		bt := make([]float64, n)
+		for j := 0; j < n; j++ {
+			for i := 0; i < n; i++ {
+				bt[j] += b[i][j] * step[i]
+			}
+			bt[j] /= d[j]
+		}
+		psNorm := 0.0
+		for i := range c.ps {
+			invSqrt := 0.0
+			for j := 0; j < n; j++ {
+				invSqrt += b[i][j] * bt[j]
+			}
+			c.ps[i] = (1-cs)*c.ps[i] + math.Sqrt(cs*(2-cs)*mueff)*invSqrt
+			psNorm += c.ps[i] * c.ps[i]
+		}
+		psNorm = math.Sqrt(psNorm)
+		hsig := 0.0
+		if psNorm/math.Sqrt(1-math.Pow(1-cs, 2*float64(c.generation+1)))/chiN < 1.4+2/(nf+1) {
+			hsig = 1
+		}
+		for i := range c.pc {
+			c.pc[i] = (1-cc)*c.pc[i] + hsig*math.Sqrt(cc*(2-cc)*mueff)*step[i]
+		}
+
+		// Adapt the covariance matrix with the rank-one and rank-mu updates
+		for i := 0; i < n; i++ {
+			for j := 0; j <= i; j++ {
+				rankMu := 0.0
+				for w, k := range order[:mu] {
+					rankMu += weights[w] * ys[k][i] * ys[k][j]
+				}
+				v := (1-c1-cmu)*c.c[i][j] +
+					c1*(c.pc[i]*c.pc[j]+(1-hsig)*cc*(2-cc)*c.c[i][j]) +
+					cmu*rankMu
+				c.c[i][j], c.c[j][i] = v, v
+			}
+		}
+
+		c.sigma *= math.Exp((cs / damps) * (psNorm/chiN - 1))
+
+		if c.Terminate != nil && c.Terminate(c.stats) {
+			c.generation++
+			break
+		}
+	}
+	return c.best, c.bestFitness, nil
+}
+
+// reset initializes the distribution from the configured mean and step size.
+func (c *CMAES) reset() {
+	n := c.Species.NumGenes()
+	c.generation = 0
+	c.mean = make([]float64, n)
+	if c.Mean != nil {
+		copy(c.mean, c.Mean)
+	} else {
+		for i := range c.mean {
+			c.mean[i] = (c.Species.Min[i] + c.Species.Max[i]) / 2
+		}
+	}
+	c.sigma = c.Sigma
+	if c.sigma == 0 {
+		for i := 0; i < n; i++ {
+			c.sigma += (c.Species.Max[i] - c.Species.Min[i]) / float64(3*n)
+		}
+	}
+	c.c = make([][]float64, n)
+	for i := range c.c {
+		c.c[i] = make([]float64, n)
+		c.c[i][i] = 1
+	}
+	c.ps = make([]float64, n)
+	c.pc = make([]float64, n)
+	c.best, c.bestFitness = RealChromosome{}, 0
+	c.stats = Stats{}
+}
+
+// symmetricEigen decomposes the symmetric matrix a into eigenvalues and a matrix
+// whose columns are the corresponding eigenvectors using the cyclic Jacobi method.
+// a is not modified.
+func symmetricEigen(a [][]float64) (values []float64, vectors [][]float64) {
+	n := len(a)
+	m := make([][]float64, n)
+	vectors = make([][]float64, n)
+	for i := range m {
+		m[i] = append([]float64(nil), a[i]...)
+		vectors[i] = make([]float64, n)
+		vectors[i][i] = 1
+	}
+	for sweep := 0; sweep < 100; sweep++ {
+		off := 0.0
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				off += m[i][j] * m[i][j]
+			}
+		}
+		if off < 1e-30 {
+			break
+		}
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if m[p][q] == 0 {
+					continue
+				}
+				theta := (m[q][q] - m[p][p]) / (2 * m[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				cos := 1 / math.Sqrt(t*t+1)
+				sin := t * cos
+				for k := 0; k < n; k++ {
+					mkp, mkq := m[k][p], m[k][q]
+					m[k][p], m[k][q] = cos*mkp-sin*mkq, sin*mkp+cos*mkq
+				}
+				for k := 0; k < n; k++ {
+					mpk, mqk := m[p][k], m[q][k]
+					m[p][k], m[q][k] = cos*mpk-sin*mqk, sin*mpk+cos*mqk
+				}
+				for k := 0; k < n; k++ {
+					vkp, vkq := vectors[k][p], vectors[k][q]
+					vectors[k][p], vectors[k][q] = cos*vkp-sin*vkq, sin*vkp+cos*vkq
+				}
+			}
+		}
+	}
+	values = make([]float64, n)
+	for i := range values {
+		values[i] = m[i][i]
+	}
+	return values, vectors
+}
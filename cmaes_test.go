@@ -0,0 +1,73 @@
+package genetics_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+// statsRecorder records the Stats of every generation.
+type statsRecorder struct {
+	stats []genetics.Stats
+}
+
+func (s *statsRecorder) OnStats(stats genetics.Stats) {
+	s.stats = append(s.stats, stats)
+}
+
+// rotatedEllipsoid is an ill-conditioned quadratic whose axes are not aligned
+// with the genes, which CMA-ES solves by learning the covariance. Its optimum
+// is at (1, 1, ..., 1); fitness is the negated value in millionths.
+func rotatedEllipsoid(c genetics.RealChromosome) genetics.Fitness {
+	total := 0.0
+	for i := range c.Genes {
+		sum := 0.0
+		for j := 0; j <= i; j++ {
+			sum += c.Genes[j] - 1
+		}
+		total += math.Pow(100, float64(i)/float64(len(c.Genes)-1)) * sum * sum
+	}
+	return -genetics.Fitness(math.Min(total, 1e12) * 1e6)
+}
+
+func TestCMAES(t *testing.T) {
+	rng := rand.New()
+	recorder := &statsRecorder{}
+	cma := genetics.CMAES{
+		Species:   genetics.NewUniformRealSpecies(5, -5, 5),
+		Evaluator: genetics.RealFitnessFunc(rotatedEllipsoid),
+		Observers: []genetics.StatsObserver{recorder},
+	}
+	best, f, err := cma.Run(rng, 400)
+	if err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if f < -1000 {
+		t.Errorf("CMAES did not converge; best %v scored %g", best.Genes, f)
+	}
+	if len(recorder.stats) == 0 || recorder.stats[0].Evaluations != 8 {
+		t.Errorf("expected Stats for generations of 4 + floor(3 ln 5) = 8 candidates; got %+v", recorder.stats)
+	}
+}
+
+func TestCMAESTerminate(t *testing.T) {
+	rng := rand.New()
+	cma := genetics.CMAES{
+		Species:   genetics.NewUniformRealSpecies(3, -5, 5),
+		Evaluator: genetics.RealFitnessFunc(rotatedEllipsoid),
+		Terminate: genetics.TargetFitness(-1e6),
+	}
+	_, f, err := cma.Run(rng, 1000)
+	if err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if f < -1e6 || cma.Stats().Best < -1e6 {
+		t.Errorf("Run() stopped before reaching the target; best=%g", f)
+	}
+	if cma.Stats().Generation > 200 {
+		t.Errorf("Run() continued to generation %d after reaching the target", cma.Stats().Generation)
+	}
+}
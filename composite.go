@@ -0,0 +1,534 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/inlined/rand"
+)
+
+const (
+	mix         = "Mix"
+	adaptiveMix = "AdaptiveMix"
+	adaptiveUCB = "AdaptiveUCB"
+
+	adaptationRateParam = "rate"
+	minProbabilityParam = "minProbability"
+	explorationParam    = "exploration"
+)
+
+// AdaptiveOperator is implemented by operators which learn from the children they
+// produce. After each generation is scored, an Engine calls Reward with the
+// provenance of every child, e.g. "MultiPointCrossover(1)+SwapMutation", and the
+// amount by which it improved on the fitter of its parents, or 0 if it did not.
+// Operators used outside of an Engine are never rewarded.
+type AdaptiveOperator interface {
+	Reward(provenances []string, gains []Fitness)
+}
+
+// mixedMutator is implemented by Mutators which apply one of several operators.
+// mutateWith returns the name of the operator which was applied so that a child's
+// provenance credits it rather than the mix.
+type mixedMutator interface {
+	Mutator
+	mutateWith(r rand.Rand, c *Chromosome) string
+}
+
+// mutate applies m to c and returns the name to record in c's provenance.
+func mutate(m Mutator, r rand.Rand, c *Chromosome) string {
+	if mm, ok := m.(mixedMutator); ok {
+		return mm.mutateWith(r, c)
+	}
+	m.Mutate(r, c)
+	return m.String()
+}
+
+// CompositeMutator applies one of its Mutators to each chromosome, chosen with
+// probability proportional to its weight, e.g. 70% SwapMutation and 30%
+// InversionMutation. A nil Weights chooses uniformly.
+type CompositeMutator struct {
+	Mutators []Mutator
+	Weights  []float64
+}
+
+func (m CompositeMutator) String() string {
+	return mixString(mix, mutatorNames(m.Mutators), m.Weights)
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (m CompositeMutator) ConcurrentSafe() bool {
+	for _, op := range m.Mutators {
+		if !IsConcurrentSafe(op) {
+			return false
+		}
+	}
+	return true
+}
+
+// WithProblem implements ContextualOperator
+func (m CompositeMutator) WithProblem(problem interface{}) (interface{}, error) {
+	mutators := make([]Mutator, len(m.Mutators))
+	for i, op := range m.Mutators {
+		var err error
+		if mutators[i], err = mutatorWithProblem(op, problem); err != nil {
+			return nil, err
+		}
+	}
+	m.Mutators = mutators
+	return m, nil
+}
+
+// Mutate implements Mutator
+func (m CompositeMutator) Mutate(r rand.Rand, c *Chromosome) {
+	m.mutateWith(r, c)
+}
+
+func (m CompositeMutator) mutateWith(r rand.Rand, c *Chromosome) string {
+	return mutate(m.Mutators[pickWeighted(r, m.Weights, len(m.Mutators))], r, c)
+}
+
+// AdaptiveMutator applies one of its Mutators to each chromosome and shifts the
+// odds toward the Mutators whose children have recently improved on their parents
+// the most. Each Mutator's quality is an exponentially weighted average of the mean
+// gain of its children per generation, and Selection decides how qualities become
+// choices. Mutators are credited by name, so names must be distinct.
+//
+// An AdaptiveMutator learns only when rewarded by an Engine, and its state is
+// shared by every Evolver which holds it. It is goroutine safe.
+type AdaptiveMutator struct {
+	Mutators []Mutator
+	// Selection defaults to ProbabilityMatching.
+	Selection OperatorSelection
+	// AdaptationRate is the weight of each generation's gains. Defaults to 0.3.
+	AdaptationRate float64
+	// MinProbability is the least probability with which ProbabilityMatching
+	// chooses each Mutator. Defaults to 0.2 divided by the number of Mutators.
+	MinProbability float64
+	// Exploration scales the bonus UpperConfidenceBound gives to rarely chosen
+	// Mutators. Defaults to 1.
+	Exploration float64
+
+	state operatorQuality
+}
+
+// NewAdaptiveMutator creates an AdaptiveMutator with the default parameters.
+func NewAdaptiveMutator(mutators ...Mutator) *AdaptiveMutator {
+	return &AdaptiveMutator{Mutators: mutators}
+}
+
+func (m *AdaptiveMutator) String() string {
+	return mixString(m.Selection.String(), append(mutatorNames(m.Mutators), m.policy().params()...), nil)
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (m *AdaptiveMutator) ConcurrentSafe() bool {
+	for _, op := range m.Mutators {
+		if !IsConcurrentSafe(op) {
+			return false
+		}
+	}
+	return true
+}
+
+// Mutate implements Mutator
+func (m *AdaptiveMutator) Mutate(r rand.Rand, c *Chromosome) {
+	m.mutateWith(r, c)
+}
+
+func (m *AdaptiveMutator) mutateWith(r rand.Rand, c *Chromosome) string {
+	return mutate(m.Mutators[m.state.pick(r, m.policy(), len(m.Mutators))], r, c)
+}
+
+// Probabilities returns the current probability of choosing each Mutator. Since
+// UpperConfidenceBound is deterministic, it gives the next choice a probability of 1.
+func (m *AdaptiveMutator) Probabilities() []float64 {
+	return m.state.probabilities(m.policy(), len(m.Mutators))
+}
+
+// Reward implements AdaptiveOperator
+func (m *AdaptiveMutator) Reward(provenances []string, gains []Fitness) {
+	m.state.reward(m.policy(), mutatorNames(m.Mutators), provenances, gains)
+}
+
+func (m *AdaptiveMutator) policy() selectionPolicy {
+	return selectionPolicy{m.Selection, m.AdaptationRate, m.MinProbability, m.Exploration}
+}
+
+// mutatorNames returns the String of each Mutator.
+func mutatorNames(mutators []Mutator) []string {
+	names := make([]string, len(mutators))
+	for i, m := range mutators {
+		names[i] = m.String()
+	}
+	return names
+}
+
+// mixedCrossover is implemented by Crossovers which apply one of several
+// operators; crossoverWith also returns the name of the operator which was applied.
+type mixedCrossover interface {
+	Crossover
+	crossoverWith(r rand.Rand, a, b Chromosome) (x, y Chromosome, name string)
+}
+
+// crossover applies c to a and b and returns the name to record in the
+// children's provenance.
+func crossover(c Crossover, r rand.Rand, a, b Chromosome) (x, y Chromosome, name string) {
+	if mc, ok := c.(mixedCrossover); ok {
+		return mc.crossoverWith(r, a, b)
+	}
+	x, y = c.Crossover(r, a, b)
+	return x, y, c.String()
+}
+
+// crossoverInto returns the CrossoverInto with which c writes children in place,
+// or nil if c must allocate them.
+func crossoverInto(c Crossover) CrossoverInto {
+	if t, ok := c.(tracedCrossover); ok {
+		if into := crossoverInto(t.crossover); into != nil {
+			return tracedCrossoverInto{t, into}
+		}
+		return nil
+	}
+	into, _ := c.(CrossoverInto)
+	return into
+}
+
+// CompositeCrossover mates each pair of parents with one of its Crossovers,
+// chosen with probability proportional to its weight. A nil Weights chooses
+// uniformly.
+type CompositeCrossover struct {
+	Crossovers []Crossover
+	Weights    []float64
+}
+
+func (c CompositeCrossover) String() string {
+	return mixString(mix, crossoverNames(c.Crossovers), c.Weights)
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (c CompositeCrossover) ConcurrentSafe() bool {
+	for _, op := range c.Crossovers {
+		if !IsConcurrentSafe(op) {
+			return false
+		}
+	}
+	return true
+}
+
+// WithProblem implements ContextualOperator
+func (c CompositeCrossover) WithProblem(problem interface{}) (interface{}, error) {
+	crossovers := make([]Crossover, len(c.Crossovers))
+	for i, op := range c.Crossovers {
+		var err error
+		if crossovers[i], err = crossoverWithProblem(op, problem); err != nil {
+			return nil, err
+		}
+	}
+	c.Crossovers = crossovers
+	return c, nil
+}
+
+// Crossover implements Crossover
+func (c CompositeCrossover) Crossover(r rand.Rand, a, b Chromosome) (x, y Chromosome) {
+	x, y, _ = c.crossoverWith(r, a, b)
+	return x, y
+}
+
+func (c CompositeCrossover) crossoverWith(r rand.Rand, a, b Chromosome) (x, y Chromosome, name string) {
+	return crossover(c.Crossovers[pickWeighted(r, c.Weights, len(c.Crossovers))], r, a, b)
+}
+
+// AdaptiveCrossover mates each pair of parents with one of its Crossovers and,
+// like AdaptiveMutator, learns which Crossovers recently produced the fittest
+// children. Adaptive operator selection commonly outperforms any single fixed
+// Crossover. Crossovers are credited by name, so names must be distinct.
+//
+// An AdaptiveCrossover learns only when rewarded by an Engine, and its state is
+// shared by every Evolver which holds it. It is goroutine safe.
+type AdaptiveCrossover struct {
+	Crossovers []Crossover
+	// Selection defaults to ProbabilityMatching.
+	Selection OperatorSelection
+	// AdaptationRate is the weight of each generation's gains. Defaults to 0.3.
+	AdaptationRate float64
+	// MinProbability is the least probability with which ProbabilityMatching
+	// chooses each Crossover. Defaults to 0.2 divided by the number of Crossovers.
+	MinProbability float64
+	// Exploration scales the bonus UpperConfidenceBound gives to rarely chosen
+	// Crossovers. Defaults to 1.
+	Exploration float64
+
+	state operatorQuality
+}
+
+// NewAdaptiveCrossover creates an AdaptiveCrossover with the default parameters.
+func NewAdaptiveCrossover(crossovers ...Crossover) *AdaptiveCrossover {
+	return &AdaptiveCrossover{Crossovers: crossovers}
+}
+
+func (c *AdaptiveCrossover) String() string {
+	return mixString(c.Selection.String(), append(crossoverNames(c.Crossovers), c.policy().params()...), nil)
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (c *AdaptiveCrossover) ConcurrentSafe() bool {
+	for _, op := range c.Crossovers {
+		if !IsConcurrentSafe(op) {
+			return false
+		}
+	}
+	return true
+}
+
+// Crossover implements Crossover
+func (c *AdaptiveCrossover) Crossover(r rand.Rand, a, b Chromosome) (x, y Chromosome) {
+	x, y, _ = c.crossoverWith(r, a, b)
+	return x, y
+}
+
+func (c *AdaptiveCrossover) crossoverWith(r rand.Rand, a, b Chromosome) (x, y Chromosome, name string) {
+	return crossover(c.Crossovers[c.state.pick(r, c.policy(), len(c.Crossovers))], r, a, b)
+}
+
+// Probabilities returns the current probability of choosing each Crossover. Since
+// UpperConfidenceBound is deterministic, it gives the next choice a probability of 1.
+func (c *AdaptiveCrossover) Probabilities() []float64 {
+	return c.state.probabilities(c.policy(), len(c.Crossovers))
+}
+
+// Reward implements AdaptiveOperator
+func (c *AdaptiveCrossover) Reward(provenances []string, gains []Fitness) {
+	c.state.reward(c.policy(), crossoverNames(c.Crossovers), provenances, gains)
+}
+
+func (c *AdaptiveCrossover) policy() selectionPolicy {
+	return selectionPolicy{c.Selection, c.AdaptationRate, c.MinProbability, c.Exploration}
+}
+
+// crossoverNames returns the String of each Crossover.
+func crossoverNames(crossovers []Crossover) []string {
+	names := make([]string, len(crossovers))
+	for i, c := range crossovers {
+		names[i] = c.String()
+	}
+	return names
+}
+
+// mixString formats a mix of operators as fn(op:weight,...) in the syntax of the
+// operator flags. Weights are omitted if nil.
+func mixString(fn string, names []string, weights []float64) string {
+	for i := range names {
+		if weights != nil {
+			names[i] += ":" + strconv.FormatFloat(weights[i], 'g', -1, 64)
+		}
+	}
+	return fmt.Sprintf("%s(%s)", fn, strings.Join(names, ","))
+}
+
+// pickWeighted chooses an index in [0, n) with probability proportional to its
+// weight, or uniformly if weights is nil.
+func pickWeighted(r rand.Rand, weights []float64, n int) int {
+	if weights == nil {
+		return int(r.Int31n(int32(n)))
+	}
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	x := r.Float64() * total
+	for i, w := range weights {
+		if x < w {
+			return i
+		}
+		x -= w
+	}
+	return n - 1
+}
+
+// OperatorSelection is how adaptive operators choose among their operators.
+type OperatorSelection int
+
+const (
+	// ProbabilityMatching chooses operators in proportion to their quality without
+	// letting any fall below a minimum probability (Thierens, 2005).
+	ProbabilityMatching OperatorSelection = iota
+	// UpperConfidenceBound chooses the operator whose quality, relative to the best,
+	// plus a bonus for operators which have rarely been chosen, is highest (UCB1).
+	UpperConfidenceBound
+)
+
+func (s OperatorSelection) String() string {
+	if s == UpperConfidenceBound {
+		return adaptiveUCB
+	}
+	return adaptiveMix
+}
+
+// selectionPolicy holds the parameters of an adaptive operator.
+type selectionPolicy struct {
+	selection      OperatorSelection
+	adaptationRate float64
+	minProbability float64
+	exploration    float64
+}
+
+// params formats the parameters which are not defaulted as name:value terms,
+// which set parses, so that adaptive operators' Strings are lossless.
+func (p selectionPolicy) params() []string {
+	var params []string
+	for _, param := range []struct {
+		name  string
+		value float64
+	}{
+		{adaptationRateParam, p.adaptationRate},
+		{minProbabilityParam, p.minProbability},
+		{explorationParam, p.exploration},
+	} {
+		if param.value != 0 {
+			params = append(params, param.name+":"+strconv.FormatFloat(param.value, 'g', -1, 64))
+		}
+	}
+	return params
+}
+
+// set parses the value of the parameter called name, regardless of case. It
+// reports false if there is no such parameter.
+func (p *selectionPolicy) set(name, value string) (bool, error) {
+	var field *float64
+	switch {
+	case strings.EqualFold(name, adaptationRateParam):
+		field = &p.adaptationRate
+	case strings.EqualFold(name, minProbabilityParam):
+		field = &p.minProbability
+	case strings.EqualFold(name, explorationParam):
+		field = &p.exploration
+	default:
+		return false, nil
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	switch {
+	case err != nil || v <= 0:
+		return true, fmt.Errorf("be positive")
+	case field != &p.exploration && v > 1:
+		return true, fmt.Errorf("be at most 1")
+	}
+	*field = v
+	return true, nil
+}
+
+// operatorQuality tracks the quality of a set of operators for adaptive operator
+// selection. The zero value chooses uniformly.
+type operatorQuality struct {
+	mu      sync.Mutex
+	quality []float64
+	// chosen counts the times each operator was chosen for UpperConfidenceBound.
+	chosen []int
+}
+
+func (o *operatorQuality) probabilities(p selectionPolicy, n int) []float64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	probs := make([]float64, n)
+	if p.selection == UpperConfidenceBound {
+		probs[o.upperConfidenceBound(p, n)] = 1
+		return probs
+	}
+	min := p.minProbability
+	if min == 0 {
+		min = 0.2 / float64(n)
+	}
+	total := 0.0
+	for _, q := range o.quality {
+		total += q
+	}
+	for i := range probs {
+		if total == 0 {
+			probs[i] = 1 / float64(n)
+			continue
+		}
+		probs[i] = min + (1-float64(n)*min)*o.quality[i]/total
+	}
+	return probs
+}
+
+// upperConfidenceBound returns the operator with the highest upper confidence
+// bound, trying every operator once first. o.mu must be held.
+func (o *operatorQuality) upperConfidenceBound(p selectionPolicy, n int) int {
+	if o.chosen == nil {
+		o.chosen = make([]int, n)
+	}
+	total, best := 0, 0.0
+	for i, c := range o.chosen {
+		if c == 0 {
+			return i
+		}
+		total += c
+		if o.quality != nil && o.quality[i] > best {
+			best = o.quality[i]
+		}
+	}
+	exploration := p.exploration
+	if exploration == 0 {
+		exploration = 1
+	}
+	choice, bound := 0, math.Inf(-1)
+	for i, c := range o.chosen {
+		b := exploration * math.Sqrt(2*math.Log(float64(total))/float64(c))
+		if best > 0 {
+			b += o.quality[i] / best
+		}
+		if b > bound {
+			choice, bound = i, b
+		}
+	}
+	return choice
+}
+
+func (o *operatorQuality) pick(r rand.Rand, p selectionPolicy, n int) int {
+	if p.selection != UpperConfidenceBound {
+		return pickWeighted(r, o.probabilities(p, n), n)
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	i := o.upperConfidenceBound(p, n)
+	o.chosen[i]++
+	return i
+}
+
+// reward credits each child's gain to the operator named in its provenance, and
+// moves each credited operator's quality toward its mean gain.
+func (o *operatorQuality) reward(p selectionPolicy, names, provenances []string, gains []Fitness) {
+	rate := p.adaptationRate
+	if rate == 0 {
+		rate = 0.3
+	}
+	index := make(map[string]int, len(names))
+	for i, name := range names {
+		index[name] = i
+	}
+	sums := make([]float64, len(names))
+	counts := make([]int, len(names))
+	for k, provenance := range provenances {
+		for _, op := range strings.Split(provenance, "+") {
+			if i, ok := index[op]; ok {
+				sums[i] += float64(gains[k])
+				counts[i]++
+				break
+			}
+		}
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.quality == nil {
+		o.quality = make([]float64, len(names))
+	}
+	for i, n := range counts {
+		if n != 0 {
+			o.quality[i] += rate * (sums[i]/float64(n) - o.quality[i])
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+// countingMutator counts its applications without changing the chromosome.
+type countingMutator struct {
+	name  string
+	count *int
+}
+
+func (m countingMutator) String() string {
+	return m.name
+}
+
+func (m countingMutator) Mutate(r rand.Rand, c *genetics.Chromosome) {
+	*m.count++
+}
+
+func TestCompositeMutator(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	var swaps, inversions int
+	m := genetics.CompositeMutator{
+		Mutators: []genetics.Mutator{countingMutator{"Swap", &swaps}, countingMutator{"Inversion", &inversions}},
+		Weights:  []float64{0.7, 0.3},
+	}
+	c := genetics.NewSpecies(4, 3).New(0, 1, 2, 3)
+	for i := 0; i < 10000; i++ {
+		m.Mutate(rng, &c)
+	}
+	if swaps < 6800 || swaps > 7200 || swaps+inversions != 10000 {
+		t.Errorf("Mutate() applied Swap %d and Inversion %d times; want about 7000 and 3000", swaps, inversions)
+	}
+	if got, want := m.String(), "Mix(Swap:0.7,Inversion:0.3)"; got != want {
+		t.Errorf("String()=%s; want %s", got, want)
+	}
+}
+
+func TestAdaptiveMutatorReward(t *testing.T) {
+	var good, bad int
+	m := genetics.NewAdaptiveMutator(countingMutator{"Good", &good}, countingMutator{"Bad", &bad})
+	if p := m.Probabilities(); p[0] != 0.5 || p[1] != 0.5 {
+		t.Errorf("Probabilities()=%v before any reward; want uniform", p)
+	}
+	for gen := 0; gen < 10; gen++ {
+		m.Reward(
+			[]string{"Clone+Good", "MultiPointCrossover(1)+Good", "Clone+Bad", "MultiPointCrossover(1)", "Initial"},
+			[]genetics.Fitness{10, 0, 0, 20, 0},
+		)
+	}
+	p := m.Probabilities()
+	if p[0] < 0.89 || p[1] < 0.1 || p[0]+p[1] < 0.999 || p[0]+p[1] > 1.001 {
+		t.Errorf("Probabilities()=%v; want Good to dominate with Bad at its minimum of 0.1", p)
+	}
+
+	rng := rand.New()
+	rng.Seed(42)
+	c := genetics.NewSpecies(4, 3).New(0, 1, 2, 3)
+	for i := 0; i < 1000; i++ {
+		m.Mutate(rng, &c)
+	}
+	if good < 850 || bad < 70 {
+		t.Errorf("Mutate() applied Good %d and Bad %d times; want about 900 and 100", good, bad)
+	}
+}
+
+func TestEngineRewardsAdaptiveMutator(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	var noops int
+	mutator := genetics.NewAdaptiveMutator(genetics.SwapMutation{}, countingMutator{"Noop", &noops})
+	pop, err := genetics.NewSpecies(20, 19).NewPermPopulation(rng, 20)
+	if err != nil {
+		t.Fatalf("NewPermPopulation(); err=%s", err)
+	}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 10,
+			MutationRate:     1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Mutator:          mutator,
+		},
+		Evaluator: genetics.FitnessFunc(inPlace),
+	}
+	if err := engine.Run(rng, pop, 50); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if p := mutator.Probabilities(); p[0] <= p[1] {
+		t.Errorf("Probabilities()=%v; SwapMutation improves children and should be preferred", p)
+	}
+}
+
+// countingCrossover counts its applications and clones the parents.
+type countingCrossover struct {
+	name  string
+	count *int
+}
+
+func (c countingCrossover) String() string {
+	return c.name
+}
+
+func (c countingCrossover) Crossover(r rand.Rand, a, b genetics.Chromosome) (x, y genetics.Chromosome) {
+	*c.count++
+	return genetics.Chromosome{Species: a.Species, Genes: append([]genetics.Gene(nil), a.Genes...)},
+		genetics.Chromosome{Species: b.Species, Genes: append([]genetics.Gene(nil), b.Genes...)}
+}
+
+func TestCompositeCrossover(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	var one, two int
+	c := genetics.CompositeCrossover{
+		Crossovers: []genetics.Crossover{countingCrossover{"One", &one}, countingCrossover{"Two", &two}},
+		Weights:    []float64{1, 3},
+	}
+	s := genetics.NewSpecies(4, 3)
+	a, b := s.New(0, 1, 2, 3), s.New(3, 2, 1, 0)
+	for i := 0; i < 10000; i++ {
+		c.Crossover(rng, a, b)
+	}
+	if one < 2300 || one > 2700 || one+two != 10000 {
+		t.Errorf("Crossover() applied One %d and Two %d times; want about 2500 and 7500", one, two)
+	}
+}
+
+func TestAdaptiveCrossoverSelection(t *testing.T) {
+	for _, selection := range []genetics.OperatorSelection{genetics.ProbabilityMatching, genetics.UpperConfidenceBound} {
+		t.Run(selection.String(), func(t *testing.T) {
+			rng := rand.New()
+			rng.Seed(42)
+			var good, bad int
+			c := &genetics.AdaptiveCrossover{
+				Crossovers: []genetics.Crossover{countingCrossover{"Good", &good}, countingCrossover{"Bad", &bad}},
+				Selection:  selection,
+			}
+			s := genetics.NewSpecies(4, 3)
+			a, b := s.New(0, 1, 2, 3), s.New(3, 2, 1, 0)
+			for gen := 0; gen < 20; gen++ {
+				var provenances []string
+				var gains []genetics.Fitness
+				for i := 0; i < 50; i++ {
+					before := good
+					c.Crossover(rng, a, b)
+					if good > before {
+						provenances, gains = append(provenances, "Good+SwapMutation"), append(gains, 5)
+					} else {
+						provenances, gains = append(provenances, "Bad"), append(gains, 0)
+					}
+				}
+				c.Reward(provenances, gains)
+			}
+			if good < 3*bad || bad == 0 {
+				t.Errorf("Crossover() applied Good %d and Bad %d times; want Good to dominate while Bad is still explored", good, bad)
+			}
+		})
+	}
+}
+
+func TestEngineRewardsAdaptiveCrossover(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	var copies int
+	crossover := genetics.NewAdaptiveCrossover(genetics.DavisOrderCrossover{}, countingCrossover{"Copy", &copies})
+	pop, err := genetics.NewSpecies(20, 19).NewPermPopulation(rng, 20)
+	if err != nil {
+		t.Fatalf("NewPermPopulation(); err=%s", err)
+	}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 10,
+			CrossoverRate:    1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        crossover,
+		},
+		Evaluator: genetics.FitnessFunc(inPlace),
+	}
+	if err := engine.Run(rng, pop, 30); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if p := crossover.Probabilities(); p[0] <= p[1] {
+		t.Errorf("Probabilities()=%v; DavisOrderCrossover improves children and should be preferred", p)
+	}
+}
@@ -0,0 +1,67 @@
+package genetics
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ConcurrentSafe is implemented by operators (NaturalSelections, Crossovers,
+// Mutators, Repairers, and Constraints) which one instance may serve to several
+// goroutines at once, e.g. to the Engines of Islands in Archipelago.RunParallel.
+// Every built-in operator is stateless or, like AdaptiveMutator, guards its state,
+// so it reports true unless it wraps an operator which is not ConcurrentSafe. The
+// exceptions are PopulationLearners such as LinkageCrossover, whose learned state
+// describes a single population.
+// Operators which do not implement ConcurrentSafe are assumed to be unsafe.
+type ConcurrentSafe interface {
+	ConcurrentSafe() bool
+}
+
+// IsConcurrentSafe reports whether op implements ConcurrentSafe and reports
+// itself safe.
+func IsConcurrentSafe(op interface{}) bool {
+	cs, ok := op.(ConcurrentSafe)
+	return ok && cs.ConcurrentSafe()
+}
+
+// ConcurrentSafe reports whether every operator of e is ConcurrentSafe, so
+// that e may be shared by Engines running concurrently. Validator and any
+// MatingRestriction Distance must be goroutine safe too, but cannot be checked.
+func (e Evolver) ConcurrentSafe() bool {
+	for _, op := range e.operators() {
+		if !IsConcurrentSafe(op) {
+			return false
+		}
+	}
+	return true
+}
+
+// operators returns the operators of e which are set.
+func (e Evolver) operators() []interface{} {
+	var ops []interface{}
+	for _, op := range []interface{}{e.Selector, e.Crossover, e.Mutator, e.Repairer} {
+		if op != nil {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// checkShared returns an error if two Islands share an operator which is not
+// ConcurrentSafe. Only operators held by pointer can be shared; others are
+// copied into each Evolver.
+func (a *Archipelago) checkShared() error {
+	owners := map[interface{}]int{}
+	for n, island := range a.Islands {
+		for _, op := range island.Engine.Evolver.operators() {
+			if IsConcurrentSafe(op) || reflect.ValueOf(op).Kind() != reflect.Ptr {
+				continue
+			}
+			if owner, ok := owners[op]; ok && owner != n {
+				return fmt.Errorf("Archipelago.RunParallel(); islands %s and %s share %s, which is not ConcurrentSafe", a.Islands[owner].Name, island.Name, op)
+			}
+			owners[op] = n
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package genetics_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestIsConcurrentSafe(t *testing.T) {
+	n := 0
+	unsafe := countingMutator{name: "Counting", count: &n}
+	for _, test := range []struct {
+		tag  string
+		op   interface{}
+		want bool
+	}{
+		{"selector", genetics.TournamentSelection{Size: 2}, true},
+		{"crossover", genetics.PartiallyMappedCrossover{}, true},
+		{"mutator", genetics.SwapMutation{}, true},
+		{"repairer", genetics.PermutationRepair{}, true},
+		{"binary", genetics.BitFlipMutation{}, true},
+		{"real", genetics.GaussianMutation{Sigma: 0.1}, true},
+		{"adaptive", genetics.NewAdaptiveMutator(genetics.SwapMutation{}, genetics.InversionMutation{}), true},
+		{"unmarked", unsafe, false},
+		{"composite of unmarked", genetics.CompositeMutator{Mutators: []genetics.Mutator{genetics.SwapMutation{}, unsafe}}, false},
+		{"adaptive of unmarked", genetics.NewAdaptiveCrossover(genetics.DavisOrderCrossover{}, countingCrossover{name: "Counting", count: &n}), false},
+		{"constrained", genetics.ConstrainedMutation{Mutator: genetics.SwapMutation{}, Constraints: genetics.Constraints{genetics.SumAtMost{Max: 3}}}, true},
+		{"constrained unmarked", genetics.ConstrainedMutation{Mutator: unsafe}, false},
+		{"linkage", genetics.NewLinkageCrossover(2), false},
+		{"constrained tournament", &genetics.ConstrainedTournamentSelection{Size: 2}, false},
+		{"evolver", genetics.NewEvolver(genetics.WithRepairer(genetics.PermutationRepair{})), true},
+		{"evolver with unmarked", genetics.NewEvolver(genetics.WithMutator(unsafe)), false},
+	} {
+		if got := genetics.IsConcurrentSafe(test.op); got != test.want {
+			t.Errorf("%s: IsConcurrentSafe(%v)=%t; want %t", test.tag, test.op, got, test.want)
+		}
+	}
+}
+
+// TestConcurrentOperators shares one instance of each built-in operator between
+// goroutines; run it with -race.
+func TestConcurrentOperators(t *testing.T) {
+	adaptiveMutator := genetics.NewAdaptiveMutator(genetics.SwapMutation{}, genetics.InversionMutation{})
+	adaptiveCrossover := genetics.NewAdaptiveCrossover(genetics.DavisOrderCrossover{}, genetics.PartiallyMappedCrossover{})
+	crossovers := append(benchCrossovers, adaptiveCrossover)
+	mutators := append(benchMutators, adaptiveMutator, genetics.ConstrainedMutation{Mutator: genetics.SwapMutation{}})
+	for _, op := range benchSelectors {
+		if !genetics.IsConcurrentSafe(op) {
+			t.Errorf("%s is not ConcurrentSafe", op)
+		}
+	}
+	for _, op := range crossovers {
+		if !genetics.IsConcurrentSafe(op) {
+			t.Errorf("%s is not ConcurrentSafe", op)
+		}
+	}
+	for _, op := range mutators {
+		if !genetics.IsConcurrentSafe(op) {
+			t.Errorf("%s is not ConcurrentSafe", op)
+		}
+	}
+
+	pa, pb := benchParents(20)
+	scores := benchScores(20)
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New()
+			rng.Seed(seed)
+			for i := 0; i < 20; i++ {
+				for _, s := range benchSelectors {
+					s.SelectParents(rng, 10, scores)
+				}
+				for _, c := range crossovers {
+					x, _ := c.Crossover(rng, pa, pb)
+					for _, m := range mutators {
+						m.Mutate(rng, &x)
+					}
+					genetics.PermutationRepair{}.Repair(rng, &x)
+				}
+				adaptiveMutator.Reward([]string{"Swap"}, []genetics.Fitness{1})
+				adaptiveCrossover.Reward([]string{"DavisOrder"}, []genetics.Fitness{1})
+			}
+		}(int64(w))
+	}
+	wg.Wait()
+}
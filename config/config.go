@@ -0,0 +1,247 @@
+// Package config describes a complete genetics run (its Species, operators,
+// rates, termination, and seed) as a JSON document, so that experiments can be
+// versioned as files instead of flags and code. For example:
+//
+//	{
+//	  "species": {"numGenes": 20, "maxAllele": 1},
+//	  "populationSize": 50,
+//	  "evolver": {
+//	    "replacementCount": 24,
+//	    "crossoverRate": 0.9,
+//	    "mutationRate": 0.3,
+//	    "selector": "TournamentSelection(3)",
+//	    "crossover": "MultiPoint(2)",
+//	    "mutator": "Inversion"
+//	  },
+//	  "termination": {"generations": 200, "targetFitness": 20, "stall": 25},
+//	  "fitness": "sum(g)",
+//	  "seed": 42
+//	}
+//
+// Operators are named as they are for NaturalSelectionFlag, CrossoverFlag, and
+// MutationFlag. An omitted crossoverRate is genetics.DefaultCrossoverRate; a
+// crossoverRate of 0 never recombines, e.g. for an evolver without a crossover. A run whose fitness function is described by the Config itself
+// can be started with
+//
+//	eval, err := c.Evaluator()
+//	...
+//	pop, err := c.Run(eval)
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/genetics/fitness"
+	"github.com/inlined/rand"
+)
+
+// Config describes a run and, optionally, its fitness function.
+type Config struct {
+	Species        Species                `json:"species"`
+	PopulationSize int                    `json:"populationSize"`
+	Objective      genetics.Objective     `json:"objective,omitempty"`
+	Evolver        genetics.EvolverConfig `json:"evolver"`
+	Termination    Termination            `json:"termination"`
+	// Fitness, if set, is the fitness function as accepted by fitness.Load: an
+	// expression over the genes or a "plugin:" reference to a Go plugin.
+	Fitness string `json:"fitness,omitempty"`
+	// Seed seeds Rand. A Seed of 0 is replaced with one from the clock.
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// Species describes a genetics.Species with either NumGenes alleles in
+// [0, MaxAllele] or one allele in [MinAlleles[i], MaxAlleles[i]] per gene.
+type Species struct {
+	NumGenes   int             `json:"numGenes,omitempty"`
+	MaxAllele  genetics.Gene   `json:"maxAllele,omitempty"`
+	MinAlleles []genetics.Gene `json:"minAlleles,omitempty"`
+	MaxAlleles []genetics.Gene `json:"maxAlleles,omitempty"`
+	// Permutation creates the initial population with NewPerm instead of NewRand.
+	Permutation bool `json:"permutation,omitempty"`
+}
+
+// Termination decides when a run ends.
+type Termination struct {
+	// Generations is the most generations to evolve.
+	Generations int `json:"generations"`
+	// TargetFitness, if set, ends the run once the best fitness reaches it.
+	TargetFitness *genetics.Fitness `json:"targetFitness,omitempty"`
+	// Stall, if positive, ends the run after Stall generations without an
+	// improvement in the best fitness.
+	Stall int `json:"stall,omitempty"`
+}
+
+// Load decodes and validates a Config. Unknown fields are errors so that typos
+// are not silently ignored.
+func Load(r io.Reader) (*Config, error) {
+	d := json.NewDecoder(r)
+	d.DisallowUnknownFields()
+	c := &Config{Evolver: genetics.EvolverConfig{CrossoverRate: genetics.DefaultCrossoverRate}}
+	if err := d.Decode(c); err != nil {
+		return nil, fmt.Errorf("config.Load(); err=%s", err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// LoadFile loads the Config in the file at path.
+func LoadFile(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("config.LoadFile(%s); err=%s", path, err)
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// Validate returns an error if c does not describe a runnable configuration.
+func (c *Config) Validate() error {
+	if _, err := c.Species.New(); err != nil {
+		return fmt.Errorf("Config.Validate(); err=%s", err)
+	}
+	switch {
+	case c.PopulationSize <= 0:
+		return fmt.Errorf("Config.Validate(); PopulationSize %d must be positive", c.PopulationSize)
+	case c.Termination.Generations <= 0:
+		return fmt.Errorf("Config.Validate(); Termination.Generations %d must be positive", c.Termination.Generations)
+	case c.Termination.Stall < 0:
+		return fmt.Errorf("Config.Validate(); Termination.Stall %d must not be negative", c.Termination.Stall)
+	}
+	e, err := c.Evolver.Evolver()
+	if err != nil {
+		return fmt.Errorf("Config.Validate(); err=%s", err)
+	}
+	if err := e.Validate(c.PopulationSize); err != nil {
+		return fmt.Errorf("Config.Validate(); err=%s", err)
+	}
+	return nil
+}
+
+// New creates the described Species.
+func (s Species) New() (*genetics.Species, error) {
+	if s.MinAlleles != nil || s.MaxAlleles != nil {
+		if s.NumGenes != 0 && s.NumGenes != len(s.MinAlleles) {
+			return nil, fmt.Errorf("Species.New(); NumGenes %d does not match %d ranges", s.NumGenes, len(s.MinAlleles))
+		}
+		if s.MaxAllele != 0 {
+			return nil, fmt.Errorf("Species.New(); MaxAllele cannot be combined with ranges")
+		}
+		return genetics.NewRangedSpecies(s.MinAlleles, s.MaxAlleles)
+	}
+	switch {
+	case s.NumGenes <= 0:
+		return nil, fmt.Errorf("Species.New(); NumGenes %d must be positive", s.NumGenes)
+	case s.MaxAllele <= 0:
+		return nil, fmt.Errorf("Species.New(); MaxAllele %d must be positive", s.MaxAllele)
+	case s.Permutation && s.MaxAllele < genetics.Gene(s.NumGenes-1):
+		return nil, fmt.Errorf("Species.New(); MaxAllele %d is too small for a permutation of %d genes", s.MaxAllele, s.NumGenes)
+	}
+	return genetics.NewSpecies(s.NumGenes, s.MaxAllele), nil
+}
+
+// Rand returns a random source seeded with Seed, first choosing a Seed from the
+// clock if it is 0 so that the run can be reproduced.
+func (c *Config) Rand() rand.Rand {
+	if c.Seed == 0 {
+		c.Seed = time.Now().UnixNano()
+	}
+	r := rand.New()
+	r.Seed(c.Seed)
+	return r
+}
+
+// Population creates the initial population.
+func (c *Config) Population(r rand.Rand) (*genetics.Population, error) {
+	s, err := c.Species.New()
+	if err != nil {
+		return nil, fmt.Errorf("Config.Population(); err=%s", err)
+	}
+	var pop *genetics.Population
+	if c.Species.Permutation {
+		pop, err = s.NewPermPopulation(r, c.PopulationSize)
+	} else {
+		pop, err = s.NewRandPopulation(r, c.PopulationSize)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Config.Population(); err=%s", err)
+	}
+	pop.Objective = c.Objective
+	return pop, nil
+}
+
+// Engine creates an Engine which evolves with the described Evolver and
+// Termination and scores with eval. Other Engine features may be set on the result.
+func (c *Config) Engine(eval genetics.Evaluator) (*genetics.Engine, error) {
+	e, err := c.Evolver.Evolver()
+	if err != nil {
+		return nil, fmt.Errorf("Config.Engine(); err=%s", err)
+	}
+	var terms []genetics.Termination
+	if c.Termination.TargetFitness != nil {
+		terms = append(terms, genetics.TargetFitness(*c.Termination.TargetFitness))
+	}
+	if c.Termination.Stall > 0 {
+		terms = append(terms, stall(c.Termination.Stall))
+	}
+	engine := &genetics.Engine{Evolver: e, Evaluator: eval}
+	if len(terms) != 0 {
+		engine.Terminate = genetics.AnyOf(terms...)
+	}
+	return engine, nil
+}
+
+// Evaluator loads the described Fitness.
+func (c *Config) Evaluator() (genetics.Evaluator, error) {
+	if c.Fitness == "" {
+		return nil, fmt.Errorf("Config.Evaluator(); Fitness is not set")
+	}
+	eval, err := fitness.Load(c.Fitness)
+	if err != nil {
+		return nil, fmt.Errorf("Config.Evaluator(); err=%s", err)
+	}
+	return eval, nil
+}
+
+// Run validates c, then evolves a new population with eval until the Termination
+// and returns it.
+func (c *Config) Run(eval genetics.Evaluator) (*genetics.Population, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	r := c.Rand()
+	pop, err := c.Population(r)
+	if err != nil {
+		return nil, err
+	}
+	engine, err := c.Engine(eval)
+	if err != nil {
+		return nil, err
+	}
+	if err := engine.Run(r, pop, c.Termination.Generations); err != nil {
+		return nil, fmt.Errorf("Config.Run(); err=%s", err)
+	}
+	return pop, nil
+}
+
+// stall stops a run after generations without an improvement in the best fitness.
+func stall(generations int) genetics.Termination {
+	var (
+		best    genetics.Fitness
+		stalled int
+	)
+	return func(s genetics.Stats) bool {
+		if s.Generation == 0 || s.Objective.Better(s.Best, best) {
+			best, stalled = s.Best, 0
+			return false
+		}
+		stalled++
+		return stalled >= generations
+	}
+}
@@ -0,0 +1,224 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/genetics/config"
+	"github.com/inlined/genetics/problems"
+)
+
+const oneMax = `{
+  "species": {"numGenes": 20, "maxAllele": 1},
+  "populationSize": 50,
+  "evolver": {
+    "replacementCount": 24,
+    "crossoverRate": 0.9,
+    "mutationRate": 0.3,
+    "selector": "TournamentSelection(3)",
+    "crossover": "MultiPoint(2)",
+    "mutator": "Inversion"
+  },
+  "termination": {"generations": 200, "targetFitness": 20},
+  "seed": 42
+}`
+
+func TestLoad(t *testing.T) {
+	c, err := config.Load(strings.NewReader(oneMax))
+	if err != nil {
+		t.Fatalf("config.Load(); err=%s", err)
+	}
+	target := genetics.Fitness(20)
+	want := &config.Config{
+		Species:        config.Species{NumGenes: 20, MaxAllele: 1},
+		PopulationSize: 50,
+		Evolver: genetics.EvolverConfig{
+			ReplacementCount: 24,
+			CrossoverRate:    0.9,
+			MutationRate:     0.3,
+			Selector:         "TournamentSelection(3)",
+			Crossover:        "MultiPoint(2)",
+			Mutator:          "Inversion",
+		},
+		Termination: config.Termination{Generations: 200, TargetFitness: &target},
+		Seed:        42,
+	}
+	if diff := cmp.Diff(want, c); diff != "" {
+		t.Errorf("config.Load() decoded an unexpected Config; diff=%s", diff)
+	}
+
+	path := filepath.Join(t.TempDir(), "onemax.json")
+	if err := os.WriteFile(path, []byte(oneMax), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fromFile, err := config.LoadFile(path)
+	if err != nil {
+		t.Fatalf("config.LoadFile(); err=%s", err)
+	}
+	if diff := cmp.Diff(c, fromFile); diff != "" {
+		t.Errorf("config.LoadFile() differs from config.Load(); diff=%s", diff)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	for _, test := range []struct {
+		tag     string
+		replace [2]string
+		err     string
+	}{
+		{
+			tag:     "unknown field",
+			replace: [2]string{`"seed"`, `"sede"`},
+			err:     `unknown field "sede"`,
+		}, {
+			tag:     "unknown operator",
+			replace: [2]string{`"Inversion"`, `"Shuffle"`},
+			err:     "unknown function name Shuffle",
+		}, {
+			tag:     "invalid rate",
+			replace: [2]string{`"mutationRate": 0.3`, `"mutationRate": 5`},
+			err:     "MutationRate 5 is outside [0, 1]",
+		}, {
+			tag:     "no generations",
+			replace: [2]string{`"generations": 200`, `"generations": 0`},
+			err:     "Termination.Generations 0 must be positive",
+		}, {
+			tag:     "no genes",
+			replace: [2]string{`"numGenes": 20`, `"numGenes": 0`},
+			err:     "NumGenes 0 must be positive",
+		}, {
+			tag:     "too many replacements",
+			replace: [2]string{`"populationSize": 50`, `"populationSize": 10`},
+			err:     "ReplacementCount 24 exceeds the population size 10",
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			doc := strings.Replace(oneMax, test.replace[0], test.replace[1], 1)
+			_, err := config.Load(strings.NewReader(doc))
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("config.Load() should fail with %q; err=%v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestLoadCrossoverRate(t *testing.T) {
+	for _, test := range []struct {
+		tag     string
+		replace [2]string
+		want    float32
+	}{
+		{tag: "explicit", want: 0.9},
+		{tag: "omitted", replace: [2]string{`"crossoverRate": 0.9,`, ""}, want: genetics.DefaultCrossoverRate},
+		{tag: "mutation only", replace: [2]string{`"crossoverRate": 0.9`, `"crossoverRate": 0`}, want: 0},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			doc := strings.Replace(oneMax, test.replace[0], test.replace[1], 1)
+			c, err := config.Load(strings.NewReader(doc))
+			if err != nil {
+				t.Fatalf("config.Load(); err=%s", err)
+			}
+			e, err := c.Evolver.Evolver()
+			if err != nil {
+				t.Fatalf("EvolverConfig.Evolver(); err=%s", err)
+			}
+			if e.CrossoverRate != test.want {
+				t.Errorf("CrossoverRate=%g; want %g", e.CrossoverRate, test.want)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	c, err := config.Load(strings.NewReader(oneMax))
+	if err != nil {
+		t.Fatalf("config.Load(); err=%s", err)
+	}
+	p := problems.OneMax{N: 20}
+	pop, err := c.Run(genetics.FitnessFunc(p.Fitness))
+	if err != nil {
+		t.Fatalf("Config.Run(); err=%s", err)
+	}
+	if _, f := pop.Best(); f != p.Optimum() {
+		t.Errorf("Config.Run() best fitness=%g; want %g", f, p.Optimum())
+	}
+
+	again, err := c.Run(genetics.FitnessFunc(p.Fitness))
+	if err != nil {
+		t.Fatalf("Config.Run(); err=%s", err)
+	}
+	if diff := cmp.Diff(pop.Fitness, again.Fitness); diff != "" {
+		t.Errorf("Config.Run() is not reproducible for a seed; diff=%s", diff)
+	}
+}
+
+func TestEvaluator(t *testing.T) {
+	c, err := config.Load(strings.NewReader(oneMax))
+	if err != nil {
+		t.Fatalf("config.Load(); err=%s", err)
+	}
+	if _, err := c.Evaluator(); err == nil {
+		t.Error("Config.Evaluator() should fail without a Fitness")
+	}
+	c.Fitness = "sum(g)"
+	eval, err := c.Evaluator()
+	if err != nil {
+		t.Fatalf("Config.Evaluator(); err=%s", err)
+	}
+	pop, err := c.Run(eval)
+	if err != nil {
+		t.Fatalf("Config.Run(); err=%s", err)
+	}
+	if _, f := pop.Best(); f != 20 {
+		t.Errorf("Config.Run() best fitness=%g; want 20", f)
+	}
+}
+
+func TestStall(t *testing.T) {
+	c := &config.Config{
+		Species:        config.Species{NumGenes: 8, MaxAllele: 7, Permutation: true},
+		PopulationSize: 10,
+		Objective:      genetics.Minimize,
+		Evolver: genetics.EvolverConfig{
+			ReplacementCount: 4,
+			CrossoverRate:    0.9,
+			MutationRate:     0.1,
+			Selector:         "sus",
+			Crossover:        "pmx",
+			Mutator:          "Swap",
+		},
+		Termination: config.Termination{Generations: 1000, Stall: 5},
+		Seed:        42,
+	}
+	// Every permutation is equally fit, so the best fitness never improves.
+	engine, err := c.Engine(genetics.FitnessFunc(func(genetics.Chromosome) genetics.Fitness { return 1 }))
+	if err != nil {
+		t.Fatalf("Config.Engine(); err=%s", err)
+	}
+	r := c.Rand()
+	pop, err := c.Population(r)
+	if err != nil {
+		t.Fatalf("Config.Population(); err=%s", err)
+	}
+	if pop.Objective != genetics.Minimize {
+		t.Errorf("Config.Population() Objective=%s; want Minimize", pop.Objective)
+	}
+	for _, chromosome := range pop.Chromosomes {
+		if err := chromosome.Species.ValidatePermutation(chromosome); err != nil {
+			t.Errorf("Config.Population() should create permutations; err=%s", err)
+		}
+	}
+	if err := engine.Run(r, pop, c.Termination.Generations); err != nil {
+		t.Fatalf("Engine.Run(); err=%s", err)
+	}
+	// Generations 1 to 5 do not improve on generation 0; the run ends after
+	// scoring generation 6, which they bred.
+	if got := engine.Generation(); got != 6 {
+		t.Errorf("Stall of 5 ended the run after %d generations; want 6", got)
+	}
+}
@@ -0,0 +1,275 @@
+package genetics
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/inlined/rand"
+)
+
+// maxRepairPasses bounds how often Constraints.Repair revisits its rules, since
+// repairing one Constraint may violate another.
+const maxRepairPasses = 10
+
+// Constraint is a rule between genes which valid Chromosomes must follow.
+// Constraints are useful when generating configurations where some settings
+// are incompatible with each other.
+type Constraint interface {
+	fmt.Stringer
+	// Violation returns 0 if c satisfies the Constraint and otherwise a positive
+	// measure of how badly the Constraint is violated.
+	Violation(c Chromosome) float64
+	// Repair modifies c so that it satisfies the Constraint.
+	Repair(r rand.Rand, c *Chromosome)
+}
+
+// Exclusion requires that gene Then is 0 whenever gene If is greater than 0.
+type Exclusion struct {
+	If   int
+	Then int
+}
+
+func (e Exclusion) String() string {
+	return fmt.Sprintf("Exclusion(%d,%d)", e.If, e.Then)
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (Exclusion) ConcurrentSafe() bool {
+	return true
+}
+
+// Violation implements Constraint
+func (e Exclusion) Violation(c Chromosome) float64 {
+	if c.Genes[e.If] <= 0 || c.Genes[e.Then] == 0 {
+		return 0
+	}
+	if c.Genes[e.Then] < 0 {
+		return float64(-c.Genes[e.Then])
+	}
+	return float64(c.Genes[e.Then])
+}
+
+// Repair implements Constraint by clearing either of the conflicting genes at random.
+func (e Exclusion) Repair(r rand.Rand, c *Chromosome) {
+	if e.Violation(*c) == 0 {
+		return
+	}
+	if r.Int31n(2) == 0 {
+		c.Genes[e.If] = 0
+	} else {
+		c.Genes[e.Then] = 0
+	}
+}
+
+// SumAtMost requires that the alleles at Loci sum to no more than Max.
+type SumAtMost struct {
+	Loci []int
+	Max  Gene
+}
+
+func (s SumAtMost) String() string {
+	loci := make([]string, len(s.Loci))
+	for n, l := range s.Loci {
+		loci[n] = fmt.Sprint(l)
+	}
+	return fmt.Sprintf("SumAtMost([%s],%d)", strings.Join(loci, ","), s.Max)
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (SumAtMost) ConcurrentSafe() bool {
+	return true
+}
+
+func (s SumAtMost) sum(c Chromosome) Gene {
+	sum := Gene(0)
+	for _, l := range s.Loci {
+		sum += c.Genes[l]
+	}
+	return sum
+}
+
+// Violation implements Constraint
+func (s SumAtMost) Violation(c Chromosome) float64 {
+	if sum := s.sum(c); sum > s.Max {
+		return float64(sum - s.Max)
+	}
+	return 0
+}
+
+// Repair implements Constraint by decrementing randomly chosen positive genes
+// until the sum is small enough.
+func (s SumAtMost) Repair(r rand.Rand, c *Chromosome) {
+	for excess := s.sum(*c) - s.Max; excess > 0; excess-- {
+		positive := make([]int, 0, len(s.Loci))
+		for _, l := range s.Loci {
+			if c.Genes[l] > 0 {
+				positive = append(positive, l)
+			}
+		}
+		if len(positive) == 0 {
+			return
+		}
+		c.Genes[positive[r.Int31n(int32(len(positive)))]]--
+	}
+}
+
+// Constraints is a set of Constraints which must all hold.
+type Constraints []Constraint
+
+func (cs Constraints) String() string {
+	names := make([]string, len(cs))
+	for n, c := range cs {
+		names[n] = c.String()
+	}
+	return strings.Join(names, ",")
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (cs Constraints) ConcurrentSafe() bool {
+	for _, c := range cs {
+		if !IsConcurrentSafe(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Violation returns the total violation of all Constraints.
+func (cs Constraints) Violation(c Chromosome) float64 {
+	total := 0.0
+	for _, constraint := range cs {
+		total += constraint.Violation(c)
+	}
+	return total
+}
+
+// Validate returns an error naming each Constraint which c violates.
+func (cs Constraints) Validate(c Chromosome) error {
+	var violated []string
+	for _, constraint := range cs {
+		if constraint.Violation(c) != 0 {
+			violated = append(violated, constraint.String())
+		}
+	}
+	if len(violated) != 0 {
+		return fmt.Errorf("Chromosome%v violates constraints %s", c.Genes, strings.Join(violated, ", "))
+	}
+	return nil
+}
+
+// Repair repairs each violated Constraint in order. Because one repair may violate
+// an earlier Constraint, Repair revisits the set a bounded number of times.
+func (cs Constraints) Repair(r rand.Rand, c *Chromosome) {
+	for pass := 0; pass < maxRepairPasses && cs.Violation(*c) != 0; pass++ {
+		for _, constraint := range cs {
+			constraint.Repair(r, c)
+		}
+	}
+}
+
+// ConstrainedMutation is a Mutator which repairs any Constraints broken by the
+// wrapped Mutator. Its String omits the Constraints, so ParseMutator cannot
+// restore it.
+type ConstrainedMutation struct {
+	Mutator     Mutator
+	Constraints Constraints
+}
+
+func (m ConstrainedMutation) String() string {
+	return fmt.Sprintf("Constrained(%s)", m.Mutator)
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (m ConstrainedMutation) ConcurrentSafe() bool {
+	return IsConcurrentSafe(m.Mutator) && m.Constraints.ConcurrentSafe()
+}
+
+// WithProblem implements ContextualOperator
+func (m ConstrainedMutation) WithProblem(problem interface{}) (interface{}, error) {
+	var err error
+	m.Mutator, err = mutatorWithProblem(m.Mutator, problem)
+	return m, err
+}
+
+// Mutate implements Mutator
+func (m ConstrainedMutation) Mutate(r rand.Rand, c *Chromosome) {
+	m.Mutator.Mutate(r, c)
+	m.Constraints.Repair(r, c)
+}
+
+// ConstrainedTournamentSelection is TournamentSelection by Deb's feasibility rules,
+// for constrained problems whose fitness should not be blended with a penalty: of
+// the Size contestants, a feasible Chromosome beats an infeasible one, two
+// feasible Chromosomes are compared by fitness, and two infeasible Chromosomes by
+// their total Violation of Constraints. An Engine teaches it the Violations of
+// each generation through Learn; until Learn is called, or if the population has
+// since changed size, every Chromosome is treated as feasible. The Evolver still
+// replaces the least fit members regardless of feasibility, so a ReplacementCount
+// equal to the population size lets the rules govern survival as well. The learned
+// Violations belong to one population, so Islands must not share an instance. Its
+// String omits the Constraints, so ParseSelection cannot restore it.
+type ConstrainedTournamentSelection struct {
+	Size        int
+	Constraints Constraints
+
+	mu         sync.RWMutex
+	violations []float64
+}
+
+func (s *ConstrainedTournamentSelection) String() string {
+	return fmt.Sprintf("ConstrainedTournament(%d)", s.Size)
+}
+
+// ConcurrentSafe implements ConcurrentSafe. Learn records the Violations of one
+// population by index, and another population's Learn would overwrite them
+// between this one's Learn and SelectParents.
+func (s *ConstrainedTournamentSelection) ConcurrentSafe() bool {
+	return false
+}
+
+// Learn implements PopulationLearner by measuring the Violation of each member of
+// pop.
+func (s *ConstrainedTournamentSelection) Learn(pop *Population) {
+	violations := make([]float64, len(pop.Chromosomes))
+	for i, c := range pop.Chromosomes {
+		violations[i] = s.Constraints.Violation(c)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.violations = violations
+}
+
+// SelectParents implements NaturalSelection
+func (s *ConstrainedTournamentSelection) SelectParents(r rand.Rand, numParents int, fitness []Fitness) (indexes []int) {
+	s.mu.RLock()
+	violations := s.violations
+	s.mu.RUnlock()
+	if len(violations) != len(fitness) {
+		violations = make([]float64, len(fitness))
+	}
+	indexes = make([]int, numParents)
+	for n := range indexes {
+		contestants := rand.Deal(r, len(fitness), s.Size)
+		winner := contestants[0]
+		for _, c := range contestants[1:] {
+			if constrainedFitter(c, winner, fitness, violations) {
+				winner = c
+			}
+		}
+		indexes[n] = winner
+	}
+	return indexes
+}
+
+// constrainedFitter reports whether member a beats member b by Deb's feasibility
+// rules, breaking ties by index.
+func constrainedFitter(a, b int, fitness []Fitness, violations []float64) bool {
+	switch {
+	case violations[a] == 0 && violations[b] == 0:
+		return tie{index: a, fitness: fitness[a]}.fitterThan(tie{index: b, fitness: fitness[b]})
+	case violations[a] != violations[b]:
+		return violations[a] < violations[b]
+	}
+	return a < b
+}
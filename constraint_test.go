@@ -0,0 +1,179 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+	"github.com/inlined/xkcd"
+
+	"github.com/inlined/genetics"
+)
+
+func TestConstraintViolation(t *testing.T) {
+	s := genetics.NewSpecies(4, 5)
+	for _, test := range []struct {
+		tag        string
+		constraint genetics.Constraint
+		genes      []genetics.Gene
+		violation  float64
+	}{
+		{
+			tag:        "exclusion inactive",
+			constraint: genetics.Exclusion{If: 0, Then: 1},
+			genes:      []genetics.Gene{0, 3, 0, 0},
+			violation:  0,
+		}, {
+			tag:        "exclusion satisfied",
+			constraint: genetics.Exclusion{If: 0, Then: 1},
+			genes:      []genetics.Gene{2, 0, 0, 0},
+			violation:  0,
+		}, {
+			tag:        "exclusion violated",
+			constraint: genetics.Exclusion{If: 0, Then: 1},
+			genes:      []genetics.Gene{2, 3, 0, 0},
+			violation:  3,
+		}, {
+			tag:        "sum satisfied",
+			constraint: genetics.SumAtMost{Loci: []int{1, 2, 3}, Max: 6},
+			genes:      []genetics.Gene{5, 2, 2, 2},
+			violation:  0,
+		}, {
+			tag:        "sum violated",
+			constraint: genetics.SumAtMost{Loci: []int{1, 2, 3}, Max: 6},
+			genes:      []genetics.Gene{0, 5, 2, 2},
+			violation:  3,
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			if got := test.constraint.Violation(s.New(test.genes...)); got != test.violation {
+				t.Errorf("%s.Violation(%v)=%f; want %f", test.constraint, test.genes, got, test.violation)
+			}
+		})
+	}
+}
+
+func TestConstraintRepair(t *testing.T) {
+	s := genetics.NewSpecies(4, 5)
+	c := s.New(2, 3, 0, 0)
+	genetics.Exclusion{If: 0, Then: 1}.Repair(xkcd.Rand(1), &c)
+	if diff := cmp.Diff([]genetics.Gene{2, 0, 0, 0}, c.Genes); diff != "" {
+		t.Errorf("Exclusion.Repair() got=%v diff=%s", c.Genes, diff)
+	}
+
+	constraints := genetics.Constraints{
+		genetics.Exclusion{If: 0, Then: 3},
+		genetics.SumAtMost{Loci: []int{0, 1, 2}, Max: 4},
+	}
+	rng := rand.New()
+	for run := 0; run < 100; run++ {
+		c, err := s.NewRand(rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		constraints.Repair(rng, &c)
+		if err := constraints.Validate(c); err != nil {
+			t.Errorf("Constraints.Repair() did not repair chromosome: %s", err)
+		}
+	}
+	if err := constraints.Validate(s.New(1, 1, 1, 1)); err == nil {
+		t.Error("Constraints.Validate() should reject violations")
+	}
+}
+
+func TestConstrainedMutation(t *testing.T) {
+	s := genetics.NewSpecies(4, 5)
+	mutator := genetics.ConstrainedMutation{
+		Mutator:     genetics.RandomResettingMutation{},
+		Constraints: genetics.Constraints{genetics.SumAtMost{Loci: []int{0, 1, 2, 3}, Max: 3}},
+	}
+	rng := rand.New()
+	c := s.New(1, 1, 1, 0)
+	for run := 0; run < 100; run++ {
+		mutator.Mutate(rng, &c)
+		if err := mutator.Constraints.Validate(c); err != nil {
+			t.Fatalf("%s produced an invalid chromosome: %s", mutator, err)
+		}
+	}
+}
+
+func TestConstrainedTournamentSelection(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	s := genetics.NewSpecies(2, 5)
+	sel := &genetics.ConstrainedTournamentSelection{
+		Size:        4,
+		Constraints: genetics.Constraints{genetics.SumAtMost{Loci: []int{0, 1}, Max: 4}},
+	}
+	fitness := []genetics.Fitness{10, 5, 8, 1}
+	// Until it learns the violations, every Chromosome is feasible.
+	if got := sel.SelectParents(rng, 1, fitness); got[0] != 0 {
+		t.Errorf("SelectParents() before Learn=%v; want the fittest, 0", got)
+	}
+	for _, test := range []struct {
+		name  string
+		genes [][]genetics.Gene
+		want  int
+	}{
+		{name: "fittest feasible", genes: [][]genetics.Gene{{5, 2}, {3, 2}, {2, 2}, {0, 0}}, want: 2},
+		{name: "only feasible", genes: [][]genetics.Gene{{5, 2}, {3, 2}, {5, 5}, {0, 1}}, want: 3},
+		{name: "least violation", genes: [][]genetics.Gene{{5, 2}, {3, 2}, {5, 5}, {4, 4}}, want: 1},
+	} {
+		pop := &genetics.Population{Species: s}
+		for _, g := range test.genes {
+			pop.Chromosomes = append(pop.Chromosomes, s.New(g...))
+		}
+		pop.Fitness = fitness
+		sel.Learn(pop)
+		if got := sel.SelectParents(rng, 3, fitness); !cmp.Equal(got, []int{test.want, test.want, test.want}) {
+			t.Errorf("%s: SelectParents()=%v; want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestConstrainedTournamentSelectionEngine(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	const numGenes, limit = 8, 12
+	s := genetics.NewSpecies(numGenes, 5)
+	constraints := genetics.Constraints{genetics.SumAtMost{Loci: []int{0, 1, 2, 3, 4, 5, 6, 7}, Max: limit}}
+	pop, err := s.NewRandPopulation(rng, 40)
+	if err != nil {
+		t.Fatalf("NewRandPopulation(); err=%s", err)
+	}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 40,
+			CrossoverRate:    0.9,
+			MutationRate:     0.5,
+			Selector:         &genetics.ConstrainedTournamentSelection{Size: 2, Constraints: constraints},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator:          genetics.RandomResettingMutation{},
+		},
+		// The sum of the genes rewards violating the constraint, and replacing the
+		// whole population keeps the Evolver from preferring to replace feasible
+		// members, so only the selector steers toward feasibility
+		Evaluator: genetics.FitnessFunc(func(c genetics.Chromosome) genetics.Fitness {
+			sum := 0
+			for _, g := range c.Genes {
+				sum += g
+			}
+			return genetics.Fitness(sum)
+		}),
+	}
+	if err := engine.Run(rng, pop, 50); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	feasible, best := 0, genetics.Fitness(0)
+	for i, c := range pop.Chromosomes {
+		if constraints.Violation(c) == 0 {
+			feasible++
+			if pop.Fitness[i] > best {
+				best = pop.Fitness[i]
+			}
+		}
+	}
+	if feasible < len(pop.Chromosomes)/2 || best != limit {
+		t.Errorf("Run() left %d of %d feasible with best fitness %g; want most feasible with fitness %d", feasible, len(pop.Chromosomes), best, limit)
+	}
+}
@@ -0,0 +1,94 @@
+package genetics
+
+import "fmt"
+
+// ContextualOperator is implemented by operators (Crossovers, Mutators, and
+// LocalSearches) which need data about the problem being solved, such as a
+// distance matrix or item weights. Each generation, an Engine replaces its
+// ContextualOperators with the result of WithProblem(Engine.Problem), so that
+// domain-aware operators need not read problem data from global variables.
+// CompositeCrossover, CompositeMutator, ConstrainedMutation, and HillClimbing
+// pass the problem on to the operators they wrap; adaptive operators do not.
+type ContextualOperator interface {
+	// WithProblem returns a copy of the operator configured for problem, which
+	// must implement the same operator interface. Operators which are already
+	// configured should return themselves unchanged; operators which cannot use
+	// problem should return an error.
+	WithProblem(problem interface{}) (interface{}, error)
+}
+
+// EdgeWeighter is a problem whose alleles are joined by weighted edges, e.g. the
+// cities of a routing problem. GreedyCrossover takes its Weight from an
+// EdgeWeighter or EdgeWeightFunc problem.
+type EdgeWeighter interface {
+	EdgeWeight(from, to Gene) float64
+}
+
+// withProblem returns op configured for problem if it is a ContextualOperator
+// and op itself otherwise.
+func withProblem(op interface{}, problem interface{}) (interface{}, error) {
+	c, ok := op.(ContextualOperator)
+	if !ok {
+		return op, nil
+	}
+	return c.WithProblem(problem)
+}
+
+// crossoverWithProblem is withProblem for Crossovers.
+func crossoverWithProblem(c Crossover, problem interface{}) (Crossover, error) {
+	if c == nil {
+		return nil, nil
+	}
+	op, err := withProblem(c, problem)
+	if err != nil {
+		return nil, err
+	}
+	x, ok := op.(Crossover)
+	if !ok {
+		return nil, fmt.Errorf("%s.WithProblem() returned %T, which is not a Crossover", c, op)
+	}
+	return x, nil
+}
+
+// mutatorWithProblem is withProblem for Mutators.
+func mutatorWithProblem(m Mutator, problem interface{}) (Mutator, error) {
+	if m == nil {
+		return nil, nil
+	}
+	op, err := withProblem(m, problem)
+	if err != nil {
+		return nil, err
+	}
+	x, ok := op.(Mutator)
+	if !ok {
+		return nil, fmt.Errorf("%s.WithProblem() returned %T, which is not a Mutator", m, op)
+	}
+	return x, nil
+}
+
+// localSearchWithProblem is withProblem for LocalSearches.
+func localSearchWithProblem(l LocalSearch, problem interface{}) (LocalSearch, error) {
+	if l == nil {
+		return nil, nil
+	}
+	op, err := withProblem(l, problem)
+	if err != nil {
+		return nil, err
+	}
+	x, ok := op.(LocalSearch)
+	if !ok {
+		return nil, fmt.Errorf("%s.WithProblem() returned %T, which is not a LocalSearch", l, op)
+	}
+	return x, nil
+}
+
+// withProblem returns a copy of e whose Crossover and Mutator are configured for
+// problem.
+func (e Evolver) withProblem(problem interface{}) (Evolver, error) {
+	var err error
+	if e.Crossover, err = crossoverWithProblem(e.Crossover, problem); err != nil {
+		return e, err
+	}
+	e.Mutator, err = mutatorWithProblem(e.Mutator, problem)
+	return e, err
+}
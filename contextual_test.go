@@ -0,0 +1,111 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+// problemMutator records the problem it was given each time it mutates.
+type problemMutator struct {
+	problem interface{}
+	seen    *[]interface{}
+}
+
+func (problemMutator) String() string {
+	return "ProblemMutator"
+}
+
+func (m problemMutator) WithProblem(problem interface{}) (interface{}, error) {
+	m.problem = problem
+	return m, nil
+}
+
+func (m problemMutator) Mutate(r rand.Rand, c *genetics.Chromosome) {
+	*m.seen = append(*m.seen, m.problem)
+}
+
+func TestContextualOperator(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	var mutated, searched []interface{}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 4,
+			CrossoverRate:    1,
+			MutationRate:     1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator: genetics.CompositeMutator{Mutators: []genetics.Mutator{
+				genetics.ConstrainedMutation{Mutator: problemMutator{seen: &mutated}},
+			}},
+		},
+		Evaluator:         genetics.FitnessFunc(oneMax),
+		LocalSearch:       genetics.HillClimbing{Mutator: problemMutator{seen: &searched}},
+		LocalSearchBudget: 1,
+		Problem:           "weights",
+	}
+	if err := engine.Run(rng, newBinaryPopulation(t, rng, 4, 8), 3); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	want := []interface{}{"weights", "weights", "weights", "weights"}
+	if diff := cmp.Diff(want, mutated[:4]); diff != "" || len(mutated) != 12 {
+		t.Errorf("wrapped Mutator saw problems %v; want 12 of weights; diff=%s", mutated, diff)
+	}
+	if diff := cmp.Diff(want, searched[:4]); diff != "" || len(searched) != 12 {
+		t.Errorf("LocalSearch Mutator saw problems %v; want 12 of weights; diff=%s", searched, diff)
+	}
+	if m := engine.Evolver.Mutator.(genetics.CompositeMutator).Mutators[0].(genetics.ConstrainedMutation).Mutator.(problemMutator); m.problem != nil {
+		t.Errorf("Engine modified its Evolver's Mutator; problem=%v", m.problem)
+	}
+}
+
+// lineTour is a routing problem whose cities are spaced evenly along a line.
+type lineTour struct{}
+
+func (lineTour) EdgeWeight(from, to genetics.Gene) float64 {
+	return lineDistance(from, to)
+}
+
+func TestEngineGreedyCrossover(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	s := genetics.NewSpecies(20, 19)
+	pop, err := s.NewPermPopulation(rng, 20)
+	if err != nil {
+		t.Fatalf("NewPermPopulation(); err=%s", err)
+	}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 10,
+			CrossoverRate:    1,
+			Selector:         genetics.TournamentSelection{Size: 3},
+			Crossover:        genetics.CompositeCrossover{Crossovers: []genetics.Crossover{genetics.GreedyCrossover{}}},
+			Mutator:          genetics.SwapMutation{},
+		},
+		Evaluator: genetics.FitnessFunc(func(c genetics.Chromosome) genetics.Fitness {
+			return genetics.Fitness(-tourLength(c, lineDistance))
+		}),
+		Problem: lineTour{},
+	}
+	if err := engine.Run(rng, pop, 10); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	// The shortest tour visits the cities in order and returns
+	if _, best := pop.Best(); best < -2*19*1.5 {
+		t.Errorf("GreedyCrossover found a tour of length %g; want near %d", -best, 2*19)
+	}
+
+	engine.Problem = nil
+	if err := engine.Run(rng, pop, 1); err == nil {
+		t.Error("Run() should fail when GreedyCrossover has no edge weights")
+	}
+
+	engine.Problem = genetics.EdgeWeightFunc(lineDistance)
+	if err := engine.Run(rng, pop, 1); err != nil {
+		t.Errorf("Run() with an EdgeWeightFunc problem; err=%s", err)
+	}
+}
@@ -0,0 +1,96 @@
+package genetics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/inlined/rand"
+)
+
+// Elites returns a copy of the n fittest distinct members of p, fittest first, for
+// use as a seed corpus. Duplicates are dropped so that a converged population
+// does not fill the corpus with copies of its best member. p must be scored.
+func (p Population) Elites(n int) Population {
+	sorted := p.Clone()
+	sorted.Sort()
+	elites := Population{Species: p.Species, Objective: p.Objective, Fitness: []Fitness{}}
+	seen := make(map[string]bool)
+	for i, c := range sorted.Chromosomes {
+		if len(elites.Chromosomes) == n {
+			break
+		}
+		key := genesKey(c.Genes)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		elites.Chromosomes = append(elites.Chromosomes, c)
+		elites.Fitness = append(elites.Fitness, sorted.Fitness[i])
+	}
+	return elites
+}
+
+// SaveElites atomically replaces path with the n fittest distinct members of p
+// and their Fitness, so that a later run of the same Species can be warm-started
+// from them with LoadElites and SeedPopulation.
+func (p Population) SaveElites(path string, n int) error {
+	if err := writeJSON(path, p.Elites(n)); err != nil {
+		return fmt.Errorf("Population.SaveElites(%s); err=%s", path, err)
+	}
+	return nil
+}
+
+// LoadElites reads the elites saved to path by SaveElites. They must have been
+// saved from a Population of a Species equal to s, and are returned as members
+// of s. If path does not exist, the error from os.ReadFile is returned as is, so
+// that the first run of an iterative workflow can detect it with os.IsNotExist.
+func (s *Species) LoadElites(path string) (*Population, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pop := &Population{}
+	if err := json.Unmarshal(b, pop); err != nil {
+		return nil, fmt.Errorf("Species.LoadElites(%s); err=%s", path, err)
+	}
+	if !s.Equal(pop.Species) {
+		return nil, fmt.Errorf("Species.LoadElites(%s); elites of a different Species", path)
+	}
+	pop.Species = s
+	for i := range pop.Chromosomes {
+		pop.Chromosomes[i].Species = s
+	}
+	return pop, nil
+}
+
+// SeedPopulation creates a Population of size Chromosomes of s which starts with
+// copies of seeds, e.g. the elites of an earlier run, and is filled with
+// Chromosomes created by init, which defaults to s.NewRand. If there are more
+// than size seeds, only the first size are used. Every seed is validated against
+// s, but none is scored; an Engine evaluates the whole population when it is
+// reset, in case the fitness function has changed since the seeds were scored.
+func (s *Species) SeedPopulation(r rand.Rand, seeds []Chromosome, size int, init func(r rand.Rand) (Chromosome, error)) (*Population, error) {
+	if init == nil {
+		init = s.NewRand
+	}
+	pop := &Population{
+		Species:     s,
+		Chromosomes: make([]Chromosome, size),
+	}
+	for i := range pop.Chromosomes {
+		if i < len(seeds) {
+			if err := s.validateGenes(seeds[i].Genes); err != nil {
+				return nil, fmt.Errorf("Species.SeedPopulation(); seed %d: %s", i, err)
+			}
+			pop.Chromosomes[i] = Chromosome{Species: s, Genes: append([]Gene(nil), seeds[i].Genes...)}
+			continue
+		}
+		c, err := init(r)
+		if err != nil {
+			return nil, fmt.Errorf("Species.SeedPopulation(); err=%s", err)
+		}
+		pop.Chromosomes[i] = c
+	}
+	return pop, nil
+}
@@ -0,0 +1,112 @@
+package genetics_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestElites(t *testing.T) {
+	s := genetics.NewSpecies(2, 5)
+	pop := genetics.Population{
+		Species: s,
+		Chromosomes: []genetics.Chromosome{
+			s.New(1, 1), s.New(5, 5), s.New(2, 2), s.New(5, 5), s.New(3, 3),
+		},
+		Fitness:   []genetics.Fitness{2, 10, 4, 10, 6},
+		Objective: genetics.Maximize,
+	}
+	elites := pop.Elites(3)
+	var genes [][]genetics.Gene
+	for _, c := range elites.Chromosomes {
+		genes = append(genes, c.Genes)
+	}
+	if diff := cmp.Diff([][]genetics.Gene{{5, 5}, {3, 3}, {2, 2}}, genes); diff != "" {
+		t.Errorf("Elites(3) got=%v diff=%s", genes, diff)
+	}
+	if diff := cmp.Diff([]genetics.Fitness{10, 6, 4}, elites.Fitness); diff != "" {
+		t.Errorf("Elites(3) fitness diff=%s", diff)
+	}
+	// The elites are copies
+	elites.Chromosomes[0].Genes[0] = 0
+	if pop.Chromosomes[1].Genes[0] != 5 {
+		t.Error("Elites() shares Genes with the Population")
+	}
+
+	pop.Objective = genetics.Minimize
+	if got := pop.Elites(10).Fitness; !cmp.Equal(got, []genetics.Fitness{2, 4, 6, 10}) {
+		t.Errorf("Elites(10) minimizing fitness=%v; want [2 4 6 10]", got)
+	}
+}
+
+func TestSaveAndLoadElites(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	s := genetics.NewSpecies(8, 1)
+	path := filepath.Join(t.TempDir(), "elites.json")
+	if _, err := s.LoadElites(path); !os.IsNotExist(err) {
+		t.Fatalf("LoadElites() of a missing file; err=%v, want a not-exist error", err)
+	}
+
+	// Each run warm-starts from the elites of the last
+	var seeds []genetics.Chromosome
+	var best []genetics.Fitness
+	for run := 0; run < 3; run++ {
+		pop, err := s.SeedPopulation(rng, seeds, 20, nil)
+		if err != nil {
+			t.Fatalf("SeedPopulation(); err=%s", err)
+		}
+		engine := genetics.Engine{
+			Evolver: genetics.Evolver{
+				ReplacementCount: 10,
+				CrossoverRate:    0.9,
+				MutationRate:     0.1,
+				Selector:         genetics.TournamentSelection{Size: 2},
+				Crossover:        genetics.MultiPointCrossover{Points: 1},
+				Mutator:          genetics.RandomResettingMutation{},
+			},
+			// OneMax
+			Evaluator: genetics.FitnessFunc(func(c genetics.Chromosome) genetics.Fitness {
+				sum := 0
+				for _, g := range c.Genes {
+					sum += g
+				}
+				return genetics.Fitness(sum)
+			}),
+		}
+		if err := engine.Run(rng, pop, 3); err != nil {
+			t.Fatalf("Run(); err=%s", err)
+		}
+		_, f := pop.Best()
+		best = append(best, f)
+		if err := pop.SaveElites(path, 5); err != nil {
+			t.Fatalf("SaveElites(); err=%s", err)
+		}
+		elites, err := s.LoadElites(path)
+		if err != nil {
+			t.Fatalf("LoadElites(); err=%s", err)
+		}
+		// A converged population may have fewer than 5 distinct members
+		if elites.Species != s || len(elites.Chromosomes) == 0 || len(elites.Chromosomes) > 5 || elites.Fitness[0] != f {
+			t.Fatalf("LoadElites()=%d elites, best %g; want up to 5 of %v, best %g", len(elites.Chromosomes), elites.Fitness[0], s, f)
+		}
+		seeds = elites.Chromosomes
+	}
+	for run := 1; run < len(best); run++ {
+		if best[run] < best[run-1] {
+			t.Errorf("warm-started runs got best fitness %v; want no regression", best)
+		}
+	}
+
+	if _, err := genetics.NewSpecies(8, 2).LoadElites(path); err == nil {
+		t.Error("LoadElites() should reject elites of a different Species")
+	}
+	if _, err := genetics.NewSpecies(8, 0).SeedPopulation(rng, seeds, 10, nil); err == nil {
+		t.Error("SeedPopulation() should reject seeds which are not members of the Species")
+	}
+}
@@ -12,6 +12,8 @@ const (
 	multiPointCrossover          = "MultiPointCrossover"
 	wholeArithmeticRecombination = "WholeArithmeticRecombination"
 	davisOrderCrossover          = "DavisOrderCrossover"
+	partiallyMappedCrossover     = "PartiallyMappedCrossover"
+	greedyCrossover              = "GreedyCrossover"
 )
 
 // Crossover is a strategy for generating two children based
@@ -21,6 +23,16 @@ type Crossover interface {
 	Crossover(r rand.Rand, a, b Chromosome) (x, y Chromosome)
 }
 
+// CrossoverInto is implemented by Crossovers which can write their children into
+// existing Chromosomes rather than allocating them; see Engine.ReuseBuffers.
+// CrossoverInto overwrites every gene of x and y, which are Chromosomes of the
+// parents' Species, with the same children Crossover would produce from the same
+// random stream.
+type CrossoverInto interface {
+	Crossover
+	CrossoverInto(r rand.Rand, a, b, x, y Chromosome)
+}
+
 // MultiPointCrossover is a generalization of the Crossover method.
 // N crossover points are selected and children are made of parens'
 // chromosomes alternating sources at the crossover points.
@@ -35,24 +47,41 @@ func (c MultiPointCrossover) String() string {
 	return fmt.Sprintf("%s(%d)", multiPointCrossover, c.Points)
 }
 
+// ConcurrentSafe implements ConcurrentSafe
+func (MultiPointCrossover) ConcurrentSafe() bool {
+	return true
+}
+
 // Crossover imnplements Crossover.
-// Inefficiency: This algorithm makes n^2 data copies because it assumes
-// N is ~1-3
+// Each gene is copied once: the children fill each segment between crossover
+// points from alternating parents.
 func (c MultiPointCrossover) Crossover(r rand.Rand, a, b Chromosome) (x, y Chromosome) {
+	x, y = a.Species.New(), a.Species.New()
+	c.CrossoverInto(r, a, b, x, y)
+	return x, y
+}
+
+// CrossoverInto implements CrossoverInto
+func (c MultiPointCrossover) CrossoverInto(r rand.Rand, a, b, x, y Chromosome) {
 	s := a.Species
-	x = s.New()
-	y = s.New()
-	temp := s.New()
-	copy(x.Genes[:], a.Genes[:])
-	copy(y.Genes[:], b.Genes[:])
 	indexes := rand.Deal(r, s.NumGenes, c.Points)
 	sort.Ints(indexes)
-	for _, n := range indexes {
-		copy(temp.Genes[n:], x.Genes[n:])
-		copy(x.Genes[n:], y.Genes[n:])
-		copy(y.Genes[n:], temp.Genes[n:])
+	crossSegments(a, b, x, y, indexes)
+}
+
+// crossSegments writes into x and y the children of a and b which alternate
+// parents at each of the sorted indexes.
+func crossSegments(a, b, x, y Chromosome, indexes []int) {
+	from, to := a.Genes, b.Genes
+	start := 0
+	for _, end := range indexes {
+		copy(x.Genes[start:end], from[start:end])
+		copy(y.Genes[start:end], to[start:end])
+		from, to = to, from
+		start = end
 	}
-	return x, y
+	copy(x.Genes[start:], from[start:])
+	copy(y.Genes[start:], to[start:])
 }
 
 // WholeArithmeticRecombination picks a random float weight from 0-1. The children are
@@ -65,12 +94,22 @@ func (WholeArithmeticRecombination) String() string {
 	return wholeArithmeticRecombination
 }
 
+// ConcurrentSafe implements ConcurrentSafe
+func (WholeArithmeticRecombination) ConcurrentSafe() bool {
+	return true
+}
+
 // Crossover implements Crossover
 func (c WholeArithmeticRecombination) Crossover(r rand.Rand, a, b Chromosome) (x, y Chromosome) {
+	x, y = a.Species.New(), a.Species.New()
+	c.CrossoverInto(r, a, b, x, y)
+	return x, y
+}
+
+// CrossoverInto implements CrossoverInto
+func (c WholeArithmeticRecombination) CrossoverInto(r rand.Rand, a, b, x, y Chromosome) {
 	f := r.Float64()
 	s := a.Species
-	x = s.New()
-	y = s.New()
 	for i := 0; i < s.NumGenes; i++ {
 		// Because we're dealing with integers, a strict linear interpolation
 		// will floor twice.
@@ -81,7 +120,6 @@ func (c WholeArithmeticRecombination) Crossover(r rand.Rand, a, b Chromosome) (x
 		d := x.Genes[i] - a.Genes[i]
 		y.Genes[i] = b.Genes[i] - d
 	}
-	return x, y
 }
 
 // DavisOrderCrossover aka OX1 picks two crossover points, dividing the genomes
@@ -94,20 +132,39 @@ func (DavisOrderCrossover) String() string {
 	return davisOrderCrossover
 }
 
+// ConcurrentSafe implements ConcurrentSafe
+func (DavisOrderCrossover) ConcurrentSafe() bool {
+	return true
+}
+
 // Crossover implements Crossover
 func (c DavisOrderCrossover) Crossover(r rand.Rand, a, b Chromosome) (x, y Chromosome) {
+	x, y = a.Species.New(), a.Species.New()
+	c.CrossoverInto(r, a, b, x, y)
+	return x, y
+}
 
+// CrossoverInto implements CrossoverInto
+func (c DavisOrderCrossover) CrossoverInto(r rand.Rand, a, b, x, y Chromosome) {
 	indexes := rand.Deal(r, len(b.Genes)+1, 2)
 	if indexes[0] > indexes[1] {
 		indexes[0], indexes[1] = indexes[1], indexes[0]
 	}
-	return davisCrossoverOne(a, b, indexes[0], indexes[1]), davisCrossoverOne(b, a, indexes[0], indexes[1])
+	seen := make([]bool, a.Species.NumGenes)
+	davisCrossoverOne(a, b, x, indexes[0], indexes[1], seen)
+	for i := range seen {
+		seen[i] = false
+	}
+	davisCrossoverOne(b, a, y, indexes[0], indexes[1], seen)
 }
 
-func davisCrossoverOne(p1, p2 Chromosome, lower, upper int) Chromosome {
+// davisCrossoverOne writes the child of p1 and p2 into child. seen must be all false.
+func davisCrossoverOne(p1, p2, child Chromosome, lower, upper int, seen []bool) {
 	s := p1.Species
-	child := s.New()
-	seen := make([]bool, s.NumGenes)
+	// Genes which are not filled (if p2 is not a permutation) are 0.
+	for i := range child.Genes {
+		child.Genes[i] = 0
+	}
 
 	// 1. Preserve the range [lower, upper) of p1
 	for i := lower; i < upper; i++ {
@@ -125,5 +182,156 @@ func davisCrossoverOne(p1, p2 Chromosome, lower, upper int) Chromosome {
 		child.Genes[insert] = p2.Genes[read]
 		insert = (insert + 1) % s.NumGenes
 	}
-	return child
+}
+
+// PartiallyMappedCrossover aka PMX picks two crossover points. Each child keeps
+// the middle segment of one parent and takes the rest of its genes from the other
+// parent in place, following the mapping between the two segments for genes that
+// would otherwise repeat. PMX is appropriate for permutative genes and preserves
+// more absolute positions than OX1.
+type PartiallyMappedCrossover struct{}
+
+func (PartiallyMappedCrossover) String() string {
+	return partiallyMappedCrossover
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (PartiallyMappedCrossover) ConcurrentSafe() bool {
+	return true
+}
+
+// Crossover implements Crossover
+func (c PartiallyMappedCrossover) Crossover(r rand.Rand, a, b Chromosome) (x, y Chromosome) {
+	x, y = a.Species.New(), a.Species.New()
+	c.CrossoverInto(r, a, b, x, y)
+	return x, y
+}
+
+// CrossoverInto implements CrossoverInto
+func (c PartiallyMappedCrossover) CrossoverInto(r rand.Rand, a, b, x, y Chromosome) {
+	indexes := rand.Deal(r, len(b.Genes)+1, 2)
+	if indexes[0] > indexes[1] {
+		indexes[0], indexes[1] = indexes[1], indexes[0]
+	}
+	segment := make([]int, a.Species.NumGenes)
+	pmxCrossoverOne(a, b, x, indexes[0], indexes[1], segment)
+	pmxCrossoverOne(b, a, y, indexes[0], indexes[1], segment)
+}
+
+// pmxCrossoverOne writes the child of p1 and p2 into child, using segment as scratch.
+func pmxCrossoverOne(p1, p2, child Chromosome, lower, upper int, segment []int) {
+	s := p1.Species
+	// segment[g] is the index of gene g in the range [lower, upper) of p1, or -1
+	for i := range segment {
+		segment[i] = -1
+	}
+	for i := lower; i < upper; i++ {
+		segment[p1.Genes[i]] = i
+		child.Genes[i] = p1.Genes[i]
+	}
+	for i := 0; i < s.NumGenes; i++ {
+		if i >= lower && i < upper {
+			continue
+		}
+		g := p2.Genes[i]
+		for segment[g] != -1 {
+			g = p2.Genes[segment[g]]
+		}
+		child.Genes[i] = g
+	}
+}
+
+// EdgeWeightFunc returns the cost of visiting allele to immediately after allele
+// from, e.g. the distance between two cities of a tour. It must be goroutine safe
+// if it is shared by Engines running concurrently.
+type EdgeWeightFunc func(from, to Gene) float64
+
+// GreedyCrossover builds each child of two permutation-encoded tours from a random
+// starting allele by repeatedly following the cheaper of the parents' edges from
+// the current allele to an allele not yet visited. If both parents' edges lead to
+// visited alleles, it follows the cheapest edge to any unvisited allele. Tours are
+// cyclic, so the first allele follows the last. If Weight is nil, an Engine sets
+// it from its Problem; see ContextualOperator.
+type GreedyCrossover struct {
+	Weight EdgeWeightFunc
+}
+
+func (GreedyCrossover) String() string {
+	return greedyCrossover
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (GreedyCrossover) ConcurrentSafe() bool {
+	return true
+}
+
+// WithProblem implements ContextualOperator
+func (c GreedyCrossover) WithProblem(problem interface{}) (interface{}, error) {
+	if c.Weight != nil {
+		return c, nil
+	}
+	switch p := problem.(type) {
+	case EdgeWeightFunc:
+		c.Weight = p
+	case EdgeWeighter:
+		c.Weight = p.EdgeWeight
+	default:
+		return nil, fmt.Errorf("GreedyCrossover.WithProblem(); Weight is nil and problem %T is not an EdgeWeighter", problem)
+	}
+	return c, nil
+}
+
+// Crossover implements Crossover
+func (c GreedyCrossover) Crossover(r rand.Rand, a, b Chromosome) (x, y Chromosome) {
+	x, y = a.Species.New(), a.Species.New()
+	c.CrossoverInto(r, a, b, x, y)
+	return x, y
+}
+
+// CrossoverInto implements CrossoverInto
+func (c GreedyCrossover) CrossoverInto(r rand.Rand, a, b, x, y Chromosome) {
+	n := len(a.Genes)
+	// succ[g] and succ[n+g] are the alleles after g in a and b respectively
+	succ := make([]Gene, 2*n)
+	for i := range a.Genes {
+		succ[a.Genes[i]] = a.Genes[(i+1)%n]
+		succ[n+b.Genes[i]] = b.Genes[(i+1)%n]
+	}
+	visited := make([]bool, n)
+	c.greedyOne(r, succ, x, visited)
+	for i := range visited {
+		visited[i] = false
+	}
+	c.greedyOne(r, succ, y, visited)
+}
+
+// greedyOne writes a greedy tour along the edges succ into child. visited must be
+// all false.
+func (c GreedyCrossover) greedyOne(r rand.Rand, succ []Gene, child Chromosome, visited []bool) {
+	n := len(visited)
+	cur := Gene(r.Int31n(int32(n)))
+	child.Genes[0], visited[cur] = cur, true
+	for i := 1; i < n; i++ {
+		next, best := Gene(-1), 0.0
+		for _, g := range [2]Gene{succ[cur], succ[n+cur]} {
+			if visited[g] {
+				continue
+			}
+			if w := c.Weight(cur, g); next < 0 || w < best {
+				next, best = g, w
+			}
+		}
+		if next < 0 {
+			for g := range visited {
+				if visited[g] {
+					continue
+				}
+				if w := c.Weight(cur, Gene(g)); next < 0 || w < best {
+					next, best = Gene(g), w
+				}
+			}
+		}
+		child.Genes[i], visited[next] = next, true
+		cur = next
+	}
 }
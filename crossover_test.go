@@ -1,6 +1,9 @@
 package genetics_test
 
 import (
+	"fmt"
+	"math"
+	"sort"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -105,6 +108,22 @@ func TestCrossover(t *testing.T) {
 			p2:       []genetics.Gene{4, 3, 2, 1, 0},
 			c1:       []genetics.Gene{0, 1, 2, 4, 3},
 			c2:       []genetics.Gene{4, 3, 2, 0, 1},
+		}, {
+			tag:      "PMX",
+			strategy: genetics.PartiallyMappedCrossover{},
+			rand:     xkcd.Rand(1, 3),
+			p1:       []genetics.Gene{0, 1, 2, 3, 4},
+			p2:       []genetics.Gene{4, 3, 2, 1, 0},
+			c1:       []genetics.Gene{4, 1, 2, 3, 0},
+			c2:       []genetics.Gene{0, 3, 2, 1, 4},
+		}, {
+			tag:      "PMX follows the mapping",
+			strategy: genetics.PartiallyMappedCrossover{},
+			rand:     xkcd.Rand(2, 5),
+			p1:       []genetics.Gene{0, 1, 2, 3, 4},
+			p2:       []genetics.Gene{2, 3, 4, 0, 1},
+			c1:       []genetics.Gene{1, 0, 2, 3, 4},
+			c2:       []genetics.Gene{3, 2, 4, 0, 1},
 		},
 	} {
 		t.Run(test.tag, func(t *testing.T) {
@@ -129,3 +148,146 @@ func TestCrossover(t *testing.T) {
 		})
 	}
 }
+
+// multiPointReference is the original MultiPointCrossover, which swaps the tails
+// of the children at each crossover point.
+func multiPointReference(r rand.Rand, points int, a, b genetics.Chromosome) (x, y genetics.Chromosome) {
+	s := a.Species
+	x, y, temp := s.New(a.Genes...), s.New(b.Genes...), s.New()
+	indexes := rand.Deal(r, s.NumGenes, points)
+	sort.Ints(indexes)
+	for _, n := range indexes {
+		copy(temp.Genes[n:], x.Genes[n:])
+		copy(x.Genes[n:], y.Genes[n:])
+		copy(y.Genes[n:], temp.Genes[n:])
+	}
+	return x, y
+}
+
+func TestMultiPointCrossoverMatchesReference(t *testing.T) {
+	ref, rng := rand.New(), rand.New()
+	ref.Seed(42)
+	rng.Seed(42)
+	s := genetics.NewSpecies(50, 1000)
+	for _, points := range []int{1, 2, 3, 8, 49} {
+		for i := 0; i < 20; i++ {
+			a, _ := s.NewRand(ref)
+			b, _ := s.NewRand(ref)
+			rng.Seed(int64(points*100 + i))
+			ref.Seed(int64(points*100 + i))
+			wantX, wantY := multiPointReference(ref, points, a, b)
+			gotX, gotY := genetics.MultiPointCrossover{Points: points}.Crossover(rng, a, b)
+			if diff := cmp.Diff(wantX.Genes, gotX.Genes); diff != "" {
+				t.Fatalf("MultiPointCrossover(%d) first child differs from the reference; diff=%s", points, diff)
+			}
+			if diff := cmp.Diff(wantY.Genes, gotY.Genes); diff != "" {
+				t.Fatalf("MultiPointCrossover(%d) second child differs from the reference; diff=%s", points, diff)
+			}
+		}
+	}
+}
+
+func BenchmarkMultiPointCrossover(b *testing.B) {
+	for _, numGenes := range []int{100, 10000} {
+		for _, points := range []int{1, 2, 8} {
+			rng := rand.New()
+			s := genetics.NewSpecies(numGenes, 1)
+			pa, _ := s.NewRand(rng)
+			pb, _ := s.NewRand(rng)
+			c := genetics.MultiPointCrossover{Points: points}
+			b.Run(fmt.Sprintf("%d/%d", numGenes, points), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					c.Crossover(rng, pa, pb)
+				}
+			})
+			b.Run(fmt.Sprintf("%d/%d/reference", numGenes, points), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					multiPointReference(rng, points, pa, pb)
+				}
+			})
+		}
+	}
+}
+
+func TestCrossoverInto(t *testing.T) {
+	s := genetics.NewSpecies(20, 19)
+	for _, c := range []genetics.CrossoverInto{
+		genetics.MultiPointCrossover{Points: 3},
+		genetics.WholeArithmeticRecombination{},
+		genetics.DavisOrderCrossover{},
+		genetics.PartiallyMappedCrossover{},
+		genetics.GreedyCrossover{Weight: lineDistance},
+	} {
+		rng := rand.New()
+		rng.Seed(42)
+		for i := 0; i < 20; i++ {
+			a, _ := s.NewPerm(rng)
+			b, _ := s.NewPerm(rng)
+			// Stale genes must be overwritten.
+			x, _ := s.NewRand(rng)
+			y, _ := s.NewRand(rng)
+			state := rng.Int63n(1 << 62)
+			rng.Seed(state)
+			wantX, wantY := c.Crossover(rng, a, b)
+			rng.Seed(state)
+			c.CrossoverInto(rng, a, b, x, y)
+			if diff := cmp.Diff(wantX.Genes, x.Genes); diff != "" {
+				t.Fatalf("%s.CrossoverInto() first child differs from Crossover; diff=%s", c, diff)
+			}
+			if diff := cmp.Diff(wantY.Genes, y.Genes); diff != "" {
+				t.Fatalf("%s.CrossoverInto() second child differs from Crossover; diff=%s", c, diff)
+			}
+		}
+	}
+}
+
+// lineDistance is the distance between cities spaced evenly along a line.
+func lineDistance(from, to genetics.Gene) float64 {
+	return math.Abs(float64(from - to))
+}
+
+// tourLength is the length of the cyclic tour c under weight.
+func tourLength(c genetics.Chromosome, weight genetics.EdgeWeightFunc) float64 {
+	total := 0.0
+	for i, g := range c.Genes {
+		total += weight(g, c.Genes[(i+1)%len(c.Genes)])
+	}
+	return total
+}
+
+func TestGreedyCrossover(t *testing.T) {
+	s := genetics.NewSpecies(6, 5)
+	a, b := s.New(0, 1, 2, 3, 4, 5), s.New(0, 2, 4, 1, 3, 5)
+	c := genetics.GreedyCrossover{Weight: lineDistance}
+	// The children start from 0 and 3 and prefer a's shorter edges
+	x, y := c.Crossover(xkcd.Rand(0, 3), a, b)
+	if diff := cmp.Diff([]genetics.Gene{0, 1, 2, 3, 4, 5}, x.Genes); diff != "" {
+		t.Errorf("GreedyCrossover first child; diff=%s", diff)
+	}
+	if diff := cmp.Diff([]genetics.Gene{3, 4, 5, 0, 1, 2}, y.Genes); diff != "" {
+		t.Errorf("GreedyCrossover second child; diff=%s", diff)
+	}
+
+	// Children of random tours are permutations and shorter than their parents
+	rng := rand.New()
+	rng.Seed(42)
+	s = genetics.NewSpecies(30, 29)
+	var parents, children float64
+	for i := 0; i < 100; i++ {
+		a, _ := s.NewPerm(rng)
+		b, _ := s.NewPerm(rng)
+		x, y := c.Crossover(rng, a, b)
+		for _, child := range []genetics.Chromosome{x, y} {
+			if err := s.ValidatePermutation(child); err != nil {
+				t.Fatalf("GreedyCrossover produced %v; err=%s", child.Genes, err)
+			}
+		}
+		parents += tourLength(a, lineDistance) + tourLength(b, lineDistance)
+		children += tourLength(x, lineDistance) + tourLength(y, lineDistance)
+	}
+	if children >= 0.9*parents {
+		t.Errorf("GreedyCrossover children have total length %g; want well under parents' %g", children, parents)
+	}
+}
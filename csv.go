@@ -0,0 +1,90 @@
+package genetics
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// fitnessColumn is the header of the optional fitness column in CSV exports
+const fitnessColumn = "fitness"
+
+// ExportCSV writes p with one row per Chromosome and one column per gene under a
+// header row of gene0, gene1, ... If withFitness is set, a final "fitness"
+// column holds p.Fitness, which must then score every Chromosome.
+func (p Population) ExportCSV(w io.Writer, withFitness bool) error {
+	if withFitness && len(p.Fitness) != len(p.Chromosomes) {
+		return fmt.Errorf("ExportCSV(); %d Fitness for %d Chromosomes", len(p.Fitness), len(p.Chromosomes))
+	}
+	out := csv.NewWriter(w)
+	header := make([]string, p.Species.NumGenes)
+	for i := range header {
+		header[i] = fmt.Sprintf("gene%d", i)
+	}
+	if withFitness {
+		header = append(header, fitnessColumn)
+	}
+	if err := out.Write(header); err != nil {
+		return err
+	}
+
+	row := make([]string, len(header))
+	for n, c := range p.Chromosomes {
+		for i, g := range c.Genes {
+			row[i] = strconv.Itoa(g)
+		}
+		if withFitness {
+			row[len(row)-1] = strconv.FormatFloat(float64(p.Fitness[n]), 'g', -1, 64)
+		}
+		if err := out.Write(row); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// ImportCSV reads a Population of s in the format written by ExportCSV, e.g. after
+// it was edited in a spreadsheet. The header row is required and the fitness column
+// is optional. Every Chromosome is validated against s.
+func (s *Species) ImportCSV(r io.Reader) (*Population, error) {
+	in := csv.NewReader(r)
+	header, err := in.Read()
+	if err != nil {
+		return nil, fmt.Errorf("ImportCSV(); cannot read header: %s", err)
+	}
+	withFitness := len(header) == s.NumGenes+1 && header[s.NumGenes] == fitnessColumn
+	if len(header) != s.NumGenes && !withFitness {
+		return nil, fmt.Errorf("ImportCSV(); header has %d columns; expected %d genes and an optional %s column", len(header), s.NumGenes, fitnessColumn)
+	}
+
+	pop := &Population{Species: s}
+	for line := 2; ; line++ {
+		row, err := in.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ImportCSV(); err=%s", err)
+		}
+		c := s.New()
+		for i := range c.Genes {
+			if c.Genes[i], err = strconv.Atoi(row[i]); err != nil {
+				return nil, fmt.Errorf("ImportCSV(); line %d: gene %d: %s", line, i, err)
+			}
+		}
+		if err := s.validateGenes(c.Genes); err != nil {
+			return nil, fmt.Errorf("ImportCSV(); line %d: %s", line, err)
+		}
+		pop.Chromosomes = append(pop.Chromosomes, c)
+		if withFitness {
+			f, err := strconv.ParseFloat(row[s.NumGenes], 64)
+			if err != nil {
+				return nil, fmt.Errorf("ImportCSV(); line %d: fitness: %s", line, err)
+			}
+			pop.Fitness = append(pop.Fitness, Fitness(f))
+		}
+	}
+	return pop, nil
+}
@@ -0,0 +1,81 @@
+package genetics_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/inlined/genetics"
+)
+
+func TestCSVRoundTrip(t *testing.T) {
+	s := genetics.NewSpecies(3, 9)
+	pop := genetics.Population{
+		Species:     s,
+		Chromosomes: []genetics.Chromosome{s.New(1, 2, 3), s.New(9, 0, 4)},
+		Fitness:     []genetics.Fitness{6, -13},
+	}
+	for _, withFitness := range []bool{true, false} {
+		var buf bytes.Buffer
+		if err := pop.ExportCSV(&buf, withFitness); err != nil {
+			t.Fatalf("ExportCSV(); err=%s", err)
+		}
+		got, err := s.ImportCSV(&buf)
+		if err != nil {
+			t.Fatalf("ImportCSV(); err=%s", err)
+		}
+		want := pop
+		if !withFitness {
+			want.Fitness = nil
+		}
+		if diff := cmp.Diff(&want, got); diff != "" {
+			t.Errorf("Population did not round trip with fitness=%t; diff=%s", withFitness, diff)
+		}
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	s := genetics.NewSpecies(2, 9)
+	pop := genetics.Population{
+		Species:     s,
+		Chromosomes: []genetics.Chromosome{s.New(1, 2)},
+		Fitness:     []genetics.Fitness{3},
+	}
+	var buf bytes.Buffer
+	if err := pop.ExportCSV(&buf, true); err != nil {
+		t.Fatalf("ExportCSV(); err=%s", err)
+	}
+	if want := "gene0,gene1,fitness\n1,2,3\n"; buf.String() != want {
+		t.Errorf("ExportCSV() wrote %q; want %q", buf.String(), want)
+	}
+	pop.Fitness = nil
+	if err := pop.ExportCSV(&bytes.Buffer{}, true); err == nil {
+		t.Error("ExportCSV() with fitness should fail when the Population is unscored")
+	}
+	if err := pop.ExportCSV(&bytes.Buffer{}, false); err != nil {
+		t.Errorf("ExportCSV() without fitness; err=%s", err)
+	}
+}
+
+func TestImportCSVErrors(t *testing.T) {
+	s := genetics.NewSpecies(2, 9)
+	for _, test := range []struct {
+		tag string
+		csv string
+	}{
+		{tag: "empty", csv: ""},
+		{tag: "wrong header", csv: "gene0,gene1,gene2\n1,2,3\n"},
+		{tag: "not a number", csv: "gene0,gene1\n1,x\n"},
+		{tag: "allele too large", csv: "gene0,gene1\n1,10\n"},
+		{tag: "bad fitness", csv: "gene0,gene1,fitness\n1,2,best\n"},
+		{tag: "ragged", csv: "gene0,gene1\n1,2,3\n"},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			if _, err := s.ImportCSV(strings.NewReader(test.csv)); err == nil {
+				t.Errorf("ImportCSV(%q) should fail", test.csv)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package genetics
+
+import (
+	"fmt"
+
+	"github.com/inlined/rand"
+)
+
+// DEStrategy chooses how differential evolution builds its mutant vectors.
+type DEStrategy int
+
+const (
+	// RandOneBin mutates a random base vector (rand/1/bin). It explores well and is
+	// the usual default.
+	RandOneBin DEStrategy = iota
+	// BestOneBin mutates the fittest vector (best/1/bin). It converges faster but
+	// is more likely to stall on multimodal problems.
+	BestOneBin
+)
+
+func (s DEStrategy) String() string {
+	switch s {
+	case RandOneBin:
+		return "rand/1/bin"
+	case BestOneBin:
+		return "best/1/bin"
+	}
+	return fmt.Sprintf("DEStrategy(%d)", int(s))
+}
+
+// DifferentialEvolution optimizes RealSpecies problems. For each member of the
+// population, a mutant is built by adding F times the difference of two random
+// members to a base member; binomial crossover with probability CR mixes the
+// mutant with the member, and the trial replaces the member if it is at least as
+// fit. Genes which leave the species' bounds are placed randomly between the base
+// member and the bound. DifferentialEvolution maximizes Fitness, so a problem
+// whose cost is to be minimized should evaluate to the negated cost. Like Engine,
+// a DifferentialEvolution is not goroutine safe.
+type DifferentialEvolution struct {
+	Species   *RealSpecies
+	Evaluator RealEvaluator
+
+	// Size is the number of members of the population; at least 4.
+	Size     int
+	Strategy DEStrategy
+	// F is the differential weight. Defaults to 0.5.
+	F float64
+	// CR is the crossover probability. Defaults to 0.9.
+	CR float64
+
+	// Terminate, if set, ends a Run early once a generation satisfies it.
+	Terminate Termination
+	// Observers are notified of the Stats of each generation.
+	Observers []StatsObserver
+
+	pop     []RealChromosome
+	fitness []Fitness
+	stats   Stats
+}
+
+// Population returns the current population and its fitness.
+func (d *DifferentialEvolution) Population() ([]RealChromosome, []Fitness) {
+	return d.pop, d.fitness
+}
+
+// Stats summarizes the most recent generation.
+func (d *DifferentialEvolution) Stats() Stats {
+	return d.stats
+}
+
+// Run evolves a random population for up to the given number of generations and
+// returns its fittest member.
+func (d *DifferentialEvolution) Run(r rand.Rand, generations int) (RealChromosome, Fitness, error) {
+	if d.Size < 4 {
+		return RealChromosome{}, 0, fmt.Errorf("DifferentialEvolution.Run(); Size %d must be at least 4", d.Size)
+	}
+	f, cr := d.F, d.CR
+	if f == 0 {
+		f = 0.5
+	}
+	if cr == 0 {
+		cr = 0.9
+	}
+	d.pop = make([]RealChromosome, d.Size)
+	d.fitness = make([]Fitness, d.Size)
+	d.stats = Stats{}
+	for i := range d.pop {
+		d.pop[i] = d.Species.NewRand(r)
+		score, err := d.Evaluator.Evaluate(d.pop[i])
+		if err != nil {
+			return RealChromosome{}, 0, fmt.Errorf("DifferentialEvolution.Run(); cannot evaluate initial member %d: %s", i, err)
+		}
+		d.fitness[i] = score
+	}
+
+	n := d.Species.NumGenes()
+	for gen := 0; gen < generations; gen++ {
+		best := rankIndexes(d.fitness)[0]
+		improvements := 0
+		for i := range d.pop {
+			// Pick three distinct members other than i
+			others := rand.Deal(r, d.Size-1, 3)
+			for k := range others {
+				if others[k] >= i {
+					others[k]++
+				}
+			}
+			base := d.pop[others[0]]
+			if d.Strategy == BestOneBin {
+				base = d.pop[best]
+			}
+			a, b := d.pop[others[1]], d.pop[others[2]]
+
+			trial := d.Species.New(d.pop[i].Genes...)
+			forced := int(r.Int31n(int32(n)))
+			for j := range trial.Genes {
+				if j == forced || r.Float64() < cr {
+					trial.Genes[j] = base.Genes[j] + f*(a.Genes[j]-b.Genes[j])
+					// Bounce back between the base and the violated bound; clamping
+					// would collapse members onto the bounds and end the search.
+					if min := d.Species.Min[j]; trial.Genes[j] < min {
+						trial.Genes[j] = min + r.Float64()*(base.Genes[j]-min)
+					} else if max := d.Species.Max[j]; trial.Genes[j] > max {
+						trial.Genes[j] = max - r.Float64()*(max-base.Genes[j])
+					}
+				}
+			}
+			score, err := d.Evaluator.Evaluate(trial)
+			if err != nil {
+				return d.pop[best], d.fitness[best], fmt.Errorf("DifferentialEvolution.Run(); generation %d: %s", gen, err)
+			}
+			// Members are replaced in place, so later trials in this generation
+			// may already use the improved members.
+			if score >= d.fitness[i] {
+				if score > d.fitness[i] {
+					improvements++
+				}
+				d.pop[i], d.fitness[i] = trial, score
+			}
+		}
+
+		d.stats = Stats{
+			Generation:   gen,
+			Evaluations:  d.Size,
+			Offspring:    d.Size,
+			Improvements: improvements,
+		}
+		d.stats.summarize(d.fitness)
+		for _, o := range d.Observers {
+			o.OnStats(d.stats)
+		}
+		if d.Terminate != nil && d.Terminate(d.stats) {
+			break
+		}
+	}
+	best := rankIndexes(d.fitness)[0]
+	return d.pop[best], d.fitness[best], nil
+}
@@ -0,0 +1,62 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestDifferentialEvolution(t *testing.T) {
+	for _, strategy := range []genetics.DEStrategy{genetics.RandOneBin, genetics.BestOneBin} {
+		t.Run(strategy.String(), func(t *testing.T) {
+			rng := rand.New()
+			rng.Seed(42)
+			de := &genetics.DifferentialEvolution{
+				Species:   genetics.NewUniformRealSpecies(3, -5.12, 5.12),
+				Evaluator: genetics.RealFitnessFunc(sphere),
+				Size:      30,
+				Strategy:  strategy,
+			}
+			best, f, err := de.Run(rng, 200)
+			if err != nil {
+				t.Fatalf("Run(); err=%s", err)
+			}
+			if f < -1000 {
+				t.Errorf("%s did not converge; best %v scored %g", strategy, best.Genes, f)
+			}
+			pop, fitness := de.Population()
+			if len(pop) != 30 || len(fitness) != 30 {
+				t.Errorf("Population() has %d members and %d scores; want 30", len(pop), len(fitness))
+			}
+		})
+	}
+}
+
+func TestDifferentialEvolutionTerminate(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	recorder := &statsRecorder{}
+	de := &genetics.DifferentialEvolution{
+		Species:   genetics.NewUniformRealSpecies(2, -5.12, 5.12),
+		Evaluator: genetics.RealFitnessFunc(sphere),
+		Size:      20,
+		Terminate: genetics.TargetFitness(-1e4),
+		Observers: []genetics.StatsObserver{recorder},
+	}
+	_, f, err := de.Run(rng, 1000)
+	if err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if f < -1e4 {
+		t.Errorf("Run() stopped at %g before reaching the target", f)
+	}
+	if n := len(recorder.stats); n == 1000 || recorder.stats[n-1].Best < -1e4 {
+		t.Errorf("Run() did not stop when it reached the target; generations=%d", n)
+	}
+
+	if _, _, err := (&genetics.DifferentialEvolution{Species: de.Species, Evaluator: de.Evaluator, Size: 3}).Run(rng, 1); err == nil {
+		t.Error("Run() should reject populations smaller than 4")
+	}
+}
@@ -0,0 +1,119 @@
+// Package demo evolves a run described by a config.Config one call at a time and
+// reports each generation as plain data, for interactive teaching demos which
+// visualize evolution. The package has no platform dependencies and runs anywhere
+// the rest of the module does, including WebAssembly, where Register exports it
+// to JavaScript. The demo/wasm directory is a browser page which uses it:
+//
+//	GOOS=js GOARCH=wasm go build -o demo/wasm/demo.wasm ./demo/wasm
+//	cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" demo/wasm/
+//
+// Serve demo/wasm over HTTP and open index.html.
+package demo
+
+import (
+	"fmt"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/genetics/config"
+	"github.com/inlined/rand"
+)
+
+// State is a scored generation of a Demo.
+type State struct {
+	Stats genetics.Stats
+	// Genes and Fitness are copies of the population's members and their scores.
+	Genes   [][]genetics.Gene
+	Fitness []genetics.Fitness
+	// Best is the index of the fittest member.
+	Best int
+	// Done reports whether the run has reached its Termination.
+	Done bool
+}
+
+// Demo is a run which is evolved by calls to Step. It is not goroutine safe.
+type Demo struct {
+	config *config.Config
+	eval   genetics.Evaluator
+	r      rand.Rand
+	engine *genetics.Engine
+	state  State
+}
+
+// New validates c, which must describe its Fitness, and starts a run.
+func New(c *config.Config) (*Demo, error) {
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("demo.New(); err=%s", err)
+	}
+	eval, err := c.Evaluator()
+	if err != nil {
+		return nil, fmt.Errorf("demo.New(); err=%s", err)
+	}
+	d := &Demo{config: c, eval: eval}
+	if err := d.Reset(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Reset restarts the run from a new population. The run is seeded with the
+// Config's Seed, so that a Reset demo replays the same generations.
+func (d *Demo) Reset() error {
+	d.r = d.config.Rand()
+	pop, err := d.config.Population(d.r)
+	if err != nil {
+		return fmt.Errorf("Demo.Reset(); err=%s", err)
+	}
+	if d.engine, err = d.config.Engine(d.eval); err != nil {
+		return fmt.Errorf("Demo.Reset(); err=%s", err)
+	}
+	d.engine.Observers = append(d.engine.Observers, snapshotObserver{d: d})
+	d.engine.Reset(pop)
+	d.state = State{}
+	return nil
+}
+
+// Step evolves up to generations more generations, stopping early once the run
+// reaches its Termination, and returns the State of the last one scored.
+func (d *Demo) Step(generations int) (State, error) {
+	if generations <= 0 {
+		return d.state, fmt.Errorf("Demo.Step(%d); generations must be positive", generations)
+	}
+	for k := 0; k < generations && !d.state.Done; k++ {
+		if err := d.engine.Step(d.r); err != nil {
+			return d.state, fmt.Errorf("Demo.Step(%d); err=%s", generations, err)
+		}
+		d.state.Done = d.engine.Generation() >= d.config.Termination.Generations ||
+			(d.engine.Terminate != nil && d.engine.Terminate(d.state.Stats))
+	}
+	return d.state, nil
+}
+
+// State returns the State of the generation most recently scored by Step. It is
+// the zero State until Step is first called.
+func (d *Demo) State() State {
+	return d.state
+}
+
+// snapshotObserver records the State of each generation once it has been scored
+// and before it is evolved.
+type snapshotObserver struct {
+	genetics.NopObserver
+	d *Demo
+}
+
+// OnStats implements genetics.StatsObserver
+func (o snapshotObserver) OnStats(s genetics.Stats) {
+	pop := o.d.engine.Population()
+	state := State{
+		Stats:   s,
+		Genes:   make([][]genetics.Gene, len(pop.Chromosomes)),
+		Fitness: append([]genetics.Fitness(nil), pop.Fitness...),
+	}
+	for i, c := range pop.Chromosomes {
+		state.Genes[i] = append([]genetics.Gene(nil), c.Genes...)
+		if pop.Objective.Better(pop.Fitness[i], pop.Fitness[state.Best]) {
+			state.Best = i
+		}
+	}
+	o.d.state = state
+}
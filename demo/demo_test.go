@@ -0,0 +1,114 @@
+package demo_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/genetics/config"
+	"github.com/inlined/genetics/demo"
+)
+
+const oneMax = `{
+  "species": {"numGenes": 16, "maxAllele": 1},
+  "populationSize": 30,
+  "evolver": {
+    "replacementCount": 14,
+    "crossoverRate": 0.9,
+    "mutationRate": 0.3,
+    "selector": "TournamentSelection(3)",
+    "crossover": "MultiPoint(2)",
+    "mutator": "Inversion"
+  },
+  "termination": {"generations": 200, "targetFitness": 16},
+  "fitness": "sum(g == 1)",
+  "seed": 42
+}`
+
+func newDemo(t *testing.T) *demo.Demo {
+	c, err := config.Load(strings.NewReader(oneMax))
+	if err != nil {
+		t.Fatalf("config.Load(); err=%s", err)
+	}
+	d, err := demo.New(c)
+	if err != nil {
+		t.Fatalf("demo.New(); err=%s", err)
+	}
+	return d
+}
+
+func TestStep(t *testing.T) {
+	d := newDemo(t)
+	s, err := d.Step(3)
+	if err != nil {
+		t.Fatalf("Demo.Step(); err=%s", err)
+	}
+	if s.Stats.Generation != 2 || len(s.Genes) != 30 || len(s.Fitness) != 30 {
+		t.Fatalf("Demo.Step() generation=%d with %d members; want generation 2 with 30", s.Stats.Generation, len(s.Genes))
+	}
+	for i, genes := range s.Genes {
+		ones := 0
+		for _, g := range genes {
+			ones += g
+		}
+		if s.Fitness[i] != genetics.Fitness(ones) {
+			t.Errorf("State.Fitness[%d]=%g; want %d for %v", i, s.Fitness[i], ones, genes)
+		}
+		if s.Fitness[i] > s.Fitness[s.Best] {
+			t.Errorf("State.Best=%d with fitness %g but member %d has %g", s.Best, s.Fitness[s.Best], i, s.Fitness[i])
+		}
+	}
+	if diff := cmp.Diff(s, d.State()); diff != "" {
+		t.Errorf("Demo.State() differs from the result of Step; diff=%s", diff)
+	}
+
+	for !s.Done {
+		if s, err = d.Step(10); err != nil {
+			t.Fatalf("Demo.Step(); err=%s", err)
+		}
+	}
+	if s.Stats.Best != 16 {
+		t.Errorf("Demo.Step() ended with best fitness %g; want 16", s.Stats.Best)
+	}
+	if again, err := d.Step(1); err != nil || again.Stats.Generation != s.Stats.Generation {
+		t.Errorf("Demo.Step() after Done evolved to generation %d, %v; want %d", again.Stats.Generation, err, s.Stats.Generation)
+	}
+
+	if err := d.Reset(); err != nil {
+		t.Fatalf("Demo.Reset(); err=%s", err)
+	}
+	replay, err := d.Step(s.Stats.Generation + 1)
+	if err != nil {
+		t.Fatalf("Demo.Step(); err=%s", err)
+	}
+	if diff := cmp.Diff(s, replay); diff != "" {
+		t.Errorf("Demo.Reset() did not replay the run; diff=%s", diff)
+	}
+	if _, err := d.Step(0); err == nil {
+		t.Error("Demo.Step(0) should fail")
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		edit func(c *config.Config)
+	}{
+		{name: "no fitness", edit: func(c *config.Config) { c.Fitness = "" }},
+		{name: "bad fitness", edit: func(c *config.Config) { c.Fitness = "sum(" }},
+		{name: "bad config", edit: func(c *config.Config) { c.PopulationSize = 0 }},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := config.Load(strings.NewReader(oneMax))
+			if err != nil {
+				t.Fatalf("config.Load(); err=%s", err)
+			}
+			test.edit(c)
+			if _, err := demo.New(c); err == nil {
+				t.Error("demo.New() should fail")
+			}
+		})
+	}
+}
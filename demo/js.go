@@ -0,0 +1,89 @@
+//go:build js && wasm
+
+package demo
+
+import (
+	"encoding/json"
+	"strings"
+	"syscall/js"
+
+	"github.com/inlined/genetics/config"
+)
+
+// Register exports the package to JavaScript as a global function called name,
+// which takes a Config as a JSON string and returns a demo object with methods:
+//
+//	step(generations) // Step; returns the State
+//	state()           // State
+//	reset()           // Reset
+//	release()         // frees the demo's methods, after which it must not be used
+//
+// States are plain objects with the fields of State. Failures are returned as
+// JavaScript Error objects rather than thrown.
+func Register(name string) {
+	js.Global().Set(name, js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if len(args) != 1 || args[0].Type() != js.TypeString {
+			return jsError("demo: expected a Config as a JSON string")
+		}
+		c, err := config.Load(strings.NewReader(args[0].String()))
+		if err != nil {
+			return jsError(err.Error())
+		}
+		d, err := New(c)
+		if err != nil {
+			return jsError(err.Error())
+		}
+		return d.js()
+	}))
+}
+
+// js wraps d in a JavaScript object.
+func (d *Demo) js() js.Value {
+	var funcs []js.Func
+	obj := js.Global().Get("Object").New()
+	method := func(name string, f func(args []js.Value) interface{}) {
+		fn := js.FuncOf(func(this js.Value, args []js.Value) interface{} { return f(args) })
+		funcs = append(funcs, fn)
+		obj.Set(name, fn)
+	}
+	method("step", func(args []js.Value) interface{} {
+		generations := 1
+		if len(args) > 0 && args[0].Type() == js.TypeNumber {
+			generations = args[0].Int()
+		}
+		state, err := d.Step(generations)
+		if err != nil {
+			return jsError(err.Error())
+		}
+		return jsState(state)
+	})
+	method("state", func(args []js.Value) interface{} {
+		return jsState(d.State())
+	})
+	method("reset", func(args []js.Value) interface{} {
+		if err := d.Reset(); err != nil {
+			return jsError(err.Error())
+		}
+		return js.Undefined()
+	})
+	method("release", func(args []js.Value) interface{} {
+		for _, fn := range funcs {
+			fn.Release()
+		}
+		return js.Undefined()
+	})
+	return obj
+}
+
+// jsState converts s to a plain JavaScript object by way of JSON.
+func jsState(s State) js.Value {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return jsError(err.Error())
+	}
+	return js.Global().Get("JSON").Call("parse", string(b))
+}
+
+func jsError(msg string) js.Value {
+	return js.Global().Get("Error").New(msg)
+}
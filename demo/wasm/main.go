@@ -0,0 +1,13 @@
+//go:build js && wasm
+
+// Command wasm is the WebAssembly half of the browser demo in this directory. It
+// exports the demo package to JavaScript as newDemo and then waits forever so
+// that the page can keep calling into it.
+package main
+
+import "github.com/inlined/genetics/demo"
+
+func main() {
+	demo.Register("newDemo")
+	select {}
+}
@@ -0,0 +1,156 @@
+package genetics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+)
+
+// LocusDiff compares the alleles which two Populations hold at one locus.
+type LocusDiff struct {
+	Locus int
+	// Before and After map each allele at the locus to the fraction of the
+	// population which holds it.
+	Before, After map[Gene]float64
+	// Shift is the total variation distance between Before and After: half the sum
+	// of the changes in each allele's frequency, from 0 if the frequencies are
+	// unchanged to 1 if the populations share no allele at the locus.
+	Shift float64
+	// ConvergenceBefore and ConvergenceAfter are the frequencies of the most
+	// common allele, which reach 1 once every member holds the same allele.
+	ConvergenceBefore, ConvergenceAfter float64
+}
+
+// PopulationDiff compares two snapshots of a population, e.g. the checkpoints of
+// generations 100 and 200, to show which genes the search has settled and which
+// genotypes it has found or abandoned in between. Loci which converge while the
+// population stops improving are the usual sign of premature convergence.
+type PopulationDiff struct {
+	// Loci compares each locus, in order.
+	Loci []LocusDiff
+	// New lists the distinct genotypes of the later snapshot which the earlier one
+	// lacks, and Lost those of the earlier snapshot which the later one lacks, in
+	// the order in which they first appear.
+	New, Lost []Chromosome
+	// Retained is the number of distinct genotypes in both snapshots.
+	Retained int
+}
+
+// DiffPopulations compares the earlier snapshot before with the later snapshot
+// after. Both must be non-empty Populations of equal Species; their Fitness is
+// ignored.
+func DiffPopulations(before, after *Population) (*PopulationDiff, error) {
+	switch {
+	case !before.Species.Equal(after.Species):
+		return nil, fmt.Errorf("DiffPopulations(); the snapshots are of different Species")
+	case len(before.Chromosomes) == 0 || len(after.Chromosomes) == 0:
+		return nil, fmt.Errorf("DiffPopulations(); the snapshots must not be empty")
+	}
+	d := &PopulationDiff{Loci: make([]LocusDiff, before.Species.NumGenes)}
+	for i := range d.Loci {
+		l := LocusDiff{
+			Locus:  i,
+			Before: alleleFrequencies(before.Chromosomes, i),
+			After:  alleleFrequencies(after.Chromosomes, i),
+		}
+		for a, f := range l.Before {
+			l.Shift += math.Abs(f - l.After[a])
+		}
+		for a, f := range l.After {
+			if _, ok := l.Before[a]; !ok {
+				l.Shift += f
+			}
+		}
+		l.Shift /= 2
+		_, l.ConvergenceBefore = modalAllele(l.Before)
+		_, l.ConvergenceAfter = modalAllele(l.After)
+		d.Loci[i] = l
+	}
+
+	genotypes := func(cs []Chromosome) map[string]bool {
+		keys := make(map[string]bool, len(cs))
+		for _, c := range cs {
+			keys[genesKey(c.Genes)] = true
+		}
+		return keys
+	}
+	beforeKeys, afterKeys := genotypes(before.Chromosomes), genotypes(after.Chromosomes)
+	d.New = distinctExcept(after.Chromosomes, beforeKeys)
+	d.Lost = distinctExcept(before.Chromosomes, afterKeys)
+	d.Retained = len(afterKeys) - len(d.New)
+	return d, nil
+}
+
+// Converged returns the loci whose most common allele is held by at least the
+// fraction threshold of the later snapshot, e.g. 0.95.
+func (d *PopulationDiff) Converged(threshold float64) []int {
+	var loci []int
+	for _, l := range d.Loci {
+		if l.ConvergenceAfter >= threshold {
+			loci = append(loci, l.Locus)
+		}
+	}
+	return loci
+}
+
+// WriteReport writes d as an aligned text table of the loci, from the largest
+// Shift to the smallest, followed by a summary of the genotypes.
+func (d *PopulationDiff) WriteReport(w io.Writer) error {
+	loci := append([]LocusDiff(nil), d.Loci...)
+	sort.SliceStable(loci, func(i, j int) bool { return loci[i].Shift > loci[j].Shift })
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "Locus\tShift\tBefore\tAfter")
+	for _, l := range loci {
+		a, f := modalAllele(l.Before)
+		b, g := modalAllele(l.After)
+		fmt.Fprintf(tw, "%d\t%.3f\t%d (%.2f)\t%d (%.2f)\n", l.Locus, l.Shift, a, f, b, g)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "Genotypes: %d new, %d lost, %d retained\n", len(d.New), len(d.Lost), d.Retained)
+	return err
+}
+
+// alleleFrequencies maps each allele at locus to the fraction of cs holding it.
+func alleleFrequencies(cs []Chromosome, locus int) map[Gene]float64 {
+	freq := make(map[Gene]float64)
+	for _, c := range cs {
+		freq[c.Genes[locus]]++
+	}
+	for a := range freq {
+		freq[a] /= float64(len(cs))
+	}
+	return freq
+}
+
+// modalAllele returns the most frequent allele of freq and its frequency. Ties
+// go to the lowest allele.
+func modalAllele(freq map[Gene]float64) (Gene, float64) {
+	var mode Gene
+	best := -1.0
+	for a, f := range freq {
+		if f > best || (f == best && a < mode) {
+			mode, best = a, f
+		}
+	}
+	return mode, best
+}
+
+// distinctExcept returns the first of each distinct genotype of cs whose key is not
+// in except.
+func distinctExcept(cs []Chromosome, except map[string]bool) []Chromosome {
+	var distinct []Chromosome
+	seen := make(map[string]bool)
+	for _, c := range cs {
+		key := genesKey(c.Genes)
+		if except[key] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		distinct = append(distinct, c)
+	}
+	return distinct
+}
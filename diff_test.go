@@ -0,0 +1,91 @@
+package genetics_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/inlined/genetics"
+)
+
+func TestDiffPopulations(t *testing.T) {
+	s := genetics.NewSpecies(3, 3)
+	population := func(genes ...[]genetics.Gene) *genetics.Population {
+		pop := &genetics.Population{Species: s}
+		for _, g := range genes {
+			pop.Chromosomes = append(pop.Chromosomes, s.New(g...))
+		}
+		return pop
+	}
+	before := population([]genetics.Gene{0, 1, 2}, []genetics.Gene{1, 1, 2}, []genetics.Gene{2, 1, 3}, []genetics.Gene{3, 1, 3})
+	after := population([]genetics.Gene{3, 1, 0}, []genetics.Gene{3, 1, 0}, []genetics.Gene{3, 1, 3}, []genetics.Gene{1, 1, 2})
+	d, err := genetics.DiffPopulations(before, after)
+	if err != nil {
+		t.Fatalf("DiffPopulations(); err=%s", err)
+	}
+
+	want := []genetics.LocusDiff{
+		{
+			Locus:             0,
+			Before:            map[genetics.Gene]float64{0: 0.25, 1: 0.25, 2: 0.25, 3: 0.25},
+			After:             map[genetics.Gene]float64{1: 0.25, 3: 0.75},
+			Shift:             0.5,
+			ConvergenceBefore: 0.25,
+			ConvergenceAfter:  0.75,
+		}, {
+			Locus:             1,
+			Before:            map[genetics.Gene]float64{1: 1},
+			After:             map[genetics.Gene]float64{1: 1},
+			ConvergenceBefore: 1,
+			ConvergenceAfter:  1,
+		}, {
+			Locus:             2,
+			Before:            map[genetics.Gene]float64{2: 0.5, 3: 0.5},
+			After:             map[genetics.Gene]float64{0: 0.5, 2: 0.25, 3: 0.25},
+			Shift:             0.5,
+			ConvergenceBefore: 0.5,
+			ConvergenceAfter:  0.5,
+		},
+	}
+	if diff := cmp.Diff(want, d.Loci); diff != "" {
+		t.Errorf("DiffPopulations().Loci diff=%s", diff)
+	}
+	genes := func(cs []genetics.Chromosome) [][]genetics.Gene {
+		var g [][]genetics.Gene
+		for _, c := range cs {
+			g = append(g, c.Genes)
+		}
+		return g
+	}
+	if diff := cmp.Diff([][]genetics.Gene{{3, 1, 0}}, genes(d.New)); diff != "" {
+		t.Errorf("DiffPopulations().New diff=%s", diff)
+	}
+	if diff := cmp.Diff([][]genetics.Gene{{0, 1, 2}, {2, 1, 3}}, genes(d.Lost)); diff != "" {
+		t.Errorf("DiffPopulations().Lost diff=%s", diff)
+	}
+	if d.Retained != 2 {
+		t.Errorf("DiffPopulations().Retained=%d; want 2", d.Retained)
+	}
+	if got := d.Converged(0.75); !cmp.Equal(got, []int{0, 1}) {
+		t.Errorf("Converged(0.75)=%v; want [0 1]", got)
+	}
+
+	var b strings.Builder
+	if err := d.WriteReport(&b); err != nil {
+		t.Fatalf("WriteReport(); err=%s", err)
+	}
+	wantReport := `Locus  Shift  Before    After
+0      0.500  0 (0.25)  3 (0.75)
+2      0.500  2 (0.50)  0 (0.50)
+1      0.000  1 (1.00)  1 (1.00)
+Genotypes: 1 new, 2 lost, 2 retained
+`
+	if diff := cmp.Diff(wantReport, b.String()); diff != "" {
+		t.Errorf("WriteReport() diff=%s", diff)
+	}
+
+	if _, err := genetics.DiffPopulations(before, &genetics.Population{Species: genetics.NewSpecies(4, 3)}); err == nil {
+		t.Error("DiffPopulations() should reject snapshots of different Species")
+	}
+}
@@ -0,0 +1,190 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+const (
+	hammingDistance     = "Hamming"
+	euclideanDistance   = "Euclidean"
+	kendallTauDistance  = "KendallTau"
+	levenshteinDistance = "Levenshtein"
+)
+
+var (
+	distanceName = regexp.MustCompile(`^\w+$`)
+
+	distancesMu sync.RWMutex
+	distances   = map[string]DistanceFunc{
+		hammingDistance:     HammingDistance,
+		euclideanDistance:   EuclideanDistance,
+		kendallTauDistance:  KendallTauDistance,
+		levenshteinDistance: LevenshteinDistance,
+	}
+)
+
+// DistanceFunc measures how different two Chromosomes of the same Species are.
+// Diversity mechanisms (e.g. fitness sharing, crowding, or novelty search) refer to
+// DistanceFuncs by the name they were registered under.
+type DistanceFunc func(a, b Chromosome) float64
+
+// RegisterDistance makes fn available under name to LookupDistance and DistanceFlag.
+// Names must be alphanumeric so they can be used in flags; a name cannot be registered twice.
+func RegisterDistance(name string, fn DistanceFunc) error {
+	if !distanceName.MatchString(name) {
+		return fmt.Errorf("RegisterDistance(%s): name must be alphanumeric", name)
+	}
+	distancesMu.Lock()
+	defer distancesMu.Unlock()
+	if _, ok := distances[name]; ok {
+		return fmt.Errorf("RegisterDistance(%s): already registered", name)
+	}
+	distances[name] = fn
+	return nil
+}
+
+// LookupDistance returns the DistanceFunc registered under name. The built-in
+// distances are Hamming, Euclidean, KendallTau, and Levenshtein.
+func LookupDistance(name string) (DistanceFunc, error) {
+	distancesMu.RLock()
+	defer distancesMu.RUnlock()
+	fn, ok := distances[name]
+	if !ok {
+		return nil, fmt.Errorf("LookupDistance(%s): unknown distance", name)
+	}
+	return fn, nil
+}
+
+// registeredName returns the name under which fn was registered, if it was.
+func registeredName(fn DistanceFunc) (string, bool) {
+	distancesMu.RLock()
+	defer distancesMu.RUnlock()
+	p := reflect.ValueOf(fn).Pointer()
+	for name, d := range distances {
+		if reflect.ValueOf(d).Pointer() == p {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// Distances lists the names of all registered DistanceFuncs in sorted order.
+func Distances() []string {
+	distancesMu.RLock()
+	defer distancesMu.RUnlock()
+	names := make([]string, 0, len(distances))
+	for name := range distances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HammingDistance counts the loci where a and b have different alleles.
+func HammingDistance(a, b Chromosome) float64 {
+	return float64(a.HammingDistance(b))
+}
+
+// EuclideanDistance treats the Genes of a and b as points in space.
+func EuclideanDistance(a, b Chromosome) float64 {
+	return a.EuclideanDistance(b)
+}
+
+// KendallTauDistance counts the pairs of alleles which a and b order differently.
+// It is only meaningful for permutation-encoded Chromosomes.
+func KendallTauDistance(a, b Chromosome) float64 {
+	return float64(a.KendallTauDistance(b))
+}
+
+// LevenshteinDistance counts the insertions, deletions, and substitutions of
+// alleles which turn a into b.
+func LevenshteinDistance(a, b Chromosome) float64 {
+	return float64(a.LevenshteinDistance(b))
+}
+
+// HammingDistance counts the loci where c and o have different alleles. Both must
+// have the same number of Genes.
+func (c Chromosome) HammingDistance(o Chromosome) int {
+	d := 0
+	for i := range c.Genes {
+		if c.Genes[i] != o.Genes[i] {
+			d++
+		}
+	}
+	return d
+}
+
+// EuclideanDistance treats the Genes of c and o as points in space.
+func (c Chromosome) EuclideanDistance(o Chromosome) float64 {
+	sum := 0.0
+	for i := range c.Genes {
+		d := float64(c.Genes[i] - o.Genes[i])
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+// KendallTauDistance counts the pairs of alleles which c and o order differently,
+// i.e. the number of swaps of adjacent genes which turn c into o. It is only
+// meaningful for permutation-encoded Chromosomes.
+func (c Chromosome) KendallTauDistance(o Chromosome) int {
+	// pos[v] is the index of allele v in o
+	pos := make(map[Gene]int, len(o.Genes))
+	for i, v := range o.Genes {
+		pos[v] = i
+	}
+	d := 0
+	for i := 0; i < len(c.Genes); i++ {
+		for j := i + 1; j < len(c.Genes); j++ {
+			if pos[c.Genes[i]] > pos[c.Genes[j]] {
+				d++
+			}
+		}
+	}
+	return d
+}
+
+// LevenshteinDistance counts the insertions, deletions, and substitutions of
+// alleles which turn c into o. Unlike HammingDistance, it recognizes a segment
+// which has shifted, e.g. by an insertion mutation, and it accepts Chromosomes
+// of different lengths.
+func (c Chromosome) LevenshteinDistance(o Chromosome) int {
+	// row[j] is the distance from the genes of c seen so far to o.Genes[:j]
+	row := make([]int, len(o.Genes)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := range c.Genes {
+		diag := row[0]
+		row[0] = i + 1
+		for j := range o.Genes {
+			d := diag
+			if c.Genes[i] != o.Genes[j] {
+				d++
+			}
+			if row[j]+1 < d {
+				d = row[j] + 1
+			}
+			if row[j+1]+1 < d {
+				d = row[j+1] + 1
+			}
+			diag, row[j+1] = row[j+1], d
+		}
+	}
+	return row[len(o.Genes)]
+}
+
+// EuclideanDistance treats the Genes of c and o as points in space.
+func (c RealChromosome) EuclideanDistance(o RealChromosome) float64 {
+	sum := 0.0
+	for i := range c.Genes {
+		d := c.Genes[i] - o.Genes[i]
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
@@ -0,0 +1,149 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/inlined/genetics"
+)
+
+func TestDistances(t *testing.T) {
+	s := genetics.NewSpecies(4, 10)
+	for _, test := range []struct {
+		tag      string
+		distance string
+		a, b     []genetics.Gene
+		want     float64
+	}{
+		{
+			tag:      "hamming identical",
+			distance: "Hamming",
+			a:        []genetics.Gene{1, 2, 3, 4},
+			b:        []genetics.Gene{1, 2, 3, 4},
+			want:     0,
+		}, {
+			tag:      "hamming",
+			distance: "Hamming",
+			a:        []genetics.Gene{1, 2, 3, 4},
+			b:        []genetics.Gene{1, 0, 3, 0},
+			want:     2,
+		}, {
+			tag:      "euclidean",
+			distance: "Euclidean",
+			a:        []genetics.Gene{0, 0, 0, 0},
+			b:        []genetics.Gene{3, 4, 0, 0},
+			want:     5,
+		}, {
+			tag:      "kendall tau adjacent swap",
+			distance: "KendallTau",
+			a:        []genetics.Gene{0, 1, 2, 3},
+			b:        []genetics.Gene{1, 0, 2, 3},
+			want:     1,
+		}, {
+			tag:      "kendall tau reversed",
+			distance: "KendallTau",
+			a:        []genetics.Gene{0, 1, 2, 3},
+			b:        []genetics.Gene{3, 2, 1, 0},
+			want:     6,
+		}, {
+			tag:      "levenshtein identical",
+			distance: "Levenshtein",
+			a:        []genetics.Gene{0, 1, 2, 3},
+			b:        []genetics.Gene{0, 1, 2, 3},
+			want:     0,
+		}, {
+			tag:      "levenshtein shifted",
+			distance: "Levenshtein",
+			a:        []genetics.Gene{0, 1, 2, 3},
+			b:        []genetics.Gene{1, 2, 3, 0},
+			want:     2,
+		}, {
+			tag:      "levenshtein substitution",
+			distance: "Levenshtein",
+			a:        []genetics.Gene{0, 1, 2, 3},
+			b:        []genetics.Gene{0, 5, 2, 3},
+			want:     1,
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			fn, err := genetics.LookupDistance(test.distance)
+			if err != nil {
+				t.Fatalf("LookupDistance(%s); err=%s", test.distance, err)
+			}
+			if got := fn(s.New(test.a...), s.New(test.b...)); got != test.want {
+				t.Errorf("%s(%v, %v)=%f; want %f", test.distance, test.a, test.b, got, test.want)
+			}
+		})
+	}
+}
+
+func TestRegisterDistance(t *testing.T) {
+	first := func(a, b genetics.Chromosome) float64 {
+		return float64(a.Genes[0] - b.Genes[0])
+	}
+	// The registry is global, so the distance may remain from a previous -count run.
+	if _, err := genetics.LookupDistance("FirstGene"); err != nil {
+		if err := genetics.RegisterDistance("FirstGene", first); err != nil {
+			t.Fatalf("RegisterDistance(); err=%s", err)
+		}
+	}
+	if err := genetics.RegisterDistance("FirstGene", first); err == nil {
+		t.Error("RegisterDistance() should not allow a name to be registered twice")
+	}
+	if err := genetics.RegisterDistance("not a name", first); err == nil {
+		t.Error("RegisterDistance() should reject names which cannot be used in flags")
+	}
+
+	var flag genetics.DistanceFlag
+	if err := flag.Set("FirstGene"); err != nil {
+		t.Fatalf("DistanceFlag.Set(FirstGene); err=%s", err)
+	}
+	s := genetics.NewSpecies(1, 10)
+	if got := flag.Get()(s.New(7), s.New(3)); got != 4 {
+		t.Errorf("DistanceFlag did not resolve the registered distance; got=%f want=4", got)
+	}
+	if flag.String() != "FirstGene" {
+		t.Errorf("DistanceFlag.String()=%s; want FirstGene", flag.String())
+	}
+
+	var lower genetics.DistanceFlag
+	if err := lower.Set("firstgene"); err != nil || lower.String() != "FirstGene" {
+		t.Errorf("DistanceFlag.Set(firstgene) should match FirstGene; got=%s err=%v", lower.String(), err)
+	}
+
+	var unknown genetics.DistanceFlag
+	if err := unknown.Set("Manhattan"); err == nil {
+		t.Error("DistanceFlag.Set(Manhattan) should fail for unregistered distances")
+	}
+	var malformed genetics.DistanceFlag
+	if err := malformed.Set("First Gene"); err == nil {
+		t.Error("DistanceFlag.Set(First Gene) should fail for malformed names")
+	}
+}
+
+func TestChromosomeDistances(t *testing.T) {
+	s := genetics.NewSpecies(6, 10)
+	a, b := s.New(1, 2, 3, 4, 5, 6), s.New(2, 3, 4, 5, 6, 1)
+	if got := a.HammingDistance(b); got != 6 {
+		t.Errorf("HammingDistance()=%d; want 6", got)
+	}
+	if got := a.LevenshteinDistance(b); got != 2 {
+		t.Errorf("LevenshteinDistance()=%d; want 2", got)
+	}
+	if got := a.KendallTauDistance(b); got != 5 {
+		t.Errorf("KendallTauDistance()=%d; want 5", got)
+	}
+
+	// Levenshtein accepts Chromosomes of different lengths
+	short := genetics.NewSpecies(3, 10).New(1, 3, 5)
+	if got, want := a.LevenshteinDistance(short), 3; got != want {
+		t.Errorf("LevenshteinDistance(short)=%d; want %d", got, want)
+	}
+	if got, want := short.LevenshteinDistance(a), 3; got != want {
+		t.Errorf("short.LevenshteinDistance()=%d; want %d", got, want)
+	}
+
+	r := genetics.NewUniformRealSpecies(2, -10, 10)
+	if got := r.New(1, 1).EuclideanDistance(r.New(4, -3)); got != 5 {
+		t.Errorf("RealChromosome.EuclideanDistance()=%f; want 5", got)
+	}
+}
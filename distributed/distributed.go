@@ -0,0 +1,246 @@
+// Package distributed evaluates fitness on remote workers, for fitness functions
+// which are heavyweight simulations. A worker serves a genetics.Evaluator over HTTP
+// with Handler; a Client is a genetics.FallibleBatchEvaluator which shards each
+// generation across workers, retrying failed requests and hedging stragglers.
+// Requests and responses are JSON, so workers need not be written in Go.
+package distributed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/inlined/genetics"
+)
+
+// request is the body of an evaluation request. Each Chromosome carries its Species.
+type request struct {
+	Chromosomes []genetics.Chromosome `json:"chromosomes"`
+}
+
+// result is the outcome of evaluating one chromosome.
+type result struct {
+	Fitness genetics.Fitness `json:"fitness"`
+	Error   string           `json:"error,omitempty"`
+}
+
+// response is the body of a reply to a request, with a result per chromosome.
+type response struct {
+	Results []result `json:"results"`
+}
+
+// MaxRequestBytes is the largest request body that Handler reads.
+const MaxRequestBytes = 32 << 20
+
+// Handler serves POST requests to evaluate batches of chromosomes with eval.
+// Failed evaluations are reported per chromosome; they do not fail the request.
+// Requests larger than MaxRequestBytes are rejected.
+func Handler(eval genetics.Evaluator) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "expected POST", http.StatusMethodNotAllowed)
+			return
+		}
+		var req request
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxRequestBytes)).Decode(&req); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, fmt.Sprintf("request exceeds %d bytes", tooLarge.Limit), http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, fmt.Sprintf("malformed request: %s", err), http.StatusBadRequest)
+			return
+		}
+		resp := response{Results: make([]result, len(req.Chromosomes))}
+		for i, c := range req.Chromosomes {
+			f, err := eval.Evaluate(c)
+			resp.Results[i].Fitness = f
+			if err != nil {
+				resp.Results[i].Error = err.Error()
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	})
+}
+
+// Client is a genetics.Evaluator backed by remote workers serving Handler. It also
+// implements genetics.FallibleBatchEvaluator, so an Engine sends each generation
+// to the workers in one shard per worker. It is goroutine safe.
+type Client struct {
+	// Workers are the URLs at which workers serve Handler.
+	Workers []string
+	// HTTP is the client used to reach workers. Defaults to http.DefaultClient.
+	HTTP *http.Client
+	// Retries is the number of times a failed request is retried, each time on the
+	// next worker. Failed evaluations are not retried.
+	Retries int
+	// StragglerTimeout, if set, is how long to wait for a worker before sending the
+	// same request to the next worker as well and taking whichever replies first.
+	StragglerTimeout time.Duration
+
+	next uint32
+}
+
+// Evaluate implements genetics.Evaluator by sending c to the next worker in turn.
+func (c *Client) Evaluate(ch genetics.Chromosome) (genetics.Fitness, error) {
+	worker := int(atomic.AddUint32(&c.next, 1) - 1)
+	res, err := c.shard(context.Background(), []genetics.Chromosome{ch}, worker)
+	if err != nil {
+		return 0, err
+	}
+	if res[0].Error != "" {
+		return 0, errors.New(res[0].Error)
+	}
+	return res[0].Fitness, nil
+}
+
+// EvaluateAll implements genetics.BatchEvaluator. Chromosomes which could not be
+// evaluated score 0; use EvaluateAllErrs to tell them apart.
+func (c *Client) EvaluateAll(ctx context.Context, cs []genetics.Chromosome) []genetics.Fitness {
+	fitness, _ := c.EvaluateAllErrs(ctx, cs)
+	return fitness
+}
+
+// EvaluateAllErrs implements genetics.FallibleBatchEvaluator by splitting cs into
+// one shard per worker and evaluating the shards concurrently. errs[i] is set if
+// cs[i] could not be evaluated, either because its evaluation failed or because no
+// worker could be reached before ctx was done.
+func (c *Client) EvaluateAllErrs(ctx context.Context, cs []genetics.Chromosome) (fitness []genetics.Fitness, errs []error) {
+	fitness = make([]genetics.Fitness, len(cs))
+	errs = make([]error, len(cs))
+	n := len(c.Workers)
+	if n == 0 {
+		for i := range errs {
+			errs[i] = errors.New("Client.EvaluateAllErrs(); no workers")
+		}
+		return fitness, errs
+	}
+	if n > len(cs) {
+		n = len(cs)
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < n; w++ {
+		lo, hi := w*len(cs)/n, (w+1)*len(cs)/n
+		wg.Add(1)
+		go func(w, lo, hi int) {
+			defer wg.Done()
+			res, err := c.shard(ctx, cs[lo:hi], w)
+			for i := lo; i < hi; i++ {
+				switch {
+				case err != nil:
+					errs[i] = err
+				case res[i-lo].Error != "":
+					errs[i] = errors.New(res[i-lo].Error)
+				default:
+					fitness[i] = res[i-lo].Fitness
+				}
+			}
+		}(w, lo, hi)
+	}
+	wg.Wait()
+	return fitness, errs
+}
+
+// shard evaluates cs starting with the given worker, retrying on later workers
+// until ctx is done.
+func (c *Client) shard(ctx context.Context, cs []genetics.Chromosome, worker int) ([]result, error) {
+	if len(c.Workers) == 0 {
+		return nil, errors.New("Client.Evaluate(); no workers")
+	}
+	var lastErr error
+	for attempt := 0; attempt <= c.Retries; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("Client.Evaluate(); %s", err)
+		}
+		res, err := c.hedged(ctx, cs, worker+attempt)
+		if err == nil {
+			return res, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("Client.Evaluate(); %d attempts failed; last err=%s", c.Retries+1, lastErr)
+}
+
+// hedged sends cs to worker and, if it straggles, to the next worker too. It
+// returns the first successful reply.
+func (c *Client) hedged(ctx context.Context, cs []genetics.Chromosome, worker int) ([]result, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	type reply struct {
+		res []result
+		err error
+	}
+	replies := make(chan reply, 2)
+	send := func(w int) {
+		go func() {
+			res, err := c.post(ctx, c.Workers[w%len(c.Workers)], cs)
+			replies <- reply{res, err}
+		}()
+	}
+
+	send(worker)
+	pending := 1
+	var straggling <-chan time.Time
+	if c.StragglerTimeout > 0 && len(c.Workers) > 1 {
+		t := time.NewTimer(c.StragglerTimeout)
+		defer t.Stop()
+		straggling = t.C
+	}
+	var lastErr error
+	for pending > 0 {
+		select {
+		case r := <-replies:
+			pending--
+			if r.err == nil {
+				return r.res, nil
+			}
+			lastErr = r.err
+		case <-straggling:
+			straggling = nil
+			send(worker + 1)
+			pending++
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return nil, lastErr
+}
+
+func (c *Client) post(ctx context.Context, url string, cs []genetics.Chromosome) ([]result, error) {
+	body, err := json.Marshal(request{Chromosomes: cs})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	var r response
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("%s returned a malformed response: %s", url, err)
+	}
+	if len(r.Results) != len(cs) {
+		return nil, fmt.Errorf("%s returned %d results for %d chromosomes", url, len(r.Results), len(cs))
+	}
+	return r.Results, nil
+}
@@ -0,0 +1,275 @@
+package distributed_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/genetics/distributed"
+)
+
+func sum(c genetics.Chromosome) genetics.Fitness {
+	f := genetics.Fitness(0)
+	for _, g := range c.Genes {
+		f += genetics.Fitness(g)
+	}
+	return f
+}
+
+// worker serves sum, counting requests and optionally failing or stalling them.
+type worker struct {
+	*httptest.Server
+	requests int32
+	failures int32 // number of requests to fail before succeeding
+	delay    time.Duration
+}
+
+func newWorker(t *testing.T, eval genetics.Evaluator) *worker {
+	w := &worker{}
+	h := distributed.Handler(eval)
+	w.Server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&w.requests, 1)
+		if atomic.AddInt32(&w.failures, -1) >= 0 {
+			http.Error(rw, "overloaded", http.StatusServiceUnavailable)
+			return
+		}
+		if w.delay != 0 {
+			// Read the request first so that the server notices when the client
+			// cancels it.
+			body, _ := io.ReadAll(r.Body)
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			select {
+			case <-time.After(w.delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		h.ServeHTTP(rw, r)
+	}))
+	t.Cleanup(w.Close)
+	return w
+}
+
+func population(t *testing.T, n int) []genetics.Chromosome {
+	rng := rand.New()
+	rng.Seed(42)
+	s := genetics.NewSpecies(5, 9)
+	pop := make([]genetics.Chromosome, n)
+	for i := range pop {
+		var err error
+		if pop[i], err = s.NewRand(rng); err != nil {
+			t.Fatalf("NewRand(); err=%s", err)
+		}
+	}
+	return pop
+}
+
+func TestEvaluateAll(t *testing.T) {
+	workers := []*worker{newWorker(t, genetics.FitnessFunc(sum)), newWorker(t, genetics.FitnessFunc(sum)), newWorker(t, genetics.FitnessFunc(sum))}
+	c := &distributed.Client{}
+	for _, w := range workers {
+		c.Workers = append(c.Workers, w.URL)
+	}
+	pop := population(t, 10)
+	fitness, errs := c.EvaluateAllErrs(context.Background(), pop)
+	want := make([]genetics.Fitness, len(pop))
+	for i, ch := range pop {
+		want[i] = sum(ch)
+	}
+	if diff := cmp.Diff(want, fitness); diff != "" {
+		t.Errorf("EvaluateAllErrs() fitness differs; -want +got:\n%s", diff)
+	}
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("EvaluateAllErrs() failed chromosome %d: %s", i, err)
+		}
+	}
+	for i, w := range workers {
+		if w.requests != 1 {
+			t.Errorf("worker %d served %d requests; want 1 shard each", i, w.requests)
+		}
+	}
+
+	if f, err := c.Evaluate(pop[0]); err != nil || f != want[0] {
+		t.Errorf("Evaluate()=%g,%v; want %g", f, err, want[0])
+	}
+	if diff := cmp.Diff(want, c.EvaluateAll(context.Background(), pop)); diff != "" {
+		t.Errorf("EvaluateAll() fitness differs; -want +got:\n%s", diff)
+	}
+}
+
+func TestEvaluateAllCanceled(t *testing.T) {
+	w := newWorker(t, genetics.FitnessFunc(sum))
+	w.delay = 10 * time.Second
+	c := &distributed.Client{Workers: []string{w.URL}, Retries: 3}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, errs := c.EvaluateAllErrs(ctx, population(t, 2))
+	for i, err := range errs {
+		if err == nil {
+			t.Errorf("EvaluateAllErrs() scored chromosome %d after ctx was done", i)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("EvaluateAllErrs() waited %s after ctx was done", elapsed)
+	}
+	if w.requests != 1 {
+		t.Errorf("worker served %d requests; want no retries once ctx is done", w.requests)
+	}
+}
+
+func TestEvaluationErrors(t *testing.T) {
+	fail := errors.New("simulation diverged")
+	w := newWorker(t, evaluatorFunc(func(c genetics.Chromosome) (genetics.Fitness, error) {
+		if c.Genes[0] == 0 {
+			return 0, fail
+		}
+		return sum(c), nil
+	}))
+	c := &distributed.Client{Workers: []string{w.URL}, Retries: 2}
+	s := genetics.NewSpecies(2, 9)
+	_, errs := c.EvaluateAllErrs(context.Background(), []genetics.Chromosome{s.New(0, 1), s.New(1, 1)})
+	if errs[0] == nil || errs[0].Error() != fail.Error() || errs[1] != nil {
+		t.Errorf("EvaluateAllErrs() errs=%v; want only the first to fail", errs)
+	}
+	if w.requests != 1 {
+		t.Errorf("failed evaluations were retried; %d requests", w.requests)
+	}
+}
+
+type evaluatorFunc func(c genetics.Chromosome) (genetics.Fitness, error)
+
+func (f evaluatorFunc) Evaluate(c genetics.Chromosome) (genetics.Fitness, error) {
+	return f(c)
+}
+
+func TestRetries(t *testing.T) {
+	flaky, healthy := newWorker(t, genetics.FitnessFunc(sum)), newWorker(t, genetics.FitnessFunc(sum))
+	flaky.failures = 100
+	c := &distributed.Client{Workers: []string{flaky.URL, healthy.URL}, Retries: 1}
+	pop := population(t, 4)
+	_, errs := c.EvaluateAllErrs(context.Background(), pop)
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("EvaluateAllErrs() failed chromosome %d: %s", i, err)
+		}
+	}
+	if flaky.requests != 1 || healthy.requests != 2 {
+		t.Errorf("flaky worker served %d and healthy worker %d requests; want 1 and 2", flaky.requests, healthy.requests)
+	}
+
+	c.Retries = 0
+	c.Workers = []string{flaky.URL}
+	if _, err := c.Evaluate(pop[0]); err == nil {
+		t.Error("Evaluate() should fail when every attempt fails")
+	}
+}
+
+func TestStragglers(t *testing.T) {
+	slow, fast := newWorker(t, genetics.FitnessFunc(sum)), newWorker(t, genetics.FitnessFunc(sum))
+	slow.delay = 10 * time.Second
+	c := &distributed.Client{Workers: []string{slow.URL, fast.URL}, StragglerTimeout: 20 * time.Millisecond}
+	pop := population(t, 1)
+	start := time.Now()
+	if f, err := c.Evaluate(pop[0]); err != nil || f != sum(pop[0]) {
+		t.Errorf("Evaluate()=%g,%v; want %g", f, err, sum(pop[0]))
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Evaluate() waited %s for a straggler", elapsed)
+	}
+	if fast.requests != 1 {
+		t.Errorf("the straggling request was not hedged; fast worker served %d requests", fast.requests)
+	}
+}
+
+func TestEngine(t *testing.T) {
+	workers := []*worker{newWorker(t, genetics.FitnessFunc(sum)), newWorker(t, genetics.FitnessFunc(sum))}
+	c := &distributed.Client{}
+	for _, w := range workers {
+		c.Workers = append(c.Workers, w.URL)
+	}
+	rng := rand.New()
+	rng.Seed(42)
+	pop := &genetics.Population{Species: genetics.NewSpecies(5, 9), Chromosomes: population(t, 6)}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 2,
+			CrossoverRate:    1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator:          genetics.SwapMutation{},
+		},
+		Evaluator: c,
+	}
+	const generations = 3
+	if err := engine.Run(rng, pop, generations); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	for i, c := range pop.Chromosomes {
+		if pop.Fitness[i] != sum(c) {
+			t.Errorf("chromosome %d scored %g; want %g", i, pop.Fitness[i], sum(c))
+		}
+	}
+	// Each generation is one shard per worker rather than a request per chromosome
+	for i, w := range workers {
+		if w.requests == 0 || w.requests > generations+1 {
+			t.Errorf("worker %d served %d requests; want one per generation", i, w.requests)
+		}
+	}
+}
+
+func TestEngineEvaluationErrors(t *testing.T) {
+	w := newWorker(t, evaluatorFunc(func(c genetics.Chromosome) (genetics.Fitness, error) {
+		return 0, errors.New("simulation diverged")
+	}))
+	pop := &genetics.Population{Species: genetics.NewSpecies(5, 9), Chromosomes: population(t, 4)}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 2,
+			CrossoverRate:    1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator:          genetics.SwapMutation{},
+		},
+		Evaluator: &distributed.Client{Workers: []string{w.URL}},
+	}
+	var budget *genetics.EvaluationBudgetError
+	if err := engine.Run(rand.New(), pop, 1); !errors.As(err, &budget) || budget.Err.Error() != "simulation diverged" {
+		t.Errorf("Run(); err=%v, want an EvaluationBudgetError for the workers' failures", err)
+	}
+}
+
+func TestHandlerLimits(t *testing.T) {
+	h := distributed.Handler(genetics.FitnessFunc(sum))
+	for _, test := range []struct {
+		name   string
+		method string
+		body   string
+		want   int
+	}{
+		{name: "GET", method: "GET", want: http.StatusMethodNotAllowed},
+		{name: "malformed", method: "POST", body: "{", want: http.StatusBadRequest},
+		{name: "too large", method: "POST", body: `{"chromosomes": [` + strings.Repeat(" ", distributed.MaxRequestBytes) + `]}`, want: http.StatusRequestEntityTooLarge},
+		{name: "empty", method: "POST", body: `{"chromosomes": []}`, want: http.StatusOK},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(test.method, "/", strings.NewReader(test.body)))
+			if rec.Code != test.want {
+				t.Errorf("ServeHTTP() status=%d; want %d", rec.Code, test.want)
+			}
+		})
+	}
+}
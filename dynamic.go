@@ -0,0 +1,31 @@
+package genetics
+
+// reevaluateOperator is the provenance of chromosomes rescored after FitnessChanged
+const reevaluateOperator = "Reevaluate"
+
+// FitnessChanged marks the Engine's scores as stale because the fitness function
+// changed, e.g. when optimizing against live data. It clears the Cache and
+// rescores every member of the population, including the elites, at the start of
+// the next generation so that outdated scores do not steer selection. If response
+// is set, it is triggered to restore diversity with hypermutation or partial
+// reinitialization; it should also be one of the Engine's Observers.
+//
+// FitnessChanged may be called between Steps or by an Observer's OnGenerationStart.
+func (e *Engine) FitnessChanged(response *StagnationPolicy) {
+	if e.Cache != nil {
+		e.Cache.Clear()
+	}
+	pending := make(map[int]bool, len(e.pending))
+	for _, i := range e.pending {
+		pending[i] = true
+	}
+	for i := range e.pop.Chromosomes {
+		if !pending[i] {
+			e.origins[i] = reevaluateOperator
+			e.pending = append(e.pending, i)
+		}
+	}
+	if response != nil {
+		response.Trigger(e)
+	}
+}
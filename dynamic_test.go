@@ -0,0 +1,76 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestEngineFitnessChanged(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	// The environment rewards ones until it changes to reward zeros.
+	changed := false
+	eval := &countingEvaluator{fn: func(c genetics.Chromosome) genetics.Fitness {
+		if changed {
+			return genetics.Fitness(len(c.Genes)) - oneMax(c)
+		}
+		return oneMax(c)
+	}}
+	policy := &genetics.StagnationPolicy{
+		Generations:              1000,
+		HypermutationRate:        0.9,
+		HypermutationGenerations: 2,
+	}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 4,
+			CrossoverRate:    1,
+			MutationRate:     0.1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator:          genetics.SwapMutation{},
+		},
+		Evaluator: eval,
+		Cache:     genetics.NewFitnessCache(100),
+		Observers: []genetics.Observer{policy},
+	}
+	pop := newBinaryPopulation(t, rng, 8, 10)
+	engine.Reset(pop)
+	for gen := 0; gen < 5; gen++ {
+		if err := engine.Step(rng); err != nil {
+			t.Fatalf("Step(); err=%s", err)
+		}
+	}
+
+	changed = true
+	engine.FitnessChanged(policy)
+	if engine.Cache.Len() != 0 {
+		t.Errorf("FitnessChanged() left %d cached scores", engine.Cache.Len())
+	}
+	if policy.Triggers() != 1 || engine.Evolver.MutationRate != 0.9 {
+		t.Errorf("FitnessChanged() triggered the response %d times with MutationRate %g; want 1 and 0.9", policy.Triggers(), engine.Evolver.MutationRate)
+	}
+	before, snapshot := eval.count, pop.Clone()
+	if err := engine.Step(rng); err != nil {
+		t.Fatalf("Step(); err=%s", err)
+	}
+	stats := engine.Stats()
+	if stats.Evaluations != 10 || stats.Offspring != 4 {
+		t.Errorf("Stats()=%+v after the change; want all 10 chromosomes rescored, of which 4 were offspring", stats)
+	}
+	if eval.count-before > 10 {
+		t.Errorf("Step() made %d evaluations; want at most 10", eval.count-before)
+	}
+	best := genetics.Fitness(-1)
+	for _, c := range snapshot.Chromosomes {
+		if f := eval.fn(c); f > best {
+			best = f
+		}
+	}
+	if stats.Best != best {
+		t.Errorf("Stats().Best=%g; want %g in the new environment", stats.Best, best)
+	}
+}
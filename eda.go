@@ -0,0 +1,306 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/inlined/rand"
+)
+
+// distribution is the model shared by the estimation-of-distribution drivers
+// (CompactGA, PBIL, and UMDA), which evolve the probability that each gene of a
+// binary Species is 1 instead of a population. Like the other drivers they share
+// Evaluators, Terminations, Stats, and StatsObservers with Engine, and they are
+// not goroutine safe. Unlike Engine, which follows a Population's Objective,
+// they always maximize Fitness.
+type distribution struct {
+	generation  int
+	p           []float64
+	best        Chromosome
+	bestFitness Fitness
+	stats       Stats
+}
+
+// Probabilities returns the probability that each gene is 1. The slice is
+// updated in place by later generations.
+func (d *distribution) Probabilities() []float64 {
+	return d.p
+}
+
+// Best returns the fittest Chromosome evaluated since the run began.
+func (d *distribution) Best() (Chromosome, Fitness) {
+	return d.best, d.bestFitness
+}
+
+// Stats summarizes the most recent generation.
+func (d *distribution) Stats() Stats {
+	return d.stats
+}
+
+// reset validates s and sets every probability to 0.5.
+func (d *distribution) reset(name string, s *Species) error {
+	if s == nil || s.MaxAllele != 1 || s.MaxAlleles != nil {
+		return fmt.Errorf("%s.Run(); Species must be binary, with a MaxAllele of 1", name)
+	}
+	d.generation = 0
+	d.p = make([]float64, s.NumGenes)
+	for i := range d.p {
+		d.p[i] = 0.5
+	}
+	d.best, d.bestFitness, d.stats = Chromosome{}, 0, Stats{}
+	return nil
+}
+
+// sample overwrites c with genes drawn from the distribution.
+func (d *distribution) sample(r rand.Rand, c Chromosome) {
+	for i, p := range d.p {
+		c.Genes[i] = 0
+		if r.Float64() < p {
+			c.Genes[i] = 1
+		}
+	}
+}
+
+// evaluate scores c and remembers a copy of it if it is the fittest so far.
+func (d *distribution) evaluate(e Evaluator, c Chromosome) (Fitness, error) {
+	f, err := e.Evaluate(c)
+	if err != nil {
+		return 0, err
+	}
+	if d.best.Genes == nil || f > d.bestFitness {
+		d.best, d.bestFitness = c.Clone(), f
+	}
+	return f, nil
+}
+
+// run calls step, which samples, scores, and learns from one generation, for up
+// to generations generations and reports each generation's Stats. step returns
+// the scores to summarize and the number of evaluations it made.
+func (d *distribution) run(name string, generations int, terminate Termination, observers []StatsObserver, step func() ([]Fitness, int, error)) (Chromosome, Fitness, error) {
+	for ; d.generation < generations; d.generation++ {
+		scores, evaluations, err := step()
+		if err != nil {
+			return d.best, d.bestFitness, fmt.Errorf("%s.Run(); generation %d: %s", name, d.generation, err)
+		}
+		d.stats = Stats{Generation: d.generation, Evaluations: evaluations}
+		d.stats.summarize(scores)
+		for _, o := range observers {
+			o.OnStats(d.stats)
+		}
+		if (terminate != nil && terminate(d.stats)) || d.converged() {
+			d.generation++
+			break
+		}
+	}
+	return d.best, d.bestFitness, nil
+}
+
+// converged reports whether every probability is 0 or 1, so that every sample
+// would be the same.
+func (d *distribution) converged() bool {
+	for _, p := range d.p {
+		if p > 0 && p < 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// CompactGA is the compact genetic algorithm (cGA) of Harik, Lobo, and Goldberg.
+// It mimics a genetic algorithm with a population of PopulationSize and uniform
+// crossover using only a probability per gene: each generation samples two
+// Chromosomes and shifts the probabilities of the genes on which they differ by
+// 1/PopulationSize toward the fitter. It needs memory for only two Chromosomes.
+// A run ends early once every probability is 0 or 1.
+type CompactGA struct {
+	distribution
+
+	Species   *Species
+	Evaluator Evaluator
+
+	// PopulationSize is the size of the simulated population. Defaults to 100.
+	PopulationSize int
+
+	// Terminate, if set, ends a Run early once a generation satisfies it.
+	Terminate Termination
+	// Observers are notified of the Stats of each generation.
+	Observers []StatsObserver
+}
+
+// Run evolves the distribution for up to the given number of generations and
+// returns the fittest Chromosome evaluated.
+func (c *CompactGA) Run(r rand.Rand, generations int) (Chromosome, Fitness, error) {
+	if err := c.reset("CompactGA", c.Species); err != nil {
+		return Chromosome{}, 0, err
+	}
+	n := c.PopulationSize
+	if n == 0 {
+		n = 100
+	}
+	if n < 2 {
+		return Chromosome{}, 0, fmt.Errorf("CompactGA.Run(); PopulationSize %d must be at least 2", n)
+	}
+	shift := 1 / float64(n)
+	a, b := c.Species.New(), c.Species.New()
+	scores := make([]Fitness, 2)
+	return c.run("CompactGA", generations, c.Terminate, c.Observers, func() ([]Fitness, int, error) {
+		var err error
+		for i, x := range []Chromosome{a, b} {
+			c.sample(r, x)
+			if scores[i], err = c.evaluate(c.Evaluator, x); err != nil {
+				return nil, 0, err
+			}
+		}
+		winner, loser := a, b
+		if scores[1] > scores[0] {
+			winner, loser = b, a
+		}
+		for i := range c.p {
+			switch {
+			case winner.Genes[i] == loser.Genes[i]:
+			case winner.Genes[i] == 1:
+				c.p[i] = math.Min(1, c.p[i]+shift)
+			default:
+				c.p[i] = math.Max(0, c.p[i]-shift)
+			}
+		}
+		return scores, len(scores), nil
+	})
+}
+
+// PBIL is population-based incremental learning (Baluja). Each generation samples
+// Samples Chromosomes and moves each gene's probability toward the fittest
+// sample's gene by LearningRate. With probability MutationRate, each probability
+// is also moved toward a random 0 or 1 by MutationShift to preserve diversity.
+type PBIL struct {
+	distribution
+
+	Species   *Species
+	Evaluator Evaluator
+
+	// Samples is the number of Chromosomes sampled per generation. Defaults to 100.
+	Samples int
+	// LearningRate is in (0, 1]. Defaults to 0.1.
+	LearningRate float64
+	// MutationRate is the probability that each probability is mutated. Defaults
+	// to 0.
+	MutationRate float64
+	// MutationShift is in (0, 1]. Defaults to 0.05.
+	MutationShift float64
+
+	// Terminate, if set, ends a Run early once a generation satisfies it.
+	Terminate Termination
+	// Observers are notified of the Stats of each generation.
+	Observers []StatsObserver
+}
+
+// Run evolves the distribution for up to the given number of generations and
+// returns the fittest Chromosome evaluated.
+func (p *PBIL) Run(r rand.Rand, generations int) (Chromosome, Fitness, error) {
+	if err := p.reset("PBIL", p.Species); err != nil {
+		return Chromosome{}, 0, err
+	}
+	samples, rate, shift := p.Samples, p.LearningRate, p.MutationShift
+	if samples == 0 {
+		samples = 100
+	}
+	if rate == 0 {
+		rate = 0.1
+	}
+	if shift == 0 {
+		shift = 0.05
+	}
+	if samples < 1 || rate <= 0 || rate > 1 || shift <= 0 || shift > 1 {
+		return Chromosome{}, 0, fmt.Errorf("PBIL.Run(); Samples %d must be positive and LearningRate %g and MutationShift %g must be in (0, 1]", samples, rate, shift)
+	}
+	x, fittest := p.Species.New(), p.Species.New()
+	scores := make([]Fitness, samples)
+	return p.run("PBIL", generations, p.Terminate, p.Observers, func() ([]Fitness, int, error) {
+		for k := range scores {
+			p.sample(r, x)
+			f, err := p.evaluate(p.Evaluator, x)
+			if err != nil {
+				return nil, 0, err
+			}
+			scores[k] = f
+			if k == 0 || f > scores[0] {
+				// Keep the fittest score first; the order of scores is not reported
+				scores[0], scores[k] = f, scores[0]
+				copy(fittest.Genes, x.Genes)
+			}
+		}
+		for i := range p.p {
+			p.p[i] = (1-rate)*p.p[i] + rate*float64(fittest.Genes[i])
+			if p.MutationRate > 0 && r.Float64() < p.MutationRate {
+				p.p[i] = (1-shift)*p.p[i] + shift*float64(r.Int31n(2))
+			}
+		}
+		return scores, len(scores), nil
+	})
+}
+
+// UMDA is the univariate marginal distribution algorithm (Mühlenbein). Each
+// generation samples Samples Chromosomes and sets each gene's probability to its
+// frequency among the Selected fittest. Probabilities are kept within 1/NumGenes
+// of 0 and 1 so that no allele is lost for good.
+type UMDA struct {
+	distribution
+
+	Species   *Species
+	Evaluator Evaluator
+
+	// Samples is the number of Chromosomes sampled per generation. Defaults to 100.
+	Samples int
+	// Selected is the number of fittest samples learned from. Defaults to half of
+	// Samples.
+	Selected int
+
+	// Terminate, if set, ends a Run early once a generation satisfies it.
+	Terminate Termination
+	// Observers are notified of the Stats of each generation.
+	Observers []StatsObserver
+}
+
+// Run evolves the distribution for up to the given number of generations and
+// returns the fittest Chromosome evaluated.
+func (u *UMDA) Run(r rand.Rand, generations int) (Chromosome, Fitness, error) {
+	if err := u.reset("UMDA", u.Species); err != nil {
+		return Chromosome{}, 0, err
+	}
+	samples := u.Samples
+	if samples == 0 {
+		samples = 100
+	}
+	selected := u.Selected
+	if selected == 0 {
+		selected = samples / 2
+	}
+	if selected < 1 || selected > samples {
+		return Chromosome{}, 0, fmt.Errorf("UMDA.Run(); Selected %d must be in [1, Samples %d]", selected, samples)
+	}
+	margin := 1 / float64(u.Species.NumGenes)
+	xs := make([]Chromosome, samples)
+	for k := range xs {
+		xs[k] = u.Species.New()
+	}
+	scores := make([]Fitness, samples)
+	return u.run("UMDA", generations, u.Terminate, u.Observers, func() ([]Fitness, int, error) {
+		for k, x := range xs {
+			u.sample(r, x)
+			f, err := u.evaluate(u.Evaluator, x)
+			if err != nil {
+				return nil, 0, err
+			}
+			scores[k] = f
+		}
+		order := rankIndexes(scores)[:selected]
+		for i := range u.p {
+			ones := 0
+			for _, k := range order {
+				ones += xs[k].Genes[i]
+			}
+			u.p[i] = math.Max(margin, math.Min(1-margin, float64(ones)/float64(selected)))
+		}
+		return scores, len(scores), nil
+	})
+}
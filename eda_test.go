@@ -0,0 +1,96 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestEDAs(t *testing.T) {
+	s := genetics.NewSpecies(50, 1)
+	for _, test := range []struct {
+		tag string
+		run func(rng rand.Rand, observers []genetics.StatsObserver) (genetics.Chromosome, genetics.Fitness, []float64, error)
+		// evaluations is the number of evaluations per generation
+		evaluations int
+	}{
+		{
+			tag: "CompactGA",
+			run: func(rng rand.Rand, observers []genetics.StatsObserver) (genetics.Chromosome, genetics.Fitness, []float64, error) {
+				c := &genetics.CompactGA{Species: s, Evaluator: genetics.FitnessFunc(oneMax), Observers: observers}
+				best, f, err := c.Run(rng, 5000)
+				return best, f, c.Probabilities(), err
+			},
+			evaluations: 2,
+		}, {
+			tag: "PBIL",
+			run: func(rng rand.Rand, observers []genetics.StatsObserver) (genetics.Chromosome, genetics.Fitness, []float64, error) {
+				p := &genetics.PBIL{Species: s, Evaluator: genetics.FitnessFunc(oneMax), Samples: 50, MutationRate: 0.02, Observers: observers}
+				best, f, err := p.Run(rng, 100)
+				return best, f, p.Probabilities(), err
+			},
+			evaluations: 50,
+		}, {
+			tag: "UMDA",
+			run: func(rng rand.Rand, observers []genetics.StatsObserver) (genetics.Chromosome, genetics.Fitness, []float64, error) {
+				u := &genetics.UMDA{Species: s, Evaluator: genetics.FitnessFunc(oneMax), Observers: observers}
+				best, f, err := u.Run(rng, 50)
+				return best, f, u.Probabilities(), err
+			},
+			evaluations: 100,
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			rng := rand.New()
+			rng.Seed(42)
+			recorder := &statsRecorder{}
+			best, f, p, err := test.run(rng, []genetics.StatsObserver{recorder})
+			if err != nil {
+				t.Fatalf("Run(); err=%s", err)
+			}
+			if f < 48 || oneMax(best) != f {
+				t.Errorf("Run() found %v scoring %g; want at least 48 of 50", best.Genes, f)
+			}
+			mean := 0.0
+			for _, pi := range p {
+				mean += pi
+			}
+			if mean /= float64(len(p)); mean < 0.9 {
+				t.Errorf("Probabilities() have mean %g; want them near 1", mean)
+			}
+			if len(recorder.stats) == 0 || recorder.stats[0].Evaluations != test.evaluations {
+				t.Fatalf("expected Stats for generations of %d evaluations; got %+v", test.evaluations, recorder.stats)
+			}
+			if last := recorder.stats[len(recorder.stats)-1]; last.Best < recorder.stats[0].Best {
+				t.Errorf("best of the last generation %g is worse than the first %g", last.Best, recorder.stats[0].Best)
+			}
+		})
+	}
+}
+
+func TestEDATerminate(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	u := &genetics.UMDA{
+		Species:   genetics.NewSpecies(20, 1),
+		Evaluator: genetics.FitnessFunc(oneMax),
+		Terminate: genetics.TargetFitness(20),
+	}
+	if _, f, err := u.Run(rng, 1000); err != nil || f != 20 {
+		t.Fatalf("Run()=%g, %v; want 20", f, err)
+	}
+	if u.Stats().Generation > 100 {
+		t.Errorf("Run() continued to generation %d after reaching the target", u.Stats().Generation)
+	}
+
+	c := &genetics.CompactGA{Species: genetics.NewSpecies(10, 3), Evaluator: genetics.FitnessFunc(oneMax)}
+	if _, _, err := c.Run(rng, 10); err == nil {
+		t.Error("CompactGA.Run() should reject non-binary Species")
+	}
+	p := &genetics.PBIL{Species: genetics.NewSpecies(10, 1), Evaluator: genetics.FitnessFunc(oneMax), LearningRate: 2}
+	if _, _, err := p.Run(rng, 10); err == nil {
+		t.Error("PBIL.Run() should reject a LearningRate above 1")
+	}
+}
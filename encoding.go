@@ -0,0 +1,118 @@
+package genetics
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type jsonSpecies struct {
+	NumGenes   int    `json:"numGenes"`
+	MaxAllele  Gene   `json:"maxAllele"`
+	MinAlleles []Gene `json:"minAlleles,omitempty"`
+	MaxAlleles []Gene `json:"maxAlleles,omitempty"`
+}
+
+type jsonChromosome struct {
+	Species *Species `json:"species"`
+	Genes   []Gene   `json:"genes"`
+}
+
+type jsonPopulation struct {
+	Species     *Species  `json:"species"`
+	Chromosomes [][]Gene  `json:"chromosomes"`
+	Fitness     []Fitness `json:"fitness,omitempty"`
+	Objective   Objective `json:"objective,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler
+func (s Species) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonSpecies{
+		NumGenes:   s.NumGenes,
+		MaxAllele:  s.MaxAllele,
+		MinAlleles: s.MinAlleles,
+		MaxAlleles: s.MaxAlleles,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *Species) UnmarshalJSON(b []byte) error {
+	var j jsonSpecies
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	if j.NumGenes <= 0 || j.MaxAllele < 0 {
+		return fmt.Errorf("Species.UnmarshalJSON(%s); NumGenes must be positive and MaxAllele must not be negative", b)
+	}
+	if j.MinAlleles != nil || j.MaxAlleles != nil {
+		r, err := NewRangedSpecies(j.MinAlleles, j.MaxAlleles)
+		if err != nil {
+			return fmt.Errorf("Species.UnmarshalJSON(%s); err=%s", b, err)
+		}
+		if r.NumGenes != j.NumGenes {
+			return fmt.Errorf("Species.UnmarshalJSON(%s); expected %d allele ranges, got %d", b, j.NumGenes, r.NumGenes)
+		}
+		*s = *r
+		return nil
+	}
+	s.NumGenes, s.MaxAllele = j.NumGenes, j.MaxAllele
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The Chromosome's Species is encoded alongside
+// its Genes so that the Chromosome can be validated when it is decoded.
+func (c Chromosome) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonChromosome{Species: c.Species, Genes: c.Genes})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (c *Chromosome) UnmarshalJSON(b []byte) error {
+	var j jsonChromosome
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	if j.Species == nil {
+		return fmt.Errorf("Chromosome.UnmarshalJSON(); missing species")
+	}
+	if err := j.Species.validateGenes(j.Genes); err != nil {
+		return fmt.Errorf("Chromosome.UnmarshalJSON(); %s", err)
+	}
+	c.Species, c.Genes = j.Species, j.Genes
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The Species is encoded once for the whole Population.
+func (p Population) MarshalJSON() ([]byte, error) {
+	j := jsonPopulation{
+		Species:     p.Species,
+		Chromosomes: make([][]Gene, len(p.Chromosomes)),
+		Fitness:     p.Fitness,
+		Objective:   p.Objective,
+	}
+	for n, c := range p.Chromosomes {
+		j.Chromosomes[n] = c.Genes
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (p *Population) UnmarshalJSON(b []byte) error {
+	var j jsonPopulation
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	if j.Species == nil {
+		return fmt.Errorf("Population.UnmarshalJSON(); missing species")
+	}
+	if j.Fitness != nil && len(j.Fitness) != len(j.Chromosomes) {
+		return fmt.Errorf("Population.UnmarshalJSON(); %d chromosomes but %d fitness scores", len(j.Chromosomes), len(j.Fitness))
+	}
+	chromosomes := make([]Chromosome, len(j.Chromosomes))
+	for n, g := range j.Chromosomes {
+		if err := j.Species.validateGenes(g); err != nil {
+			return fmt.Errorf("Population.UnmarshalJSON(); chromosome %d: %s", n, err)
+		}
+		chromosomes[n] = Chromosome{Species: j.Species, Genes: g}
+	}
+	p.Species, p.Chromosomes, p.Fitness, p.Objective = j.Species, chromosomes, j.Fitness, j.Objective
+	return nil
+}
@@ -0,0 +1,113 @@
+package genetics_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/inlined/genetics"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	s := genetics.NewSpecies(3, 9)
+	c := s.New(1, 9, 0)
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("json.Marshal(Chromosome); err=%s", err)
+	}
+	var gotChromosome genetics.Chromosome
+	if err := json.Unmarshal(b, &gotChromosome); err != nil {
+		t.Fatalf("json.Unmarshal(%s); err=%s", b, err)
+	}
+	if diff := cmp.Diff(c, gotChromosome); diff != "" {
+		t.Errorf("Chromosome did not round trip through %s; diff=%s", b, diff)
+	}
+
+	pop := genetics.Population{
+		Species:     s,
+		Chromosomes: []genetics.Chromosome{s.New(1, 2, 3), s.New(4, 5, 6)},
+		Fitness:     []genetics.Fitness{6, 15},
+	}
+	b, err = json.Marshal(pop)
+	if err != nil {
+		t.Fatalf("json.Marshal(Population); err=%s", err)
+	}
+	var gotPop genetics.Population
+	if err := json.Unmarshal(b, &gotPop); err != nil {
+		t.Fatalf("json.Unmarshal(%s); err=%s", b, err)
+	}
+	if diff := cmp.Diff(pop, gotPop); diff != "" {
+		t.Errorf("Population did not round trip through %s; diff=%s", b, diff)
+	}
+	for n, c := range gotPop.Chromosomes {
+		if c.Species != gotPop.Species {
+			t.Errorf("decoded chromosome %d does not share the Population's Species", n)
+		}
+	}
+
+	ranged, err := genetics.NewRangedSpecies([]genetics.Gene{0, 10}, []genetics.Gene{7, 255})
+	if err != nil {
+		t.Fatalf("NewRangedSpecies(); err=%s", err)
+	}
+	b, err = json.Marshal(ranged)
+	if err != nil {
+		t.Fatalf("json.Marshal(Species); err=%s", err)
+	}
+	var gotSpecies genetics.Species
+	if err := json.Unmarshal(b, &gotSpecies); err != nil {
+		t.Fatalf("json.Unmarshal(%s); err=%s", b, err)
+	}
+	if diff := cmp.Diff(*ranged, gotSpecies); diff != "" {
+		t.Errorf("Species did not round trip through %s; diff=%s", b, diff)
+	}
+}
+
+func TestJSONValidation(t *testing.T) {
+	for _, test := range []struct {
+		tag  string
+		json string
+		val  interface{}
+	}{
+		{
+			tag:  "species without genes",
+			json: `{"numGenes":0,"maxAllele":1}`,
+			val:  &genetics.Species{},
+		}, {
+			tag:  "species with mismatched ranges",
+			json: `{"numGenes":3,"maxAllele":7,"minAlleles":[0,0],"maxAlleles":[7,7]}`,
+			val:  &genetics.Species{},
+		}, {
+			tag:  "chromosome too short",
+			json: `{"species":{"numGenes":3,"maxAllele":1},"genes":[0,1]}`,
+			val:  &genetics.Chromosome{},
+		}, {
+			tag:  "chromosome allele too large",
+			json: `{"species":{"numGenes":2,"maxAllele":1},"genes":[0,2]}`,
+			val:  &genetics.Chromosome{},
+		}, {
+			tag:  "chromosome negative allele",
+			json: `{"species":{"numGenes":2,"maxAllele":1},"genes":[-1,0]}`,
+			val:  &genetics.Chromosome{},
+		}, {
+			tag:  "chromosome without species",
+			json: `{"genes":[0,1]}`,
+			val:  &genetics.Chromosome{},
+		}, {
+			tag:  "population with bad chromosome",
+			json: `{"species":{"numGenes":2,"maxAllele":1},"chromosomes":[[0,1],[0,5]]}`,
+			val:  &genetics.Population{},
+		}, {
+			tag:  "population with missing scores",
+			json: `{"species":{"numGenes":2,"maxAllele":1},"chromosomes":[[0,1],[1,0]],"fitness":[1]}`,
+			val:  &genetics.Population{},
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(test.json), test.val); err == nil {
+				t.Errorf("json.Unmarshal(%s) should fail", test.json)
+			}
+		})
+	}
+}
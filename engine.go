@@ -0,0 +1,600 @@
+package genetics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/inlined/rand"
+)
+
+// initialOperator is the provenance of chromosomes which were passed to Engine.Reset
+const initialOperator = "Initial"
+
+// Evaluator scores a Chromosome. Evaluation may fail, e.g. when fitness is computed
+// by an external simulation or service.
+type Evaluator interface {
+	Evaluate(c Chromosome) (Fitness, error)
+}
+
+// BatchEvaluator scores many Chromosomes in one call, e.g. with a vectorized
+// library, a GPU, or an external service. EvaluateAll returns a score for each
+// Chromosome in order. It cannot report individual failures (see
+// FallibleBatchEvaluator); it should return early once ctx is done, and its scores
+// are then discarded.
+//
+// An Engine whose Evaluator also implements BatchEvaluator scores each generation
+// with a single call to EvaluateAll. Use BatchFunc to adapt a function.
+type BatchEvaluator interface {
+	EvaluateAll(ctx context.Context, cs []Chromosome) []Fitness
+}
+
+// FallibleBatchEvaluator is a BatchEvaluator whose evaluations can fail one by one,
+// e.g. the remote workers of package distributed. An Engine calls EvaluateAllErrs
+// instead of EvaluateAll and treats errs[i] as the error of evaluating cs[i], which
+// counts against MaxFailureRate.
+type FallibleBatchEvaluator interface {
+	BatchEvaluator
+	EvaluateAllErrs(ctx context.Context, cs []Chromosome) (fitness []Fitness, errs []error)
+}
+
+// BatchFunc adapts a batch fitness function into an Evaluator and BatchEvaluator.
+type BatchFunc func(ctx context.Context, cs []Chromosome) []Fitness
+
+// Evaluate implements Evaluator by scoring a batch of one.
+func (f BatchFunc) Evaluate(c Chromosome) (Fitness, error) {
+	return f(context.Background(), []Chromosome{c})[0], nil
+}
+
+// EvaluateAll implements BatchEvaluator
+func (f BatchFunc) EvaluateAll(ctx context.Context, cs []Chromosome) []Fitness {
+	return f(ctx, cs)
+}
+
+// FitnessFunc adapts a fitness function which cannot fail into an Evaluator.
+type FitnessFunc func(c Chromosome) Fitness
+
+// Evaluate implements Evaluator
+func (f FitnessFunc) Evaluate(c Chromosome) (Fitness, error) {
+	return f(c), nil
+}
+
+// EvaluationBudgetError is returned when more than an Engine's MaxFailureRate of
+// evaluations in a generation fail.
+type EvaluationBudgetError struct {
+	Generation  int
+	Failures    int
+	Evaluations int
+	// Err is the most recent evaluation failure
+	Err error
+}
+
+func (e *EvaluationBudgetError) Error() string {
+	return fmt.Sprintf("generation %d: %d of %d evaluations failed; last err=%s", e.Generation, e.Failures, e.Evaluations, e.Err)
+}
+
+// Unwrap returns the most recent evaluation failure
+func (e *EvaluationBudgetError) Unwrap() error {
+	return e.Err
+}
+
+// Engine runs an Evolver for many generations, scoring the population with an Evaluator.
+// Only chromosomes which are new to the population are evaluated each generation.
+// Like NaturalSelection, an Engine is not goroutine safe.
+type Engine struct {
+	Evolver   Evolver
+	Evaluator Evaluator
+
+	// MaxFailureRate is the fraction of a generation's evaluations which may fail
+	// before the run is aborted with an *EvaluationBudgetError. Chromosomes which
+	// could not be evaluated are given the worst fitness scored in their generation,
+	// or so far in it if the run is aborted. Chromosomes which an aborted run did not
+	// reach keep their stale Fitness and are evaluated if the Engine steps again.
+	// The zero value aborts on the first failure.
+	MaxFailureRate float64
+
+	// LocalSearch, if set, improves each child after it is first evaluated, spending
+	// up to LocalSearchBudget additional evaluations per child.
+	LocalSearch       LocalSearch
+	LocalSearchBudget int
+
+	// Problem, if set, is data about the problem being solved (e.g. a distance
+	// matrix) which is passed to the ContextualOperators of Evolver and
+	// LocalSearch each generation.
+	Problem interface{}
+
+	// MutationControl, if set, adapts Evolver.MutationRate after each generation is scored.
+	MutationControl MutationController
+
+	// Cache, if set, memoizes scores so that chromosomes identical to a recently
+	// evaluated one are not evaluated again.
+	Cache *FitnessCache
+
+	// Audit, if set, records every evaluation made by the Engine.
+	Audit *AuditLog
+
+	// Lineage, if set, records the genealogy of every chromosome in the population.
+	Lineage *Lineage
+
+	// Speciation, if set, selects parents by their fitness shared within Niches
+	// and replaces the members of eliminated Niches first. Stats and Population
+	// scores remain raw.
+	Speciation *Speciation
+
+	// Terminate, if set, ends a Run early once a scored generation satisfies it.
+	Terminate Termination
+
+	// AnalyzeLoci, if set, fills in Stats.Loci for each scored generation, at the
+	// cost of a pass over every gene of the population.
+	AnalyzeLoci bool
+
+	// Tracer, if set, records spans around the evaluation, selection, crossover, and
+	// mutation of each generation. Spans are children of Context, if set, which is
+	// also passed to a BatchEvaluator.
+	Tracer  Tracer
+	Context context.Context
+
+	// Observers are notified of progress in the order they are listed.
+	Observers []Observer
+
+	// ReuseBuffers, if set, keeps the scratch space of each generation and
+	// releases the Chromosomes its children replace (or that Replace replaces) to
+	// their Species, so that later children are written into them instead of being
+	// allocated; see Species.Release. This cuts garbage collection in large
+	// populations, but a Chromosome taken from the population (including by an
+	// Observer) may be overwritten once it is replaced and must be cloned to be
+	// kept. Members of the population must not share Genes.
+	ReuseBuffers bool
+
+	// steering is held by runs while they evolve a generation and by callers
+	// between Pause and Resume.
+	steering sync.Mutex
+
+	generation int
+	pop        *Population
+	origins    []string
+	parents    []Fitness
+	pending    []int
+	stats      Stats
+	buffers    *evolveBuffers
+
+	cacheLookups int
+	cacheHits    int
+}
+
+// Reset prepares the Engine to evolve pop, which is modified in place. Every
+// member of pop will be evaluated, even if pop already has Fitness scores.
+func (e *Engine) Reset(pop *Population) {
+	e.generation = 0
+	e.pop = pop
+	e.buffers = nil
+	if len(pop.Fitness) != len(pop.Chromosomes) {
+		pop.Fitness = make([]Fitness, len(pop.Chromosomes))
+	}
+	e.origins = make([]string, len(pop.Chromosomes))
+	e.parents = make([]Fitness, len(pop.Chromosomes))
+	e.pending = make([]int, len(pop.Chromosomes))
+	e.stats = Stats{}
+	for i := range pop.Chromosomes {
+		e.origins[i] = initialOperator
+		e.pending[i] = i
+	}
+	if e.Lineage != nil {
+		e.Lineage.reset(len(pop.Chromosomes))
+	}
+}
+
+// Generation returns the number of generations evolved since the last Reset.
+func (e *Engine) Generation() int {
+	return e.generation
+}
+
+// Population returns the population being evolved. Scores of chromosomes which
+// have not yet been evaluated are stale.
+func (e *Engine) Population() *Population {
+	return e.pop
+}
+
+// Stats summarizes the most recently scored generation.
+func (e *Engine) Stats() Stats {
+	return e.stats
+}
+
+// Step scores any unevaluated members of the population and then evolves the next generation.
+func (e *Engine) Step(r rand.Rand) error {
+	if err := e.Evolver.Validate(len(e.pop.Chromosomes)); err != nil {
+		return err
+	}
+	if e.MutationControl != nil && e.Evolver.Mutator == nil {
+		return fmt.Errorf("Engine.Step(); MutationControl is set but Mutator is nil")
+	}
+	if e.Speciation != nil {
+		if err := e.Speciation.validate(); err != nil {
+			return fmt.Errorf("Engine.Step(); err=%s", err)
+		}
+	}
+	ctx, span := e.startSpan(e.rootContext(), GenerationSpan)
+	defer span.End()
+	for _, o := range e.Observers {
+		o.OnGenerationStart(e, e.generation)
+	}
+	if err := e.tracedEvaluate(ctx, r); err != nil {
+		return err
+	}
+	if e.MutationControl != nil {
+		e.Evolver.MutationRate = e.MutationControl.AdjustMutationRate(e.Evolver.MutationRate, e.stats)
+	}
+	scores := e.pop.scores()
+	selection, replacement := scores, scores
+	if e.Speciation != nil {
+		selection = e.Speciation.Share(e.generation, e.pop)
+		replacement = e.Speciation.eliminate(e.pop)
+	}
+	switch {
+	case !e.ReuseBuffers:
+		e.buffers = nil
+	case e.buffers == nil:
+		e.buffers = &evolveBuffers{}
+	}
+	ev, err := e.Evolver.withProblem(e.Problem)
+	if err != nil {
+		return fmt.Errorf("Engine.Step(); err=%s", err)
+	}
+	e.learn()
+	evolveCtx, evolveSpan := e.startSpan(ctx, EvolveSpan)
+	replaced := e.traced(evolveCtx, ev).evolve(r, e.pop.Chromosomes, selection, replacement, scores, e.buffers)
+	evolveSpan.End()
+	if e.Lineage != nil {
+		// Look up every parent before any child takes an ID.
+		parents := make([][]int, len(replaced))
+		for n, o := range replaced {
+			for _, source := range o.sources {
+				parents[n] = append(parents[n], e.Lineage.ID(source))
+			}
+		}
+		for n, o := range replaced {
+			e.Lineage.replace(o.index, parents[n], o.operator)
+		}
+	}
+	for _, o := range replaced {
+		e.origins[o.index] = o.operator
+		e.parents[o.index] = o.parent
+		e.pending = append(e.pending, o.index)
+		for _, obs := range e.Observers {
+			obs.OnReplacement(e, o.index, e.pop.Chromosomes[o.index])
+		}
+	}
+	e.generation++
+	return nil
+}
+
+// Replace puts c into the population at index. c will be evaluated at the start
+// of the next generation.
+func (e *Engine) Replace(index int, c Chromosome, operator string) {
+	if old := e.pop.Chromosomes[index]; e.ReuseBuffers && !sharesGenes(old, c) {
+		old.release()
+	}
+	e.pop.Chromosomes[index] = c
+	e.origins[index] = operator
+	if e.Lineage != nil {
+		e.Lineage.replace(index, nil, operator)
+	}
+	for _, i := range e.pending {
+		if i == index {
+			return
+		}
+	}
+	e.pending = append(e.pending, index)
+}
+
+// Run evolves pop in place for the given number of generations and scores the final
+// population. If the run is aborted, pop holds the scores known so far.
+func (e *Engine) Run(r rand.Rand, pop *Population, generations int) error {
+	return e.run(context.Background(), r, pop, generations)
+}
+
+// run implements Run, stopping before the next generation once ctx is done.
+func (e *Engine) run(ctx context.Context, r rand.Rand, pop *Population, generations int) error {
+	e.steeredReset(pop)
+	var err error
+	for err == nil && e.generation < generations {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		var stop bool
+		if stop, err = e.steeredStep(r, nil); stop {
+			break
+		}
+	}
+	return e.finish(r, err)
+}
+
+// finish scores the final population of a run which ended with err and notifies Observers.
+func (e *Engine) finish(r rand.Rand, err error) error {
+	e.steering.Lock()
+	defer e.steering.Unlock()
+	if err == nil {
+		err = e.tracedEvaluate(e.rootContext(), r)
+	}
+	for _, o := range e.Observers {
+		o.OnTermination(e, err)
+	}
+	return err
+}
+
+// tracedEvaluate evaluates the pending members of the population in an Evaluate span.
+func (e *Engine) tracedEvaluate(ctx context.Context, r rand.Rand) error {
+	_, span := e.startSpan(ctx, EvaluateSpan)
+	defer span.End()
+	return e.evaluate(ctx, r)
+}
+
+func (e *Engine) evaluate(ctx context.Context, r rand.Rand) error {
+	var failed []int
+	var lastErr error
+	search, err := localSearchWithProblem(e.LocalSearch, e.Problem)
+	if err != nil {
+		return fmt.Errorf("Engine.Step(); err=%s", err)
+	}
+	eval := e.Evaluator
+	if e.Cache != nil {
+		eval = cachedEvaluator{e}
+	}
+	e.cacheLookups, e.cacheHits = 0, 0
+	var (
+		batch     []Fitness
+		batchErrs []error
+		futures   []*Future
+	)
+	if b, ok := e.Evaluator.(BatchEvaluator); ok && len(e.pending) != 0 {
+		var err error
+		if batch, batchErrs, err = e.evaluateBatch(ctx, b); err != nil {
+			return err
+		}
+	} else if a, ok := e.Evaluator.(AsyncEvaluator); ok {
+		futures = e.submit(a)
+	}
+	for n, i := range e.pending {
+		var f Fitness
+		var err error
+		switch {
+		case batch != nil:
+			f, err = batch[n], batchErrs[n]
+		case futures != nil:
+			if f, err = e.await(ctx, futures[n], i); ctx.Err() != nil {
+				e.pending = e.pending[n:]
+				return fmt.Errorf("Engine.Step(); evaluation was interrupted: %s", err)
+			}
+		default:
+			f, err = eval.Evaluate(e.pop.Chromosomes[i])
+		}
+		if err == nil && search != nil && e.origins[i] != initialOperator {
+			o := e.pop.Objective
+			f, err = search.Improve(r, &e.pop.Chromosomes[i], o.score(f), scoredEvaluator{eval, o}, e.LocalSearchBudget)
+			f = o.score(f)
+			e.origins[i] += "+" + search.String()
+		}
+		if e.Audit != nil {
+			rec := AuditRecord{
+				Generation: e.generation,
+				Genes:      e.pop.Chromosomes[i].Genes,
+				Fitness:    f,
+				Operator:   e.origins[i],
+			}
+			if err != nil {
+				rec.Error = err.Error()
+			}
+			if err := e.Audit.Record(rec); err != nil {
+				e.pending = e.pending[n:]
+				return err
+			}
+		}
+		if err != nil {
+			failed = append(failed, i)
+			lastErr = err
+			if float64(len(failed)) > e.MaxFailureRate*float64(len(e.pending)) {
+				e.scoreFailed(e.pending[:n+1], failed)
+				e.pending = e.pending[n+1:]
+				return &EvaluationBudgetError{
+					Generation:  e.generation,
+					Failures:    len(failed),
+					Evaluations: n + 1,
+					Err:         lastErr,
+				}
+			}
+			continue
+		}
+		e.pop.Fitness[i] = f
+		for _, o := range e.Observers {
+			o.OnEvaluate(e, e.pop.Chromosomes[i], f)
+		}
+	}
+
+	e.scoreFailed(e.pending, failed)
+	if e.Lineage != nil {
+		for _, i := range e.pending {
+			e.Lineage.evaluated(i, e.generation, e.pop.Chromosomes[i], e.pop.Fitness[i], e.origins[i])
+		}
+	}
+	e.updateStats(failed)
+	e.pending = e.pending[:0]
+	return nil
+}
+
+// evaluateBatch scores the pending members of the population with a single call
+// to b, and reports the error of each member that b failed to score. With a Cache,
+// chromosomes which are cached or repeated in the batch are passed to b only once.
+func (e *Engine) evaluateBatch(ctx context.Context, b BatchEvaluator) ([]Fitness, []error, error) {
+	scores := make([]Fitness, len(e.pending))
+	errs := make([]error, len(e.pending))
+	var (
+		// misses[n] is the index in cs which scores pending member n, or -1
+		misses = make([]int, len(e.pending))
+		cs     []Chromosome
+		seen   map[string]int
+	)
+	if e.Cache != nil {
+		seen = make(map[string]int)
+	}
+	for n, i := range e.pending {
+		c := e.pop.Chromosomes[i]
+		misses[n] = -1
+		if e.Cache != nil {
+			e.cacheLookups++
+			if f, ok := e.Cache.Get(c); ok {
+				e.cacheHits++
+				scores[n] = f
+				continue
+			}
+			key := genesKey(c.Genes)
+			if k, ok := seen[key]; ok {
+				e.cacheHits++
+				misses[n] = k
+				continue
+			}
+			seen[key] = len(cs)
+		}
+		misses[n] = len(cs)
+		cs = append(cs, c)
+	}
+	if len(cs) == 0 {
+		return scores, errs, nil
+	}
+	var (
+		fitness []Fitness
+		failed  []error
+	)
+	if fb, ok := b.(FallibleBatchEvaluator); ok {
+		fitness, failed = fb.EvaluateAllErrs(ctx, cs)
+	} else {
+		fitness = b.EvaluateAll(ctx, cs)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, fmt.Errorf("Engine.Step(); batch evaluation was interrupted: %s", err)
+	}
+	if len(fitness) != len(cs) || failed != nil && len(failed) != len(cs) {
+		return nil, nil, fmt.Errorf("Engine.Step(); BatchEvaluator returned %d scores and %d errors for %d chromosomes", len(fitness), len(failed), len(cs))
+	}
+	for n, k := range misses {
+		if k >= 0 {
+			scores[n] = fitness[k]
+			if failed != nil {
+				errs[n] = failed[k]
+			}
+		}
+	}
+	if e.Cache != nil {
+		for k, c := range cs {
+			if failed == nil || failed[k] == nil {
+				e.Cache.Add(c, fitness[k])
+			}
+		}
+	}
+	return scores, errs, nil
+}
+
+// updateStats summarizes the generation whose pending members were just scored.
+func (e *Engine) updateStats(failed []int) {
+	s := Stats{
+		Generation:   e.generation,
+		Objective:    e.pop.Objective,
+		Evaluations:  len(e.pending),
+		Failures:     len(failed),
+		CacheLookups: e.cacheLookups,
+		CacheHits:    e.cacheHits,
+	}
+	var (
+		provenances []string
+		gains       []Fitness
+	)
+	for _, i := range e.pending {
+		if e.origins[i] == initialOperator || e.origins[i] == reevaluateOperator {
+			continue
+		}
+		s.Offspring++
+		gain := e.pop.Objective.score(e.pop.Fitness[i]) - e.parents[i]
+		if gain > 0 {
+			s.Improvements++
+		} else {
+			gain = 0
+		}
+		provenances = append(provenances, e.origins[i])
+		gains = append(gains, gain)
+	}
+	if s.Offspring != 0 {
+		e.reward(provenances, gains)
+		s.Operators = map[string]OperatorStats{}
+		for n, provenance := range provenances {
+			for _, op := range strings.Split(provenance, "+") {
+				o := s.Operators[op]
+				o.add(gains[n])
+				s.Operators[op] = o
+			}
+		}
+	}
+	s.summarize(e.pop.Fitness)
+	if e.AnalyzeLoci && len(e.pop.Chromosomes) != 0 {
+		s.Loci = AnalyzePopulation(e.pop)
+	}
+	e.stats = s
+	for _, o := range e.Observers {
+		if so, ok := o.(StatsObserver); ok {
+			so.OnStats(s)
+		}
+	}
+}
+
+// reward reports the gains of the generation's offspring to the Evolver's
+// adaptive operators.
+func (e *Engine) reward(provenances []string, gains []Fitness) {
+	if a, ok := e.Evolver.Crossover.(AdaptiveOperator); ok {
+		a.Reward(provenances, gains)
+	}
+	if a, ok := e.Evolver.Mutator.(AdaptiveOperator); ok {
+		a.Reward(provenances, gains)
+	}
+}
+
+// learn passes the scored population to the Evolver's PopulationLearners.
+func (e *Engine) learn() {
+	if l, ok := e.Evolver.Selector.(PopulationLearner); ok {
+		l.Learn(e.pop)
+	}
+	if l, ok := e.Evolver.Crossover.(PopulationLearner); ok {
+		l.Learn(e.pop)
+	}
+	if l, ok := e.Evolver.Mutator.(PopulationLearner); ok {
+		l.Learn(e.pop)
+	}
+}
+
+// scoreFailed gives the members in failed the worst fitness of the other members
+// of evaluated.
+func (e *Engine) scoreFailed(evaluated, failed []int) {
+	if len(failed) == 0 {
+		return
+	}
+	worst := e.worstEvaluated(evaluated, failed)
+	for _, i := range failed {
+		e.pop.Fitness[i] = worst
+	}
+}
+
+// worstEvaluated returns the least fit score among the evaluated chromosomes,
+// excluding the indexes in failed.
+func (e *Engine) worstEvaluated(evaluated, failed []int) Fitness {
+	skip := make(map[int]bool, len(failed))
+	for _, i := range failed {
+		skip[i] = true
+	}
+	var worst Fitness
+	found := false
+	for _, i := range evaluated {
+		if !skip[i] && (!found || e.pop.Objective.Better(worst, e.pop.Fitness[i])) {
+			worst = e.pop.Fitness[i]
+			found = true
+		}
+	}
+	return worst
+}
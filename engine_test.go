@@ -0,0 +1,485 @@
+package genetics_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/rand"
+)
+
+func oneMax(c genetics.Chromosome) genetics.Fitness {
+	f := genetics.Fitness(0)
+	for _, g := range c.Genes {
+		f += genetics.Fitness(g)
+	}
+	return f
+}
+
+func newBinaryPopulation(t *testing.T, rng rand.Rand, numGenes, size int) *genetics.Population {
+	pop, err := genetics.NewSpecies(numGenes, 1).NewRandPopulation(rng, size)
+	if err != nil {
+		t.Fatalf("NewRandPopulation(); err=%s", err)
+	}
+	return pop
+}
+
+func TestEngineRun(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	pop := newBinaryPopulation(t, rng, 30, 20)
+	initial := pop.Clone()
+	initial.Fitness = make([]genetics.Fitness, initial.Len())
+	for i, c := range initial.Chromosomes {
+		initial.Fitness[i] = oneMax(c)
+	}
+
+	evaluations := 0
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 10,
+			CrossoverRate:    1,
+			MutationRate:     0.1,
+			Selector:         genetics.TournamentSelection{Size: 3},
+			Crossover:        genetics.MultiPointCrossover{Points: 2},
+			Mutator:          genetics.RandomResettingMutation{},
+		},
+		Evaluator: genetics.FitnessFunc(func(c genetics.Chromosome) genetics.Fitness {
+			evaluations++
+			return oneMax(c)
+		}),
+	}
+	if err := engine.Run(rng, pop, 25); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if engine.Generation() != 25 {
+		t.Errorf("Generation()=%d; want 25", engine.Generation())
+	}
+	if want := 20 + 25*10; evaluations != want {
+		t.Errorf("Run() made %d evaluations; want %d", evaluations, want)
+	}
+	for i, c := range pop.Chromosomes {
+		if pop.Fitness[i] != oneMax(c) {
+			t.Errorf("Run() left stale score %g for chromosome %d; want %g", pop.Fitness[i], i, oneMax(c))
+		}
+	}
+	_, best := pop.Best()
+	_, initialBest := initial.Best()
+	if best <= initialBest {
+		t.Errorf("Run() did not improve the population; got=%g initial=%g", best, initialBest)
+	}
+}
+
+func TestEngineMinimize(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	pop := newBinaryPopulation(t, rng, 30, 20)
+	pop.Objective = genetics.Minimize
+	var initial genetics.Fitness = 30
+	for _, c := range pop.Chromosomes {
+		if f := oneMax(c); f < initial {
+			initial = f
+		}
+	}
+
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 10,
+			CrossoverRate:    1,
+			MutationRate:     0.1,
+			Selector:         genetics.StochasticUniversalSampling{},
+			Crossover:        genetics.MultiPointCrossover{Points: 2},
+			Mutator:          genetics.RandomResettingMutation{},
+		},
+		Evaluator: genetics.FitnessFunc(oneMax),
+		Terminate: genetics.TargetFitness(0),
+	}
+	if err := engine.Run(rng, pop, 100); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	stats := engine.Stats()
+	if stats.Objective != genetics.Minimize || stats.Best > stats.Worst {
+		t.Errorf("Stats()=%+v; want Best and Worst judged by Minimize", stats)
+	}
+	if _, best := pop.Best(); best != stats.Best || best >= initial {
+		t.Errorf("Run() did not minimize the population; got=%g initial=%g", best, initial)
+	}
+}
+
+// statsObserver is an Observer which also receives Stats.
+type statsObserver struct {
+	genetics.NopObserver
+	statsRecorder
+}
+
+func TestEngineTerminate(t *testing.T) {
+	rng := rand.New()
+	observer := &statsObserver{}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 4,
+			CrossoverRate:    1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator:          genetics.SwapMutation{},
+		},
+		Evaluator: genetics.FitnessFunc(oneMax),
+		Terminate: func(s genetics.Stats) bool { return s.Generation >= 3 },
+		Observers: []genetics.Observer{observer},
+	}
+	if err := engine.Run(rng, newBinaryPopulation(t, rng, 8, 10), 100); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if engine.Generation() != 4 {
+		t.Errorf("Generation()=%d; want 4 after generation 3 satisfied Terminate", engine.Generation())
+	}
+	// Generations 0 through 3 and the final population are scored
+	if len(observer.stats) != 5 || observer.stats[4].Generation != 4 {
+		t.Errorf("StatsObserver saw %+v; want Stats for generations 0 through 4", observer.stats)
+	}
+}
+
+type failingEvaluator struct{}
+
+func (failingEvaluator) Evaluate(genetics.Chromosome) (genetics.Fitness, error) {
+	return 0, errors.New("simulation crashed")
+}
+
+func TestEngineRunEvaluationError(t *testing.T) {
+	rng := rand.New()
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 2,
+			CrossoverRate:    1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator:          genetics.SwapMutation{},
+		},
+		Evaluator: failingEvaluator{},
+	}
+	if err := engine.Run(rng, newBinaryPopulation(t, rng, 4, 4), 10); err == nil {
+		t.Error("Run() should fail when the Evaluator fails")
+	}
+}
+
+func TestEngineRunInvalidEvolver(t *testing.T) {
+	rng := rand.New()
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 3,
+			CrossoverRate:    1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator:          genetics.SwapMutation{},
+		},
+		Evaluator: genetics.FitnessFunc(oneMax),
+	}
+	if err := engine.Run(rng, newBinaryPopulation(t, rng, 4, 4), 10); err == nil {
+		t.Error("Run() should reject an odd ReplacementCount")
+	}
+}
+
+func TestEngineBatchEvaluator(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	var batches []int
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 4,
+			CrossoverRate:    1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+		},
+		Evaluator: genetics.BatchFunc(func(ctx context.Context, cs []genetics.Chromosome) []genetics.Fitness {
+			batches = append(batches, len(cs))
+			f := make([]genetics.Fitness, len(cs))
+			for i, c := range cs {
+				f[i] = oneMax(c)
+			}
+			return f
+		}),
+	}
+	pop := newBinaryPopulation(t, rng, 8, 10)
+	if err := engine.Run(rng, pop, 3); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if diff := cmp.Diff([]int{10, 4, 4, 4}, batches); diff != "" {
+		t.Errorf("Run() scored unexpected batches; -want +got:\n%s", diff)
+	}
+	for i, c := range pop.Chromosomes {
+		if pop.Fitness[i] != oneMax(c) {
+			t.Errorf("chromosome %d scored %g; want %g", i, pop.Fitness[i], oneMax(c))
+		}
+	}
+}
+
+func TestEngineBatchEvaluatorErrors(t *testing.T) {
+	for _, test := range []struct {
+		tag    string
+		eval   genetics.BatchFunc
+		cancel bool
+	}{
+		{
+			tag: "short batch",
+			eval: func(ctx context.Context, cs []genetics.Chromosome) []genetics.Fitness {
+				return make([]genetics.Fitness, len(cs)-1)
+			},
+		}, {
+			tag: "canceled",
+			eval: func(ctx context.Context, cs []genetics.Chromosome) []genetics.Fitness {
+				<-ctx.Done()
+				return nil
+			},
+			cancel: true,
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			rng := rand.New()
+			ctx, cancel := context.WithCancel(context.Background())
+			if test.cancel {
+				cancel()
+			}
+			defer cancel()
+			engine := genetics.Engine{
+				Evolver: genetics.Evolver{
+					ReplacementCount: 2,
+					CrossoverRate:    1,
+					Selector:         genetics.TournamentSelection{Size: 2},
+					Crossover:        genetics.MultiPointCrossover{Points: 1},
+				},
+				Evaluator: test.eval,
+				Context:   ctx,
+			}
+			if err := engine.Run(rng, newBinaryPopulation(t, rng, 4, 4), 2); err == nil {
+				t.Error("Run() should fail")
+			}
+		})
+	}
+}
+
+// flakyEvaluator fails on every chromosome whose first gene is 1.
+type flakyEvaluator struct{}
+
+func (flakyEvaluator) Evaluate(c genetics.Chromosome) (genetics.Fitness, error) {
+	if c.Genes[0] == 1 {
+		return 0, errors.New("timeout")
+	}
+	return oneMax(c) + 1, nil
+}
+
+// flakyBatch scores batches with flakyEvaluator, reporting its failures per
+// chromosome.
+type flakyBatch struct{ flakyEvaluator }
+
+func (b flakyBatch) EvaluateAll(ctx context.Context, cs []genetics.Chromosome) []genetics.Fitness {
+	f, _ := b.EvaluateAllErrs(ctx, cs)
+	return f
+}
+
+func (b flakyBatch) EvaluateAllErrs(ctx context.Context, cs []genetics.Chromosome) ([]genetics.Fitness, []error) {
+	f, errs := make([]genetics.Fitness, len(cs)), make([]error, len(cs))
+	for i, c := range cs {
+		f[i], errs[i] = b.Evaluate(c)
+	}
+	return f, errs
+}
+
+func TestEngineFallibleBatchEvaluator(t *testing.T) {
+	s := genetics.NewSpecies(3, 1)
+	pop := &genetics.Population{
+		Species:     s,
+		Chromosomes: []genetics.Chromosome{s.New(0, 1, 1), s.New(1, 1, 1), s.New(0, 0, 1), s.New(0, 0, 0)},
+	}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 2,
+			CrossoverRate:    1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+		},
+		Evaluator:      flakyBatch{},
+		MaxFailureRate: 0.25,
+		Cache:          genetics.NewFitnessCache(10),
+	}
+	if err := engine.Run(rand.New(), pop, 0); err != nil {
+		t.Fatalf("Run() should tolerate 1 of 4 failures; err=%s", err)
+	}
+	if diff := cmp.Diff([]genetics.Fitness{3, 1, 2, 1}, pop.Fitness); diff != "" {
+		t.Errorf("Run() should give the failure the worst score; diff=%s", diff)
+	}
+	if _, ok := engine.Cache.Get(s.New(1, 1, 1)); ok {
+		t.Error("a failed evaluation should not be cached")
+	}
+
+	engine.MaxFailureRate = 0
+	var budgetErr *genetics.EvaluationBudgetError
+	if err := engine.Run(rand.New(), pop, 0); !errors.As(err, &budgetErr) || budgetErr.Err.Error() != "timeout" {
+		t.Errorf("Run(); err=%v, want an EvaluationBudgetError for the batch's failure", err)
+	}
+}
+
+func TestEngineFailureBudget(t *testing.T) {
+	s := genetics.NewSpecies(3, 1)
+	newEngine := func(rate float64) genetics.Engine {
+		return genetics.Engine{
+			Evolver: genetics.Evolver{
+				ReplacementCount: 2,
+				CrossoverRate:    1,
+				Selector:         genetics.TournamentSelection{Size: 2},
+				Crossover:        genetics.MultiPointCrossover{Points: 1},
+				Mutator:          genetics.SwapMutation{},
+			},
+			Evaluator:      flakyEvaluator{},
+			MaxFailureRate: rate,
+		}
+	}
+	newPop := func() *genetics.Population {
+		return &genetics.Population{
+			Species:     s,
+			Chromosomes: []genetics.Chromosome{s.New(0, 1, 1), s.New(1, 1, 1), s.New(0, 0, 1), s.New(0, 0, 0)},
+		}
+	}
+
+	engine := newEngine(0.25)
+	pop := newPop()
+	if err := engine.Run(rand.New(), pop, 0); err != nil {
+		t.Fatalf("Run() should tolerate 1 of 4 failures; err=%s", err)
+	}
+	if pop.Fitness[1] != 1 {
+		t.Errorf("failed evaluation was scored %g; want the generation's worst score 1", pop.Fitness[1])
+	}
+
+	engine = newEngine(0.2)
+	pop = newPop()
+	err := engine.Run(rand.New(), pop, 0)
+	var budgetErr *genetics.EvaluationBudgetError
+	if !errors.As(err, &budgetErr) {
+		t.Fatalf("Run() should abort with an EvaluationBudgetError; err=%v", err)
+	}
+	if budgetErr.Generation != 0 || budgetErr.Failures != 1 || budgetErr.Evaluations != 2 {
+		t.Errorf("unexpected EvaluationBudgetError %+v", budgetErr)
+	}
+	// The failure gets the worst score so far; unreached members are unscored
+	if diff := cmp.Diff([]genetics.Fitness{3, 3, 0, 0}, pop.Fitness); diff != "" {
+		t.Errorf("Run() should leave partial results; scores=%v diff=%s", pop.Fitness, diff)
+	}
+}
+
+func TestEngineReuseBuffers(t *testing.T) {
+	for _, test := range []struct {
+		tag     string
+		evolver genetics.Evolver
+		tracer  genetics.Tracer
+	}{
+		{
+			tag: "in place crossover",
+			evolver: genetics.Evolver{
+				ReplacementCount: 10,
+				CrossoverRate:    0.9,
+				MutationRate:     0.2,
+				Selector:         genetics.TournamentSelection{Size: 3},
+				Crossover:        genetics.PartiallyMappedCrossover{},
+				Mutator:          genetics.SwapMutation{},
+			},
+		}, {
+			tag: "duplicate retries",
+			evolver: genetics.Evolver{
+				ReplacementCount: 10,
+				CrossoverRate:    0.5,
+				MutationRate:     0.2,
+				Selector:         genetics.TournamentSelection{Size: 3},
+				Crossover:        genetics.DavisOrderCrossover{},
+				Mutator:          genetics.InversionMutation{},
+				DuplicateRetries: 3,
+			},
+		}, {
+			tag: "traced",
+			evolver: genetics.Evolver{
+				ReplacementCount: 10,
+				CrossoverRate:    0.9,
+				MutationRate:     0.2,
+				Selector:         genetics.TournamentSelection{Size: 3},
+				Crossover:        genetics.PartiallyMappedCrossover{},
+				Mutator:          genetics.SwapMutation{},
+			},
+			tracer: &recordingTracer{},
+		}, {
+			tag: "allocating crossover",
+			evolver: genetics.Evolver{
+				ReplacementCount: 10,
+				CrossoverRate:    0.9,
+				MutationRate:     0.2,
+				Selector:         genetics.TournamentSelection{Size: 3},
+				Crossover:        genetics.CompositeCrossover{Crossovers: []genetics.Crossover{genetics.DavisOrderCrossover{}, genetics.PartiallyMappedCrossover{}}},
+				Mutator:          genetics.SwapMutation{},
+			},
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			var pops [2]*genetics.Population
+			for n, reuse := range []bool{false, true} {
+				rng := rand.New()
+				rng.Seed(42)
+				pop, err := genetics.NewSpecies(12, 11).NewPermPopulation(rng, 20)
+				if err != nil {
+					t.Fatalf("NewPermPopulation(); err=%s", err)
+				}
+				engine := genetics.Engine{
+					Evolver: test.evolver,
+					Evaluator: genetics.FitnessFunc(func(c genetics.Chromosome) genetics.Fitness {
+						// Reward sorted genes.
+						f := genetics.Fitness(0)
+						for i, g := range c.Genes {
+							if int(g) == i {
+								f++
+							}
+						}
+						return f
+					}),
+					Tracer:       test.tracer,
+					ReuseBuffers: reuse,
+				}
+				if err := engine.Run(rng, pop, 30); err != nil {
+					t.Fatalf("Run(); err=%s", err)
+				}
+				pops[n] = pop
+			}
+			if diff := cmp.Diff(pops[0].Chromosomes, pops[1].Chromosomes); diff != "" {
+				t.Errorf("ReuseBuffers changed the evolved population; diff=%s", diff)
+			}
+			if diff := cmp.Diff(pops[0].Fitness, pops[1].Fitness); diff != "" {
+				t.Errorf("ReuseBuffers changed the scores; diff=%s", diff)
+			}
+		})
+	}
+}
+
+func BenchmarkEngineStep(b *testing.B) {
+	for _, reuse := range []bool{false, true} {
+		b.Run(fmt.Sprintf("ReuseBuffers=%t", reuse), func(b *testing.B) {
+			rng := rand.New()
+			rng.Seed(42)
+			pop, err := genetics.NewSpecies(1000, 1).NewRandPopulation(rng, 1000)
+			if err != nil {
+				b.Fatalf("NewRandPopulation(); err=%s", err)
+			}
+			engine := genetics.Engine{
+				Evolver:      genetics.NewEvolver(genetics.WithReplacementCount(500)),
+				Evaluator:    genetics.FitnessFunc(oneMax),
+				ReuseBuffers: reuse,
+			}
+			engine.Reset(pop)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := engine.Step(rng); err != nil {
+					b.Fatalf("Step(); err=%s", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/inlined/rand"
+)
+
+// EvolutionStrategy is a classic (μ, λ) or (μ + λ) evolution strategy for
+// RealSpecies problems, a lighter-weight alternative to CMAES. Each of the Lambda
+// offspring is a mutated copy of a random parent. Every individual carries its own
+// per-gene step sizes, which are mutated log-normally before the genes, so step
+// sizes adapt to the landscape without an explicit schedule. The Mu fittest
+// offspring (or, with Plus, the fittest of parents and offspring together) become
+// the next parents. Fittest means highest Fitness: an EvolutionStrategy has no
+// Objective, so a cost should be negated by its Evaluator. Like Engine, an
+// EvolutionStrategy is not goroutine safe.
+type EvolutionStrategy struct {
+	Species   *RealSpecies
+	Evaluator RealEvaluator
+
+	Mu     int
+	Lambda int
+	// Plus selects (μ + λ), in which parents compete with their offspring, rather
+	// than (μ, λ), in which parents always die.
+	Plus bool
+	// InitialStep is every gene's initial step size as a fraction of its range.
+	// Defaults to 0.1.
+	InitialStep float64
+	// Seeds, if set, are the first parents; any remaining parents are random.
+	Seeds []RealChromosome
+
+	// Terminate, if set, ends a Run early once a generation satisfies it.
+	Terminate Termination
+	// Observers are notified of the Stats of each generation.
+	Observers []StatsObserver
+
+	parents []esIndividual
+	stats   Stats
+}
+
+// esIndividual is a candidate solution with its self-adaptive step sizes.
+type esIndividual struct {
+	c       RealChromosome
+	steps   []float64
+	fitness Fitness
+}
+
+func (s *EvolutionStrategy) String() string {
+	op := ","
+	if s.Plus {
+		op = "+"
+	}
+	return fmt.Sprintf("(%d%s%d)-ES", s.Mu, op, s.Lambda)
+}
+
+// Parents returns the current parents, fittest first, and their fitness.
+func (s *EvolutionStrategy) Parents() ([]RealChromosome, []Fitness) {
+	cs := make([]RealChromosome, len(s.parents))
+	fs := make([]Fitness, len(s.parents))
+	for i, p := range s.parents {
+		cs[i], fs[i] = p.c, p.fitness
+	}
+	return cs, fs
+}
+
+// Stats summarizes the most recent generation's offspring.
+func (s *EvolutionStrategy) Stats() Stats {
+	return s.stats
+}
+
+// Run evolves the strategy for up to the given number of generations and returns
+// the fittest parent.
+func (s *EvolutionStrategy) Run(r rand.Rand, generations int) (RealChromosome, Fitness, error) {
+	if s.Mu < 1 || s.Lambda < 1 || (!s.Plus && s.Lambda < s.Mu) {
+		return RealChromosome{}, 0, fmt.Errorf("%s.Run(); requires 1 <= μ and 1 <= λ, and μ <= λ without plus selection", s)
+	}
+	if err := s.reset(r); err != nil {
+		return RealChromosome{}, 0, err
+	}
+
+	n := float64(s.Species.NumGenes())
+	tauGlobal := 1 / math.Sqrt(2*n)
+	tauLocal := 1 / math.Sqrt(2*math.Sqrt(n))
+	for gen := 0; gen < generations; gen++ {
+		offspring := make([]esIndividual, s.Lambda)
+		scores := make([]Fitness, s.Lambda)
+		for k := range offspring {
+			p := s.parents[r.Int31n(int32(len(s.parents)))]
+			child := esIndividual{
+				c:     s.Species.New(p.c.Genes...),
+				steps: make([]float64, len(p.steps)),
+			}
+			global := tauGlobal * normFloat64(r)
+			for i := range child.steps {
+				child.steps[i] = math.Max(p.steps[i]*math.Exp(global+tauLocal*normFloat64(r)), 1e-12)
+				child.c.Genes[i] += child.steps[i] * normFloat64(r)
+			}
+			s.Species.clamp(&child.c)
+			f, err := s.Evaluator.Evaluate(child.c)
+			if err != nil {
+				return s.parents[0].c, s.parents[0].fitness, fmt.Errorf("%s.Run(); generation %d: %s", s, gen, err)
+			}
+			child.fitness, scores[k] = f, f
+			offspring[k] = child
+		}
+		s.stats = Stats{Generation: gen, Evaluations: s.Lambda}
+		s.stats.summarize(scores)
+		for _, o := range s.Observers {
+			o.OnStats(s.stats)
+		}
+
+		pool := offspring
+		if s.Plus {
+			pool = append(pool, s.parents...)
+		}
+		s.parents = fittest(pool, s.Mu)
+		if s.Terminate != nil && s.Terminate(s.stats) {
+			break
+		}
+	}
+	return s.parents[0].c, s.parents[0].fitness, nil
+}
+
+// reset evaluates the initial parents.
+func (s *EvolutionStrategy) reset(r rand.Rand) error {
+	initialStep := s.InitialStep
+	if initialStep == 0 {
+		initialStep = 0.1
+	}
+	s.parents = make([]esIndividual, s.Mu)
+	s.stats = Stats{}
+	for i := range s.parents {
+		var c RealChromosome
+		if i < len(s.Seeds) {
+			c = s.Species.New(s.Seeds[i].Genes...)
+		} else {
+			c = s.Species.NewRand(r)
+		}
+		steps := make([]float64, s.Species.NumGenes())
+		for g := range steps {
+			steps[g] = initialStep * (s.Species.Max[g] - s.Species.Min[g])
+		}
+		f, err := s.Evaluator.Evaluate(c)
+		if err != nil {
+			return fmt.Errorf("%s.Run(); cannot evaluate initial parent %d: %s", s, i, err)
+		}
+		s.parents[i] = esIndividual{c: c, steps: steps, fitness: f}
+	}
+	s.parents = fittest(s.parents, s.Mu)
+	return nil
+}
+
+// fittest returns the k fittest individuals of pool, fittest first.
+func fittest(pool []esIndividual, k int) []esIndividual {
+	scores := make([]Fitness, len(pool))
+	for i, ind := range pool {
+		scores[i] = ind.fitness
+	}
+	res := make([]esIndividual, k)
+	for i, index := range TopK(scores, k) {
+		res[i] = pool[index]
+	}
+	return res
+}
@@ -0,0 +1,89 @@
+package genetics_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+// sphere is minimized at the origin; fitness is the negated value in millionths.
+func sphere(c genetics.RealChromosome) genetics.Fitness {
+	total := 0.0
+	for _, g := range c.Genes {
+		total += g * g
+	}
+	return -genetics.Fitness(total * 1e6)
+}
+
+func TestEvolutionStrategy(t *testing.T) {
+	for _, plus := range []bool{false, true} {
+		es := &genetics.EvolutionStrategy{
+			Species:   genetics.NewUniformRealSpecies(5, -5, 5),
+			Evaluator: genetics.RealFitnessFunc(sphere),
+			Mu:        5,
+			Lambda:    35,
+			Plus:      plus,
+		}
+		t.Run(es.String(), func(t *testing.T) {
+			rng := rand.New()
+			recorder := &statsRecorder{}
+			es.Observers = []genetics.StatsObserver{recorder}
+			best, f, err := es.Run(rng, 200)
+			if err != nil {
+				t.Fatalf("Run(); err=%s", err)
+			}
+			if f < -1000 {
+				t.Errorf("%s did not converge; best %v scored %g", es, best.Genes, f)
+			}
+			parents, scores := es.Parents()
+			if len(parents) != 5 || scores[0] != f {
+				t.Errorf("Parents() returned %d parents led by %g; want 5 led by %g", len(parents), scores[0], f)
+			}
+			if len(recorder.stats) != 200 || recorder.stats[0].Evaluations != 35 {
+				t.Errorf("expected 200 generations of 35 evaluations; got %d generations", len(recorder.stats))
+			}
+		})
+	}
+}
+
+// elitismCheck counts generations in which the best parent got worse.
+type elitismCheck struct {
+	es          *genetics.EvolutionStrategy
+	best        genetics.Fitness
+	regressions int
+}
+
+func (e *elitismCheck) OnStats(genetics.Stats) {
+	_, scores := e.es.Parents()
+	if scores[0] < e.best {
+		e.regressions++
+	}
+	e.best = scores[0]
+}
+
+func TestEvolutionStrategyPlusIsElitist(t *testing.T) {
+	rng := rand.New()
+	es := &genetics.EvolutionStrategy{
+		Species:   genetics.NewUniformRealSpecies(3, -5, 5),
+		Evaluator: genetics.RealFitnessFunc(sphere),
+		Mu:        2,
+		Lambda:    4,
+		Plus:      true,
+	}
+	check := &elitismCheck{es: es, best: genetics.Fitness(math.Inf(-1))}
+	es.Observers = []genetics.StatsObserver{check}
+	if _, _, err := es.Run(rng, 100); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if check.regressions != 0 {
+		t.Errorf("the best parent of %s got worse in %d generations", es, check.regressions)
+	}
+
+	invalid := &genetics.EvolutionStrategy{Species: es.Species, Evaluator: es.Evaluator, Mu: 5, Lambda: 2}
+	if _, _, err := invalid.Run(rng, 1); err == nil {
+		t.Errorf("Run() should reject %s, which cannot replace its parents", invalid)
+	}
+}
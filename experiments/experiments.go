@@ -0,0 +1,204 @@
+// Package experiments runs parameter sweeps over genetics.Evolver configurations.
+// Each configuration is run for several repetitions with independent seeds and
+// summarized by the mean and standard deviation of the best fitness found, with a
+// 95% confidence interval, so operator comparisons need not be written by hand.
+package experiments
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/rand"
+)
+
+// Level is one setting of a Factor, e.g. a Mutator or a MutationRate.
+type Level struct {
+	Name  string
+	Apply func(e *genetics.Evolver)
+}
+
+// Factor is a dimension of a sweep, e.g. "Mutator", with the Levels to try.
+type Factor struct {
+	Name   string
+	Levels []Level
+}
+
+// Trial runs one repetition of an Evolver configuration and returns the best
+// fitness it found.
+type Trial func(r rand.Rand, e genetics.Evolver) (genetics.Fitness, error)
+
+// EngineTrial is a Trial which evolves a new population for the given number of
+// generations with an Engine.
+func EngineTrial(newPopulation func(r rand.Rand) (*genetics.Population, error), eval genetics.Evaluator, generations int) Trial {
+	return func(r rand.Rand, e genetics.Evolver) (genetics.Fitness, error) {
+		pop, err := newPopulation(r)
+		if err != nil {
+			return 0, err
+		}
+		engine := genetics.Engine{Evolver: e, Evaluator: eval}
+		if err := engine.Run(r, pop, generations); err != nil {
+			return 0, err
+		}
+		_, f := pop.Best()
+		return f, nil
+	}
+}
+
+// Experiment sweeps the Factors applied to Base. By default every combination of
+// Levels is run (a grid sweep); if Samples is positive, Samples combinations are
+// drawn at random instead.
+type Experiment struct {
+	Base    genetics.Evolver
+	Factors []Factor
+	Trial   Trial
+
+	// Repetitions is the number of runs of each configuration. Defaults to 10.
+	Repetitions int
+	// Repetition i of every configuration is seeded with Seed+i, so configurations
+	// are compared on the same random streams while repetitions stay independent.
+	Seed int64
+	// Samples, if positive, runs a random sweep of Samples configurations.
+	Samples int
+}
+
+// Result summarizes the repetitions of one configuration.
+type Result struct {
+	// Levels names the Level of each Factor, in order.
+	Levels  []string
+	Evolver genetics.Evolver
+	// Best is the best fitness of each repetition.
+	Best   []genetics.Fitness
+	Mean   float64
+	StdDev float64
+	// CI95 is the half-width of the 95% confidence interval of Mean.
+	CI95 float64
+}
+
+func (r Result) String() string {
+	return fmt.Sprintf("%s: %.2f ± %.2f", strings.Join(r.Levels, ","), r.Mean, r.CI95)
+}
+
+// Run runs the sweep and returns a Result per configuration in the order they ran.
+func (x Experiment) Run() ([]Result, error) {
+	reps := x.Repetitions
+	if reps == 0 {
+		reps = 10
+	}
+	for _, f := range x.Factors {
+		if len(f.Levels) == 0 {
+			return nil, fmt.Errorf("Experiment.Run(); factor %s has no levels", f.Name)
+		}
+	}
+
+	var configs [][]int
+	if x.Samples > 0 {
+		r := rand.New()
+		r.Seed(x.Seed)
+		for i := 0; i < x.Samples; i++ {
+			config := make([]int, len(x.Factors))
+			for n, f := range x.Factors {
+				config[n] = int(r.Int31n(int32(len(f.Levels))))
+			}
+			configs = append(configs, config)
+		}
+	} else {
+		configs = grid(x.Factors)
+	}
+
+	results := make([]Result, len(configs))
+	for i, config := range configs {
+		res := Result{
+			Levels:  make([]string, len(x.Factors)),
+			Evolver: x.Base,
+			Best:    make([]genetics.Fitness, reps),
+		}
+		for n, level := range config {
+			l := x.Factors[n].Levels[level]
+			res.Levels[n] = l.Name
+			l.Apply(&res.Evolver)
+		}
+		for rep := range res.Best {
+			r := rand.New()
+			r.Seed(x.Seed + int64(rep))
+			f, err := x.Trial(r, res.Evolver)
+			if err != nil {
+				return results[:i], fmt.Errorf("Experiment.Run(); %s repetition %d: %s", strings.Join(res.Levels, ","), rep, err)
+			}
+			res.Best[rep] = f
+		}
+		res.Mean, res.StdDev, res.CI95 = summarize(res.Best)
+		results[i] = res
+	}
+	return results, nil
+}
+
+// grid lists every combination of the factors' levels; the last factor varies fastest.
+func grid(factors []Factor) [][]int {
+	configs := [][]int{{}}
+	for _, f := range factors {
+		var next [][]int
+		for _, c := range configs {
+			for l := range f.Levels {
+				next = append(next, append(append([]int(nil), c...), l))
+			}
+		}
+		configs = next
+	}
+	return configs
+}
+
+// summarize returns the mean, sample standard deviation, and the half-width of the
+// 95% confidence interval of the mean of samples.
+func summarize(samples []genetics.Fitness) (mean, stddev, ci95 float64) {
+	n := float64(len(samples))
+	for _, s := range samples {
+		mean += float64(s)
+	}
+	mean /= n
+	if len(samples) < 2 {
+		return mean, 0, 0
+	}
+	for _, s := range samples {
+		d := float64(s) - mean
+		stddev += d * d
+	}
+	stddev = math.Sqrt(stddev / (n - 1))
+	return mean, stddev, tCritical(len(samples)-1) * stddev / math.Sqrt(n)
+}
+
+// tTable holds the two-sided 95% critical values of Student's t distribution for
+// 1 through 30 degrees of freedom.
+var tTable = [...]float64{
+	12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+	2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+	2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+}
+
+func tCritical(df int) float64 {
+	if df <= len(tTable) {
+		return tTable[df-1]
+	}
+	// The normal approximation is within 2% beyond 30 degrees of freedom.
+	return 1.96
+}
+
+// WriteTable writes results as an aligned table with a column per Factor followed
+// by the mean, standard deviation, and confidence interval of the best fitness.
+func WriteTable(w io.Writer, factors []Factor, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, f := range factors {
+		fmt.Fprintf(tw, "%s\t", f.Name)
+	}
+	fmt.Fprintln(tw, "Mean\tStdDev\tCI95")
+	for _, r := range results {
+		for _, l := range r.Levels {
+			fmt.Fprintf(tw, "%s\t", l)
+		}
+		fmt.Fprintf(tw, "%.2f\t%.2f\t±%.2f\n", r.Mean, r.StdDev, r.CI95)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,124 @@
+package experiments_test
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/genetics/experiments"
+	"github.com/inlined/genetics/problems"
+)
+
+var factors = []experiments.Factor{
+	{
+		Name: "MutationRate",
+		Levels: []experiments.Level{
+			{Name: "0.1", Apply: func(e *genetics.Evolver) { e.MutationRate = 0.1 }},
+			{Name: "0.5", Apply: func(e *genetics.Evolver) { e.MutationRate = 0.5 }},
+		},
+	}, {
+		Name: "Mutator",
+		Levels: []experiments.Level{
+			{Name: "Swap", Apply: func(e *genetics.Evolver) { e.Mutator = genetics.SwapMutation{} }},
+			{Name: "Inversion", Apply: func(e *genetics.Evolver) { e.Mutator = genetics.InversionMutation{} }},
+		},
+	},
+}
+
+func TestGridSweep(t *testing.T) {
+	var seeds []int64
+	x := experiments.Experiment{
+		Factors:     factors,
+		Repetitions: 4,
+		// Scores depend on the configuration and the repetition's random stream.
+		Trial: func(r rand.Rand, e genetics.Evolver) (genetics.Fitness, error) {
+			f := genetics.Fitness(e.MutationRate * 10)
+			if e.Mutator == (genetics.InversionMutation{}) {
+				f += 100
+			}
+			seeds = append(seeds, r.Int63n(1<<62))
+			return f + genetics.Fitness(len(seeds)%4), nil
+		},
+	}
+	results, err := x.Run()
+	if err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	var levels [][]string
+	for _, r := range results {
+		levels = append(levels, r.Levels)
+	}
+	want := [][]string{{"0.1", "Swap"}, {"0.1", "Inversion"}, {"0.5", "Swap"}, {"0.5", "Inversion"}}
+	if diff := cmp.Diff(want, levels); diff != "" {
+		t.Errorf("Run() swept the wrong configurations; -want +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]genetics.Fitness{106, 107, 108, 105}, results[3].Best); diff != "" {
+		t.Errorf("Best differs; -want +got:\n%s", diff)
+	}
+	r := results[3]
+	if r.Mean != 106.5 || math.Abs(r.StdDev-math.Sqrt(5.0/3)) > 1e-9 || math.Abs(r.CI95-3.182*r.StdDev/2) > 1e-9 {
+		t.Errorf("Result %s has mean %g, stddev %g, CI95 %g", r, r.Mean, r.StdDev, r.CI95)
+	}
+
+	// Repetition i of every configuration shares a seed; repetitions do not.
+	for i := 4; i < len(seeds); i++ {
+		if seeds[i] != seeds[i%4] {
+			t.Errorf("configuration %d repetition %d was not seeded like configuration 0", i/4, i%4)
+		}
+	}
+	if seeds[0] == seeds[1] {
+		t.Error("repetitions should have independent seeds")
+	}
+
+	var buf bytes.Buffer
+	if err := experiments.WriteTable(&buf, factors, results); err != nil {
+		t.Fatalf("WriteTable(); err=%s", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[0], "MutationRate  Mutator") || !strings.Contains(lines[4], "106.50") {
+		t.Errorf("WriteTable() wrote:\n%s", buf.String())
+	}
+}
+
+func TestRandomSweep(t *testing.T) {
+	queens := problems.NQueens{N: 8}
+	x := experiments.Experiment{
+		Base: genetics.Evolver{
+			ReplacementCount: 10,
+			CrossoverRate:    0.9,
+			Selector:         genetics.TournamentSelection{Size: 3},
+			Crossover:        genetics.DavisOrderCrossover{},
+		},
+		Factors:     factors,
+		Repetitions: 3,
+		Samples:     3,
+		Trial: experiments.EngineTrial(func(r rand.Rand) (*genetics.Population, error) {
+			return queens.Species().NewPermPopulation(r, 20)
+		}, genetics.FitnessFunc(queens.Fitness), 10),
+	}
+	results, err := x.Run()
+	if err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Run() returned %d results; want 3 samples", len(results))
+	}
+	for _, r := range results {
+		if len(r.Best) != 3 || r.Evolver.Mutator == nil || r.Evolver.MutationRate == 0 {
+			t.Errorf("Result %s ran %d repetitions of %+v", r, len(r.Best), r.Evolver)
+		}
+		if r.Mean > 0 {
+			t.Errorf("Result %s has a mean above the optimum", r)
+		}
+	}
+
+	x.Factors = append(x.Factors, experiments.Factor{Name: "Empty"})
+	if _, err := x.Run(); err == nil {
+		t.Error("Run() should reject factors without levels")
+	}
+}
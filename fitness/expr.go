@@ -0,0 +1,514 @@
+package fitness
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/inlined/genetics"
+)
+
+// Expression is an arithmetic expression over the genes of a Chromosome, compiled
+// by Compile. It is safe for concurrent use.
+type Expression struct {
+	source string
+	root   *node
+}
+
+// Compile parses an arithmetic expression over genes. Values are numbers or
+// vectors with one element per gene:
+//
+//	g          the genes (alleles or real values)
+//	i          the index of each gene: 0, 1, ..., n-1
+//	n          the number of genes
+//	g[k]       the gene at index k; any vector may be indexed
+//
+// Operators, from the lowest precedence to the highest, are the comparisons
+// == != < <= > >= (which are 1 when true and 0 otherwise), + -, * / %, unary -,
+// and ^ (exponentiation, which associates to the right). Operators apply to
+// vectors element by element, repeating numbers as needed, so that g*i weights
+// each gene by its index. The functions abs, sqrt, exp, log, sin, cos, and floor
+// apply element by element; sum, mean, prod, and len reduce a vector to a number;
+// min and max reduce a vector given one argument and compare element by element
+// given two. An expression must reduce to a number, e.g. sum(g == 1) counts the
+// genes set to 1.
+func Compile(source string) (*Expression, error) {
+	p := &parser{source: source}
+	if err := p.scan(); err != nil {
+		return nil, err
+	}
+	root, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.errorf(t, "unexpected %q", t.text)
+	}
+	if !root.scalar {
+		return nil, fmt.Errorf("fitness.Compile(%s); the expression is a vector; reduce it to a number, e.g. with sum", source)
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+func (x *Expression) String() string {
+	return x.source
+}
+
+// Value evaluates x over genes. It fails if an index is out of range.
+func (x *Expression) Value(genes []float64) (float64, error) {
+	env := &env{genes: genes}
+	v, err := x.root.eval(env)
+	if err != nil {
+		return 0, fmt.Errorf("fitness.Expression(%s); err=%s", x.source, err)
+	}
+	return v[0], nil
+}
+
+// Evaluate implements genetics.Evaluator
+func (x *Expression) Evaluate(c genetics.Chromosome) (genetics.Fitness, error) {
+	genes := make([]float64, len(c.Genes))
+	for k, g := range c.Genes {
+		genes[k] = float64(g)
+	}
+	v, err := x.Value(genes)
+	return genetics.Fitness(v), err
+}
+
+// Real adapts x into a genetics.RealEvaluator.
+func (x *Expression) Real() genetics.RealEvaluator {
+	return realExpression{x}
+}
+
+// realExpression evaluates an Expression over RealChromosomes.
+type realExpression struct {
+	x *Expression
+}
+
+// Evaluate implements genetics.RealEvaluator
+func (r realExpression) Evaluate(c genetics.RealChromosome) (genetics.Fitness, error) {
+	v, err := r.x.Value(c.Genes)
+	return genetics.Fitness(v), err
+}
+
+// env holds the genes an Expression is evaluated over.
+type env struct {
+	genes []float64
+	index []float64
+}
+
+// indexes returns the vector i, computing it once per evaluation.
+func (e *env) indexes() []float64 {
+	if e.index == nil {
+		e.index = make([]float64, len(e.genes))
+		for k := range e.index {
+			e.index[k] = float64(k)
+		}
+	}
+	return e.index
+}
+
+// node is a compiled subexpression. Scalar nodes always evaluate to one value;
+// others evaluate to one value per gene.
+type node struct {
+	eval   func(e *env) ([]float64, error)
+	scalar bool
+}
+
+// elementwise applies fn to each element of the values of args, repeating
+// scalars.
+func elementwise(fn func(xs ...float64) float64, args ...*node) *node {
+	scalar := true
+	for _, a := range args {
+		scalar = scalar && a.scalar
+	}
+	return &node{scalar: scalar, eval: func(e *env) ([]float64, error) {
+		values := make([][]float64, len(args))
+		size := 1
+		for n, a := range args {
+			v, err := a.eval(e)
+			if err != nil {
+				return nil, err
+			}
+			values[n] = v
+			if !a.scalar {
+				size = len(v)
+			}
+		}
+		out := make([]float64, size)
+		xs := make([]float64, len(args))
+		for k := range out {
+			for n, v := range values {
+				if args[n].scalar {
+					xs[n] = v[0]
+				} else {
+					xs[n] = v[k]
+				}
+			}
+			out[k] = fn(xs...)
+		}
+		return out, nil
+	}}
+}
+
+// reduction folds the values of arg into one.
+func reduction(fn func(v []float64) float64, arg *node) *node {
+	return &node{scalar: true, eval: func(e *env) ([]float64, error) {
+		v, err := arg.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		return []float64{fn(v)}, nil
+	}}
+}
+
+func boolean(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+var binaryOperators = map[string]func(xs ...float64) float64{
+	"==": func(xs ...float64) float64 { return boolean(xs[0] == xs[1]) },
+	"!=": func(xs ...float64) float64 { return boolean(xs[0] != xs[1]) },
+	"<":  func(xs ...float64) float64 { return boolean(xs[0] < xs[1]) },
+	"<=": func(xs ...float64) float64 { return boolean(xs[0] <= xs[1]) },
+	">":  func(xs ...float64) float64 { return boolean(xs[0] > xs[1]) },
+	">=": func(xs ...float64) float64 { return boolean(xs[0] >= xs[1]) },
+	"+":  func(xs ...float64) float64 { return xs[0] + xs[1] },
+	"-":  func(xs ...float64) float64 { return xs[0] - xs[1] },
+	"*":  func(xs ...float64) float64 { return xs[0] * xs[1] },
+	"/":  func(xs ...float64) float64 { return xs[0] / xs[1] },
+	"%":  func(xs ...float64) float64 { return math.Mod(xs[0], xs[1]) },
+	"^":  func(xs ...float64) float64 { return math.Pow(xs[0], xs[1]) },
+}
+
+var elementFunctions = map[string]func(float64) float64{
+	"abs":   math.Abs,
+	"sqrt":  math.Sqrt,
+	"exp":   math.Exp,
+	"log":   math.Log,
+	"sin":   math.Sin,
+	"cos":   math.Cos,
+	"floor": math.Floor,
+}
+
+var reductions = map[string]func(v []float64) float64{
+	"sum": func(v []float64) float64 {
+		total := 0.0
+		for _, x := range v {
+			total += x
+		}
+		return total
+	},
+	"mean": func(v []float64) float64 {
+		total := 0.0
+		for _, x := range v {
+			total += x
+		}
+		return total / float64(len(v))
+	},
+	"prod": func(v []float64) float64 {
+		total := 1.0
+		for _, x := range v {
+			total *= x
+		}
+		return total
+	},
+	"len": func(v []float64) float64 {
+		return float64(len(v))
+	},
+	"min": func(v []float64) float64 {
+		m := math.Inf(1)
+		for _, x := range v {
+			m = math.Min(m, x)
+		}
+		return m
+	},
+	"max": func(v []float64) float64 {
+		m := math.Inf(-1)
+		for _, x := range v {
+			m = math.Max(m, x)
+		}
+		return m
+	},
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// parser is a recursive descent parser over the tokens of an expression.
+type parser struct {
+	source string
+	tokens []token
+	next   int
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return fmt.Errorf("fitness.Compile(%s); at offset %d: %s", p.source, t.pos, fmt.Sprintf(format, args...))
+}
+
+// scan splits the source into tokens.
+func (p *parser) scan() error {
+	s := p.source
+	for pos := 0; pos < len(s); {
+		c := rune(s[pos])
+		switch {
+		case unicode.IsSpace(c):
+			pos++
+		case unicode.IsDigit(c) || c == '.':
+			end := pos
+			for end < len(s) && (unicode.IsDigit(rune(s[end])) || s[end] == '.' ||
+				((s[end] == 'e' || s[end] == 'E') && end+1 < len(s)) ||
+				((s[end] == '+' || s[end] == '-') && (s[end-1] == 'e' || s[end-1] == 'E'))) {
+				end++
+			}
+			if _, err := strconv.ParseFloat(s[pos:end], 64); err != nil {
+				return p.errorf(token{pos: pos}, "malformed number %q", s[pos:end])
+			}
+			p.tokens = append(p.tokens, token{kind: tokenNumber, text: s[pos:end], pos: pos})
+			pos = end
+		case unicode.IsLetter(c) || c == '_':
+			end := pos
+			for end < len(s) && (unicode.IsLetter(rune(s[end])) || unicode.IsDigit(rune(s[end])) || s[end] == '_') {
+				end++
+			}
+			p.tokens = append(p.tokens, token{kind: tokenIdent, text: s[pos:end], pos: pos})
+			pos = end
+		default:
+			text := s[pos : pos+1]
+			if pos+1 < len(s) && s[pos+1] == '=' && strings.ContainsRune("=!<>", c) {
+				text = s[pos : pos+2]
+			} else if !strings.ContainsRune("+-*/%^<>()[],", c) {
+				return p.errorf(token{pos: pos}, "unexpected %q", text)
+			}
+			p.tokens = append(p.tokens, token{kind: tokenOperator, text: text, pos: pos})
+			pos += len(text)
+		}
+	}
+	p.tokens = append(p.tokens, token{kind: tokenEOF, pos: len(s)})
+	return nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+// accept consumes the next token if it is one of the operators ops.
+func (p *parser) accept(ops ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokenOperator {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			p.next++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) expect(op string) error {
+	if _, ok := p.accept(op); !ok {
+		t := p.peek()
+		return p.errorf(t, "expected %q; got %q", op, t.text)
+	}
+	return nil
+}
+
+// parseBinary parses a left-associative chain of ops between operands.
+func (p *parser) parseBinary(operand func() (*node, error), ops ...string) (*node, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept(ops...)
+		if !ok {
+			return left, nil
+		}
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = elementwise(binaryOperators[op], left, right)
+	}
+}
+
+func (p *parser) parseComparison() (*node, error) {
+	return p.parseBinary(p.parseSum, "==", "!=", "<=", ">=", "<", ">")
+}
+
+func (p *parser) parseSum() (*node, error) {
+	return p.parseBinary(p.parseProduct, "+", "-")
+}
+
+func (p *parser) parseProduct() (*node, error) {
+	return p.parseBinary(p.parseUnary, "*", "/", "%")
+}
+
+func (p *parser) parseUnary() (*node, error) {
+	if _, ok := p.accept("-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return elementwise(func(xs ...float64) float64 { return -xs[0] }, operand), nil
+	}
+	return p.parsePower()
+}
+
+func (p *parser) parsePower() (*node, error) {
+	base, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept("^"); !ok {
+		return base, nil
+	}
+	exponent, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return elementwise(binaryOperators["^"], base, exponent), nil
+}
+
+// parsePostfix parses an operand followed by any number of indexes.
+func (p *parser) parsePostfix() (*node, error) {
+	operand, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("["); !ok {
+			return operand, nil
+		}
+		t := p.peek()
+		index, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		if !index.scalar {
+			return nil, p.errorf(t, "an index must be a number")
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		operand = indexed(operand, index)
+	}
+}
+
+// indexed selects the element of the value of operand at the value of index.
+func indexed(operand, index *node) *node {
+	return &node{scalar: true, eval: func(e *env) ([]float64, error) {
+		v, err := operand.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		k, err := index.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		if k[0] != math.Trunc(k[0]) || k[0] < 0 || k[0] >= float64(len(v)) {
+			return nil, fmt.Errorf("index %g is out of range [0, %d)", k[0], len(v))
+		}
+		return []float64{v[int(k[0])]}, nil
+	}}
+}
+
+func (p *parser) parsePrimary() (*node, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenNumber:
+		p.next++
+		v, _ := strconv.ParseFloat(t.text, 64)
+		return &node{scalar: true, eval: func(*env) ([]float64, error) { return []float64{v}, nil }}, nil
+	case tokenIdent:
+		p.next++
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(t)
+		}
+		switch t.text {
+		case "g":
+			return &node{eval: func(e *env) ([]float64, error) { return e.genes, nil }}, nil
+		case "i":
+			return &node{eval: func(e *env) ([]float64, error) { return e.indexes(), nil }}, nil
+		case "n":
+			return &node{scalar: true, eval: func(e *env) ([]float64, error) { return []float64{float64(len(e.genes))}, nil }}, nil
+		}
+		return nil, p.errorf(t, "unknown variable %q; use g, i, or n", t.text)
+	case tokenOperator:
+		if t.text == "(" {
+			p.next++
+			inner, err := p.parseComparison()
+			if err != nil {
+				return nil, err
+			}
+			return inner, p.expect(")")
+		}
+	}
+	if t.kind == tokenEOF {
+		return nil, p.errorf(t, "unexpected end of expression")
+	}
+	return nil, p.errorf(t, "unexpected %q", t.text)
+}
+
+// parseCall parses the arguments of a call to the function named by t, whose
+// opening parenthesis has been consumed.
+func (p *parser) parseCall(t token) (*node, error) {
+	var args []*node
+	if _, ok := p.accept(")"); !ok {
+		for {
+			arg, err := p.parseComparison()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if _, ok := p.accept(","); !ok {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+	name := t.text
+	if fn, ok := elementFunctions[name]; ok && len(args) == 1 {
+		return elementwise(func(xs ...float64) float64 { return fn(xs[0]) }, args[0]), nil
+	}
+	if (name == "min" || name == "max") && len(args) == 2 {
+		fn := math.Min
+		if name == "max" {
+			fn = math.Max
+		}
+		return elementwise(func(xs ...float64) float64 { return fn(xs[0], xs[1]) }, args...), nil
+	}
+	if fn, ok := reductions[name]; ok && len(args) == 1 {
+		return reduction(fn, args[0]), nil
+	}
+	if _, ok := elementFunctions[name]; ok {
+		return nil, p.errorf(t, "%s takes 1 argument; got %d", name, len(args))
+	}
+	if name == "min" || name == "max" {
+		return nil, p.errorf(t, "%s takes 1 or 2 arguments; got %d", name, len(args))
+	}
+	if _, ok := reductions[name]; ok {
+		return nil, p.errorf(t, "%s takes 1 argument; got %d", name, len(args))
+	}
+	return nil, p.errorf(t, "unknown function %q", name)
+}
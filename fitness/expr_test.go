@@ -0,0 +1,89 @@
+package fitness_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/genetics/fitness"
+)
+
+func TestCompile(t *testing.T) {
+	s := genetics.NewSpecies(4, 3)
+	c := s.New(1, 0, 3, 1)
+	for _, test := range []struct {
+		expr string
+		want genetics.Fitness
+	}{
+		{expr: "sum(g == 1)", want: 2},
+		{expr: "sum(g)", want: 5},
+		{expr: "g[2]", want: 3},
+		{expr: "g[n - 1] + g[0]", want: 2},
+		{expr: "sum(g * i)", want: 0 + 0 + 6 + 3},
+		{expr: "-2^2", want: -4},
+		{expr: "2^3^2", want: 512},
+		{expr: "(1 + 2) * 3 - 4 / 2 % 3", want: 7},
+		{expr: "1 + 2 == 3", want: 1},
+		{expr: "3 >= 4", want: 0},
+		{expr: "sum(abs(g - 1))", want: 0 + 1 + 2 + 0},
+		{expr: "max(g) - min(g)", want: 3},
+		{expr: "sum(max(g, 2))", want: 2 + 2 + 3 + 2},
+		{expr: "mean(g)", want: 1.25},
+		{expr: "prod(g + 1)", want: 2 * 1 * 4 * 2},
+		{expr: "len(g)", want: 4},
+		{expr: "(g * 2)[2]", want: 6},
+		{expr: "1.5e1 + .5", want: 15.5},
+		{expr: "sqrt(16) + floor(2.7) + exp(0) + log(1) + sin(0) + cos(0)", want: 8},
+	} {
+		x, err := fitness.Compile(test.expr)
+		if err != nil {
+			t.Errorf("Compile(%s); err=%s", test.expr, err)
+			continue
+		}
+		got, err := x.Evaluate(c)
+		if err != nil || math.Abs(float64(got-test.want)) > 1e-9 {
+			t.Errorf("Compile(%s).Evaluate(%v)=%g, %v; want %g", test.expr, c.Genes, got, err, test.want)
+		}
+	}
+
+	x, err := fitness.Compile("-sum((g - 0.5)^2)")
+	if err != nil {
+		t.Fatalf("Compile(); err=%s", err)
+	}
+	if got, err := x.Real().Evaluate(genetics.NewUniformRealSpecies(2, -1, 1).New(0.5, -0.5)); got != -1 || err != nil {
+		t.Errorf("Real().Evaluate()=%g, %v; want -1", got, err)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, test := range []struct {
+		expr string
+		want string
+	}{
+		{expr: "", want: "unexpected end"},
+		{expr: "g", want: "vector"},
+		{expr: "g + 1", want: "vector"},
+		{expr: "sum(g", want: `expected ")"`},
+		{expr: "sum(g))", want: `unexpected ")"`},
+		{expr: "x", want: "unknown variable"},
+		{expr: "foo(g)", want: "unknown function"},
+		{expr: "sum(g, g)", want: "takes 1 argument"},
+		{expr: "min()", want: "takes 1 or 2 arguments"},
+		{expr: "g[g]", want: "index must be a number"},
+		{expr: "1 $ 2", want: "unexpected"},
+		{expr: "1.2.3", want: "malformed number"},
+	} {
+		if _, err := fitness.Compile(test.expr); err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("Compile(%q); err=%v; want an error containing %q", test.expr, err, test.want)
+		}
+	}
+
+	x, err := fitness.Compile("g[n]")
+	if err != nil {
+		t.Fatalf("Compile(); err=%s", err)
+	}
+	if _, err := x.Evaluate(genetics.NewSpecies(3, 1).New(1, 1, 1)); err == nil || !strings.Contains(err.Error(), "out of range") {
+		t.Errorf("Evaluate(); err=%v; want an index out of range", err)
+	}
+}
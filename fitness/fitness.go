@@ -0,0 +1,69 @@
+// Package fitness loads fitness functions which are not compiled into a program,
+// so that people who do not write Go can run experiments. A function is either an
+// arithmetic expression over the genes of a Chromosome, for example
+//
+//	sum(g == 1) - 2*abs(g[0] - g[1])
+//
+// (see Compile), or a symbol of a Go plugin (see LoadPlugin). Load and Flag accept
+// both, e.g. from a command line or the fitness field of a config.Config.
+package fitness
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/inlined/genetics"
+)
+
+// pluginPrefix marks a Load spec which names a Go plugin.
+const pluginPrefix = "plugin:"
+
+// Load returns the Evaluator described by spec: "plugin:path" or
+// "plugin:path#Symbol" loads Symbol (default Fitness) from the Go plugin at path
+// with LoadPlugin, and anything else is compiled as an expression with Compile.
+func Load(spec string) (genetics.Evaluator, error) {
+	if !IsPlugin(spec) {
+		return Compile(spec)
+	}
+	path, symbol := strings.TrimPrefix(spec, pluginPrefix), defaultSymbol
+	if i := strings.LastIndex(path, "#"); i >= 0 {
+		path, symbol = path[:i], path[i+1:]
+	}
+	return LoadPlugin(path, symbol)
+}
+
+// IsPlugin reports whether spec names a Go plugin rather than an expression.
+// Loading a plugin runs its code, so a program which loads specs from untrusted
+// sources should refuse those for which IsPlugin is true.
+func IsPlugin(spec string) bool {
+	return strings.HasPrefix(spec, pluginPrefix)
+}
+
+// Flag is a flag.Value which loads an Evaluator with Load, e.g.
+// --fitness='sum(g == 1)' or --fitness=plugin:./knapsack.so#Fitness.
+type Flag struct {
+	spec string
+	eval genetics.Evaluator
+}
+
+func (f Flag) String() string {
+	return f.spec
+}
+
+// Set implements flag.Value
+func (f *Flag) Set(s string) error {
+	if f.eval != nil {
+		return fmt.Errorf("fitness.Flag.Set(%s); fitness is already set to %s", s, f.spec)
+	}
+	eval, err := Load(s)
+	if err != nil {
+		return err
+	}
+	f.spec, f.eval = s, eval
+	return nil
+}
+
+// Get returns the loaded Evaluator, or nil if the flag was not set.
+func (f Flag) Get() genetics.Evaluator {
+	return f.eval
+}
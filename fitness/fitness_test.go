@@ -0,0 +1,42 @@
+package fitness_test
+
+import (
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/genetics/fitness"
+)
+
+func TestLoad(t *testing.T) {
+	eval, err := fitness.Load("sum(g == 1)")
+	if err != nil {
+		t.Fatalf("Load(); err=%s", err)
+	}
+	if got, err := eval.Evaluate(genetics.NewSpecies(3, 1).New(1, 0, 1)); got != 2 || err != nil {
+		t.Errorf("Evaluate()=%g, %v; want 2", got, err)
+	}
+	if _, err := fitness.Load("plugin:/does/not/exist.so#Score"); err == nil || !strings.Contains(err.Error(), "/does/not/exist.so") {
+		t.Errorf("Load() of a missing plugin; err=%v", err)
+	}
+}
+
+func TestFlag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var f fitness.Flag
+	fs.Var(&f, "fitness", "the fitness function")
+	if err := fs.Parse([]string{"--fitness=sum(g)"}); err != nil {
+		t.Fatalf("Parse(); err=%s", err)
+	}
+	if f.String() != "sum(g)" || f.Get() == nil {
+		t.Errorf("Flag=%s, %v; want sum(g)", f, f.Get())
+	}
+	if err := f.Set("sum(g)"); err == nil {
+		t.Error("Set() should fail when the flag is already set")
+	}
+	var bad fitness.Flag
+	if err := bad.Set("sum("); err == nil {
+		t.Error("Set() should fail for a malformed expression")
+	}
+}
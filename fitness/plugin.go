@@ -0,0 +1,44 @@
+package fitness
+
+import (
+	"fmt"
+	"plugin"
+
+	"github.com/inlined/genetics"
+)
+
+// defaultSymbol is the symbol Load looks up in a plugin when none is named.
+const defaultSymbol = "Fitness"
+
+// LoadPlugin opens the Go plugin at path, built with go build -buildmode=plugin
+// against the same version of this module, and returns its exported symbol. The
+// symbol must be a function of type func(genetics.Chromosome) genetics.Fitness or
+// a variable which implements genetics.Evaluator. Plugins are only supported on
+// some platforms; elsewhere LoadPlugin returns an error.
+func LoadPlugin(path, symbol string) (genetics.Evaluator, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("fitness.LoadPlugin(%s); err=%s", path, err)
+	}
+	sym, err := p.Lookup(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("fitness.LoadPlugin(%s); err=%s", path, err)
+	}
+	return pluginEvaluator(sym)
+}
+
+// pluginEvaluator adapts a symbol looked up in a plugin. Functions are looked up
+// as themselves and variables as pointers to themselves.
+func pluginEvaluator(sym interface{}) (genetics.Evaluator, error) {
+	switch s := sym.(type) {
+	case func(genetics.Chromosome) genetics.Fitness:
+		return genetics.FitnessFunc(s), nil
+	case *genetics.Evaluator:
+		if *s != nil {
+			return *s, nil
+		}
+	case genetics.Evaluator:
+		return s, nil
+	}
+	return nil, fmt.Errorf("fitness.LoadPlugin(); symbol of type %T is neither a func(genetics.Chromosome) genetics.Fitness nor a genetics.Evaluator", sym)
+}
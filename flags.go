@@ -1,27 +1,145 @@
 package genetics
 
 import (
+	"flag"
 	"fmt"
+	"math"
 	"regexp"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/inlined/rand"
 )
 
 const (
 	errAlreadySet      = "%sFlag.Set(%s): already set to %s"
-	errUnexpectedFn    = "%sFlag.Set(%s): unknown function name %s"
-	errUnexpectedParam = "%sFlag.Set(%s): function %s does not accept parameters"
-	errInvalidParam    = "%sFlag.Set(%s): param %s should %s"
+	errUnexpectedFn    = "%s(%s): unknown function name %s"
+	errUnexpectedParam = "%s(%s): function %s does not accept parameters"
+	errInvalidParam    = "%s(%s): param %s should %s"
+	errMalformed       = "%s(%s): expected a name or name(params)"
 )
 
 var (
-	flagFmt = regexp.MustCompile(`^(\w+)(\((\w*)\))?$`)
+	flagFmt = regexp.MustCompile(`^(\w+)(\(([\w.,:()+-]*)\))?$`)
+
+	// The values of each flag are the names of its functions, with a description
+	// of their parameters if any.
+	selectionValues = []flagValue{
+		{stochasticUniversalSampling, ""},
+		{rouletteWheelSelection, ""},
+		{rankedSelection, "[pressure]"},
+		{exponentialRankedSelection, "base"},
+		{tournamentSelection, "size[,p]"},
+	}
+	crossoverValues = []flagValue{
+		{multiPointCrossover, "points"},
+		{wholeArithmeticRecombination, ""},
+		{davisOrderCrossover, ""},
+		{partiallyMappedCrossover, ""},
+		{greedyCrossover, ""},
+		{linkageCrossover, "points"},
+	}
+	mutationValues = []flagValue{
+		{randomResettingMutation, ""},
+		{swapMutation, ""},
+		{scrambleMutation, ""},
+		{inversionMutation, ""},
+		{insertionMutation, ""},
+		{displacementMutation, ""},
+		{invertedDisplacementMutation, ""},
+	}
+	binaryCrossoverValues = []flagValue{
+		{uniformMaskCrossover, ""},
+		{pointMaskCrossover, "points"},
+	}
+	binaryMutationValues = []flagValue{
+		{bitFlipMutation, "rate"},
+		{maskFlipMutation, "rate"},
+	}
+	byteCrossoverValues = []flagValue{
+		{byteUniformCrossover, ""},
+		{bytePointCrossover, "points"},
+	}
+	byteMutationValues = []flagValue{
+		{byteMaskMutation, "rate"},
+	}
+	realCrossoverValues = []flagValue{
+		{arithmeticCrossover, ""},
+		{blendCrossover, "alpha"},
+	}
+	realMutationValues = []flagValue{
+		{gaussianMutation, "sigma,rate"},
+		{polynomialMutation, "eta,rate"},
+	}
+	mixValues = []flagValue{
+		{mix, "op[:weight],..."},
+		{adaptiveMix, "op,...[,param:value]"},
+		{adaptiveUCB, "op,...[,param:value]"},
+	}
+
+	// flagAliases maps short names, in lower case, to the functions they stand for.
+	flagAliases = map[string]string{
+		"sus":      stochasticUniversalSampling,
+		"roulette": rouletteWheelSelection,
+		"ox1":      davisOrderCrossover,
+		"pmx":      partiallyMappedCrossover,
+	}
 )
 
+// flagValue is a function accepted by a flag.
+type flagValue struct {
+	name   string
+	params string
+}
+
+func (v flagValue) String() string {
+	if v.params == "" {
+		return v.name
+	}
+	return fmt.Sprintf("%s(%s)", v.name, v.params)
+}
+
+// lookupName returns the name of the function in values which fn refers to. Names
+// are matched regardless of case and with or without suffix, and an alias matches
+// the function it stands for. If nothing matches, lookupName returns fn.
+func lookupName(fn, suffix string, values ...[]flagValue) string {
+	lower := strings.ToLower(fn)
+	if alias, ok := flagAliases[lower]; ok {
+		lower = strings.ToLower(alias)
+	}
+	for _, vs := range values {
+		for _, v := range vs {
+			name := strings.ToLower(v.name)
+			if name == lower || name == lower+strings.ToLower(suffix) {
+				return v.name
+			}
+		}
+	}
+	return fn
+}
+
+// listValues describes each function in values.
+func listValues(values ...[]flagValue) []string {
+	var list []string
+	for _, vs := range values {
+		for _, v := range vs {
+			list = append(list, v.String())
+		}
+	}
+	return list
+}
+
 // NaturalSelectionFlag allows developers to pick a NaturalSelection
-// strategy using flag.Value. Vallid values include:
+// strategy using flag.Value. Names are not case sensitive, may omit the Selection
+// suffix, and may be abbreviated to sus or roulette. Vallid values include:
 // --flag=StochasticUniversalSampling
+// --flag=RouletteWheelSelection
 // --flag=RankedSelection
+// --flag=RankedSelection(1.5)
+// --flag=ExponentialRankedSelection(0.9)
 // --flag=TournamentSelection(3)
+// --flag=TournamentSelection(4,0.8)
 type NaturalSelectionFlag struct {
 	selection NaturalSelection
 }
@@ -38,30 +156,77 @@ func (f *NaturalSelectionFlag) Set(s string) error {
 	if f.selection != nil {
 		return fmt.Errorf(errAlreadySet, "NaturalSelection", s, f)
 	}
+	sel, err := parseSelection("NaturalSelectionFlag.Set", s)
+	if err != nil {
+		return err
+	}
+	f.selection = sel
+	return nil
+}
 
+// ParseSelection returns the NaturalSelection whose String is s. It accepts the
+// values of NaturalSelectionFlag.
+func ParseSelection(s string) (NaturalSelection, error) {
+	return parseSelection("ParseSelection", s)
+}
+
+// parseSelection parses s for ParseSelection; errors are reported in ctx.
+func parseSelection(ctx, s string) (NaturalSelection, error) {
 	match := flagFmt.FindStringSubmatch(s)
-	fn, arg := match[1], match[3]
+	if match == nil {
+		return nil, fmt.Errorf(errMalformed, ctx, s)
+	}
+	fn, arg := lookupName(match[1], "Selection", selectionValues), match[3]
 
+	if (fn == stochasticUniversalSampling || fn == rouletteWheelSelection) && arg != "" {
+		return nil, fmt.Errorf(errUnexpectedParam, ctx, s, fn)
+	}
 	switch fn {
 	case stochasticUniversalSampling:
-		f.selection = StochasticUniversalSampling{}
+		return StochasticUniversalSampling{}, nil
+	case rouletteWheelSelection:
+		return RouletteWheelSelection{}, nil
 	case rankedSelection:
-		f.selection = RankedSelection{}
+		sel := RankedSelection{}
+		if arg != "" {
+			p, err := strconv.ParseFloat(arg, 64)
+			if err != nil || p < 1 || p > 2 {
+				return nil, fmt.Errorf(errInvalidParam, ctx, s, arg, "be a pressure in [1, 2]")
+			}
+			sel.Pressure = p
+		}
+		return sel, nil
+	case exponentialRankedSelection:
+		b, err := strconv.ParseFloat(arg, 64)
+		if err != nil || b <= 0 || b >= 1 {
+			return nil, fmt.Errorf(errInvalidParam, ctx, s, arg, "be a base in (0, 1)")
+		}
+		return ExponentialRankedSelection{Base: b}, nil
 	case tournamentSelection:
-		n, err := strconv.Atoi(arg)
+		args := strings.Split(arg, ",")
+		if len(args) > 2 {
+			return nil, fmt.Errorf(errInvalidParam, ctx, s, arg, "be a size and an optional probability")
+		}
+		n, err := strconv.Atoi(args[0])
 		if err != nil || n < 2 {
-			return fmt.Errorf(errInvalidParam, "NaturalSelection", s, arg, "a whole number >= 2")
+			return nil, fmt.Errorf(errInvalidParam, ctx, s, args[0], "a whole number >= 2")
 		}
-		f.selection = TournamentSelection{Size: n}
-	default:
-		return fmt.Errorf(errUnexpectedFn, "NaturalSelection", s, fn)
-	}
-
-	if fn != tournamentSelection && arg != "" {
-		return fmt.Errorf(errUnexpectedParam, "NaturalSelection", fn, arg)
+		sel := TournamentSelection{Size: n}
+		if len(args) == 2 {
+			p, err := strconv.ParseFloat(args[1], 64)
+			if err != nil || p <= 0 || p > 1 {
+				return nil, fmt.Errorf(errInvalidParam, ctx, s, args[1], "be a probability in (0, 1]")
+			}
+			sel.P = p
+		}
+		return sel, nil
 	}
+	return nil, fmt.Errorf(errUnexpectedFn, ctx, s, fn)
+}
 
-	return nil
+// ListValues describes the accepted values, e.g. for a usage message.
+func (NaturalSelectionFlag) ListValues() []string {
+	return listValues(selectionValues)
 }
 
 // Get returns a parsed NaturalSelection value
@@ -77,6 +242,15 @@ func (f *NaturalSelectionFlag) Get() NaturalSelection {
 // --flag=MultiPointCrossover(2)
 // --flag=WholeArithmeticRecombination
 // --flag=DavisOrderCrossover
+// --flag=PartiallyMappedCrossover
+// --flag=GreedyCrossover (with an EdgeWeighter Engine.Problem)
+// --flag=LinkageCrossover(2)
+// --flag=Mix(MultiPoint(1):0.3,DavisOrder:0.7)
+// --flag=AdaptiveMix(MultiPoint(1),MultiPoint(2),DavisOrder)
+// --flag=AdaptiveUCB(MultiPoint(1),DavisOrder,exploration:2)
+// Names are not case sensitive and may omit the Crossover suffix; OX1 and PMX
+// abbreviate DavisOrderCrossover and PartiallyMappedCrossover. Weights default to 1.
+// Adaptive mixes may also set rate, minProbability, and exploration.
 type CrossoverFlag struct {
 	crossover Crossover
 }
@@ -93,30 +267,95 @@ func (f *CrossoverFlag) Set(s string) error {
 	if f.crossover != nil {
 		return fmt.Errorf(errAlreadySet, "Crossover", s, f)
 	}
+	c, err := parseCrossover("CrossoverFlag.Set", s)
+	if err != nil {
+		return err
+	}
+	f.crossover = c
+	return nil
+}
 
+// ParseCrossover returns the Crossover whose String is s. It accepts the values
+// of CrossoverFlag.
+func ParseCrossover(s string) (Crossover, error) {
+	return parseCrossover("ParseCrossover", s)
+}
+
+// parseCrossover parses s for ParseCrossover; errors are reported in ctx.
+func parseCrossover(ctx, s string) (Crossover, error) {
 	match := flagFmt.FindStringSubmatch(s)
-	fn, arg := match[1], match[3]
+	if match == nil {
+		return nil, fmt.Errorf(errMalformed, ctx, s)
+	}
+	fn, arg := lookupName(match[1], "", mixValues), match[3]
+	if !isMix(fn) {
+		return parseSimpleCrossover(ctx, s, fn, arg)
+	}
+
+	ops, weights, policy, err := parseMix(ctx, s, fn, arg, func(term string) (fmt.Stringer, error) {
+		m := flagFmt.FindStringSubmatch(term)
+		if m == nil || isMix(m[1]) {
+			return nil, fmt.Errorf(errInvalidParam, ctx, s, term, "be a Crossover")
+		}
+		return parseSimpleCrossover(ctx, s, m[1], m[3])
+	})
+	if err != nil {
+		return nil, err
+	}
+	crossovers := make([]Crossover, len(ops))
+	for i, op := range ops {
+		crossovers[i] = op.(Crossover)
+	}
+	if fn == mix {
+		return CompositeCrossover{Crossovers: crossovers, Weights: weights}, nil
+	}
+	return &AdaptiveCrossover{
+		Crossovers:     crossovers,
+		Selection:      policy.selection,
+		AdaptationRate: policy.adaptationRate,
+		MinProbability: policy.minProbability,
+		Exploration:    policy.exploration,
+	}, nil
+}
+
+// isMix reports whether fn names a Mix, AdaptiveMix, or AdaptiveUCB.
+func isMix(fn string) bool {
+	fn = lookupName(fn, "", mixValues)
+	return fn == mix || fn == adaptiveMix || fn == adaptiveUCB
+}
 
+// parseSimpleCrossover returns the Crossover called fn, with or without its
+// Crossover suffix, with the argument arg.
+func parseSimpleCrossover(ctx, s, fn, arg string) (Crossover, error) {
+	fn = lookupName(fn, "Crossover", crossoverValues)
+	if fn != multiPointCrossover && fn != linkageCrossover && arg != "" {
+		return nil, fmt.Errorf(errUnexpectedParam, ctx, s, fn)
+	}
 	switch fn {
 	case wholeArithmeticRecombination:
-		f.crossover = WholeArithmeticRecombination{}
+		return WholeArithmeticRecombination{}, nil
 	case davisOrderCrossover:
-		f.crossover = DavisOrderCrossover{}
-	case multiPointCrossover:
+		return DavisOrderCrossover{}, nil
+	case partiallyMappedCrossover:
+		return PartiallyMappedCrossover{}, nil
+	case greedyCrossover:
+		return GreedyCrossover{}, nil
+	case multiPointCrossover, linkageCrossover:
 		n, err := strconv.Atoi(arg)
-		if err != nil || n < 2 {
-			return fmt.Errorf(errInvalidParam, "Crossover", s, arg, "a whole number >= 2")
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf(errInvalidParam, ctx, s, arg, "a whole number >= 1")
 		}
-		f.crossover = MultiPointCrossover{Points: n}
-	default:
-		return fmt.Errorf(errUnexpectedFn, "Crossover", s, fn)
-	}
-
-	if fn != multiPointCrossover && arg != "" {
-		return fmt.Errorf(errUnexpectedParam, "Crossover", fn, arg)
+		if fn == linkageCrossover {
+			return NewLinkageCrossover(n), nil
+		}
+		return MultiPointCrossover{Points: n}, nil
 	}
+	return nil, fmt.Errorf(errUnexpectedFn, ctx, s, fn)
+}
 
-	return nil
+// ListValues describes the accepted values, e.g. for a usage message.
+func (CrossoverFlag) ListValues() []string {
+	return listValues(crossoverValues, mixValues)
 }
 
 // Get returns the parsed Crossover
@@ -133,6 +372,14 @@ func (f CrossoverFlag) Get() Crossover {
 // --flag=SwapMutation
 // --flag=ScrambleMutation
 // --flag=InversionMutation
+// --flag=InsertionMutation
+// --flag=DisplacementMutation
+// --flag=InvertedDisplacementMutation
+// --flag=Mix(Swap:0.7,Inversion:0.3)
+// --flag=AdaptiveMix(Swap,Scramble,Inversion,rate:0.5)
+// --flag=AdaptiveUCB(Swap,Inversion)
+// Names are not case sensitive and may omit the Mutation suffix. Weights default to 1.
+// Adaptive mixes may also set rate, minProbability, and exploration.
 type MutationFlag struct {
 	mutator Mutator
 }
@@ -149,28 +396,158 @@ func (f *MutationFlag) Set(s string) error {
 	if f.mutator != nil {
 		return fmt.Errorf(errAlreadySet, "Mutation", s, f)
 	}
+	m, err := parseMutator("MutationFlag.Set", s)
+	if err != nil {
+		return err
+	}
+	f.mutator = m
+	return nil
+}
 
+// ParseMutator returns the Mutator whose String is s. It accepts the values of
+// MutationFlag.
+func ParseMutator(s string) (Mutator, error) {
+	return parseMutator("ParseMutator", s)
+}
+
+// parseMutator parses s for ParseMutator; errors are reported in ctx.
+func parseMutator(ctx, s string) (Mutator, error) {
 	match := flagFmt.FindStringSubmatch(s)
-	fn, arg := match[1], match[3]
+	if match == nil {
+		return nil, fmt.Errorf(errMalformed, ctx, s)
+	}
+	fn, arg := lookupName(match[1], "", mixValues), match[3]
+	if !isMix(fn) {
+		m, ok := lookupMutator(fn)
+		if !ok {
+			return nil, fmt.Errorf(errUnexpectedFn, ctx, s, fn)
+		}
+		if arg != "" {
+			return nil, fmt.Errorf(errUnexpectedParam, ctx, s, m)
+		}
+		return m, nil
+	}
 
-	switch fn {
+	ops, weights, policy, err := parseMix(ctx, s, fn, arg, func(term string) (fmt.Stringer, error) {
+		m, ok := lookupMutator(term)
+		if !ok {
+			return nil, fmt.Errorf(errUnexpectedFn, ctx, s, term)
+		}
+		return m, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	mutators := make([]Mutator, len(ops))
+	for i, op := range ops {
+		mutators[i] = op.(Mutator)
+	}
+	if fn == mix {
+		return CompositeMutator{Mutators: mutators, Weights: weights}, nil
+	}
+	return &AdaptiveMutator{
+		Mutators:       mutators,
+		Selection:      policy.selection,
+		AdaptationRate: policy.adaptationRate,
+		MinProbability: policy.minProbability,
+		Exploration:    policy.exploration,
+	}, nil
+}
+
+// parseMix parses the comma separated terms of a Mix, AdaptiveMix, or AdaptiveUCB.
+// Each term is an operator, parsed by parse, which may be followed by a positive
+// :weight in a Mix. Weights default to 1. The terms of an adaptive mix may also
+// set the parameters of its selectionPolicy, e.g. rate:0.5.
+func parseMix(ctx, s, fn, arg string, parse func(term string) (fmt.Stringer, error)) ([]fmt.Stringer, []float64, selectionPolicy, error) {
+	var (
+		ops     []fmt.Stringer
+		weights []float64
+		terms   []string
+		policy  selectionPolicy
+	)
+	if fn == adaptiveUCB {
+		policy.selection = UpperConfidenceBound
+	}
+	if arg == "" {
+		return nil, nil, policy, fmt.Errorf(errInvalidParam, ctx, s, arg, "list at least one operator")
+	}
+	depth, start := 0, 0
+	for i, r := range arg {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, arg[start:i])
+				start = i + 1
+			}
+		}
+	}
+	terms = append(terms, arg[start:])
+
+	for _, term := range terms {
+		name, w := term, 1.0
+		if i := strings.LastIndex(term, ":"); i > strings.LastIndex(term, ")") {
+			if fn != mix {
+				ok, err := policy.set(term[:i], term[i+1:])
+				if err != nil {
+					return nil, nil, policy, fmt.Errorf(errInvalidParam, ctx, s, term, err)
+				}
+				if ok {
+					continue
+				}
+				return nil, nil, policy, fmt.Errorf(errInvalidParam, ctx, s, term, "not be weighted")
+			}
+			var err error
+			name = term[:i]
+			if w, err = strconv.ParseFloat(term[i+1:], 64); err != nil || w <= 0 {
+				return nil, nil, policy, fmt.Errorf(errInvalidParam, ctx, s, term[i+1:], "be a positive weight")
+			}
+		}
+		op, err := parse(name)
+		if err != nil {
+			return nil, nil, policy, err
+		}
+		for _, prev := range ops {
+			if prev.String() == op.String() {
+				return nil, nil, policy, fmt.Errorf(errInvalidParam, ctx, s, name, "be listed once")
+			}
+		}
+		ops = append(ops, op)
+		weights = append(weights, w)
+	}
+	if len(ops) == 0 {
+		return nil, nil, policy, fmt.Errorf(errInvalidParam, ctx, s, arg, "list at least one operator")
+	}
+	return ops, weights, policy, nil
+}
+
+// lookupMutator returns the Mutator called name, with or without its Mutation suffix.
+func lookupMutator(name string) (Mutator, bool) {
+	switch lookupName(name, "Mutation", mutationValues) {
 	case randomResettingMutation:
-		f.mutator = RandomResettingMutation{}
+		return RandomResettingMutation{}, true
 	case swapMutation:
-		f.mutator = SwapMutation{}
+		return SwapMutation{}, true
 	case scrambleMutation:
-		f.mutator = ScrambleMutation{}
+		return ScrambleMutation{}, true
 	case inversionMutation:
-		f.mutator = InversionMutation{}
-	default:
-		return fmt.Errorf(errUnexpectedFn, "Mutation", s, fn)
-	}
-
-	if arg != "" {
-		return fmt.Errorf(errUnexpectedParam, "Mutation", fn, arg)
+		return InversionMutation{}, true
+	case insertionMutation:
+		return InsertionMutation{}, true
+	case displacementMutation:
+		return DisplacementMutation{}, true
+	case invertedDisplacementMutation:
+		return InvertedDisplacementMutation{}, true
 	}
+	return nil, false
+}
 
-	return nil
+// ListValues describes the accepted values, e.g. for a usage message.
+func (MutationFlag) ListValues() []string {
+	return listValues(mutationValues, mixValues)
 }
 
 // Get returns the parsed Mutator
@@ -180,3 +557,308 @@ func (f MutationFlag) Get() Mutator {
 	}
 	return f.mutator
 }
+
+// ParseBinaryCrossover returns the BinaryCrossover whose String is s, e.g.
+// PointMaskCrossover(2). Names are matched as they are by CrossoverFlag.
+func ParseBinaryCrossover(s string) (BinaryCrossover, error) {
+	const ctx = "ParseBinaryCrossover"
+	fn, args, err := parseCall(ctx, s, "Crossover", binaryCrossoverValues)
+	if err != nil {
+		return nil, err
+	}
+	switch fn {
+	case uniformMaskCrossover:
+		return UniformMaskCrossover{}, nil
+	case pointMaskCrossover:
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf(errInvalidParam, ctx, s, args[0], "a whole number >= 1")
+		}
+		return PointMaskCrossover{Points: n}, nil
+	}
+	return nil, fmt.Errorf(errUnexpectedFn, ctx, s, fn)
+}
+
+// ParseBinaryMutator returns the BinaryMutator whose String is s, e.g.
+// BitFlipMutation(0.01).
+func ParseBinaryMutator(s string) (BinaryMutator, error) {
+	const ctx = "ParseBinaryMutator"
+	fn, args, err := parseCall(ctx, s, "Mutation", binaryMutationValues)
+	if err != nil {
+		return nil, err
+	}
+	switch fn {
+	case bitFlipMutation, maskFlipMutation:
+		rate, err := parseFloatParam(ctx, s, args[0], 0, 1, "be a rate in [0, 1]")
+		if err != nil {
+			return nil, err
+		}
+		if fn == maskFlipMutation {
+			return MaskFlipMutation{Rate: rate}, nil
+		}
+		return BitFlipMutation{Rate: rate}, nil
+	}
+	return nil, fmt.Errorf(errUnexpectedFn, ctx, s, fn)
+}
+
+// ParseByteCrossover returns the ByteCrossover whose String is s, e.g.
+// BytePointCrossover(2).
+func ParseByteCrossover(s string) (ByteCrossover, error) {
+	const ctx = "ParseByteCrossover"
+	fn, args, err := parseCall(ctx, s, "Crossover", byteCrossoverValues)
+	if err != nil {
+		return nil, err
+	}
+	switch fn {
+	case byteUniformCrossover:
+		return ByteUniformCrossover{}, nil
+	case bytePointCrossover:
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf(errInvalidParam, ctx, s, args[0], "a whole number >= 1")
+		}
+		return BytePointCrossover{Points: n}, nil
+	}
+	return nil, fmt.Errorf(errUnexpectedFn, ctx, s, fn)
+}
+
+// ParseByteMutator returns the ByteMutator whose String is s, e.g.
+// ByteMaskMutation(0.01).
+func ParseByteMutator(s string) (ByteMutator, error) {
+	const ctx = "ParseByteMutator"
+	fn, args, err := parseCall(ctx, s, "Mutation", byteMutationValues)
+	if err != nil {
+		return nil, err
+	}
+	switch fn {
+	case byteMaskMutation:
+		rate, err := parseFloatParam(ctx, s, args[0], 0, 1, "be a rate in [0, 1]")
+		if err != nil {
+			return nil, err
+		}
+		return ByteMaskMutation{Rate: rate}, nil
+	}
+	return nil, fmt.Errorf(errUnexpectedFn, ctx, s, fn)
+}
+
+// ParseRealCrossover returns the RealCrossover whose String is s, e.g.
+// BlendCrossover(0.5).
+func ParseRealCrossover(s string) (RealCrossover, error) {
+	const ctx = "ParseRealCrossover"
+	fn, args, err := parseCall(ctx, s, "Crossover", realCrossoverValues)
+	if err != nil {
+		return nil, err
+	}
+	switch fn {
+	case arithmeticCrossover:
+		return ArithmeticCrossover{}, nil
+	case blendCrossover:
+		alpha, err := parseFloatParam(ctx, s, args[0], 0, math.Inf(1), "be a non-negative alpha")
+		if err != nil {
+			return nil, err
+		}
+		return BlendCrossover{Alpha: alpha}, nil
+	}
+	return nil, fmt.Errorf(errUnexpectedFn, ctx, s, fn)
+}
+
+// ParseRealMutator returns the RealMutator whose String is s, e.g.
+// GaussianMutation(0.1,0).
+func ParseRealMutator(s string) (RealMutator, error) {
+	const ctx = "ParseRealMutator"
+	fn, args, err := parseCall(ctx, s, "Mutation", realMutationValues)
+	if err != nil {
+		return nil, err
+	}
+	var scale float64
+	switch fn {
+	case gaussianMutation:
+		scale, err = parseFloatParam(ctx, s, args[0], 0, math.Inf(1), "be a non-negative sigma")
+	case polynomialMutation:
+		scale, err = parseFloatParam(ctx, s, args[0], 0, math.Inf(1), "be a non-negative eta")
+	default:
+		return nil, fmt.Errorf(errUnexpectedFn, ctx, s, fn)
+	}
+	if err != nil {
+		return nil, err
+	}
+	rate, err := parseFloatParam(ctx, s, args[1], 0, 1, "be a rate in [0, 1]")
+	if err != nil {
+		return nil, err
+	}
+	if fn == gaussianMutation {
+		return GaussianMutation{Sigma: scale, Rate: rate}, nil
+	}
+	return PolynomialMutation{Eta: scale, Rate: rate}, nil
+}
+
+// parseCall splits s into the name of one of values, matched as by lookupName,
+// and exactly as many comma separated arguments as it accepts.
+func parseCall(ctx, s, suffix string, values []flagValue) (string, []string, error) {
+	match := flagFmt.FindStringSubmatch(s)
+	if match == nil {
+		return "", nil, fmt.Errorf(errMalformed, ctx, s)
+	}
+	fn, arg := lookupName(match[1], suffix, values), match[3]
+	for _, v := range values {
+		if v.name != fn {
+			continue
+		}
+		want := 0
+		if v.params != "" {
+			want = strings.Count(v.params, ",") + 1
+		}
+		var args []string
+		if arg != "" {
+			args = strings.Split(arg, ",")
+		}
+		switch {
+		case want == 0 && len(args) != 0:
+			return "", nil, fmt.Errorf(errUnexpectedParam, ctx, s, fn)
+		case len(args) != want:
+			return "", nil, fmt.Errorf(errInvalidParam, ctx, s, arg, "be "+v.params)
+		}
+		return fn, args, nil
+	}
+	return "", nil, fmt.Errorf(errUnexpectedFn, ctx, s, fn)
+}
+
+// parseFloatParam parses arg as a float in [min, max], or fails with should.
+func parseFloatParam(ctx, s, arg string, min, max float64, should string) (float64, error) {
+	f, err := strconv.ParseFloat(arg, 64)
+	if err != nil || f < min || f > max {
+		return 0, fmt.Errorf(errInvalidParam, ctx, s, arg, should)
+	}
+	return f, nil
+}
+
+// DistanceFlag allows developers to pick a registered DistanceFunc
+// using flag.Value. Valid values include:
+// --flag=Hamming
+// --flag=Euclidean
+// --flag=KendallTau
+// --flag=Levenshtein
+// as well as any name passed to RegisterDistance. A name which is not registered
+// matches a registered name which differs only in case.
+type DistanceFlag struct {
+	name     string
+	distance DistanceFunc
+}
+
+func (f DistanceFlag) String() string {
+	if f.distance == nil {
+		return hammingDistance
+	}
+	return f.name
+}
+
+// Set implements flag.Value
+func (f *DistanceFlag) Set(s string) error {
+	if f.distance != nil {
+		return fmt.Errorf(errAlreadySet, "Distance", s, f)
+	}
+
+	match := flagFmt.FindStringSubmatch(s)
+	if match == nil {
+		return fmt.Errorf(errMalformed, "DistanceFlag.Set", s)
+	}
+	fn, arg := match[1], match[3]
+	if arg != "" {
+		return fmt.Errorf(errUnexpectedParam, "DistanceFlag.Set", s, fn)
+	}
+
+	if _, err := LookupDistance(fn); err != nil {
+		for _, name := range Distances() {
+			if strings.EqualFold(name, fn) {
+				fn = name
+				break
+			}
+		}
+	}
+	d, err := LookupDistance(fn)
+	if err != nil {
+		return fmt.Errorf(errUnexpectedFn, "DistanceFlag.Set", s, fn)
+	}
+	f.name, f.distance = fn, d
+	return nil
+}
+
+// ListValues describes the accepted values, e.g. for a usage message.
+func (DistanceFlag) ListValues() []string {
+	return Distances()
+}
+
+// Get returns the parsed DistanceFunc
+func (f DistanceFlag) Get() DistanceFunc {
+	if f.distance == nil {
+		return HammingDistance
+	}
+	return f.distance
+}
+
+// Config collects the settings of a typical run from command-line flags; see
+// RegisterFlags.
+type Config struct {
+	Selection NaturalSelectionFlag
+	Crossover CrossoverFlag
+	Mutation  MutationFlag
+
+	PopulationSize int
+	Generations    int
+	// ReplacementCount defaults to half of PopulationSize, rounded down to an even number.
+	ReplacementCount int
+	CrossoverRate    float64
+	MutationRate     float64
+	// Seed seeds Rand. A Seed of 0 is replaced with one from the clock.
+	Seed int64
+}
+
+// RegisterFlags defines flags for a Config in fs, or flag.CommandLine if fs is
+// nil, with each name preceded by prefix (e.g. "ga."):
+// --selection, --crossover, and --mutation take the values of NaturalSelectionFlag,
+// CrossoverFlag, and MutationFlag; --population-size, --generations,
+// --replacement-count, --crossover-rate, --mutation-rate, and --seed set the
+// numeric parameters. The Config is populated when fs is parsed.
+func RegisterFlags(fs *flag.FlagSet, prefix string) *Config {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	c := &Config{}
+	fs.Var(&c.Selection, prefix+"selection", "the NaturalSelection strategy: "+strings.Join(c.Selection.ListValues(), ", "))
+	fs.Var(&c.Crossover, prefix+"crossover", "the Crossover strategy: "+strings.Join(c.Crossover.ListValues(), ", "))
+	fs.Var(&c.Mutation, prefix+"mutation", "the Mutator strategy: "+strings.Join(c.Mutation.ListValues(), ", "))
+	fs.IntVar(&c.PopulationSize, prefix+"population-size", 100, "the number of chromosomes in the population")
+	fs.IntVar(&c.Generations, prefix+"generations", 100, "the number of generations to evolve")
+	fs.IntVar(&c.ReplacementCount, prefix+"replacement-count", 0, "the even number of chromosomes replaced each generation; defaults to half the population")
+	fs.Float64Var(&c.CrossoverRate, prefix+"crossover-rate", DefaultCrossoverRate, "the probability that a pair of parents is recombined; 0 reproduces through mutation alone")
+	fs.Float64Var(&c.MutationRate, prefix+"mutation-rate", 0.1, "the probability that a child is mutated")
+	fs.Int64Var(&c.Seed, prefix+"seed", 0, "the random seed; 0 picks one from the clock")
+	return c
+}
+
+// Evolver returns the Evolver described by c. Use Evolver.Validate to check it.
+func (c *Config) Evolver() Evolver {
+	replacements := c.ReplacementCount
+	if replacements == 0 {
+		replacements = (c.PopulationSize / 2) &^ 1
+	}
+	return Evolver{
+		ReplacementCount: replacements,
+		CrossoverRate:    float32(c.CrossoverRate),
+		MutationRate:     float32(c.MutationRate),
+		Selector:         c.Selection.Get(),
+		Crossover:        c.Crossover.Get(),
+		Mutator:          c.Mutation.Get(),
+	}
+}
+
+// Rand returns a random source seeded with Seed, first choosing a Seed from the
+// clock if it is 0 so that the run can be reproduced.
+func (c *Config) Rand() rand.Rand {
+	if c.Seed == 0 {
+		c.Seed = time.Now().UnixNano()
+	}
+	r := rand.New()
+	r.Seed(c.Seed)
+	return r
+}
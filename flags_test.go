@@ -1,6 +1,11 @@
 package genetics_test
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -27,6 +32,49 @@ func TestNaturalSelectionFlag(t *testing.T) {
 			tag:  "TournamentSelection",
 			flag: "TournamentSelection(2)",
 			val:  genetics.TournamentSelection{Size: 2},
+		}, {
+			tag:  "RouletteWheelSelection",
+			flag: "RouletteWheelSelection",
+			val:  genetics.RouletteWheelSelection{},
+		}, {
+			tag:  "RankedSelection with pressure",
+			flag: "RankedSelection(1.5)",
+			val:  genetics.RankedSelection{Pressure: 1.5},
+		}, {
+			tag:  "ExponentialRankedSelection",
+			flag: "ExponentialRankedSelection(0.9)",
+			val:  genetics.ExponentialRankedSelection{Base: 0.9},
+		}, {
+			tag:  "RankedSelection with too much pressure",
+			flag: "RankedSelection(3)",
+			err:  errors.New("NaturalSelectionFlag.Set(RankedSelection(3)): param 3 should be a pressure in [1, 2]"),
+			val:  genetics.StochasticUniversalSampling{},
+		}, {
+			tag:  "Probabilistic TournamentSelection",
+			flag: "TournamentSelection(4,0.8)",
+			val:  genetics.TournamentSelection{Size: 4, P: 0.8},
+		}, {
+			tag:  "TournamentSelection with invalid probability",
+			flag: "TournamentSelection(4,1.5)",
+			err:  errors.New("NaturalSelectionFlag.Set(TournamentSelection(4,1.5)): param 1.5 should be a probability in (0, 1]"),
+			val:  genetics.StochasticUniversalSampling{},
+		}, {
+			tag:  "Alias",
+			flag: "sus",
+			val:  genetics.StochasticUniversalSampling{},
+		}, {
+			tag:  "Alias ignores case",
+			flag: "Roulette",
+			val:  genetics.RouletteWheelSelection{},
+		}, {
+			tag:  "Lower case without suffix",
+			flag: "tournament(3)",
+			val:  genetics.TournamentSelection{Size: 3},
+		}, {
+			tag:  "Malformed",
+			flag: "TournamentSelection(3",
+			err:  errors.New("NaturalSelectionFlag.Set(TournamentSelection(3): expected a name or name(params)"),
+			val:  genetics.StochasticUniversalSampling{},
 		},
 	} {
 		t.Run(test.tag, func(t *testing.T) {
@@ -51,3 +99,362 @@ func TestNaturalSelectionFlag(t *testing.T) {
 		})
 	}
 }
+
+func TestMutationFlag(t *testing.T) {
+	for _, test := range []struct {
+		tag  string
+		flag string
+		err  error
+		want string
+	}{
+		{
+			tag:  "SwapMutation",
+			flag: "SwapMutation",
+			want: "SwapMutation",
+		}, {
+			tag:  "Mix",
+			flag: "Mix(Swap:0.7,InversionMutation:0.3)",
+			want: "Mix(SwapMutation:0.7,InversionMutation:0.3)",
+		}, {
+			tag:  "Mix without weights",
+			flag: "Mix(Swap,Scramble)",
+			want: "Mix(SwapMutation:1,ScrambleMutation:1)",
+		}, {
+			tag:  "Insertion",
+			flag: "insertion",
+			want: "InsertionMutation",
+		}, {
+			tag:  "Mix of displacements",
+			flag: "Mix(Displacement,InvertedDisplacement)",
+			want: "Mix(DisplacementMutation:1,InvertedDisplacementMutation:1)",
+		}, {
+			tag:  "AdaptiveMix",
+			flag: "AdaptiveMix(Swap,Inversion)",
+			want: "AdaptiveMix(SwapMutation,InversionMutation)",
+		}, {
+			tag:  "Mix of unknown Mutators",
+			flag: "Mix(Swap,Shuffle)",
+			err:  errors.New("MutationFlag.Set(Mix(Swap,Shuffle)): unknown function name Shuffle"),
+			want: "ScrambleMutation",
+		}, {
+			tag:  "Mix with an invalid weight",
+			flag: "Mix(Swap:0,Inversion:1)",
+			err:  errors.New("MutationFlag.Set(Mix(Swap:0,Inversion:1)): param 0 should be a positive weight"),
+			want: "ScrambleMutation",
+		}, {
+			tag:  "Mix with a repeated Mutator",
+			flag: "Mix(Swap,SwapMutation)",
+			err:  errors.New("MutationFlag.Set(Mix(Swap,SwapMutation)): param SwapMutation should be listed once"),
+			want: "ScrambleMutation",
+		}, {
+			tag:  "Weighted AdaptiveMix",
+			flag: "AdaptiveMix(Swap:0.5,Inversion)",
+			err:  errors.New("MutationFlag.Set(AdaptiveMix(Swap:0.5,Inversion)): param Swap:0.5 should not be weighted"),
+			want: "ScrambleMutation",
+		}, {
+			tag:  "Lower case",
+			flag: "mix(swap,INVERSIONMUTATION)",
+			want: "Mix(SwapMutation:1,InversionMutation:1)",
+		}, {
+			tag:  "Malformed",
+			flag: "Swap Mutation",
+			err:  errors.New("MutationFlag.Set(Swap Mutation): expected a name or name(params)"),
+			want: "ScrambleMutation",
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			var flag genetics.MutationFlag
+			err := flag.Set(test.flag)
+			if err == nil && test.err != nil {
+				t.Errorf("expected error %s", test.err)
+				return
+			}
+			if err != nil && test.err == nil {
+				t.Errorf("failed with err %s", err)
+				return
+			}
+			if err != nil && test.err != nil && err.Error() != test.err.Error() {
+				t.Errorf("expected error %s got error %s", test.err, err)
+				return
+			}
+			if got := flag.Get().String(); got != test.want {
+				t.Errorf("failed to parse %s; got=%s want=%s", test.flag, got, test.want)
+			}
+			if err != nil {
+				return
+			}
+			var again genetics.MutationFlag
+			if err := again.Set(flag.String()); err != nil || again.String() != flag.String() {
+				t.Errorf("Set(%s) did not round trip; got=%s err=%v", flag.String(), again.String(), err)
+			}
+		})
+	}
+}
+
+func TestCrossoverFlag(t *testing.T) {
+	for _, test := range []struct {
+		tag  string
+		flag string
+		err  error
+		want string
+	}{
+		{
+			tag:  "Lower case without suffix",
+			flag: "multipoint(2)",
+			want: "MultiPointCrossover(2)",
+		}, {
+			tag:  "OX1",
+			flag: "ox1",
+			want: "DavisOrderCrossover",
+		}, {
+			tag:  "PMX",
+			flag: "PMX",
+			want: "PartiallyMappedCrossover",
+		}, {
+			tag:  "Malformed",
+			flag: "",
+			err:  errors.New("CrossoverFlag.Set(): expected a name or name(params)"),
+			want: "MultiPointCrossover(1)",
+		}, {
+			tag:  "Mix",
+			flag: "Mix(MultiPoint(1):0.3,DavisOrder:0.7)",
+			want: "Mix(MultiPointCrossover(1):0.3,DavisOrderCrossover:0.7)",
+		}, {
+			tag:  "AdaptiveMix",
+			flag: "AdaptiveMix(MultiPointCrossover(1),MultiPointCrossover(2))",
+			want: "AdaptiveMix(MultiPointCrossover(1),MultiPointCrossover(2))",
+		}, {
+			tag:  "AdaptiveUCB",
+			flag: "AdaptiveUCB(MultiPoint(2),WholeArithmeticRecombination)",
+			want: "AdaptiveUCB(MultiPointCrossover(2),WholeArithmeticRecombination)",
+		}, {
+			tag:  "Nested Mix",
+			flag: "Mix(Mix(DavisOrder),MultiPoint(1))",
+			err:  errors.New("CrossoverFlag.Set(Mix(Mix(DavisOrder),MultiPoint(1))): param Mix(DavisOrder) should be a Crossover"),
+			want: "MultiPointCrossover(1)",
+		}, {
+			tag:  "Mix with an invalid Crossover",
+			flag: "Mix(MultiPoint(0),DavisOrder)",
+			err:  errors.New("CrossoverFlag.Set(Mix(MultiPoint(0),DavisOrder)): param 0 should a whole number >= 1"),
+			want: "MultiPointCrossover(1)",
+		}, {
+			tag:  "Mix of aliases",
+			flag: "adaptivemix(pmx,ox1)",
+			want: "AdaptiveMix(PartiallyMappedCrossover,DavisOrderCrossover)",
+		}, {
+			tag:  "Nested Mix in lower case",
+			flag: "Mix(mix(ox1),pmx)",
+			err:  errors.New("CrossoverFlag.Set(Mix(mix(ox1),pmx)): param mix(ox1) should be a Crossover"),
+			want: "MultiPointCrossover(1)",
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			var flag genetics.CrossoverFlag
+			err := flag.Set(test.flag)
+			if err == nil && test.err != nil {
+				t.Errorf("expected error %s", test.err)
+				return
+			}
+			if err != nil && test.err == nil {
+				t.Errorf("failed with err %s", err)
+				return
+			}
+			if err != nil && test.err != nil && err.Error() != test.err.Error() {
+				t.Errorf("expected error %s got error %s", test.err, err)
+				return
+			}
+			if got := flag.Get().String(); got != test.want {
+				t.Errorf("failed to parse %s; got=%s want=%s", test.flag, got, test.want)
+			}
+			if err != nil {
+				return
+			}
+			var again genetics.CrossoverFlag
+			if err := again.Set(flag.String()); err != nil || again.String() != flag.String() {
+				t.Errorf("Set(%s) did not round trip; got=%s err=%v", flag.String(), again.String(), err)
+			}
+		})
+	}
+}
+
+func TestFlagListValues(t *testing.T) {
+	want := []string{
+		"StochasticUniversalSampling",
+		"RouletteWheelSelection",
+		"RankedSelection([pressure])",
+		"ExponentialRankedSelection(base)",
+		"TournamentSelection(size[,p])",
+	}
+	if diff := cmp.Diff(want, genetics.NaturalSelectionFlag{}.ListValues()); diff != "" {
+		t.Errorf("NaturalSelectionFlag.ListValues() differs; diff=%s", diff)
+	}
+
+	// Every listed name must be accepted by its flag.
+	for _, test := range []struct {
+		tag    string
+		values []string
+		set    func(string) error
+	}{
+		{"NaturalSelectionFlag", genetics.NaturalSelectionFlag{}.ListValues(), func(s string) error { return new(genetics.NaturalSelectionFlag).Set(s) }},
+		{"CrossoverFlag", genetics.CrossoverFlag{}.ListValues(), func(s string) error { return new(genetics.CrossoverFlag).Set(s) }},
+		{"MutationFlag", genetics.MutationFlag{}.ListValues(), func(s string) error { return new(genetics.MutationFlag).Set(s) }},
+		{"DistanceFlag", genetics.DistanceFlag{}.ListValues(), func(s string) error { return new(genetics.DistanceFlag).Set(s) }},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			if len(test.values) == 0 {
+				t.Fatal("ListValues() is empty")
+			}
+			for _, v := range test.values {
+				name := strings.SplitN(v, "(", 2)[0]
+				if err := test.set(name); err != nil && !strings.Contains(err.Error(), "param") {
+					t.Errorf("Set(%s) did not recognize a listed value; err=%s", name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRegisterFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	config := genetics.RegisterFlags(fs, "ga.")
+	if err := fs.Parse([]string{
+		"--ga.selection=TournamentSelection(3)",
+		"--ga.mutation=SwapMutation",
+		"--ga.population-size=30",
+		"--ga.mutation-rate=0.25",
+		"--ga.seed=42",
+	}); err != nil {
+		t.Fatalf("Parse(); err=%s", err)
+	}
+	want := genetics.Evolver{
+		ReplacementCount: 14,
+		CrossoverRate:    0.9,
+		MutationRate:     0.25,
+		Selector:         genetics.TournamentSelection{Size: 3},
+		Crossover:        genetics.MultiPointCrossover{Points: 1},
+		Mutator:          genetics.SwapMutation{},
+	}
+	got := config.Evolver()
+	if diff := cmp.Diff(want, got, cmp.Comparer(func(a, b genetics.Evolver) bool {
+		return a.ReplacementCount == b.ReplacementCount && a.CrossoverRate == b.CrossoverRate && a.MutationRate == b.MutationRate &&
+			a.Selector == b.Selector && a.Crossover == b.Crossover && a.Mutator == b.Mutator
+	})); diff != "" {
+		t.Errorf("Evolver() differs; -want +got:\n%s", diff)
+	}
+	if err := got.Validate(config.PopulationSize); err != nil {
+		t.Errorf("Evolver().Validate(); err=%s", err)
+	}
+	if config.Generations != 100 || config.Seed != 42 {
+		t.Errorf("RegisterFlags() parsed %+v", config)
+	}
+	a, b := config.Rand(), config.Rand()
+	if a.Int63n(1<<62) != b.Int63n(1<<62) {
+		t.Error("Rand() should be seeded with Seed")
+	}
+
+	unseeded := genetics.RegisterFlags(flag.NewFlagSet("test", flag.ContinueOnError), "")
+	unseeded.Rand()
+	if unseeded.Seed == 0 {
+		t.Error("Rand() should record the seed it picked")
+	}
+
+	if err := fs.Parse([]string{"--ga.crossover=Bogus"}); err == nil {
+		t.Error("Parse() should reject an unknown Crossover")
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		op    fmt.Stringer
+		parse func(s string) (fmt.Stringer, error)
+	}{
+		{genetics.StochasticUniversalSampling{}, parseSelection},
+		{genetics.RouletteWheelSelection{}, parseSelection},
+		{genetics.RankedSelection{}, parseSelection},
+		{genetics.RankedSelection{Pressure: 1.25}, parseSelection},
+		{genetics.ExponentialRankedSelection{Base: 0.9}, parseSelection},
+		{genetics.TournamentSelection{Size: 3}, parseSelection},
+		{genetics.TournamentSelection{Size: 4, P: 0.8}, parseSelection},
+		{genetics.MultiPointCrossover{Points: 2}, parseCrossover},
+		{genetics.WholeArithmeticRecombination{}, parseCrossover},
+		{genetics.GreedyCrossover{}, parseCrossover},
+		{genetics.NewLinkageCrossover(2), parseCrossover},
+		{genetics.DavisOrderCrossover{}, parseCrossover},
+		{genetics.PartiallyMappedCrossover{}, parseCrossover},
+		{genetics.CompositeCrossover{Crossovers: []genetics.Crossover{genetics.MultiPointCrossover{Points: 1}, genetics.DavisOrderCrossover{}}, Weights: []float64{0.25, 0.75}}, parseCrossover},
+		{genetics.NewAdaptiveCrossover(genetics.MultiPointCrossover{Points: 1}, genetics.PartiallyMappedCrossover{}), parseCrossover},
+		{&genetics.AdaptiveCrossover{Crossovers: []genetics.Crossover{genetics.DavisOrderCrossover{}, genetics.PartiallyMappedCrossover{}}, Selection: genetics.UpperConfidenceBound, Exploration: 2}, parseCrossover},
+		{genetics.RandomResettingMutation{}, parseMutator},
+		{genetics.SwapMutation{}, parseMutator},
+		{genetics.ScrambleMutation{}, parseMutator},
+		{genetics.InversionMutation{}, parseMutator},
+		{genetics.InsertionMutation{}, parseMutator},
+		{genetics.DisplacementMutation{}, parseMutator},
+		{genetics.InvertedDisplacementMutation{}, parseMutator},
+		{genetics.CompositeMutator{Mutators: []genetics.Mutator{genetics.SwapMutation{}, genetics.InversionMutation{}}, Weights: []float64{0.7, 0.3}}, parseMutator},
+		{&genetics.AdaptiveMutator{Mutators: []genetics.Mutator{genetics.SwapMutation{}, genetics.ScrambleMutation{}}, AdaptationRate: 0.5, MinProbability: 0.05}, parseMutator},
+		{genetics.UniformMaskCrossover{}, parseBinaryCrossover},
+		{genetics.PointMaskCrossover{Points: 3}, parseBinaryCrossover},
+		{genetics.BitFlipMutation{Rate: 0.01}, parseBinaryMutator},
+		{genetics.BitFlipMutation{}, parseBinaryMutator},
+		{genetics.MaskFlipMutation{Rate: 0.25}, parseBinaryMutator},
+		{genetics.ByteUniformCrossover{}, parseByteCrossover},
+		{genetics.BytePointCrossover{Points: 2}, parseByteCrossover},
+		{genetics.ByteMaskMutation{Rate: 0.01}, parseByteMutator},
+		{genetics.ByteMaskMutation{}, parseByteMutator},
+		{genetics.ArithmeticCrossover{}, parseRealCrossover},
+		{genetics.BlendCrossover{Alpha: 0.5}, parseRealCrossover},
+		{genetics.GaussianMutation{Sigma: 1e-05, Rate: 0.1}, parseRealMutator},
+		{genetics.PolynomialMutation{Eta: 20}, parseRealMutator},
+	} {
+		t.Run(test.op.String(), func(t *testing.T) {
+			got, err := test.parse(test.op.String())
+			if err != nil {
+				t.Fatalf("failed to parse %s; err=%s", test.op, err)
+			}
+			if got.String() != test.op.String() || fmt.Sprintf("%T", got) != fmt.Sprintf("%T", test.op) {
+				t.Errorf("%s did not round trip; got %T %s", test.op, got, got)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, test := range []struct {
+		s     string
+		parse func(s string) (fmt.Stringer, error)
+		err   string
+	}{
+		{"Ranked(3)", parseSelection, "ParseSelection(Ranked(3)): param 3 should be a pressure in [1, 2]"},
+		{"Roulette(1)", parseSelection, "ParseSelection(Roulette(1)): function RouletteWheelSelection does not accept parameters"},
+		{"Foo", parseCrossover, "ParseCrossover(Foo): unknown function name Foo"},
+		{"AdaptiveMix(Swap,rate:2)", parseMutator, "ParseMutator(AdaptiveMix(Swap,rate:2)): param rate:2 should be at most 1"},
+		{"AdaptiveMix(rate:0.5)", parseMutator, "ParseMutator(AdaptiveMix(rate:0.5)): param rate:0.5 should list at least one operator"},
+		{"Mix(Swap,rate:0.5)", parseMutator, "ParseMutator(Mix(Swap,rate:0.5)): unknown function name rate"},
+		{"PointMask", parseBinaryCrossover, "ParseBinaryCrossover(PointMask): param  should be points"},
+		{"BitFlip(2)", parseBinaryMutator, "ParseBinaryMutator(BitFlip(2)): param 2 should be a rate in [0, 1]"},
+		{"BytePoint(0)", parseByteCrossover, "ParseByteCrossover(BytePoint(0)): param 0 should a whole number >= 1"},
+		{"ByteMask(-1)", parseByteMutator, "ParseByteMutator(ByteMask(-1)): param -1 should be a rate in [0, 1]"},
+		{"Arithmetic(1)", parseRealCrossover, "ParseRealCrossover(Arithmetic(1)): function ArithmeticCrossover does not accept parameters"},
+		{"Gaussian(0.1)", parseRealMutator, "ParseRealMutator(Gaussian(0.1)): param 0.1 should be sigma,rate"},
+	} {
+		t.Run(test.s, func(t *testing.T) {
+			_, err := test.parse(test.s)
+			if err == nil || err.Error() != test.err {
+				t.Errorf("expected error %s got error %v", test.err, err)
+			}
+		})
+	}
+}
+
+// The Parse functions, adapted to a common signature.
+func parseSelection(s string) (fmt.Stringer, error)       { return genetics.ParseSelection(s) }
+func parseCrossover(s string) (fmt.Stringer, error)       { return genetics.ParseCrossover(s) }
+func parseMutator(s string) (fmt.Stringer, error)         { return genetics.ParseMutator(s) }
+func parseBinaryCrossover(s string) (fmt.Stringer, error) { return genetics.ParseBinaryCrossover(s) }
+func parseBinaryMutator(s string) (fmt.Stringer, error)   { return genetics.ParseBinaryMutator(s) }
+func parseByteCrossover(s string) (fmt.Stringer, error)   { return genetics.ParseByteCrossover(s) }
+func parseByteMutator(s string) (fmt.Stringer, error)     { return genetics.ParseByteMutator(s) }
+func parseRealCrossover(s string) (fmt.Stringer, error)   { return genetics.ParseRealCrossover(s) }
+func parseRealMutator(s string) (fmt.Stringer, error)     { return genetics.ParseRealMutator(s) }
@@ -1,24 +1,23 @@
 package genetics
 
 import (
-	"container/heap"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/inlined/rand"
 )
 
-// Should Species become Population, which has an array of array of Genes?
-// Then Population could create a new generation, which wouldn't be too bad via
-// shallow copies of slices. Though that would possibly pin memory and cause leaks
-// Still, it might help encapsulate concepts.
-
 // Gene is a single trait to control behavior.
 // IF THIS TYPE IS CHANGED FROM BYTE, Species.NewRand() MUST CHANGE
 type Gene = int
 
 // Fitness is an arbitrary fitness number based on genomes and their matching traits.
-type Fitness int64
+// Higher is fitter unless the Population's Objective is Minimize. Fitness is
+// fractional so that probabilistic or continuous objectives need not be scaled.
+// Fitness must not be NaN.
+type Fitness float64
 
 // Chromosome represents a single genetic strategy for a Species.
 type Chromosome struct {
@@ -32,6 +31,32 @@ func (c Chromosome) String() string {
 	return "DEPRECATED"
 }
 
+// Equal reports whether c and o have the same Genes and equal Species. Chromosomes
+// of one population share a Species, so only their Genes are compared.
+func (c Chromosome) Equal(o Chromosome) bool {
+	return c.Species.Equal(o.Species) && genesEqual(c.Genes, o.Genes)
+}
+
+// Hash returns the 64-bit FNV-1a hash of the Genes of c, so that Chromosomes which
+// are Equal have the same Hash. Chromosomes which are not Equal may collide, so
+// sets keyed by Hash must confirm matches with Equal.
+func (c Chromosome) Hash() uint64 {
+	const (
+		offset = 14695981039346656037
+		prime  = 1099511628211
+	)
+	h := uint64(offset)
+	for _, g := range c.Genes {
+		v := uint64(g)
+		for i := 0; i < 8; i++ {
+			h ^= v & 0xFF
+			h *= prime
+			v >>= 8
+		}
+	}
+	return h
+}
+
 // Species is a factory for all Genes in a repeated evolutionary experiment.
 // Separating this from the actual Chromosome allows easier reuse of genetic algorithms
 // in multiple circumstances as well as experimentation with the ordering of Chromosomes
@@ -39,6 +64,24 @@ func (c Chromosome) String() string {
 type Species struct {
 	NumGenes  int
 	MaxAllele Gene
+
+	// MinAlleles and MaxAlleles, if set, bound each gene to its own range
+	// [MinAlleles[i], MaxAlleles[i]] rather than [0, MaxAllele]. Use NewRangedSpecies
+	// to set them consistently.
+	MinAlleles []Gene
+	MaxAlleles []Gene
+}
+
+// Equal reports whether s and o are the same Species or have the same fields.
+func (s *Species) Equal(o *Species) bool {
+	if s == o {
+		return true
+	}
+	if s == nil || o == nil {
+		return false
+	}
+	return s.NumGenes == o.NumGenes && s.MaxAllele == o.MaxAllele &&
+		genesEqual(s.MinAlleles, o.MinAlleles) && genesEqual(s.MaxAlleles, o.MaxAlleles)
 }
 
 // NewSpecies initializes a Species
@@ -49,6 +92,129 @@ func NewSpecies(numGenes int, maxAllele Gene) *Species {
 	}
 }
 
+// NewRangedSpecies initializes a Species whose gene i is in [min[i], max[i]], e.g.
+// for parameter-tuning genomes whose genes have different scales. MaxAllele is
+// the largest of max.
+//
+// Only operators which keep each allele at its locus respect the ranges:
+// RandomResettingMutation, MultiPointCrossover, LinkageCrossover, and
+// WholeArithmeticRecombination. The permutation operators (SwapMutation,
+// ScrambleMutation, InversionMutation, InsertionMutation, DisplacementMutation,
+// InvertedDisplacementMutation, DavisOrderCrossover, PartiallyMappedCrossover,
+// and GreedyCrossover) move alleles between loci and so can leave a gene outside
+// its range; an Evolver.Validator of Species.Validate detects this.
+func NewRangedSpecies(min, max []Gene) (*Species, error) {
+	if len(min) != len(max) || len(min) == 0 {
+		return nil, fmt.Errorf("NewRangedSpecies(); expected equal, non-zero numbers of bounds; got %d and %d", len(min), len(max))
+	}
+	s := &Species{
+		NumGenes:   len(min),
+		MinAlleles: append([]Gene(nil), min...),
+		MaxAlleles: append([]Gene(nil), max...),
+	}
+	for i := range min {
+		if min[i] < 0 || min[i] > max[i] {
+			return nil, fmt.Errorf("NewRangedSpecies(); gene %d has invalid range [%d, %d]", i, min[i], max[i])
+		}
+		if max[i] > s.MaxAllele {
+			s.MaxAllele = max[i]
+		}
+	}
+	return s, nil
+}
+
+// Range returns the smallest and largest allele of gene i.
+func (s *Species) Range(i int) (min, max Gene) {
+	if s.MaxAlleles == nil {
+		return 0, s.MaxAllele
+	}
+	return s.MinAlleles[i], s.MaxAlleles[i]
+}
+
+// Validate returns an error if c is not a well-formed Chromosome of s or of an
+// Equal Species.
+func (s *Species) Validate(c Chromosome) error {
+	if !s.Equal(c.Species) {
+		return errors.New("Chromosome belongs to a different Species")
+	}
+	if err := s.validateGenes(c.Genes); err != nil {
+		return fmt.Errorf("Chromosome%v %s", c.Genes, err)
+	}
+	return nil
+}
+
+// ValidatePermutation returns an error if c is not a well-formed Chromosome of s
+// or is not a permutation of [0, NumGenes).
+func (s *Species) ValidatePermutation(c Chromosome) error {
+	if err := s.Validate(c); err != nil {
+		return err
+	}
+	seen := make([]bool, s.NumGenes)
+	for _, g := range c.Genes {
+		if g >= s.NumGenes || seen[g] {
+			return fmt.Errorf("Chromosome%v is not a permutation of [0, %d)", c.Genes, s.NumGenes)
+		}
+		seen[g] = true
+	}
+	return nil
+}
+
+// validateGenes verifies that g could be the Genes of a Chromosome of s.
+func (s *Species) validateGenes(g []Gene) error {
+	if len(g) != s.NumGenes {
+		return fmt.Errorf("expected %d alleles, got %d", s.NumGenes, len(g))
+	}
+	for i, a := range g {
+		if min, max := s.Range(i); a < min || a > max {
+			return fmt.Errorf("allele %d is outside of [%d, %d]", a, min, max)
+		}
+	}
+	return nil
+}
+
+// Encoder converts a user-level example solution (phenotype) into its Genes.
+type Encoder func(example interface{}) ([]Gene, error)
+
+// FromExamples infers a Species from example solutions and returns it along with
+// the encoded examples, which can be used to seed a population. NumGenes is taken
+// from the encoded length (which must agree across examples and be positive) and
+// MaxAllele is the largest allele seen in any example.
+func FromExamples(encode Encoder, examples ...interface{}) (*Species, []Chromosome, error) {
+	if len(examples) == 0 {
+		return nil, nil, errors.New("FromExamples() requires at least one example")
+	}
+	encoded := make([][]Gene, len(examples))
+	maxAllele := Gene(0)
+	for n, example := range examples {
+		g, err := encode(example)
+		if err != nil {
+			return nil, nil, fmt.Errorf("FromExamples(); cannot encode example %d: %s", n, err)
+		}
+		if n == 0 && len(g) == 0 {
+			return nil, nil, errors.New("FromExamples(); example 0 has no genes")
+		}
+		if n != 0 && len(g) != len(encoded[0]) {
+			return nil, nil, fmt.Errorf("FromExamples(); example %d has %d genes; example 0 has %d", n, len(g), len(encoded[0]))
+		}
+		for _, a := range g {
+			if a < 0 {
+				return nil, nil, fmt.Errorf("FromExamples(); example %d has negative allele %d", n, a)
+			}
+			if a > maxAllele {
+				maxAllele = a
+			}
+		}
+		encoded[n] = g
+	}
+
+	s := NewSpecies(len(encoded[0]), maxAllele)
+	seeds := make([]Chromosome, len(encoded))
+	for n, g := range encoded {
+		seeds[n] = s.New(g...)
+	}
+	return s, seeds, nil
+}
+
 // New creates a Chromosome of the species. Any passed Genes
 // are initialized starting at index 0. Any surpluss Genes
 // are ignored and any missing Genes are 0-initialized.
@@ -67,6 +233,13 @@ func (s *Species) New(g ...Gene) Chromosome {
 // each allele is independently randomized
 func (s *Species) NewRand(rng rand.Rand) (Chromosome, error) {
 	child := s.New()
+	if s.MaxAlleles != nil {
+		for i := range child.Genes {
+			min, max := s.Range(i)
+			child.Genes[i] = min + Gene(rng.Int63n(int64(max-min)+1))
+		}
+		return child, nil
+	}
 	b := make([]byte, s.NumGenes)
 	if n, err := rng.Read(b); n != s.NumGenes || err != nil {
 		return Chromosome{}, fmt.Errorf("rand.Read(); wanted %d bytes; got %d bytes; err=%s", s.NumGenes, n, err)
@@ -116,59 +289,300 @@ func (s *Species) ParseChromosome(encoded string) (Chromosome, error) {
 	return Chromosome{}, errors.New("DEPRECATED")
 }
 
+// cloneOperator is the provenance of children copied directly from a parent
+const cloneOperator = "Clone"
+
+// Clone returns a copy of c which does not share Genes. Chromosomes of a Species
+// reuse released Genes; see Species.Clone.
+func (c Chromosome) Clone() Chromosome {
+	if c.Species != nil {
+		return c.Species.Clone(c)
+	}
+	return Chromosome{Genes: append([]Gene(nil), c.Genes...)}
+}
+
+// release passes c to Species.Release.
+func (c Chromosome) release() {
+	if c.Species != nil {
+		c.Species.Release(c)
+	}
+}
+
 // Evolver replaces one generation of genes with another
 type Evolver struct {
 	ReplacementCount int
-	MutationRate     float32
-	Selector         NaturalSelection
-	Crossover        Crossover
-	Mutator          Mutator
+	// CrossoverRate is the probability that a pair of selected parents is recombined
+	// by Crossover; otherwise the children are clones of their parents. A rate of 0,
+	// the zero value, reproduces through mutation alone, as it does for every other
+	// Evolver. NewEvolver, RegisterFlags, and an EvolverConfig which omits the rate
+	// use DefaultCrossoverRate instead.
+	CrossoverRate float32
+	MutationRate  float32
+	Selector      NaturalSelection
+	Crossover     Crossover
+	Mutator       Mutator
+	// Repairer, if set, repairs every child after variation.
+	Repairer Repairer
+	// DuplicateRetries, if positive, rejects children which duplicate a member of
+	// the population or an earlier child and repeats the crossover and mutation of
+	// their parents up to DuplicateRetries times. This preserves genotypic diversity
+	// in small populations. Children which are still duplicates after the last retry
+	// are kept.
+	DuplicateRetries int
+	// DistinctParents, if set, guarantees that the two parents of each mating pair
+	// are different members of the population whenever it has at least two. A pair
+	// which selected the same parent twice is first re-paired with another selected
+	// parent, so that each parent keeps the number of times it was selected; if no
+	// such parent exists, it mates with a uniformly random partner instead.
+	DistinctParents bool
+	// MatingRestriction, if set, keeps parents which are too similar from being
+	// recombined.
+	MatingRestriction *MatingRestriction
+	// Validator, if set, checks every child (after any repair) and Evolve panics
+	// naming the operators which produced an invalid child. It is a debugging aid
+	// for new operators, e.g. Validator: species.ValidatePermutation.
+	Validator func(c Chromosome) error
+}
+
+// offspring records a child that Evolve placed into the population.
+type offspring struct {
+	index    int     // position in the population that the child replaced
+	operator string  // the operators which produced the child
+	parent   Fitness // the score of the child's fitter parent; see Objective.Scores
+	sources  []int   // the positions of the child's parents before replacement
 }
 
-// Evolve replaces a handful of the population with the next generation
+// Validate reports configuration errors which would otherwise panic or silently
+// misbehave during Evolve: a missing Selector, a missing Crossover or Mutator when
+// its rate is positive, rates outside [0, 1], a ReplacementCount which is not a
+// positive even number no greater than populationSize, or a MatingRestriction
+// without a positive Threshold. A populationSize of 0 skips the population size check.
+func (e Evolver) Validate(populationSize int) error {
+	if err := validateEvolver("Evolver", populationSize, e.ReplacementCount, e.CrossoverRate, e.MutationRate, e.Selector); err != nil {
+		return err
+	}
+	switch {
+	case e.Crossover == nil && e.CrossoverRate > 0:
+		return fmt.Errorf("Evolver.Validate(); Crossover is nil but CrossoverRate is %g", e.CrossoverRate)
+	case e.Mutator == nil && e.MutationRate > 0:
+		return fmt.Errorf("Evolver.Validate(); Mutator is nil but MutationRate is %g", e.MutationRate)
+	case e.MatingRestriction != nil:
+		if err := e.MatingRestriction.validate(); err != nil {
+			return fmt.Errorf("Evolver.Validate(); %s", err)
+		}
+	}
+	return nil
+}
+
+// Evolve replaces a handful of the population with the next generation. Higher
+// scores are fitter; use Objective.Scores to convert the Fitness of a minimizing
+// population. It panics if the Evolver is invalid for pop; see Validate.
 func (e Evolver) Evolve(rand rand.Rand, pop []Chromosome, scores []Fitness) {
-	indexes := e.Selector.SelectParents(rand, e.ReplacementCount, scores)
+	if err := e.Validate(len(pop)); err != nil {
+		panic(err.Error())
+	}
+	e.evolve(rand, pop, scores, scores, scores, nil)
+}
+
+// EvolvePopulation replaces a handful of p with the next generation, honoring its
+// Objective. The Fitness of replaced Chromosomes is stale until they are rescored. It panics if the
+// Evolver is invalid for p; see Validate.
+func (e Evolver) EvolvePopulation(rand rand.Rand, p *Population) {
+	e.Evolve(rand, p.Chromosomes, p.scores())
+}
+
+// evolve selects parents by selection and replaces the least fit by replacement,
+// either of which may be adjusted, e.g. by Speciation. Offspring record the raw
+// scores of their parents. All three increase with fitness. If buf is not nil,
+// the Chromosomes which children replace are released to their Species to be
+// reused by later children, and buf is reused by the next call, which
+// invalidates the returned offspring.
+func (e Evolver) evolve(rand rand.Rand, pop []Chromosome, selection, replacement, raw []Fitness, buf *evolveBuffers) []offspring {
+	indexes := e.Selector.SelectParents(rand, e.ReplacementCount, selection)
 	rand.Shuffle(len(indexes), func(i, j int) {
 		indexes[i], indexes[j] = indexes[j], indexes[i]
 	})
-	children := make([]Chromosome, e.ReplacementCount)
+	if e.DistinctParents {
+		pairDistinct(rand, indexes, len(pop))
+	}
+	var mate []bool
+	if e.MatingRestriction != nil {
+		mate = e.MatingRestriction.restrict(pop, indexes)
+	}
+	reuse := buf != nil
+	if !reuse {
+		buf = &evolveBuffers{}
+	}
+	buf.prepare(e, len(pop))
+	children, operators, parents := buf.children, buf.operators, buf.parents
+	seen := buf.seen
+	if e.DuplicateRetries > 0 {
+		for _, c := range pop {
+			seen[genesKey(c.Genes)] = true
+		}
+	}
 	for i := 0; i < e.ReplacementCount; i += 2 {
-		children[i], children[i+1] = e.Crossover.Crossover(rand, pop[indexes[i]], pop[indexes[i+1]])
-		if rand.Float32() < e.MutationRate {
-			e.Mutator.Mutate(rand, &children[i])
+		a, b := pop[indexes[i]], pop[indexes[i+1]]
+		mayMate := mate == nil || mate[i]
+		e.vary(rand, a, b, mayMate, children[i:i+2], operators[i:i+2], buf)
+		if seen != nil {
+			e.dedupe(rand, a, b, mayMate, children[i:i+2], operators[i:i+2], seen, buf)
 		}
-		if rand.Float32() < e.MutationRate {
-			e.Mutator.Mutate(rand, &children[i+1])
+		parents[i] = raw[indexes[i]]
+		if raw[indexes[i+1]] > parents[i] {
+			parents[i] = raw[indexes[i+1]]
+		}
+		parents[i+1] = parents[i]
+		for j, source := range indexes[i : i+2] {
+			if op := operators[i+j]; op == cloneOperator || strings.HasPrefix(op, cloneOperator+"+") {
+				buf.offspring[i+j].sources = append(buf.offspring[i+j].sources[:0], source)
+			} else {
+				buf.offspring[i+j].sources = append(buf.offspring[i+j].sources[:0], indexes[i], indexes[i+1])
+			}
+		}
+		for j := i; j < i+2; j++ {
+			if e.Validator != nil {
+				if err := e.Validator(children[j]); err != nil {
+					panic(fmt.Sprintf("Evolver: %s produced an invalid child: %s", operators[j], err))
+				}
+			}
 		}
 	}
 
-	minIndexes := kMinIndexes(scores, e.ReplacementCount)
+	minIndexes := replacementSlots(replacement, e.ReplacementCount)
+	res := buf.offspring
 	for child, parent := range minIndexes {
+		if reuse {
+			pop[parent].release()
+		}
 		pop[parent] = children[child]
+		res[child].index, res[child].operator, res[child].parent = parent, operators[child], parents[child]
 	}
+	return res
 }
 
-func kMinIndexes(f []Fitness, k int) []int {
-	h := make(maxTieHeap, k)
-	for i := 0; i < k; i++ {
-		h[i] = tie{
-			index:   i,
-			fitness: f[i],
+// evolveBuffers is scratch space which evolve reuses from one generation to the
+// next; see Engine.ReuseBuffers.
+type evolveBuffers struct {
+	children  []Chromosome
+	operators []string
+	parents   []Fitness
+	offspring []offspring
+	seen      map[string]bool
+	// retries and retryOperators hold the children of dedupe's retries.
+	retries        []Chromosome
+	retryOperators []string
+	// into writes children in place, or is nil if e.Crossover must allocate them.
+	into     CrossoverInto
+	intoName string
+}
+
+// prepare sizes the buffers for a generation of e in a population of size.
+func (b *evolveBuffers) prepare(e Evolver, size int) {
+	n := e.ReplacementCount
+	if cap(b.children) < n {
+		b.children = make([]Chromosome, n)
+		b.operators = make([]string, n)
+		b.parents = make([]Fitness, n)
+		b.offspring = make([]offspring, n)
+		b.retries = make([]Chromosome, 2)
+		b.retryOperators = make([]string, 2)
+	}
+	b.children, b.operators, b.parents, b.offspring = b.children[:n], b.operators[:n], b.parents[:n], b.offspring[:n]
+	switch {
+	case e.DuplicateRetries <= 0:
+		b.seen = nil
+	case b.seen == nil:
+		b.seen = make(map[string]bool, size+n)
+	default:
+		for k := range b.seen {
+			delete(b.seen, k)
 		}
 	}
-	heap.Init(h)
+	b.into = crossoverInto(e.Crossover)
+	if b.into != nil {
+		b.intoName = b.into.String()
+	}
+}
 
-	for i := k; i < len(f); i++ {
-		if f[i] < h[0].fitness {
-			h[0].index = i
-			h[0].fitness = f[i]
-			heap.Fix(h, 0)
+// pairDistinct reorders the parents in indexes, which are mated in consecutive
+// pairs, so that no pair selects the same member of a population of size twice.
+func pairDistinct(rand rand.Rand, indexes []int, size int) {
+	if size < 2 {
+		return
+	}
+	unpaired := repairPairs(indexes, func(a, b int) bool { return a != b })
+	for _, i := range unpaired {
+		partner := int(rand.Int63n(int64(size - 1)))
+		if partner >= indexes[i] {
+			partner++
 		}
+		indexes[i+1] = partner
 	}
+}
 
-	res := make([]int, k)
-	for i, v := range h {
-		res[i] = v.index
+// vary produces two children from parents a and b by crossover (or cloning),
+// mutation, and repair, and the operators which produced each child, into
+// children and operators. Parents which may not mate are always cloned.
+func (e Evolver) vary(rand rand.Rand, a, b Chromosome, mate bool, children []Chromosome, operators []string, buf *evolveBuffers) {
+	switch {
+	case !(rand.Float32() < e.CrossoverRate && mate):
+		children[0], children[1] = a.Clone(), b.Clone()
+		operators[0], operators[1] = cloneOperator, cloneOperator
+	case buf.into != nil:
+		children[0], children[1] = a.Species.alloc(), a.Species.alloc()
+		buf.into.CrossoverInto(rand, a, b, children[0], children[1])
+		operators[0], operators[1] = buf.intoName, buf.intoName
+	default:
+		children[0], children[1], operators[0] = crossover(e.Crossover, rand, a, b)
+		operators[1] = operators[0]
 	}
-	return res
+	for j := range children {
+		if rand.Float32() < e.MutationRate {
+			operators[j] += "+" + mutate(e.Mutator, rand, &children[j])
+		}
+		if e.Repairer != nil {
+			e.Repairer.Repair(rand, &children[j])
+		}
+	}
+}
+
+// dedupe replaces children which are in seen by varying their parents again, up
+// to DuplicateRetries times. Each child is kept as soon as it is unique, and the
+// kept children are added to seen. Rejected children are released to their Species.
+func (e Evolver) dedupe(rand rand.Rand, a, b Chromosome, mate bool, children []Chromosome, operators []string, seen map[string]bool, buf *evolveBuffers) {
+	var kept [2]bool
+	done := 0
+	for retry := 0; ; retry++ {
+		for j := range children {
+			if kept[j] {
+				continue
+			}
+			if k := genesKey(children[j].Genes); !seen[k] || retry == e.DuplicateRetries {
+				kept[j] = true
+				seen[k] = true
+				done++
+			}
+		}
+		if done == len(children) {
+			return
+		}
+		e.vary(rand, a, b, mate, buf.retries, buf.retryOperators, buf)
+		for j := range children {
+			if kept[j] {
+				buf.retries[j].release()
+			} else {
+				children[j].release()
+				children[j], operators[j] = buf.retries[j], buf.retryOperators[j]
+			}
+		}
+	}
+}
+
+// replacementSlots returns the indexes of the k least fit scores, which a
+// generation's children replace, in ascending order of index.
+func replacementSlots(scores []Fitness, k int) []int {
+	slots := BottomK(scores, k)
+	sort.Ints(slots)
+	return slots
 }
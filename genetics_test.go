@@ -2,6 +2,7 @@ package genetics_test
 
 import (
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -63,3 +64,430 @@ func TestNewPermFailure(t *testing.T) {
 		t.Error("NewSpecies(20, 18).NewPerm() should fail")
 	}
 }
+
+func TestChromosomeEqualHashClone(t *testing.T) {
+	s := genetics.NewSpecies(4, 300)
+	a := s.New(1, 2, 3, 256)
+	for _, test := range []struct {
+		tag   string
+		other genetics.Chromosome
+		equal bool
+	}{
+		{"identical", s.New(1, 2, 3, 256), true},
+		{"equal species", genetics.NewSpecies(4, 300).New(1, 2, 3, 256), true},
+		{"different gene", s.New(1, 2, 3, 0), false},
+		{"different high byte", s.New(1, 2, 3, 512), false},
+		{"shorter", genetics.NewSpecies(3, 300).New(1, 2, 3), false},
+	} {
+		if got := a.Equal(test.other); got != test.equal {
+			t.Errorf("%s: Equal()=%t; want %t", test.tag, got, test.equal)
+		}
+		if got := a.Hash() == test.other.Hash(); got != test.equal {
+			t.Errorf("%s: Hash() equal=%t; want %t", test.tag, got, test.equal)
+		}
+	}
+
+	// Species are compared, but Genes alone determine the Hash
+	other := genetics.NewSpecies(4, 500).New(1, 2, 3, 256)
+	if a.Equal(other) || a.Hash() != other.Hash() {
+		t.Errorf("Chromosomes of different Species: Equal()=%t, Hash() equal=%t; want false, true", a.Equal(other), a.Hash() == other.Hash())
+	}
+
+	c := a.Clone()
+	if !c.Equal(a) || c.Species != a.Species {
+		t.Errorf("Clone()=%v; want %v", c, a)
+	}
+	c.Genes[0] = 7
+	if a.Genes[0] != 1 {
+		t.Error("Clone() shares Genes with the original")
+	}
+}
+
+func TestFromExamples(t *testing.T) {
+	type item struct {
+		taken bool
+		count int
+	}
+	encode := func(example interface{}) ([]genetics.Gene, error) {
+		items, ok := example.([]item)
+		if !ok {
+			return nil, fmt.Errorf("unexpected example %v", example)
+		}
+		genes := make([]genetics.Gene, 0, 2*len(items))
+		for _, i := range items {
+			taken := 0
+			if i.taken {
+				taken = 1
+			}
+			genes = append(genes, taken, i.count)
+		}
+		return genes, nil
+	}
+
+	s, seeds, err := genetics.FromExamples(encode,
+		[]item{{true, 3}, {false, 0}},
+		[]item{{false, 1}, {true, 7}},
+	)
+	if err != nil {
+		t.Fatalf("FromExamples(); err=%s", err)
+	}
+	if s.NumGenes != 4 || s.MaxAllele != 7 {
+		t.Errorf("FromExamples() inferred NumGenes=%d MaxAllele=%d; want 4 and 7", s.NumGenes, s.MaxAllele)
+	}
+	want := [][]genetics.Gene{{1, 3, 0, 0}, {0, 1, 1, 7}}
+	for n, c := range seeds {
+		if c.Species != s {
+			t.Errorf("seed %d has the wrong Species", n)
+		}
+		if diff := cmp.Diff(want[n], c.Genes); diff != "" {
+			t.Errorf("seed %d; got=%v want=%v diff=%s", n, c.Genes, want[n], diff)
+		}
+	}
+
+	if _, _, err := genetics.FromExamples(encode, []item{{true, 1}}, []item{}); err == nil {
+		t.Error("FromExamples() should fail on examples of different lengths")
+	}
+	if _, _, err := genetics.FromExamples(encode); err == nil {
+		t.Error("FromExamples() should fail without examples")
+	}
+	if _, _, err := genetics.FromExamples(encode, []item{}, []item{}); err == nil {
+		t.Error("FromExamples() should fail on examples without genes")
+	}
+}
+
+func TestEvolverCrossoverRate(t *testing.T) {
+	s := genetics.NewSpecies(4, 9)
+	pop := []genetics.Chromosome{s.New(1, 1, 1, 1), s.New(2, 2, 2, 2), s.New(3, 3, 3, 3), s.New(4, 4, 4, 4)}
+	scores := []genetics.Fitness{1, 2, 3, 4}
+	original := map[genetics.Gene]bool{1: true, 2: true, 3: true, 4: true}
+
+	evolver := genetics.Evolver{
+		ReplacementCount: 2,
+		CrossoverRate:    0,
+		Selector:         genetics.TournamentSelection{Size: 2},
+		Crossover:        genetics.MultiPointCrossover{Points: 2},
+		Mutator:          genetics.SwapMutation{},
+	}
+	for run := 0; run < 20; run++ {
+		evolver.Evolve(rand.New(), pop, scores)
+	}
+	for n, c := range pop {
+		for _, g := range c.Genes {
+			if g != c.Genes[0] || !original[g] {
+				t.Errorf("chromosome %d = %v was not cloned from a parent", n, c.Genes)
+				break
+			}
+		}
+	}
+	pop[0].Genes[0] = 0
+	for n, c := range pop[1:] {
+		if c.Genes[0] == 0 {
+			t.Errorf("chromosome %d shares Genes with chromosome 0", n+1)
+		}
+	}
+}
+
+func TestEvolverReplacementTies(t *testing.T) {
+	s := genetics.NewSpecies(1, 9)
+	pop := []genetics.Chromosome{s.New(0), s.New(1), s.New(2), s.New(3), s.New(4)}
+	scores := []genetics.Fitness{5, 5, 5, 5, 5}
+	evolver := genetics.Evolver{
+		ReplacementCount: 2,
+		Selector:         genetics.TournamentSelection{Size: 2},
+		Crossover:        genetics.MultiPointCrossover{Points: 1},
+		Mutator:          genetics.SwapMutation{},
+	}
+	evolver.Evolve(rand.New(), pop, scores)
+	for i := 0; i < 3; i++ {
+		if pop[i].Genes[0] != genetics.Gene(i) {
+			t.Errorf("Evolve() replaced chromosome %d; ties should replace the highest indexes", i)
+		}
+	}
+}
+
+func TestEvolverValidate(t *testing.T) {
+	valid := genetics.Evolver{
+		ReplacementCount: 2,
+		CrossoverRate:    0.9,
+		MutationRate:     0.1,
+		Selector:         genetics.TournamentSelection{Size: 2},
+		Crossover:        genetics.MultiPointCrossover{Points: 1},
+		Mutator:          genetics.SwapMutation{},
+	}
+	for _, test := range []struct {
+		tag    string
+		modify func(e *genetics.Evolver)
+		size   int
+		valid  bool
+	}{
+		{tag: "valid", modify: func(e *genetics.Evolver) {}, size: 4, valid: true},
+		{tag: "unknown size", modify: func(e *genetics.Evolver) { e.ReplacementCount = 100 }, valid: true},
+		{tag: "mutation only", modify: func(e *genetics.Evolver) { e.Crossover, e.CrossoverRate = nil, 0 }, size: 4, valid: true},
+		{tag: "crossover only", modify: func(e *genetics.Evolver) { e.Mutator, e.MutationRate = nil, 0 }, size: 4, valid: true},
+		{tag: "nil Selector", modify: func(e *genetics.Evolver) { e.Selector = nil }, size: 4},
+		{tag: "nil Crossover", modify: func(e *genetics.Evolver) { e.Crossover = nil }, size: 4},
+		{tag: "nil Mutator", modify: func(e *genetics.Evolver) { e.Mutator = nil }, size: 4},
+		{tag: "odd ReplacementCount", modify: func(e *genetics.Evolver) { e.ReplacementCount = 3 }, size: 4},
+		{tag: "zero ReplacementCount", modify: func(e *genetics.Evolver) { e.ReplacementCount = 0 }, size: 4},
+		{tag: "ReplacementCount too large", modify: func(e *genetics.Evolver) { e.ReplacementCount = 6 }, size: 4},
+		{tag: "negative MutationRate", modify: func(e *genetics.Evolver) { e.MutationRate = -0.1 }, size: 4},
+		{tag: "MutationRate above 1", modify: func(e *genetics.Evolver) { e.MutationRate = 1.5 }, size: 4},
+		{tag: "CrossoverRate above 1", modify: func(e *genetics.Evolver) { e.CrossoverRate = 2 }, size: 4},
+		{tag: "NaN CrossoverRate", modify: func(e *genetics.Evolver) { e.CrossoverRate = float32(math.NaN()) }, size: 4},
+		{tag: "MatingRestriction", modify: func(e *genetics.Evolver) { e.MatingRestriction = &genetics.MatingRestriction{Threshold: 1} }, size: 4, valid: true},
+		{tag: "MatingRestriction without Threshold", modify: func(e *genetics.Evolver) { e.MatingRestriction = &genetics.MatingRestriction{} }, size: 4},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			e := valid
+			test.modify(&e)
+			err := e.Validate(test.size)
+			if test.valid && err != nil {
+				t.Errorf("Validate(%d); err=%s", test.size, err)
+			}
+			if !test.valid && err == nil {
+				t.Errorf("Validate(%d) should fail", test.size)
+			}
+		})
+	}
+}
+
+func TestEvolveInvalid(t *testing.T) {
+	s := genetics.NewSpecies(4, 3)
+	pop := []genetics.Chromosome{s.New(0, 1, 2, 3), s.New(3, 2, 1, 0)}
+	evolver := genetics.Evolver{
+		ReplacementCount: 4,
+		Selector:         genetics.TournamentSelection{Size: 2},
+		Crossover:        genetics.MultiPointCrossover{Points: 1},
+		Mutator:          genetics.SwapMutation{},
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Evolve() should panic when ReplacementCount exceeds the population")
+		}
+	}()
+	evolver.Evolve(rand.New(), pop, []genetics.Fitness{1, 2})
+}
+
+func TestValidate(t *testing.T) {
+	s := genetics.NewSpecies(3, 5)
+	for _, test := range []struct {
+		tag   string
+		c     genetics.Chromosome
+		valid bool
+	}{
+		{tag: "valid", c: s.New(0, 5, 3), valid: true},
+		{tag: "allele too large", c: s.New(0, 6, 3)},
+		{tag: "negative allele", c: s.New(0, -1, 3)},
+		{tag: "too short", c: genetics.Chromosome{Species: s, Genes: []genetics.Gene{1, 2}}},
+		{tag: "equal species", c: genetics.NewSpecies(3, 5).New(1, 2, 3), valid: true},
+		{tag: "other species", c: genetics.NewSpecies(3, 6).New(1, 2, 3)},
+		{tag: "no species", c: genetics.Chromosome{Genes: []genetics.Gene{1, 2, 3}}},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			err := s.Validate(test.c)
+			if test.valid && err != nil {
+				t.Errorf("Validate(%v); err=%s", test.c.Genes, err)
+			}
+			if !test.valid && err == nil {
+				t.Errorf("Validate(%v) should fail", test.c.Genes)
+			}
+		})
+	}
+}
+
+func TestRangedSpecies(t *testing.T) {
+	s, err := genetics.NewRangedSpecies([]genetics.Gene{0, 10}, []genetics.Gene{7, 255})
+	if err != nil {
+		t.Fatalf("NewRangedSpecies(); err=%s", err)
+	}
+	if s.NumGenes != 2 || s.MaxAllele != 255 {
+		t.Errorf("NewRangedSpecies() = {NumGenes: %d, MaxAllele: %d}; want {2, 255}", s.NumGenes, s.MaxAllele)
+	}
+	if err := s.Validate(s.New(7, 10)); err != nil {
+		t.Errorf("Validate(); err=%s", err)
+	}
+	if err := s.Validate(s.New(8, 10)); err == nil {
+		t.Error("Validate() should reject gene 0 above its own maximum")
+	}
+	if err := s.Validate(s.New(0, 9)); err == nil {
+		t.Error("Validate() should reject gene 1 below its own minimum")
+	}
+
+	rng := rand.New()
+	for run := 0; run < 100; run++ {
+		c, err := s.NewRand(rng)
+		if err != nil {
+			t.Fatalf("NewRand(); err=%s", err)
+		}
+		genetics.RandomResettingMutation{}.Mutate(rng, &c)
+		if err := s.Validate(c); err != nil {
+			t.Fatalf("random chromosome is out of range; err=%s", err)
+		}
+	}
+
+	if _, err := genetics.NewRangedSpecies([]genetics.Gene{5}, []genetics.Gene{4}); err == nil {
+		t.Error("NewRangedSpecies() should reject an empty range")
+	}
+	if _, err := genetics.NewRangedSpecies([]genetics.Gene{0}, []genetics.Gene{4, 5}); err == nil {
+		t.Error("NewRangedSpecies() should reject mismatched bounds")
+	}
+}
+
+func TestValidatePermutation(t *testing.T) {
+	s := genetics.NewSpecies(4, 9)
+	if err := s.ValidatePermutation(s.New(3, 1, 0, 2)); err != nil {
+		t.Errorf("ValidatePermutation(); err=%s", err)
+	}
+	if err := s.ValidatePermutation(s.New(3, 1, 3, 2)); err == nil {
+		t.Error("ValidatePermutation() should reject duplicate alleles")
+	}
+	if err := s.ValidatePermutation(s.New(3, 1, 0, 4)); err == nil {
+		t.Error("ValidatePermutation() should reject alleles outside of [0, NumGenes)")
+	}
+}
+
+func TestEvolverValidator(t *testing.T) {
+	s := genetics.NewSpecies(4, 3)
+	pop := []genetics.Chromosome{s.New(0, 1, 2, 3), s.New(3, 2, 1, 0), s.New(1, 0, 3, 2), s.New(2, 3, 0, 1)}
+	scores := []genetics.Fitness{1, 2, 3, 4}
+	evolver := genetics.Evolver{
+		ReplacementCount: 2,
+		CrossoverRate:    1,
+		Selector:         genetics.TournamentSelection{Size: 2},
+		// Multi-point crossovers do not preserve permutations
+		Crossover: genetics.MultiPointCrossover{Points: 1},
+		Mutator:   genetics.SwapMutation{},
+		Validator: s.ValidatePermutation,
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Evolve() should panic when a child fails validation")
+		}
+	}()
+	for run := 0; run < 100; run++ {
+		evolver.Evolve(rand.New(), pop, scores)
+	}
+}
+
+func TestEvolverDuplicateRetries(t *testing.T) {
+	rng := rand.New()
+	s := genetics.NewSpecies(8, 7)
+	pop := make([]genetics.Chromosome, 0, 10)
+	seen := map[string]bool{}
+	for len(pop) < cap(pop) {
+		c, err := s.NewPerm(rng)
+		if err != nil {
+			t.Fatalf("NewPerm(); err=%s", err)
+		}
+		if key := fmt.Sprint(c.Genes); !seen[key] {
+			seen[key] = true
+			pop = append(pop, c)
+		}
+	}
+	scores := make([]genetics.Fitness, len(pop))
+	evolver := genetics.Evolver{
+		ReplacementCount: 4,
+		// Children which are cloned and not mutated duplicate their parents
+		MutationRate:     0.5,
+		Selector:         genetics.TournamentSelection{Size: 2},
+		Crossover:        genetics.MultiPointCrossover{Points: 1},
+		Mutator:          genetics.SwapMutation{},
+		DuplicateRetries: 50,
+	}
+	for gen := 0; gen < 50; gen++ {
+		for i := range scores {
+			scores[i] = genetics.Fitness(rng.Int31n(100))
+		}
+		evolver.Evolve(rng, pop, scores)
+		seen := map[string]bool{}
+		for _, c := range pop {
+			key := fmt.Sprint(c.Genes)
+			if seen[key] {
+				t.Fatalf("generation %d has duplicate chromosome %v", gen, c.Genes)
+			}
+			seen[key] = true
+		}
+	}
+}
+
+// fixedSelection selects the same parents every time.
+type fixedSelection []int
+
+func (s fixedSelection) String() string {
+	return "FixedSelection"
+}
+
+func (s fixedSelection) SelectParents(r rand.Rand, numParents int, fitness []genetics.Fitness) []int {
+	return append([]int(nil), s[:numParents]...)
+}
+
+// pairRecorder records the first gene of each pair of parents it recombines.
+type pairRecorder struct {
+	pairs *[][2]genetics.Gene
+}
+
+func (p pairRecorder) String() string {
+	return "PairRecorder"
+}
+
+func (p pairRecorder) Crossover(r rand.Rand, a, b genetics.Chromosome) (x, y genetics.Chromosome) {
+	*p.pairs = append(*p.pairs, [2]genetics.Gene{a.Genes[0], b.Genes[0]})
+	return genetics.Chromosome{Species: a.Species, Genes: append([]genetics.Gene(nil), a.Genes...)},
+		genetics.Chromosome{Species: b.Species, Genes: append([]genetics.Gene(nil), b.Genes...)}
+}
+
+func TestEvolverDistinctParents(t *testing.T) {
+	for _, test := range []struct {
+		tag      string
+		selected fixedSelection
+		distinct bool
+		// counts is the number of times each parent should mate, if the selection can be kept.
+		counts map[genetics.Gene]int
+	}{
+		{
+			tag:      "re-paired",
+			selected: fixedSelection{0, 0, 1, 1, 2, 3},
+			distinct: true,
+			counts:   map[genetics.Gene]int{0: 2, 1: 2, 2: 1, 3: 1},
+		}, {
+			tag:      "random partners",
+			selected: fixedSelection{2, 2, 2, 2, 2, 2},
+			distinct: true,
+		}, {
+			tag:      "allowed to self-mate",
+			selected: fixedSelection{2, 2, 2, 2, 2, 2},
+			counts:   map[genetics.Gene]int{2: 6},
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			rng := rand.New()
+			rng.Seed(42)
+			s := genetics.NewSpecies(1, 9)
+			for run := 0; run < 20; run++ {
+				pop := []genetics.Chromosome{s.New(0), s.New(1), s.New(2), s.New(3), s.New(4), s.New(5), s.New(6)}
+				var pairs [][2]genetics.Gene
+				evolver := genetics.Evolver{
+					ReplacementCount: len(test.selected),
+					CrossoverRate:    1,
+					Selector:         test.selected,
+					Crossover:        pairRecorder{&pairs},
+					DistinctParents:  test.distinct,
+				}
+				evolver.Evolve(rng, pop, make([]genetics.Fitness, len(pop)))
+				counts := map[genetics.Gene]int{}
+				for _, p := range pairs {
+					if test.distinct && p[0] == p[1] {
+						t.Fatalf("Evolve() mated %d with itself; pairs=%v", p[0], pairs)
+					}
+					counts[p[0]]++
+					counts[p[1]]++
+				}
+				if test.counts != nil {
+					if diff := cmp.Diff(test.counts, counts); diff != "" {
+						t.Fatalf("Evolve() changed how often parents mate; pairs=%v; -want +got:\n%s", pairs, diff)
+					}
+				}
+			}
+		})
+	}
+}
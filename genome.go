@@ -0,0 +1,104 @@
+package genetics
+
+import (
+	"fmt"
+
+	"github.com/inlined/rand"
+)
+
+// GenomeOperators varies the genomes G of one representation, e.g. the expression
+// trees of package gp. Selection and replacement only depend on Fitness, so
+// GenomeEvolver reuses NaturalSelection and Evolver's replacement policy for
+// genomes which are not a fixed list of Genes.
+type GenomeOperators[G any] interface {
+	fmt.Stringer
+	// Crossover returns two new children of a and b.
+	Crossover(r rand.Rand, a, b G) (x, y G)
+	// Mutate returns a mutated copy of g. Mutate may modify g only if g is not
+	// shared with the population, e.g. because it was returned by Clone.
+	Mutate(r rand.Rand, g G) G
+	// Clone returns a copy of g which shares no state with g.
+	Clone(g G) G
+}
+
+// GenomeEvolver is the Evolver for populations of any genome G. It shares
+// selection and replacement with Evolver.
+type GenomeEvolver[G any] struct {
+	ReplacementCount int
+	CrossoverRate    float32
+	MutationRate     float32
+	Selector         NaturalSelection
+	Operators        GenomeOperators[G]
+}
+
+// Validate reports the configuration errors that Evolver.Validate does, other
+// than those of a MatingRestriction. Operators is required even when both rates
+// are 0, since it clones the parents.
+func (e GenomeEvolver[G]) Validate(populationSize int) error {
+	if err := validateEvolver("GenomeEvolver", populationSize, e.ReplacementCount, e.CrossoverRate, e.MutationRate, e.Selector); err != nil {
+		return err
+	}
+	if e.Operators == nil {
+		return fmt.Errorf("GenomeEvolver.Validate(); Operators is nil")
+	}
+	return nil
+}
+
+// Evolve replaces a handful of the population with the next generation. It
+// panics if the GenomeEvolver is invalid for pop; see Validate.
+func (e GenomeEvolver[G]) Evolve(rand rand.Rand, pop []G, scores []Fitness) {
+	if err := e.Validate(len(pop)); err != nil {
+		panic(err.Error())
+	}
+	breed(rand, pop, scores, e.ReplacementCount, e.Selector, func(a, b G) (x, y G) {
+		if rand.Float32() < e.CrossoverRate {
+			x, y = e.Operators.Crossover(rand, a, b)
+		} else {
+			x, y = e.Operators.Clone(a), e.Operators.Clone(b)
+		}
+		if rand.Float32() < e.MutationRate {
+			x = e.Operators.Mutate(rand, x)
+		}
+		if rand.Float32() < e.MutationRate {
+			y = e.Operators.Mutate(rand, y)
+		}
+		return x, y
+	})
+}
+
+// validateEvolver reports the configuration errors shared by every Evolver, which
+// is called name in the errors.
+func validateEvolver(name string, populationSize, replacementCount int, crossoverRate, mutationRate float32, selector NaturalSelection) error {
+	switch {
+	case selector == nil:
+		return fmt.Errorf("%s.Validate(); Selector is nil", name)
+	case !(crossoverRate >= 0 && crossoverRate <= 1):
+		return fmt.Errorf("%s.Validate(); CrossoverRate %g is outside [0, 1]", name, crossoverRate)
+	case !(mutationRate >= 0 && mutationRate <= 1):
+		return fmt.Errorf("%s.Validate(); MutationRate %g is outside [0, 1]", name, mutationRate)
+	case replacementCount <= 0 || replacementCount%2 != 0:
+		return fmt.Errorf("%s.Validate(); ReplacementCount %d must be a positive even number", name, replacementCount)
+	case populationSize > 0 && replacementCount > populationSize:
+		return fmt.Errorf("%s.Validate(); ReplacementCount %d exceeds the population size %d", name, replacementCount, populationSize)
+	}
+	return nil
+}
+
+// breed is the generation loop of the Evolvers whose genomes are not Chromosomes.
+// It selects replacementCount parents by selector, pairs them at random, and
+// replaces the least fit of pop with the two children which vary produces from
+// each pair.
+func breed[G any](rand rand.Rand, pop []G, scores []Fitness, replacementCount int, selector NaturalSelection, vary func(a, b G) (x, y G)) {
+	indexes := selector.SelectParents(rand, replacementCount, scores)
+	rand.Shuffle(len(indexes), func(i, j int) {
+		indexes[i], indexes[j] = indexes[j], indexes[i]
+	})
+	children := make([]G, replacementCount)
+	for i := 0; i < replacementCount; i += 2 {
+		children[i], children[i+1] = vary(pop[indexes[i]], pop[indexes[i+1]])
+	}
+
+	for child, parent := range replacementSlots(scores, replacementCount) {
+		pop[parent] = children[child]
+	}
+}
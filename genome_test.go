@@ -0,0 +1,56 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestGenomeEvolverValidate(t *testing.T) {
+	valid := genetics.GenomeEvolver[genetics.ByteChromosome]{
+		ReplacementCount: 2,
+		CrossoverRate:    0.9,
+		MutationRate:     0.1,
+		Selector:         genetics.TournamentSelection{Size: 2},
+		Operators:        genetics.ByteOperators{ByteCrossover: genetics.ByteUniformCrossover{}, ByteMutator: genetics.ByteMaskMutation{}},
+	}
+	for _, test := range []struct {
+		tag    string
+		modify func(e *genetics.GenomeEvolver[genetics.ByteChromosome])
+		valid  bool
+	}{
+		{tag: "valid", modify: func(e *genetics.GenomeEvolver[genetics.ByteChromosome]) {}, valid: true},
+		{tag: "nil Selector", modify: func(e *genetics.GenomeEvolver[genetics.ByteChromosome]) { e.Selector = nil }},
+		{tag: "nil Operators", modify: func(e *genetics.GenomeEvolver[genetics.ByteChromosome]) { e.Operators = nil }},
+		{tag: "odd ReplacementCount", modify: func(e *genetics.GenomeEvolver[genetics.ByteChromosome]) { e.ReplacementCount = 3 }},
+		{tag: "zero ReplacementCount", modify: func(e *genetics.GenomeEvolver[genetics.ByteChromosome]) { e.ReplacementCount = 0 }},
+		{tag: "ReplacementCount too large", modify: func(e *genetics.GenomeEvolver[genetics.ByteChromosome]) { e.ReplacementCount = 6 }},
+		{tag: "CrossoverRate above 1", modify: func(e *genetics.GenomeEvolver[genetics.ByteChromosome]) { e.CrossoverRate = 2 }},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			e := valid
+			test.modify(&e)
+			if err := e.Validate(4); test.valid != (err == nil) {
+				t.Errorf("Validate(4); valid=%t err=%v", test.valid, err)
+			}
+			if test.valid {
+				return
+			}
+			defer func() {
+				if recover() == nil {
+					t.Error("Evolve() should panic when the GenomeEvolver is invalid")
+				}
+			}()
+			rng := rand.New()
+			rng.Seed(42)
+			s := genetics.NewByteSpecies(8)
+			pop := make([]genetics.ByteChromosome, 4)
+			for i := range pop {
+				pop[i], _ = s.NewRand(rng)
+			}
+			e.Evolve(rng, pop, make([]genetics.Fitness, len(pop)))
+		})
+	}
+}
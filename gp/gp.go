@@ -0,0 +1,216 @@
+// Package gp implements genetic programming: chromosomes are expression trees
+// built from a PrimitiveSet of functions and terminals rather than fixed lists of
+// Genes. Trees are evolved by a genetics.GenomeEvolver[*Node] with Operators, so the
+// selectors and replacement policy of package genetics are shared.
+package gp
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/inlined/rand"
+)
+
+// Function is an interior node of an expression tree.
+type Function struct {
+	Name  string
+	Arity int
+	Apply func(args []float64) float64
+}
+
+// Terminal is a leaf of an expression tree, e.g. an input variable or a constant.
+type Terminal struct {
+	Name  string
+	Value func(vars []float64) float64
+}
+
+// Standard arithmetic Functions. Div is protected: dividing by zero returns 1 so
+// that every tree can be evaluated.
+var (
+	Add = &Function{Name: "+", Arity: 2, Apply: func(a []float64) float64 { return a[0] + a[1] }}
+	Sub = &Function{Name: "-", Arity: 2, Apply: func(a []float64) float64 { return a[0] - a[1] }}
+	Mul = &Function{Name: "*", Arity: 2, Apply: func(a []float64) float64 { return a[0] * a[1] }}
+	Div = &Function{Name: "/", Arity: 2, Apply: func(a []float64) float64 {
+		if a[1] == 0 {
+			return 1
+		}
+		return a[0] / a[1]
+	}}
+)
+
+// Var is a Terminal which reads vars[i].
+func Var(name string, i int) *Terminal {
+	return &Terminal{Name: name, Value: func(vars []float64) float64 { return vars[i] }}
+}
+
+// Const is a Terminal with a fixed value.
+func Const(v float64) *Terminal {
+	return &Terminal{Name: fmt.Sprintf("%g", v), Value: func([]float64) float64 { return v }}
+}
+
+// Node is an expression tree. Exactly one of Function and Terminal is set, and a
+// Function node has Function.Arity Children.
+type Node struct {
+	Function *Function
+	Terminal *Terminal
+	Children []*Node
+}
+
+// Eval evaluates the tree with the given input variables.
+func (n *Node) Eval(vars []float64) float64 {
+	if n.Terminal != nil {
+		return n.Terminal.Value(vars)
+	}
+	args := make([]float64, len(n.Children))
+	for i, c := range n.Children {
+		args[i] = c.Eval(vars)
+	}
+	v := n.Function.Apply(args)
+	if math.IsNaN(v) {
+		return 0
+	}
+	return v
+}
+
+// String prints the tree as an s-expression, e.g. (+ x (* x x)).
+func (n *Node) String() string {
+	if n.Terminal != nil {
+		return n.Terminal.Name
+	}
+	parts := make([]string, 0, len(n.Children)+1)
+	parts = append(parts, n.Function.Name)
+	for _, c := range n.Children {
+		parts = append(parts, c.String())
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// Depth is the number of edges on the longest path from n to a leaf.
+func (n *Node) Depth() int {
+	d := 0
+	for _, c := range n.Children {
+		if cd := c.Depth() + 1; cd > d {
+			d = cd
+		}
+	}
+	return d
+}
+
+// Size is the number of nodes in the tree.
+func (n *Node) Size() int {
+	s := 1
+	for _, c := range n.Children {
+		s += c.Size()
+	}
+	return s
+}
+
+// Clone returns a deep copy of the tree. Functions and Terminals are shared.
+func (n *Node) Clone() *Node {
+	c := &Node{Function: n.Function, Terminal: n.Terminal}
+	if n.Children != nil {
+		c.Children = make([]*Node, len(n.Children))
+		for i, child := range n.Children {
+			c.Children[i] = child.Clone()
+		}
+	}
+	return c
+}
+
+// At returns the i-th node of the tree in pre-order; At(0) is n.
+func (n *Node) At(i int) *Node {
+	parent, child := n.locate(i)
+	if parent == nil {
+		return n
+	}
+	return parent.Children[child]
+}
+
+// Replace returns the tree with its i-th node in pre-order replaced by sub. The
+// tree is modified in place unless i is 0, in which case sub is returned.
+func (n *Node) Replace(i int, sub *Node) *Node {
+	parent, child := n.locate(i)
+	if parent == nil {
+		return sub
+	}
+	parent.Children[child] = sub
+	return n
+}
+
+// locate finds the parent of the i-th node in pre-order and the node's index
+// among the parent's Children. The root has no parent.
+func (n *Node) locate(i int) (parent *Node, child int) {
+	if i == 0 {
+		return nil, 0
+	}
+	i--
+	for c, sub := range n.Children {
+		size := sub.Size()
+		if i < size {
+			if p, pc := sub.locate(i); p != nil {
+				return p, pc
+			}
+			return n, c
+		}
+		i -= size
+	}
+	panic(fmt.Sprintf("gp: node index out of range for tree of size %d", n.Size()))
+}
+
+// PrimitiveSet is the vocabulary from which trees are built.
+type PrimitiveSet struct {
+	Functions []*Function
+	Terminals []*Terminal
+}
+
+func (p PrimitiveSet) terminal(r rand.Rand) *Node {
+	return &Node{Terminal: p.Terminals[r.Int31n(int32(len(p.Terminals)))]}
+}
+
+func (p PrimitiveSet) function(r rand.Rand, f *Function, child func() *Node) *Node {
+	n := &Node{Function: f, Children: make([]*Node, f.Arity)}
+	for i := range n.Children {
+		n.Children[i] = child()
+	}
+	return n
+}
+
+// Grow creates a random tree of at most maxDepth. Each node is chosen uniformly
+// from all primitives, so branches may end before maxDepth.
+func (p PrimitiveSet) Grow(r rand.Rand, maxDepth int) *Node {
+	numPrimitives := len(p.Functions) + len(p.Terminals)
+	if maxDepth == 0 || len(p.Functions) == 0 {
+		return p.terminal(r)
+	}
+	i := int(r.Int31n(int32(numPrimitives)))
+	if i >= len(p.Functions) {
+		return &Node{Terminal: p.Terminals[i-len(p.Functions)]}
+	}
+	return p.function(r, p.Functions[i], func() *Node { return p.Grow(r, maxDepth-1) })
+}
+
+// Full creates a random tree whose every leaf is at depth.
+func (p PrimitiveSet) Full(r rand.Rand, depth int) *Node {
+	if depth == 0 || len(p.Functions) == 0 {
+		return p.terminal(r)
+	}
+	f := p.Functions[r.Int31n(int32(len(p.Functions)))]
+	return p.function(r, f, func() *Node { return p.Full(r, depth-1) })
+}
+
+// RampedHalfAndHalf creates n trees with depths spread evenly over
+// [minDepth, maxDepth], half of them Full and half Grown, which is the usual way
+// to seed a diverse initial population.
+func (p PrimitiveSet) RampedHalfAndHalf(r rand.Rand, n, minDepth, maxDepth int) []*Node {
+	pop := make([]*Node, n)
+	for i := range pop {
+		depth := minDepth + i/2%(maxDepth-minDepth+1)
+		if i%2 == 0 {
+			pop[i] = p.Full(r, depth)
+		} else {
+			pop[i] = p.Grow(r, depth)
+		}
+	}
+	return pop
+}
@@ -0,0 +1,140 @@
+package gp_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/inlined/rand"
+	"github.com/inlined/xkcd"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/genetics/gp"
+)
+
+var (
+	x          = gp.Var("x", 0)
+	one        = gp.Const(1)
+	primitives = gp.PrimitiveSet{
+		Functions: []*gp.Function{gp.Add, gp.Sub, gp.Mul, gp.Div},
+		Terminals: []*gp.Terminal{x, one},
+	}
+)
+
+func leaf(t *gp.Terminal) *gp.Node {
+	return &gp.Node{Terminal: t}
+}
+
+func call(f *gp.Function, children ...*gp.Node) *gp.Node {
+	return &gp.Node{Function: f, Children: children}
+}
+
+func TestNode(t *testing.T) {
+	// (+ (* x x) (/ x 0))
+	tree := call(gp.Add, call(gp.Mul, leaf(x), leaf(x)), call(gp.Div, leaf(x), leaf(gp.Const(0))))
+	if got := tree.String(); got != "(+ (* x x) (/ x 0))" {
+		t.Errorf("String() = %s", got)
+	}
+	if got := tree.Eval([]float64{3}); got != 10 {
+		t.Errorf("Eval(3) = %g; want 9 + protected division 1 = 10", got)
+	}
+	if tree.Depth() != 2 || tree.Size() != 7 {
+		t.Errorf("Depth() = %d, Size() = %d; want 2, 7", tree.Depth(), tree.Size())
+	}
+	if got := tree.At(4).String(); got != "(/ x 0)" {
+		t.Errorf("At(4) = %s; want (/ x 0)", got)
+	}
+
+	clone := tree.Clone().Replace(1, leaf(one))
+	if got := clone.String(); got != "(+ 1 (/ x 0))" {
+		t.Errorf("Replace(1) = %s", got)
+	}
+	if got := tree.String(); got != "(+ (* x x) (/ x 0))" {
+		t.Errorf("Replace() on a clone modified the original: %s", got)
+	}
+}
+
+func TestSubtreeCrossover(t *testing.T) {
+	a := call(gp.Add, leaf(x), leaf(one))
+	b := call(gp.Mul, leaf(x), leaf(x))
+	// Node 1 of a (x) is swapped with node 0 of b (the whole tree)
+	gotX, gotY := gp.SubtreeCrossover{}.Crossover(xkcd.Rand(1, 0), a, b)
+	if gotX.String() != "(+ (* x x) 1)" || gotY.String() != "x" {
+		t.Errorf("Crossover() = %s, %s; want (+ (* x x) 1), x", gotX, gotY)
+	}
+	if a.String() != "(+ x 1)" || b.String() != "(* x x)" {
+		t.Errorf("Crossover() modified its parents: %s, %s", a, b)
+	}
+}
+
+func TestMutationDepthLimit(t *testing.T) {
+	rng := rand.New()
+	ops := gp.Operators{
+		TreeCrossover: gp.SubtreeCrossover{},
+		TreeMutator:   gp.SubtreeMutation{Primitives: primitives, MaxDepth: 4},
+		MaxDepth:      5,
+	}
+	pop := primitives.RampedHalfAndHalf(rng, 20, 1, 4)
+	for run := 0; run < 200; run++ {
+		a, b := pop[rng.Int31n(20)], pop[rng.Int31n(20)]
+		cx, cy := ops.Crossover(rng, a, b)
+		pop[rng.Int31n(20)] = ops.Mutate(rng, cx)
+		pop[rng.Int31n(20)] = cy
+	}
+	for _, g := range pop {
+		if d := g.Depth(); d > 5 {
+			t.Fatalf("tree %s has depth %d; want at most 5", g, d)
+		}
+	}
+}
+
+func TestPointMutation(t *testing.T) {
+	tree := call(gp.Add, leaf(x), leaf(one))
+	// Mutate node 0, replacing + with the function at index 2 (*) of the same arity
+	got := gp.PointMutation{Primitives: primitives}.Mutate(xkcd.Rand(0, 2), tree)
+	if got.String() != "(* x 1)" {
+		t.Errorf("Mutate() = %s; want (* x 1)", got)
+	}
+}
+
+func TestSymbolicRegression(t *testing.T) {
+	rng := rand.New()
+	// Fit x^2 + x; fitness is the negated total error in thousandths
+	fitness := gp.FitnessFunc(func(t *gp.Node) genetics.Fitness {
+		err := 0.0
+		for v := -5.0; v <= 5; v++ {
+			err += math.Abs(t.Eval([]float64{v}) - (v*v + v))
+		}
+		return -genetics.Fitness(math.Min(err, 1e9) * 1000)
+	})
+	evolver := genetics.GenomeEvolver[*gp.Node]{
+		ReplacementCount: 20,
+		CrossoverRate:    0.9,
+		MutationRate:     0.2,
+		Selector:         genetics.TournamentSelection{Size: 3},
+		Operators: gp.Operators{
+			TreeCrossover: gp.SubtreeCrossover{},
+			TreeMutator:   gp.SubtreeMutation{Primitives: primitives, MaxDepth: 2},
+			MaxDepth:      6,
+		},
+	}
+	pop := primitives.RampedHalfAndHalf(rng, 60, 1, 3)
+	scores := make([]genetics.Fitness, len(pop))
+	best := genetics.Fitness(math.Inf(-1))
+	for gen := 0; gen < 30; gen++ {
+		if err := gp.Evaluate(fitness, pop, scores); err != nil {
+			t.Fatalf("Evaluate(); err=%s", err)
+		}
+		genBest := scores[0]
+		for _, f := range scores {
+			if f > genBest {
+				genBest = f
+			}
+		}
+		// Replacement never removes the fittest tree
+		if genBest < best {
+			t.Fatalf("best fitness regressed from %g to %g in generation %d", best, genBest, gen)
+		}
+		best = genBest
+		evolver.Evolve(rng, pop, scores)
+	}
+}
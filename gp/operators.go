@@ -0,0 +1,151 @@
+package gp
+
+import (
+	"fmt"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/rand"
+)
+
+const (
+	subtreeCrossover = "SubtreeCrossover"
+	pointMutation    = "PointMutation"
+	subtreeMutation  = "SubtreeMutation"
+)
+
+// Crossover is a strategy for generating two children from two parent trees.
+// Parents must not be modified.
+type Crossover interface {
+	fmt.Stringer
+	Crossover(r rand.Rand, a, b *Node) (x, y *Node)
+}
+
+// Mutator returns a randomly modified copy of a tree. t must not be modified.
+type Mutator interface {
+	fmt.Stringer
+	Mutate(r rand.Rand, t *Node) *Node
+}
+
+// SubtreeCrossover swaps a random subtree of each parent.
+type SubtreeCrossover struct{}
+
+func (SubtreeCrossover) String() string {
+	return subtreeCrossover
+}
+
+// Crossover implements Crossover
+func (SubtreeCrossover) Crossover(r rand.Rand, a, b *Node) (x, y *Node) {
+	i := int(r.Int31n(int32(a.Size())))
+	j := int(r.Int31n(int32(b.Size())))
+	x, y = a.Clone(), b.Clone()
+	subX, subY := x.At(i), y.At(j)
+	return x.Replace(i, subY), y.Replace(j, subX)
+}
+
+// PointMutation replaces a random node with another primitive of the same arity.
+type PointMutation struct {
+	Primitives PrimitiveSet
+}
+
+func (PointMutation) String() string {
+	return pointMutation
+}
+
+// Mutate implements Mutator
+func (m PointMutation) Mutate(r rand.Rand, t *Node) *Node {
+	t = t.Clone()
+	n := t.At(int(r.Int31n(int32(t.Size()))))
+	if n.Terminal != nil {
+		n.Terminal = m.Primitives.Terminals[r.Int31n(int32(len(m.Primitives.Terminals)))]
+		return t
+	}
+	var candidates []*Function
+	for _, f := range m.Primitives.Functions {
+		if f.Arity == n.Function.Arity {
+			candidates = append(candidates, f)
+		}
+	}
+	if len(candidates) != 0 {
+		n.Function = candidates[r.Int31n(int32(len(candidates)))]
+	}
+	return t
+}
+
+// SubtreeMutation replaces a random subtree with a new tree grown to at most MaxDepth.
+type SubtreeMutation struct {
+	Primitives PrimitiveSet
+	MaxDepth   int
+}
+
+func (m SubtreeMutation) String() string {
+	return fmt.Sprintf("%s(%d)", subtreeMutation, m.MaxDepth)
+}
+
+// Mutate implements Mutator
+func (m SubtreeMutation) Mutate(r rand.Rand, t *Node) *Node {
+	i := int(r.Int31n(int32(t.Size())))
+	return t.Clone().Replace(i, m.Primitives.Grow(r, m.MaxDepth))
+}
+
+// Operators adapts a Crossover and Mutator to genetics.GenomeOperators[*Node] so
+// that trees can be evolved by a genetics.GenomeEvolver[*Node]. If MaxDepth is positive,
+// children deeper than MaxDepth are discarded in favor of a copy of their parent,
+// which keeps trees from bloating.
+type Operators struct {
+	TreeCrossover Crossover
+	TreeMutator   Mutator
+	MaxDepth      int
+}
+
+func (o Operators) String() string {
+	return fmt.Sprintf("%s+%s", o.TreeCrossover, o.TreeMutator)
+}
+
+// Crossover implements genetics.GenomeOperators
+func (o Operators) Crossover(r rand.Rand, a, b *Node) (x, y *Node) {
+	tx, ty := o.TreeCrossover.Crossover(r, a, b)
+	return o.limit(tx, a), o.limit(ty, b)
+}
+
+// Mutate implements genetics.GenomeOperators
+func (o Operators) Mutate(r rand.Rand, t *Node) *Node {
+	return o.limit(o.TreeMutator.Mutate(r, t), t)
+}
+
+// Clone implements genetics.GenomeOperators
+func (o Operators) Clone(t *Node) *Node {
+	return t.Clone()
+}
+
+// limit returns child, or a copy of parent if child is deeper than MaxDepth.
+func (o Operators) limit(child, parent *Node) *Node {
+	if o.MaxDepth > 0 && child.Depth() > o.MaxDepth {
+		return parent.Clone()
+	}
+	return child
+}
+
+// Evaluator scores an expression tree.
+type Evaluator interface {
+	Evaluate(t *Node) (genetics.Fitness, error)
+}
+
+// FitnessFunc adapts a fitness function which cannot fail into an Evaluator.
+type FitnessFunc func(t *Node) genetics.Fitness
+
+// Evaluate implements Evaluator
+func (f FitnessFunc) Evaluate(t *Node) (genetics.Fitness, error) {
+	return f(t), nil
+}
+
+// Evaluate scores every tree of pop into scores.
+func Evaluate(e Evaluator, pop []*Node, scores []genetics.Fitness) error {
+	for i, t := range pop {
+		f, err := e.Evaluate(t)
+		if err != nil {
+			return fmt.Errorf("Evaluate(%s); err=%s", t, err)
+		}
+		scores[i] = f
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package genetics
+
+import "fmt"
+
+// GrayEncode returns the reflected binary Gray code of v. Consecutive values
+// differ in exactly one bit of their Gray codes.
+func GrayEncode(v uint64) uint64 {
+	return v ^ (v >> 1)
+}
+
+// GrayDecode inverts GrayEncode.
+func GrayDecode(g uint64) uint64 {
+	for shift := uint(1); shift < 64; shift <<= 1 {
+		g ^= g >> shift
+	}
+	return g
+}
+
+// GrayCodec packs numeric parameters into the binary genes (MaxAllele 1) of a
+// Chromosome, Bits genes per parameter with the most significant bit first.
+// Values are Gray coded so that a single mutation can move a parameter to an
+// adjacent value, which plain binary encoding cannot do across e.g. 0111 and 1000.
+type GrayCodec struct {
+	Bits int
+}
+
+// Species returns a binary Species with room for n parameters.
+func (g GrayCodec) Species(n int) *Species {
+	return NewSpecies(n*g.Bits, 1)
+}
+
+// Encode writes v to the Bits genes starting at genes[0].
+func (g GrayCodec) Encode(v uint64, genes []Gene) error {
+	if len(genes) < g.Bits {
+		return fmt.Errorf("GrayCodec.Encode(); need %d genes, got %d", g.Bits, len(genes))
+	}
+	if g.Bits < 64 && v>>uint(g.Bits) != 0 {
+		return fmt.Errorf("GrayCodec.Encode(); %d does not fit in %d bits", v, g.Bits)
+	}
+	code := GrayEncode(v)
+	for i := 0; i < g.Bits; i++ {
+		genes[i] = Gene(code >> uint(g.Bits-1-i) & 1)
+	}
+	return nil
+}
+
+// Decode reads the value stored in the Bits genes starting at genes[0]. Any
+// non-zero allele is treated as a set bit.
+func (g GrayCodec) Decode(genes []Gene) uint64 {
+	var code uint64
+	for i := 0; i < g.Bits; i++ {
+		code <<= 1
+		if genes[i] != 0 {
+			code |= 1
+		}
+	}
+	return GrayDecode(code)
+}
+
+// EncodeAll creates a Chromosome of s holding values, one parameter per Bits genes.
+func (g GrayCodec) EncodeAll(s *Species, values ...uint64) (Chromosome, error) {
+	if len(values)*g.Bits != s.NumGenes {
+		return Chromosome{}, fmt.Errorf("GrayCodec.EncodeAll(); %d values of %d bits do not fill %d genes", len(values), g.Bits, s.NumGenes)
+	}
+	c := s.New()
+	for i, v := range values {
+		if err := g.Encode(v, c.Genes[i*g.Bits:]); err != nil {
+			return Chromosome{}, err
+		}
+	}
+	return c, nil
+}
+
+// DecodeAll returns every parameter stored in c.
+func (g GrayCodec) DecodeAll(c Chromosome) []uint64 {
+	res := make([]uint64, len(c.Genes)/g.Bits)
+	for i := range res {
+		res[i] = g.Decode(c.Genes[i*g.Bits:])
+	}
+	return res
+}
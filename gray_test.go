@@ -0,0 +1,48 @@
+package genetics_test
+
+import (
+	"math/bits"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/inlined/genetics"
+)
+
+func TestGrayCode(t *testing.T) {
+	for v := uint64(0); v < 1024; v++ {
+		if got := genetics.GrayDecode(genetics.GrayEncode(v)); got != v {
+			t.Fatalf("GrayDecode(GrayEncode(%d)) = %d", v, got)
+		}
+		if d := bits.OnesCount64(genetics.GrayEncode(v) ^ genetics.GrayEncode(v+1)); d != 1 {
+			t.Fatalf("Gray codes of %d and %d differ by %d bits; want 1", v, v+1, d)
+		}
+	}
+	if got := genetics.GrayDecode(genetics.GrayEncode(1<<63 + 5)); got != 1<<63+5 {
+		t.Errorf("GrayDecode() did not round trip a 64-bit value; got %d", got)
+	}
+}
+
+func TestGrayCodec(t *testing.T) {
+	codec := genetics.GrayCodec{Bits: 4}
+	s := codec.Species(3)
+	c, err := codec.EncodeAll(s, 7, 8, 15)
+	if err != nil {
+		t.Fatalf("EncodeAll(); err=%s", err)
+	}
+	// 7 = 0100, 8 = 1100, 15 = 1000 in Gray code
+	want := []genetics.Gene{0, 1, 0, 0, 1, 1, 0, 0, 1, 0, 0, 0}
+	if diff := cmp.Diff(want, c.Genes); diff != "" {
+		t.Errorf("EncodeAll() produced unexpected genes; diff=%s", diff)
+	}
+	if diff := cmp.Diff([]uint64{7, 8, 15}, codec.DecodeAll(c)); diff != "" {
+		t.Errorf("DecodeAll() did not round trip; diff=%s", diff)
+	}
+
+	if _, err := codec.EncodeAll(s, 16, 0, 0); err == nil {
+		t.Error("EncodeAll() should reject values which do not fit in Bits")
+	}
+	if _, err := codec.EncodeAll(s, 1, 2); err == nil {
+		t.Error("EncodeAll() should reject too few values")
+	}
+}
@@ -87,6 +87,7 @@ func solveKnapsackGenetically(params searchParams, knapsack knapsackProblem, rng
 
 	evolver := genetics.Evolver{
 		ReplacementCount: params.generationSize / 2,
+		CrossoverRate:    1,
 		MutationRate:     0.03,
 		Selector:         genetics.StochasticUniversalSampling{},
 		Crossover:        genetics.MultiPointCrossover{Points: 2},
@@ -146,7 +147,7 @@ func TestKnapsackProblem(t *testing.T) {
 	fmt.Printf("Genetic growth: %v\n", geneticSolution.samples)
 
 	if randSolution.score >= geneticSolution.score {
-		t.Errorf("Evolution did not benefit over randomness: %d vs %d", randSolution.score, geneticSolution.score)
+		t.Errorf("Evolution did not benefit over randomness: %g vs %g", randSolution.score, geneticSolution.score)
 	}
 }
 
@@ -183,12 +184,13 @@ func solveTravellingSalespersonRandomly(params searchParams, weights [][]int, rn
 func solveTravellingSalespersonGenetically(params searchParams, weights [][]int, rng rand.Rand) solution {
 	soln := solution{
 		samples: make([]genetics.Fitness, params.numGenerations/params.sampleRate),
-		score:   genetics.Fitness(math.MinInt64),
+		score:   genetics.Fitness(math.Inf(-1)),
 	}
 	s := genetics.NewSpecies(len(weights), genetics.Gene(len(weights)-1))
 
 	evolver := genetics.Evolver{
 		ReplacementCount: params.generationSize / 2,
+		CrossoverRate:    1,
 		MutationRate:     0.03,
 		Selector:         genetics.TournamentSelection{Size: 4},
 		Crossover:        genetics.DavisOrderCrossover{},
@@ -245,6 +247,6 @@ func TestTravellingSalesperson(t *testing.T) {
 	fmt.Printf("Genetic growth: %v\n", geneticSolution.samples)
 
 	if randSolution.score >= geneticSolution.score {
-		t.Errorf("Evolution did not benefit over randomness: %d vs %d", randSolution.score, geneticSolution.score)
+		t.Errorf("Evolution did not benefit over randomness: %g vs %g", randSolution.score, geneticSolution.score)
 	}
 }
@@ -0,0 +1,51 @@
+package genetics
+
+import (
+	"fmt"
+
+	"github.com/inlined/rand"
+)
+
+// immigrantOperator is the provenance of chromosomes inserted by RandomImmigrants
+const immigrantOperator = "Immigrant"
+
+// RandomImmigrants is an Observer which maintains diversity by replacing the least
+// fit Fraction of an Engine's population with new chromosomes every Interval
+// generations. Unlike a StagnationPolicy, it does not wait for the search to
+// stall. Add a *RandomImmigrants to Engine.Observers.
+type RandomImmigrants struct {
+	NopObserver
+
+	// Interval is the number of generations between waves of immigrants.
+	Interval int
+	// Fraction of the population, ranked by its most recent scores, which is
+	// replaced by each wave.
+	Fraction float64
+	// New creates immigrants, e.g. species.NewPerm. Defaults to the population's
+	// Species.NewRand.
+	New func(r rand.Rand) (Chromosome, error)
+	// Rand is the source of randomness for New.
+	Rand rand.Rand
+
+	immigrants int
+}
+
+// Immigrants returns the number of chromosomes inserted since the Engine was reset.
+func (m *RandomImmigrants) Immigrants() int {
+	return m.immigrants
+}
+
+// OnGenerationStart implements Observer
+func (m *RandomImmigrants) OnGenerationStart(e *Engine, generation int) {
+	if generation == 0 {
+		m.immigrants = 0
+		return
+	}
+	if m.Interval <= 0 || generation%m.Interval != 0 {
+		return
+	}
+	if err := replaceWorst(e, m.Fraction, m.New, m.Rand, immigrantOperator); err != nil {
+		panic(fmt.Sprintf("RandomImmigrants: %s", err))
+	}
+	m.immigrants += int(m.Fraction * float64(len(e.Population().Chromosomes)))
+}
@@ -0,0 +1,46 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestRandomImmigrants(t *testing.T) {
+	rng := rand.New()
+	pop := newBinaryPopulation(t, rng, 8, 10)
+	var created []genetics.Chromosome
+	immigrants := &genetics.RandomImmigrants{
+		Interval: 3,
+		Fraction: 0.2,
+		New: func(r rand.Rand) (genetics.Chromosome, error) {
+			// Immigrants are the fittest possible chromosome, so they survive
+			c := pop.Species.New(1, 1, 1, 1, 1, 1, 1, 1)
+			created = append(created, c)
+			return c, nil
+		},
+		Rand: rng,
+	}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 2,
+			CrossoverRate:    1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+		},
+		Evaluator: genetics.FitnessFunc(oneMax),
+		Observers: []genetics.Observer{immigrants},
+	}
+	if err := engine.Run(rng, pop, 10); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	// Generations 3, 6, and 9 each replace 2 of 10 chromosomes
+	if len(created) != 6 || immigrants.Immigrants() != 6 {
+		t.Errorf("created %d immigrants and counted %d; want 6", len(created), immigrants.Immigrants())
+	}
+	if _, best := pop.Best(); best != 8 {
+		t.Errorf("best fitness is %g; immigrants should have been evaluated", best)
+	}
+}
@@ -0,0 +1,132 @@
+package genetics
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/inlined/rand"
+)
+
+// migrationOperator is the provenance of chromosomes which migrated between islands
+const migrationOperator = "Migration"
+
+// Island is a Population evolved independently by its own Engine.
+type Island struct {
+	Name       string
+	Engine     *Engine
+	Population *Population
+}
+
+// Archipelago evolves several Islands side by side. Every MigrationInterval
+// generations the Migrants fittest members of each Island replace the least fit
+// members of the next Island (a ring topology), which shares building blocks
+// between islands while preserving their diversity.
+type Archipelago struct {
+	Islands           []*Island
+	MigrationInterval int
+	Migrants          int
+
+	// Checkpoints, if set, periodically saves each Island.
+	Checkpoints *Checkpointer
+}
+
+// Run evolves every Island for the given number of generations.
+func (a *Archipelago) Run(r rand.Rand, generations int) error {
+	for _, island := range a.Islands {
+		island.Engine.Reset(island.Population)
+	}
+	for gen := 0; gen < generations; gen++ {
+		for n, island := range a.Islands {
+			if err := island.Engine.Step(r); err != nil {
+				return fmt.Errorf("island %s: %s", island.Name, err)
+			}
+			if a.Checkpoints != nil {
+				if err := a.Checkpoints.Save(n, len(a.Islands), island); err != nil {
+					return err
+				}
+			}
+		}
+		if a.MigrationInterval > 0 && (gen+1)%a.MigrationInterval == 0 {
+			a.migrate()
+		}
+	}
+	for _, island := range a.Islands {
+		if err := island.Engine.finish(r, nil); err != nil {
+			return fmt.Errorf("island %s: %s", island.Name, err)
+		}
+	}
+	return nil
+}
+
+// RunParallel evolves every Island for the given number of generations, stepping
+// the Islands concurrently between migrations. Each Island draws from its own
+// random stream split from master by a SeedSplitter, so runs are reproducible from
+// master regardless of scheduling. The Islands' Engines and Observers must not be
+// shared between Islands, and operators shared between Islands must be
+// ConcurrentSafe; RunParallel returns an error for shared operators which are not.
+func (a *Archipelago) RunParallel(master int64, generations int) error {
+	if err := a.checkShared(); err != nil {
+		return err
+	}
+	streams := NewSeedSplitter(master).Streams(len(a.Islands))
+	for _, island := range a.Islands {
+		island.Engine.Reset(island.Population)
+	}
+	errs := make([]error, len(a.Islands))
+	parallel := func(step func(r rand.Rand, island *Island) error) error {
+		var wg sync.WaitGroup
+		for n, island := range a.Islands {
+			wg.Add(1)
+			go func(n int, island *Island) {
+				defer wg.Done()
+				errs[n] = step(streams[n], island)
+			}(n, island)
+		}
+		wg.Wait()
+		for n, err := range errs {
+			if err != nil {
+				return fmt.Errorf("island %s: %s", a.Islands[n].Name, err)
+			}
+		}
+		return nil
+	}
+
+	for gen := 0; gen < generations; gen++ {
+		if err := parallel(func(r rand.Rand, island *Island) error {
+			return island.Engine.Step(r)
+		}); err != nil {
+			return err
+		}
+		if a.Checkpoints != nil {
+			for n, island := range a.Islands {
+				if err := a.Checkpoints.Save(n, len(a.Islands), island); err != nil {
+					return err
+				}
+			}
+		}
+		if a.MigrationInterval > 0 && (gen+1)%a.MigrationInterval == 0 {
+			a.migrate()
+		}
+	}
+	return parallel(func(r rand.Rand, island *Island) error {
+		return island.Engine.finish(r, nil)
+	})
+}
+
+// migrate copies the fittest members of each island over the least fit members of
+// the next island, judged by their most recent scores.
+func (a *Archipelago) migrate() {
+	migrants := make([][]Chromosome, len(a.Islands))
+	for n, island := range a.Islands {
+		for _, i := range TopK(island.Population.scores(), a.Migrants) {
+			migrants[n] = append(migrants[n], island.Population.Chromosomes[i].Clone())
+		}
+	}
+	for n, island := range a.Islands {
+		from := migrants[(n+len(a.Islands)-1)%len(a.Islands)]
+		worst := BottomK(island.Population.scores(), len(from))
+		for m, c := range from {
+			island.Engine.Replace(worst[m], c, migrationOperator)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package genetics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func newIsland(t *testing.T, rng rand.Rand, name string) *genetics.Island {
+	return &genetics.Island{
+		Name: name,
+		Engine: &genetics.Engine{
+			Evolver: genetics.Evolver{
+				ReplacementCount: 4,
+				CrossoverRate:    1,
+				MutationRate:     0.1,
+				Selector:         genetics.TournamentSelection{Size: 2},
+				Crossover:        genetics.MultiPointCrossover{Points: 1},
+				Mutator:          genetics.RandomResettingMutation{},
+			},
+			Evaluator: genetics.FitnessFunc(oneMax),
+		},
+		Population: newBinaryPopulation(t, rng, 10, 8),
+	}
+}
+
+func TestArchipelagoMigration(t *testing.T) {
+	rng := rand.New()
+	a := genetics.Archipelago{
+		Islands:           []*genetics.Island{newIsland(t, rng, "a"), newIsland(t, rng, "b")},
+		MigrationInterval: 1,
+		Migrants:          1,
+	}
+	// Island a has a perfect individual which should migrate to island b
+	a.Islands[0].Population.Chromosomes[0] = a.Islands[0].Population.Species.New(1, 1, 1, 1, 1, 1, 1, 1, 1, 1)
+	for _, c := range a.Islands[1].Population.Chromosomes {
+		for i := range c.Genes {
+			c.Genes[i] = 0
+		}
+	}
+
+	if err := a.Run(rng, 1); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if _, best := a.Islands[1].Population.Best(); best != 10 {
+		t.Errorf("the fittest member of island a did not migrate to island b; best=%g", best)
+	}
+}
+
+func TestCheckpointRotation(t *testing.T) {
+	rng := rand.New()
+	dir := t.TempDir()
+	a := genetics.Archipelago{
+		Islands: []*genetics.Island{newIsland(t, rng, "a"), newIsland(t, rng, "b")},
+		Checkpoints: &genetics.Checkpointer{
+			Dir:      dir,
+			Interval: 4,
+			KeepLast: 2,
+			KeepBest: true,
+		},
+	}
+	if err := a.Run(rng, 16); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+
+	m, err := genetics.LoadManifest(dir)
+	if err != nil {
+		t.Fatalf("LoadManifest(); err=%s", err)
+	}
+	generations := func(island string) []int {
+		var gens []int
+		for _, cp := range m.Islands[island].Checkpoints {
+			gens = append(gens, cp.Generation)
+		}
+		return gens
+	}
+	// Island a checkpoints on generations 4, 8, 12, 16; b is staggered to 2, 6, 10, 14
+	if diff := cmp.Diff([]int{12, 16}, generations("a")); diff != "" {
+		t.Errorf("island a retained checkpoints %v; diff=%s", generations("a"), diff)
+	}
+	if diff := cmp.Diff([]int{10, 14}, generations("b")); diff != "" {
+		t.Errorf("island b retained checkpoints %v; diff=%s", generations("b"), diff)
+	}
+
+	for name, ic := range m.Islands {
+		if ic.Best == nil {
+			t.Fatalf("island %s has no best checkpoint", name)
+		}
+		for _, cp := range append(ic.Checkpoints, *ic.Best) {
+			pop, err := genetics.LoadCheckpoint(dir, cp)
+			if err != nil {
+				t.Errorf("LoadCheckpoint(%s); err=%s", cp.File, err)
+				continue
+			}
+			if pop.Len() != 8 {
+				t.Errorf("checkpoint %s has %d chromosomes; want 8", cp.File, pop.Len())
+			}
+			// The children bred in the checkpointed generation are unevaluated
+			if len(cp.Pending) != 4 {
+				t.Errorf("checkpoint %s lists pending members %v; want the 4 children", cp.File, cp.Pending)
+			}
+			pending := map[int]bool{}
+			for _, i := range cp.Pending {
+				pending[i] = true
+			}
+			best := genetics.Fitness(-1)
+			for i, c := range pop.Chromosomes {
+				if !pending[i] && oneMax(c) > best {
+					best = oneMax(c)
+				}
+			}
+			if cp.Best != best {
+				t.Errorf("checkpoint %s records best %g; want %g, the best of the evaluated members", cp.File, cp.Best, best)
+			}
+		}
+	}
+
+	// Only the retained checkpoints remain on disk
+	for name, ic := range m.Islands {
+		files, err := filepath.Glob(filepath.Join(dir, name, "*.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		retained := map[string]bool{filepath.Join(dir, ic.Best.File): true}
+		for _, cp := range ic.Checkpoints {
+			retained[filepath.Join(dir, cp.File)] = true
+		}
+		if len(files) != len(retained) {
+			t.Errorf("island %s has checkpoint files %v; want only %v", name, files, retained)
+		}
+	}
+}
+
+func TestRemoteIslandMigration(t *testing.T) {
+	rng := rand.New()
+	ta, tb := &genetics.HTTPTransport{}, &genetics.HTTPTransport{}
+	sa, sb := httptest.NewServer(ta), httptest.NewServer(tb)
+	defer sa.Close()
+	defer sb.Close()
+	ta.Peer, tb.Peer = sb.URL, sa.URL
+
+	a := &genetics.RemoteIsland{Island: newIsland(t, rng, "a"), MigrationInterval: 1, Migrants: 1, Transport: ta}
+	b := &genetics.RemoteIsland{Island: newIsland(t, rng, "b"), MigrationInterval: 1, Migrants: 1, Transport: tb}
+	// Island a has a perfect individual which should migrate to island b
+	a.Island.Population.Chromosomes[0] = a.Island.Population.Species.New(1, 1, 1, 1, 1, 1, 1, 1, 1, 1)
+	for _, c := range b.Island.Population.Chromosomes {
+		for i := range c.Genes {
+			c.Genes[i] = 0
+		}
+	}
+
+	if err := a.Run(rng, 1); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if err := b.Run(rng, 1); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if _, best := b.Island.Population.Best(); best != 10 {
+		t.Errorf("the fittest member of island a did not migrate to island b; best=%g", best)
+	}
+	// Island b's migrant is waiting for island a's next migration
+	if migrants, err := ta.Receive(); err != nil || len(migrants) != 1 {
+		t.Errorf("Receive()=%v,%v; want island b's migrant", migrants, err)
+	}
+
+	// Migrants of another Species are rejected
+	if err := ta.Send([]genetics.Chromosome{genetics.NewSpecies(3, 1).New(1, 1, 1)}); err != nil {
+		t.Fatalf("Send(); err=%s", err)
+	}
+	if err := b.Run(rng, 1); err == nil {
+		t.Error("Run() should reject migrants of another Species")
+	}
+
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+	ta.Peer = missing.URL
+	if err := a.Run(rng, 1); err == nil {
+		t.Error("Run() should fail when migrants cannot be sent")
+	}
+}
+
+func TestArchipelagoRunParallel(t *testing.T) {
+	run := func() [][]genetics.Chromosome {
+		rng := rand.New()
+		rng.Seed(42)
+		a := genetics.Archipelago{
+			Islands:           []*genetics.Island{newIsland(t, rng, "a"), newIsland(t, rng, "b"), newIsland(t, rng, "c")},
+			MigrationInterval: 2,
+			Migrants:          1,
+		}
+		if err := a.RunParallel(7, 6); err != nil {
+			t.Fatalf("RunParallel(); err=%s", err)
+		}
+		var pops [][]genetics.Chromosome
+		for _, island := range a.Islands {
+			pops = append(pops, island.Population.Chromosomes)
+		}
+		return pops
+	}
+	if diff := cmp.Diff(run(), run()); diff != "" {
+		t.Errorf("parallel runs with the same master seed differ; -first +second:\n%s", diff)
+	}
+}
+
+func TestArchipelagoRunParallelSharedOperators(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	islands := func(m genetics.Mutator) []*genetics.Island {
+		islands := []*genetics.Island{newIsland(t, rng, "a"), newIsland(t, rng, "b")}
+		for _, island := range islands {
+			island.Engine.Evolver.Mutator = m
+		}
+		return islands
+	}
+
+	shared := genetics.Archipelago{Islands: islands(genetics.NewAdaptiveMutator(genetics.SwapMutation{}, genetics.InversionMutation{}))}
+	if err := shared.RunParallel(7, 3); err != nil {
+		t.Errorf("RunParallel() with a shared AdaptiveMutator; err=%s", err)
+	}
+
+	n := 0
+	unsafe := genetics.Archipelago{Islands: islands(&countingMutator{name: "Counting", count: &n})}
+	if err := unsafe.RunParallel(7, 3); err == nil {
+		t.Errorf("RunParallel() should reject a shared Mutator which is not ConcurrentSafe")
+	}
+	if n != 0 {
+		t.Errorf("RunParallel() mutated %d chromosomes before rejecting the Archipelago", n)
+	}
+}
@@ -0,0 +1,157 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/inlined/rand"
+)
+
+// LandscapeProbe samples a problem's fitness landscape to estimate how hard it is
+// to search before committing to a large run, e.g. to choose operators and rates.
+// Probes treat higher fitness as better; when minimizing, the signs of their
+// correlations flip.
+type LandscapeProbe struct {
+	Evaluator Evaluator
+	// New creates random Chromosomes, e.g. species.NewPerm.
+	New func(r rand.Rand) (Chromosome, error)
+	// Mutator takes the steps of RandomWalk, defining the neighborhood whose
+	// ruggedness is measured. Probe the Mutator you plan to evolve with.
+	Mutator Mutator
+	// Distance compares Chromosomes in FitnessDistanceCorrelation. Defaults to
+	// HammingDistance.
+	Distance DistanceFunc
+}
+
+// FitnessDistanceCorrelation is the correlation, over samples random Chromosomes,
+// between fitness and distance to optimum (Jones and Forrest). If optimum has no
+// Genes, the fittest sample stands in for it. Near -1, fitness rises toward the
+// optimum and the problem is easy; near 0, fitness says little about the distance
+// left; a positive correlation means the problem is deceptive.
+func (p LandscapeProbe) FitnessDistanceCorrelation(r rand.Rand, samples int, optimum Chromosome) (float64, error) {
+	if p.Evaluator == nil || p.New == nil || samples < 2 {
+		return 0, fmt.Errorf("LandscapeProbe.FitnessDistanceCorrelation(); need an Evaluator, New, and at least 2 samples")
+	}
+	distance := p.Distance
+	if distance == nil {
+		distance = HammingDistance
+	}
+	cs := make([]Chromosome, samples)
+	fitness := make([]float64, samples)
+	best := 0
+	for n := range cs {
+		c, err := p.New(r)
+		if err != nil {
+			return 0, fmt.Errorf("LandscapeProbe.FitnessDistanceCorrelation(); err=%s", err)
+		}
+		f, err := p.Evaluator.Evaluate(c)
+		if err != nil {
+			return 0, fmt.Errorf("LandscapeProbe.FitnessDistanceCorrelation(); err=%s", err)
+		}
+		cs[n], fitness[n] = c, float64(f)
+		if fitness[n] > fitness[best] {
+			best = n
+		}
+	}
+	if optimum.Genes == nil {
+		optimum = cs[best]
+	}
+	distances := make([]float64, samples)
+	for n, c := range cs {
+		distances[n] = distance(c, optimum)
+	}
+	return correlation(fitness, distances), nil
+}
+
+// Ruggedness summarizes the fitness of a random walk over a landscape.
+type Ruggedness struct {
+	// Autocorrelation[s] is the correlation between the fitness of steps s apart.
+	// Autocorrelation[0] is 1.
+	Autocorrelation []float64
+	// CorrelationLength is -1/ln|Autocorrelation[1]|, roughly the number of steps
+	// over which fitness stays correlated (Weinberger). Smooth landscapes have long
+	// correlation lengths; rugged ones, where a step tells little about the next,
+	// approach 0.
+	CorrelationLength float64
+}
+
+// RandomWalk scores a walk of the given number of steps from a random Chromosome,
+// each step applying Mutator to the last, and measures the autocorrelation of
+// fitness for lags up to maxLag.
+func (p LandscapeProbe) RandomWalk(r rand.Rand, steps, maxLag int) (Ruggedness, error) {
+	if p.Evaluator == nil || p.New == nil || p.Mutator == nil {
+		return Ruggedness{}, fmt.Errorf("LandscapeProbe.RandomWalk(); need an Evaluator, New, and Mutator")
+	}
+	if maxLag < 1 || steps <= 2*maxLag {
+		return Ruggedness{}, fmt.Errorf("LandscapeProbe.RandomWalk(); %d steps are too few for lags up to %d", steps, maxLag)
+	}
+	c, err := p.New(r)
+	if err != nil {
+		return Ruggedness{}, fmt.Errorf("LandscapeProbe.RandomWalk(); err=%s", err)
+	}
+	fitness := make([]float64, steps)
+	for s := range fitness {
+		if s > 0 {
+			c = c.Clone()
+			p.Mutator.Mutate(r, &c)
+		}
+		f, err := p.Evaluator.Evaluate(c)
+		if err != nil {
+			return Ruggedness{}, fmt.Errorf("LandscapeProbe.RandomWalk(); step %d: %s", s, err)
+		}
+		fitness[s] = float64(f)
+	}
+
+	mean, variance := 0.0, 0.0
+	for _, f := range fitness {
+		mean += f
+	}
+	mean /= float64(steps)
+	for _, f := range fitness {
+		variance += (f - mean) * (f - mean)
+	}
+	rugged := Ruggedness{Autocorrelation: make([]float64, maxLag+1)}
+	rugged.Autocorrelation[0] = 1
+	for lag := 1; lag <= maxLag; lag++ {
+		if variance == 0 {
+			// A flat walk is perfectly correlated
+			rugged.Autocorrelation[lag] = 1
+			continue
+		}
+		sum := 0.0
+		for s := 0; s+lag < steps; s++ {
+			sum += (fitness[s] - mean) * (fitness[s+lag] - mean)
+		}
+		rugged.Autocorrelation[lag] = sum / float64(steps-lag) / (variance / float64(steps))
+	}
+	switch rho := math.Abs(rugged.Autocorrelation[1]); {
+	case rho >= 1:
+		rugged.CorrelationLength = math.Inf(1)
+	case rho == 0:
+		rugged.CorrelationLength = 0
+	default:
+		rugged.CorrelationLength = -1 / math.Log(rho)
+	}
+	return rugged, nil
+}
+
+// correlation is the Pearson correlation of x and y, or 0 if either is constant.
+func correlation(x, y []float64) float64 {
+	n := float64(len(x))
+	mx, my := 0.0, 0.0
+	for i := range x {
+		mx += x[i]
+		my += y[i]
+	}
+	mx, my = mx/n, my/n
+	var sxy, sxx, syy float64
+	for i := range x {
+		sxy += (x[i] - mx) * (y[i] - my)
+		sxx += (x[i] - mx) * (x[i] - mx)
+		syy += (y[i] - my) * (y[i] - my)
+	}
+	if sxx == 0 || syy == 0 {
+		return 0
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
@@ -0,0 +1,98 @@
+package genetics_test
+
+import (
+	"hash/fnv"
+	"math"
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+// flipMutator flips one random gene of a binary Chromosome.
+type flipMutator struct{}
+
+func (flipMutator) String() string {
+	return "Flip"
+}
+
+func (flipMutator) Mutate(r rand.Rand, c *genetics.Chromosome) {
+	i := r.Int31n(int32(len(c.Genes)))
+	c.Genes[i] = 1 - c.Genes[i]
+}
+
+// noise scores Chromosomes by a hash of their Genes, a landscape with no structure.
+func noise(c genetics.Chromosome) genetics.Fitness {
+	h := fnv.New32a()
+	for _, g := range c.Genes {
+		h.Write([]byte{byte(g)})
+	}
+	return genetics.Fitness(h.Sum32() % 1000)
+}
+
+func TestLandscapeProbe(t *testing.T) {
+	s := genetics.NewSpecies(trapSize*trapBlocks, 1)
+	optimum := s.New()
+	for i := range optimum.Genes {
+		optimum.Genes[i] = 1
+	}
+	for _, test := range []struct {
+		tag     string
+		fitness genetics.FitnessFunc
+		// fdc is the range of the fitness-distance correlation
+		fdc [2]float64
+		// length is the range of the correlation length
+		length [2]float64
+	}{
+		{tag: "OneMax", fitness: oneMax, fdc: [2]float64{-1, -0.99}, length: [2]float64{5, 100}},
+		{tag: "Trap", fitness: trap, fdc: [2]float64{0.15, 1}, length: [2]float64{2, 100}},
+		{tag: "Noise", fitness: noise, fdc: [2]float64{-0.2, 0.2}, length: [2]float64{0, 0.5}},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			rng := rand.New()
+			rng.Seed(42)
+			probe := genetics.LandscapeProbe{
+				Evaluator: test.fitness,
+				New:       s.NewRand,
+				Mutator:   flipMutator{},
+			}
+			fdc, err := probe.FitnessDistanceCorrelation(rng, 500, optimum)
+			if err != nil {
+				t.Fatalf("FitnessDistanceCorrelation(); err=%s", err)
+			}
+			if fdc < test.fdc[0] || fdc > test.fdc[1] {
+				t.Errorf("FitnessDistanceCorrelation()=%g; want in %v", fdc, test.fdc)
+			}
+			rugged, err := probe.RandomWalk(rng, 2000, 5)
+			if err != nil {
+				t.Fatalf("RandomWalk(); err=%s", err)
+			}
+			if l := rugged.CorrelationLength; l < test.length[0] || l > test.length[1] {
+				t.Errorf("RandomWalk() has CorrelationLength %g; want in %v (autocorrelation %v)", l, test.length, rugged.Autocorrelation)
+			}
+			if len(rugged.Autocorrelation) != 6 || rugged.Autocorrelation[0] != 1 {
+				t.Errorf("RandomWalk() has Autocorrelation %v; want lags 0 through 5 starting at 1", rugged.Autocorrelation)
+			}
+		})
+	}
+
+	// OneMax after n flips decorrelates as (1 - 2/n)^s
+	rng := rand.New()
+	rng.Seed(42)
+	probe := genetics.LandscapeProbe{Evaluator: genetics.FitnessFunc(oneMax), New: s.NewRand, Mutator: flipMutator{}}
+	rugged, err := probe.RandomWalk(rng, 20000, 1)
+	if err != nil {
+		t.Fatalf("RandomWalk(); err=%s", err)
+	}
+	if want := 1 - 2/float64(s.NumGenes); math.Abs(rugged.Autocorrelation[1]-want) > 0.02 {
+		t.Errorf("RandomWalk() has Autocorrelation[1]=%g; want %g", rugged.Autocorrelation[1], want)
+	}
+
+	if _, err := probe.RandomWalk(rng, 10, 5); err == nil {
+		t.Error("RandomWalk() should reject walks of too few steps")
+	}
+	if _, err := (genetics.LandscapeProbe{}).FitnessDistanceCorrelation(rng, 10, optimum); err == nil {
+		t.Error("FitnessDistanceCorrelation() should require an Evaluator")
+	}
+}
@@ -0,0 +1,164 @@
+package genetics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// LineageRecord describes one chromosome born into an Engine's population.
+type LineageRecord struct {
+	ID int
+	// Parents are the IDs of the chromosomes the child was bred from: two for a
+	// crossover and one for a clone. Chromosomes which were not bred (e.g. the
+	// initial population or immigrants) have none.
+	Parents []int
+	// Generation is the generation in which the chromosome was first evaluated.
+	Generation int
+	// Operator is the chromosome's provenance, e.g. "MultiPointCrossover(2)+SwapMutation".
+	Operator string
+	// Genes and Fitness are recorded when the chromosome is evaluated, after any local search.
+	Genes     []Gene
+	Fitness   Fitness
+	Evaluated bool
+}
+
+// Lineage records the genealogy of an Engine's chromosomes: every chromosome
+// which enters the population gets an ID and a LineageRecord linking it to its
+// parents. Set Engine.Lineage to record it, then use Ancestors or WriteDOT to see
+// how a solution was constructed. Records are kept for the whole run, so memory
+// grows with the number of evaluations.
+type Lineage struct {
+	records []LineageRecord
+	// ids[i] is the ID of the population member at index i
+	ids []int
+}
+
+// ID returns the ID of the population member at index.
+func (l *Lineage) ID(index int) int {
+	return l.ids[index]
+}
+
+// Len returns the number of chromosomes recorded.
+func (l *Lineage) Len() int {
+	return len(l.records)
+}
+
+// Record returns the LineageRecord of the chromosome with id.
+func (l *Lineage) Record(id int) LineageRecord {
+	return l.records[id]
+}
+
+// Ancestors returns the records of id and all of its ancestors, in ascending order of ID.
+func (l *Lineage) Ancestors(id int) []LineageRecord {
+	seen := map[int]bool{}
+	l.visit(id, seen)
+	ids := make([]int, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	res := make([]LineageRecord, len(ids))
+	for i, id := range ids {
+		res[i] = l.records[id]
+	}
+	return res
+}
+
+func (l *Lineage) visit(id int, seen map[int]bool) {
+	stack := []int{id}
+	for len(stack) != 0 {
+		id, stack = stack[len(stack)-1], stack[:len(stack)-1]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		stack = append(stack, l.records[id].Parents...)
+	}
+}
+
+// WriteDOT writes the genealogy of the chromosomes with ids, or of every recorded
+// chromosome if ids is empty, as a GraphViz digraph with edges from parents to
+// children. The chromosomes with ids are drawn in bold. Render it with e.g.
+// `dot -Tsvg lineage.dot > lineage.svg`.
+func (l *Lineage) WriteDOT(w io.Writer, ids ...int) error {
+	records := l.records
+	bold := make(map[int]bool, len(ids))
+	if len(ids) != 0 {
+		seen := map[int]bool{}
+		for _, id := range ids {
+			if id < 0 || id >= len(l.records) {
+				return fmt.Errorf("Lineage.WriteDOT(); unknown ID %d", id)
+			}
+			bold[id] = true
+			l.visit(id, seen)
+		}
+		records = nil
+		for _, r := range l.records {
+			if seen[r.ID] {
+				records = append(records, r)
+			}
+		}
+	}
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintln(b, "digraph lineage {")
+	fmt.Fprintln(b, "\tnode [shape=box];")
+	for _, r := range records {
+		label := fmt.Sprintf("#%d gen %d\\n%s", r.ID, r.Generation, dotEscape(r.Operator))
+		if r.Evaluated {
+			label += fmt.Sprintf("\\nfitness %g", r.Fitness)
+		}
+		style := ""
+		if bold[r.ID] {
+			style = ", style=bold"
+		}
+		fmt.Fprintf(b, "\tn%d [label=\"%s\"%s];\n", r.ID, label, style)
+	}
+	for _, r := range records {
+		for _, p := range r.Parents {
+			fmt.Fprintf(b, "\tn%d -> n%d;\n", p, r.ID)
+		}
+	}
+	fmt.Fprintln(b, "}")
+	return b.Flush()
+}
+
+// dotEscape quotes s for use in a DOT string.
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+// reset forgets all records and gives each of the size members of a new population an ID.
+func (l *Lineage) reset(size int) {
+	l.records = l.records[:0]
+	l.ids = make([]int, size)
+	for i := range l.ids {
+		l.ids[i] = l.birth(nil, initialOperator)
+	}
+}
+
+// birth records a new chromosome bred from the parent IDs and returns its ID.
+func (l *Lineage) birth(parents []int, operator string) int {
+	id := len(l.records)
+	l.records = append(l.records, LineageRecord{ID: id, Parents: parents, Operator: operator})
+	return id
+}
+
+// replace records a new chromosome at index, bred from the parent IDs.
+func (l *Lineage) replace(index int, parents []int, operator string) {
+	l.ids[index] = l.birth(parents, operator)
+}
+
+// evaluated records the evaluation of the member at index, whose provenance is
+// operator, in generation.
+func (l *Lineage) evaluated(index int, generation int, c Chromosome, f Fitness, operator string) {
+	r := &l.records[l.ids[index]]
+	if !r.Evaluated {
+		r.Generation, r.Operator = generation, operator
+		r.Genes = append([]Gene(nil), c.Genes...)
+	}
+	r.Fitness, r.Evaluated = f, true
+}
@@ -0,0 +1,117 @@
+package genetics_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestLineage(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	pop := newBinaryPopulation(t, rng, 20, 10)
+	lineage := &genetics.Lineage{}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 4,
+			CrossoverRate:    0.7,
+			MutationRate:     0.2,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator:          genetics.RandomResettingMutation{},
+		},
+		Evaluator: genetics.FitnessFunc(oneMax),
+		Lineage:   lineage,
+	}
+	if err := engine.Run(rng, pop, 5); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if want := 10 + 5*4; lineage.Len() != want {
+		t.Errorf("Len()=%d; want one record per evaluation (%d)", lineage.Len(), want)
+	}
+	for id := 0; id < lineage.Len(); id++ {
+		r := lineage.Record(id)
+		if !r.Evaluated || r.Fitness != oneMax(genetics.Chromosome{Genes: r.Genes}) {
+			t.Errorf("Record(%d)=%+v was not evaluated", id, r)
+		}
+		if (r.Operator == "Initial") != (len(r.Parents) == 0) {
+			t.Errorf("Record(%d) from %s has parents %v", id, r.Operator, r.Parents)
+		}
+		for _, p := range r.Parents {
+			if parent := lineage.Record(p); parent.Generation >= r.Generation {
+				t.Errorf("Record(%d) in generation %d has parent %d from generation %d", id, r.Generation, p, parent.Generation)
+			}
+		}
+		if strings.HasPrefix(r.Operator, "Clone") && len(r.Parents) != 1 || strings.HasPrefix(r.Operator, "MultiPoint") && len(r.Parents) != 2 {
+			t.Errorf("Record(%d) from %s has parents %v", id, r.Operator, r.Parents)
+		}
+	}
+	for i, c := range pop.Chromosomes {
+		r := lineage.Record(lineage.ID(i))
+		if diff := cmp.Diff(c.Genes, r.Genes); diff != "" || r.Fitness != pop.Fitness[i] {
+			t.Errorf("Record(ID(%d)) does not describe the member; -want +got:\n%s", i, diff)
+		}
+	}
+
+	best := 0
+	for i := range pop.Fitness {
+		if pop.Fitness[i] > pop.Fitness[best] {
+			best = i
+		}
+	}
+	id := lineage.ID(best)
+	ancestors := lineage.Ancestors(id)
+	if last := ancestors[len(ancestors)-1]; last.ID != id {
+		t.Errorf("Ancestors(%d) ends with %d; want itself", id, last.ID)
+	}
+	edges := 0
+	for _, r := range ancestors {
+		edges += len(r.Parents)
+	}
+	var buf bytes.Buffer
+	if err := lineage.WriteDOT(&buf, id); err != nil {
+		t.Fatalf("WriteDOT(); err=%s", err)
+	}
+	dot := buf.String()
+	if !strings.HasPrefix(dot, "digraph lineage {") || strings.Count(dot, " -> ") != edges ||
+		strings.Count(dot, "[label=") != len(ancestors) || strings.Count(dot, "style=bold") != 1 {
+		t.Errorf("WriteDOT(%d) wrote %d ancestors and %d edges as:\n%s", id, len(ancestors), edges, dot)
+	}
+	if err := lineage.WriteDOT(&buf, lineage.Len()); err == nil {
+		t.Error("WriteDOT() should reject unknown IDs")
+	}
+}
+
+func TestLineageReplace(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	pop := newBinaryPopulation(t, rng, 20, 10)
+	lineage := &genetics.Lineage{}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 2,
+			MutationRate:     1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Mutator:          genetics.RandomResettingMutation{},
+		},
+		Evaluator: genetics.FitnessFunc(oneMax),
+		Lineage:   lineage,
+	}
+	engine.Reset(pop)
+	engine.Replace(3, pop.Species.New(make([]genetics.Gene, 20)...), "Custom")
+	if err := engine.Step(rng); err != nil {
+		t.Fatalf("Step(); err=%s", err)
+	}
+	r := lineage.Record(10)
+	if r.Operator != "Custom" || len(r.Parents) != 0 || r.Fitness != 0 || !r.Evaluated {
+		t.Errorf("Record(10)=%+v; want the evaluated replacement", r)
+	}
+	if lineage.Record(3).Evaluated {
+		t.Error("the replaced initial member should never have been evaluated")
+	}
+}
@@ -0,0 +1,205 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/inlined/rand"
+)
+
+const linkageCrossover = "LinkageCrossover"
+
+// linkageSignificance is the probability that at least one pair of independent
+// genes is mistaken for linked.
+const linkageSignificance = 0.05
+
+// PopulationLearner is implemented by operators (Selectors, Crossovers, and
+// Mutators) which model the population itself, e.g. the linkage between its
+// genes. Before each generation is evolved, an Engine calls Learn with its scored
+// population. Operators used outside of an Engine must be taught by calling Learn
+// directly.
+type PopulationLearner interface {
+	Learn(pop *Population)
+}
+
+// LinkageCrossover is MultiPointCrossover which avoids splitting building blocks.
+// Learn estimates the linkage of every pair of genes as their mutual information
+// across the population, ignoring pairs whose dependence is not significant (by a
+// G-test at the 5% level, corrected for the number of pairs), and
+// LinkageCrossover places its Points crossover points between genes with
+// probability inversely proportional to the linkage they would sever. Building
+// blocks of deceptive problems, whose genes are only fit together, are thus
+// exchanged whole. Until Learn is called, points are placed uniformly.
+//
+// Learn takes O(NumGenes^2 * population size) time, and Points should be small
+// relative to the number of weakly linked boundaries. The learned linkage is that
+// of one population, so Islands must not share an instance.
+type LinkageCrossover struct {
+	Points int
+
+	mu sync.RWMutex
+	// cumulative[k-1] is the sum of the weights of boundaries 1..k, where
+	// boundary k precedes gene k.
+	cumulative []float64
+}
+
+// NewLinkageCrossover initializes a LinkageCrossover with points crossover points.
+func NewLinkageCrossover(points int) *LinkageCrossover {
+	return &LinkageCrossover{Points: points}
+}
+
+func (c *LinkageCrossover) String() string {
+	return fmt.Sprintf("%s(%d)", linkageCrossover, c.Points)
+}
+
+// ConcurrentSafe implements ConcurrentSafe. Learn models one population, which
+// another population's Learn would replace.
+func (c *LinkageCrossover) ConcurrentSafe() bool {
+	return false
+}
+
+// Learn implements PopulationLearner
+func (c *LinkageCrossover) Learn(pop *Population) {
+	cut := linkageCuts(pop.Chromosomes)
+	mean := 0.0
+	for _, l := range cut {
+		mean += l
+	}
+	if len(cut) != 0 {
+		mean /= float64(len(cut))
+	}
+	// Unlinked boundaries are a thousand times likelier than those of average linkage
+	eps := 1e-3 * mean
+	if eps == 0 {
+		eps = 1
+	}
+	cumulative := make([]float64, len(cut))
+	total := 0.0
+	for k, l := range cut {
+		total += 1 / (eps + l)
+		cumulative[k] = total
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cumulative = cumulative
+}
+
+// linkageCuts returns, for each boundary k in [1, NumGenes), the total mutual
+// information of the significantly linked pairs of genes it separates.
+func linkageCuts(pop []Chromosome) []float64 {
+	if len(pop) == 0 || len(pop[0].Genes) < 2 {
+		return nil
+	}
+	n, size := len(pop[0].Genes), len(pop)
+	// alleles[i][m] is the dense index of the allele of gene i of member m
+	alleles := make([][]int, n)
+	counts := make([][]int, n)
+	for i := range alleles {
+		alleles[i] = make([]int, size)
+		index := map[Gene]int{}
+		for m, c := range pop {
+			k, ok := index[c.Genes[i]]
+			if !ok {
+				k = len(index)
+				index[c.Genes[i]] = k
+				counts[i] = append(counts[i], 0)
+			}
+			alleles[i][m] = k
+			counts[i][k]++
+		}
+	}
+	// critical is the G statistic per degree of freedom which a pair must exceed;
+	// it is exact for one degree of freedom and conservative for more.
+	pairs := float64(n * (n - 1) / 2)
+	critical := 2 * math.Pow(math.Erfinv(1-linkageSignificance/pairs), 2)
+	var joint []int
+	mi := func(i, j int) float64 {
+		ki, kj := len(counts[i]), len(counts[j])
+		if ki < 2 || kj < 2 {
+			return 0
+		}
+		if cap(joint) < ki*kj {
+			joint = make([]int, ki*kj)
+		}
+		joint = joint[:ki*kj]
+		for k := range joint {
+			joint[k] = 0
+		}
+		for m := 0; m < size; m++ {
+			joint[alleles[i][m]*kj+alleles[j][m]]++
+		}
+		total, nf := 0.0, float64(size)
+		for a, ca := range counts[i] {
+			for b, cb := range counts[j] {
+				if cab := joint[a*kj+b]; cab != 0 {
+					total += float64(cab) / nf * math.Log(float64(cab)*nf/float64(ca*cb))
+				}
+			}
+		}
+		if 2*nf*total < critical*float64((ki-1)*(kj-1)) {
+			return 0
+		}
+		return total
+	}
+
+	// The pair (i, j) is separated by boundaries i+1 through j, so its linkage is
+	// added to a difference array at i+1 and removed after j.
+	diff := make([]float64, n+1)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if l := mi(i, j); l != 0 {
+				diff[i+1] += l
+				diff[j+1] -= l
+			}
+		}
+	}
+	cut := make([]float64, n-1)
+	sum := 0.0
+	for k := 1; k < n; k++ {
+		sum += diff[k]
+		cut[k-1] = math.Max(sum, 0)
+	}
+	return cut
+}
+
+// Crossover implements Crossover
+func (c *LinkageCrossover) Crossover(r rand.Rand, a, b Chromosome) (x, y Chromosome) {
+	x, y = a.Species.New(), a.Species.New()
+	c.CrossoverInto(r, a, b, x, y)
+	return x, y
+}
+
+// CrossoverInto implements CrossoverInto
+func (c *LinkageCrossover) CrossoverInto(r rand.Rand, a, b, x, y Chromosome) {
+	n := a.Species.NumGenes
+	c.mu.RLock()
+	cumulative := c.cumulative
+	c.mu.RUnlock()
+	if len(cumulative) != n-1 || c.Points >= n-1 {
+		MultiPointCrossover{Points: c.Points}.CrossoverInto(r, a, b, x, y)
+		return
+	}
+	total := cumulative[len(cumulative)-1]
+	indexes := make([]int, 0, c.Points)
+	for len(indexes) < c.Points {
+		k := sort.SearchFloat64s(cumulative, r.Float64()*total) + 1
+		if k >= n || containsInt(indexes, k) {
+			continue
+		}
+		indexes = append(indexes, k)
+	}
+	sort.Ints(indexes)
+	crossSegments(a, b, x, y, indexes)
+}
+
+// containsInt reports whether v is in s.
+func containsInt(s []int, v int) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+// newBlockPopulation returns a population whose genes are linked in blocks of
+// blockSize: each block is all 0s or all 1s.
+func newBlockPopulation(rng rand.Rand, numGenes, blockSize, size int) *genetics.Population {
+	s := genetics.NewSpecies(numGenes, 1)
+	pop := &genetics.Population{}
+	for m := 0; m < size; m++ {
+		c := s.New()
+		for i := 0; i < numGenes; i += blockSize {
+			g := genetics.Gene(rng.Int31n(2))
+			for j := i; j < i+blockSize; j++ {
+				c.Genes[j] = g
+			}
+		}
+		pop.Chromosomes = append(pop.Chromosomes, c)
+	}
+	return pop
+}
+
+// intactBlocks counts the blocks of c whose genes are all equal.
+func intactBlocks(c genetics.Chromosome, blockSize int) int {
+	n := 0
+	for i := 0; i < len(c.Genes); i += blockSize {
+		intact := true
+		for j := i; j < i+blockSize; j++ {
+			intact = intact && c.Genes[j] == c.Genes[i]
+		}
+		if intact {
+			n++
+		}
+	}
+	return n
+}
+
+func TestLinkageCrossover(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	pop := newBlockPopulation(rng, 40, 4, 100)
+	c := genetics.NewLinkageCrossover(2)
+	if c.String() != "LinkageCrossover(2)" {
+		t.Errorf("String()=%s; want LinkageCrossover(2)", c.String())
+	}
+
+	// measure returns the fraction of blocks which survive crossover intact.
+	measure := func(c genetics.Crossover) float64 {
+		intact, total := 0, 0
+		for i := 0; i < 500; i++ {
+			a, b := pop.Chromosomes[rng.Int31n(100)], pop.Chromosomes[rng.Int31n(100)]
+			x, y := c.Crossover(rng, a, b)
+			intact += intactBlocks(x, 4) + intactBlocks(y, 4)
+			total += 20
+		}
+		return float64(intact) / float64(total)
+	}
+	untrained, baseline := measure(c), measure(genetics.MultiPointCrossover{Points: 2})
+	c.Learn(pop)
+	if got := measure(c); got < 0.99 || got <= baseline {
+		t.Errorf("LinkageCrossover kept %g of blocks intact; want at least 0.99 (untrained %g, MultiPointCrossover %g)", got, untrained, baseline)
+	}
+
+	// Unlinked genes are crossed anywhere
+	unlinked := newBlockPopulation(rng, 40, 1, 100)
+	c.Learn(unlinked)
+	points := map[int]bool{}
+	for i := 0; i < 500; i++ {
+		a, b := unlinked.Chromosomes[0].Species.New(), unlinked.Chromosomes[0].Species.New()
+		for j := range b.Genes {
+			b.Genes[j] = 1
+		}
+		x, _ := c.Crossover(rng, a, b)
+		for j := 1; j < len(x.Genes); j++ {
+			if x.Genes[j] != x.Genes[j-1] {
+				points[j] = true
+			}
+		}
+	}
+	if len(points) < 35 {
+		t.Errorf("LinkageCrossover of unlinked genes only crossed at %d of 39 boundaries", len(points))
+	}
+}
+
+// learningCrossover counts the populations it learns from.
+type learningCrossover struct {
+	genetics.MultiPointCrossover
+	sizes *[]int
+}
+
+func (c learningCrossover) Learn(pop *genetics.Population) {
+	*c.sizes = append(*c.sizes, pop.Len())
+}
+
+func TestEnginePopulationLearner(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	var sizes []int
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 4,
+			CrossoverRate:    1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        learningCrossover{genetics.MultiPointCrossover{Points: 1}, &sizes},
+			Mutator:          genetics.SwapMutation{},
+		},
+		Evaluator: genetics.FitnessFunc(oneMax),
+	}
+	if err := engine.Run(rng, newBinaryPopulation(t, rng, 8, 10), 3); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if len(sizes) != 3 || sizes[0] != 10 {
+		t.Errorf("Engine taught its Crossover populations of sizes %v; want 3 of 10", sizes)
+	}
+}
@@ -0,0 +1,110 @@
+package genetics
+
+import (
+	"fmt"
+
+	"github.com/inlined/rand"
+)
+
+const (
+	hillClimbing = "HillClimbing"
+	twoOpt       = "TwoOpt"
+)
+
+// LocalSearch refines a single Chromosome, turning a genetic algorithm into a
+// memetic algorithm. The Engine runs a LocalSearch on each child after it is
+// evaluated, sharing the Engine's Evaluator.
+type LocalSearch interface {
+	fmt.Stringer
+	// Improve modifies c, whose current score is fitness, spending at most budget
+	// evaluations. It returns the fitness of the modified c. Improve maximizes:
+	// when a Population is minimized, the Engine passes scores which increase
+	// with fitness, as for Evolver.Evolve, and an eval which returns them.
+	Improve(r rand.Rand, c *Chromosome, fitness Fitness, eval Evaluator, budget int) (Fitness, error)
+}
+
+// scoredEvaluator adapts an Evaluator to return scores which increase with
+// fitness under objective, so that a LocalSearch can improve minimized fitness.
+type scoredEvaluator struct {
+	Evaluator
+	objective Objective
+}
+
+// Evaluate implements Evaluator
+func (s scoredEvaluator) Evaluate(c Chromosome) (Fitness, error) {
+	f, err := s.Evaluator.Evaluate(c)
+	return s.objective.score(f), err
+}
+
+// HillClimbing is a stochastic hill climber which repeatedly applies Mutator and
+// keeps any mutation which improves fitness. It suits any encoding for which
+// Mutator is appropriate, e.g. knapsack problems with RandomResettingMutation.
+type HillClimbing struct {
+	Mutator Mutator
+}
+
+func (h HillClimbing) String() string {
+	return fmt.Sprintf("%s(%s)", hillClimbing, h.Mutator)
+}
+
+// WithProblem implements ContextualOperator
+func (h HillClimbing) WithProblem(problem interface{}) (interface{}, error) {
+	var err error
+	h.Mutator, err = mutatorWithProblem(h.Mutator, problem)
+	return h, err
+}
+
+// Improve implements LocalSearch
+func (h HillClimbing) Improve(r rand.Rand, c *Chromosome, fitness Fitness, eval Evaluator, budget int) (Fitness, error) {
+	for ; budget > 0; budget-- {
+		candidate := c.Clone()
+		h.Mutator.Mutate(r, &candidate)
+		f, err := eval.Evaluate(candidate)
+		if err != nil {
+			return fitness, err
+		}
+		if f > fitness {
+			*c, fitness = candidate, f
+		}
+	}
+	return fitness, nil
+}
+
+// TwoOpt is the classic 2-opt search for routing problems: it reverses each
+// segment of a permutation-encoded Chromosome in turn, keeping any reversal which
+// improves fitness, until no reversal helps or the budget is spent.
+type TwoOpt struct{}
+
+func (TwoOpt) String() string {
+	return twoOpt
+}
+
+// Improve implements LocalSearch
+func (TwoOpt) Improve(r rand.Rand, c *Chromosome, fitness Fitness, eval Evaluator, budget int) (Fitness, error) {
+	n := len(c.Genes)
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < n-1; i++ {
+			for j := i + 1; j < n; j++ {
+				if budget == 0 {
+					return fitness, nil
+				}
+				budget--
+
+				candidate := c.Clone()
+				for l, u := i, j; l < u; l, u = l+1, u-1 {
+					candidate.Genes[l], candidate.Genes[u] = candidate.Genes[u], candidate.Genes[l]
+				}
+				f, err := eval.Evaluate(candidate)
+				if err != nil {
+					return fitness, err
+				}
+				if f > fitness {
+					*c, fitness = candidate, f
+					improved = true
+				}
+			}
+		}
+	}
+	return fitness, nil
+}
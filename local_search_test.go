@@ -0,0 +1,162 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+// countingEvaluator scores chromosomes with a fitness function and counts evaluations.
+type countingEvaluator struct {
+	fn    genetics.FitnessFunc
+	count int
+}
+
+func (e *countingEvaluator) Evaluate(c genetics.Chromosome) (genetics.Fitness, error) {
+	e.count++
+	return e.fn(c), nil
+}
+
+func TestHillClimbing(t *testing.T) {
+	s := genetics.NewSpecies(10, 1)
+	c := s.New()
+	eval := &countingEvaluator{fn: oneMax}
+	search := genetics.HillClimbing{Mutator: genetics.RandomResettingMutation{}}
+	f, err := search.Improve(rand.New(), &c, 0, eval, 50)
+	if err != nil {
+		t.Fatalf("Improve(); err=%s", err)
+	}
+	if eval.count != 50 {
+		t.Errorf("Improve() spent %d evaluations; want the budget of 50", eval.count)
+	}
+	if f != oneMax(c) {
+		t.Errorf("Improve() returned fitness %g for a chromosome scoring %g", f, oneMax(c))
+	}
+}
+
+func TestTwoOpt(t *testing.T) {
+	// Scores a tour of cities on a line; the best tour visits them in order.
+	lineTour := func(c genetics.Chromosome) genetics.Fitness {
+		f := genetics.Fitness(0)
+		for i := 1; i < len(c.Genes); i++ {
+			d := c.Genes[i] - c.Genes[i-1]
+			if d < 0 {
+				d = -d
+			}
+			f -= genetics.Fitness(d)
+		}
+		return f
+	}
+	s := genetics.NewSpecies(6, 5)
+	c := s.New(0, 1, 4, 3, 2, 5)
+	eval := &countingEvaluator{fn: lineTour}
+	f, err := genetics.TwoOpt{}.Improve(rand.New(), &c, lineTour(c), eval, 100)
+	if err != nil {
+		t.Fatalf("Improve(); err=%s", err)
+	}
+	if diff := cmp.Diff([]genetics.Gene{0, 1, 2, 3, 4, 5}, c.Genes); diff != "" {
+		t.Errorf("TwoOpt did not untangle the tour; got=%v diff=%s", c.Genes, diff)
+	}
+	if f != -5 {
+		t.Errorf("Improve() returned fitness %g; want -5", f)
+	}
+
+	c = s.New(5, 4, 3, 2, 1, 0)
+	eval.count = 0
+	if _, err := (genetics.TwoOpt{}).Improve(rand.New(), &c, lineTour(c), eval, 3); err != nil {
+		t.Fatalf("Improve(); err=%s", err)
+	}
+	if eval.count != 3 {
+		t.Errorf("Improve() spent %d evaluations; want the budget of 3", eval.count)
+	}
+}
+
+func TestEngineLocalSearch(t *testing.T) {
+	rng := rand.New()
+	eval := &countingEvaluator{fn: oneMax}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 4,
+			CrossoverRate:    1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator:          genetics.SwapMutation{},
+		},
+		Evaluator:         eval,
+		LocalSearch:       genetics.HillClimbing{Mutator: genetics.RandomResettingMutation{}},
+		LocalSearchBudget: 5,
+	}
+	pop := newBinaryPopulation(t, rng, 8, 8)
+	if err := engine.Run(rng, pop, 3); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if want := 8 + 3*4*(1+5); eval.count != want {
+		t.Errorf("Run() made %d evaluations; want %d", eval.count, want)
+	}
+	for i, c := range pop.Chromosomes {
+		if pop.Fitness[i] != oneMax(c) {
+			t.Errorf("chromosome %d scored %g after local search; want %g", i, pop.Fitness[i], oneMax(c))
+		}
+	}
+}
+
+// onesSearch records the number of ones in each Chromosome before and after an
+// inner LocalSearch improves it.
+type onesSearch struct {
+	genetics.LocalSearch
+	before, after *[]genetics.Fitness
+}
+
+func (s onesSearch) Improve(r rand.Rand, c *genetics.Chromosome, fitness genetics.Fitness, eval genetics.Evaluator, budget int) (genetics.Fitness, error) {
+	*s.before = append(*s.before, oneMax(*c))
+	f, err := s.LocalSearch.Improve(r, c, fitness, eval, budget)
+	*s.after = append(*s.after, oneMax(*c))
+	return f, err
+}
+
+func TestEngineLocalSearchMinimize(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	var before, after []genetics.Fitness
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 4,
+			CrossoverRate:    1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator:          genetics.SwapMutation{},
+		},
+		Evaluator: genetics.FitnessFunc(oneMax),
+		LocalSearch: onesSearch{
+			LocalSearch: genetics.HillClimbing{Mutator: genetics.RandomResettingMutation{}},
+			before:      &before,
+			after:       &after,
+		},
+		LocalSearchBudget: 5,
+	}
+	pop := newBinaryPopulation(t, rng, 8, 8)
+	pop.Objective = genetics.Minimize
+	if err := engine.Run(rng, pop, 3); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	improved := 0
+	for i := range before {
+		if after[i] > before[i] {
+			t.Fatalf("LocalSearch raised a minimized fitness from %g to %g", before[i], after[i])
+		}
+		if after[i] < before[i] {
+			improved++
+		}
+	}
+	if improved == 0 {
+		t.Error("LocalSearch never lowered a minimized fitness")
+	}
+	for i, c := range pop.Chromosomes {
+		if pop.Fitness[i] != oneMax(c) {
+			t.Errorf("chromosome %d scored %g after local search; want %g", i, pop.Fitness[i], oneMax(c))
+		}
+	}
+}
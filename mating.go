@@ -0,0 +1,74 @@
+package genetics
+
+import "fmt"
+
+// MatingRestriction prevents incest: chromosomes whose genotypes are closer than
+// Threshold may not be recombined, which slows the loss of diversity that lets a
+// population converge prematurely (Eshelman and Schaffer, 1991). Set
+// Evolver.MatingRestriction to enable it.
+//
+// A mating pair which is too close is first re-paired with another selected
+// parent, so that each parent keeps the number of times it was selected. If no
+// such parent exists, the pair reproduces without crossover: its children are
+// clones, which may still be mutated.
+type MatingRestriction struct {
+	// Distance defaults to HammingDistance.
+	Distance DistanceFunc
+	// Threshold is the least distance at which two chromosomes may mate. It must
+	// be positive.
+	Threshold float64
+}
+
+// restrict re-pairs the consecutive mating pairs of indexes into pop which are
+// too close to mate and returns whether each pair, by its first index, may mate.
+func (m *MatingRestriction) restrict(pop []Chromosome, indexes []int) []bool {
+	distance := m.Distance
+	if distance == nil {
+		distance = HammingDistance
+	}
+	unmated := repairPairs(indexes, func(a, b int) bool {
+		return distance(pop[a], pop[b]) >= m.Threshold
+	})
+	mate := make([]bool, len(indexes))
+	for i := 0; i+1 < len(indexes); i += 2 {
+		mate[i] = true
+	}
+	for _, i := range unmated {
+		mate[i] = false
+	}
+	return mate
+}
+
+// validate reports whether m can restrict mating.
+func (m *MatingRestriction) validate() error {
+	if m.Threshold <= 0 {
+		return fmt.Errorf("MatingRestriction; Threshold %g must be positive", m.Threshold)
+	}
+	return nil
+}
+
+// repairPairs swaps parents between the consecutive mating pairs of indexes so
+// that as many pairs as possible satisfy allowed, and returns the first index of
+// each pair which still does not.
+func repairPairs(indexes []int, allowed func(a, b int) bool) (unpaired []int) {
+	for i := 0; i+1 < len(indexes); i += 2 {
+		if allowed(indexes[i], indexes[i+1]) {
+			continue
+		}
+		repaired := false
+		for j := range indexes {
+			// Swap indexes[i+1] with indexes[j] if both pairs are allowed afterwards.
+			if j/2 == i/2 || j^1 >= len(indexes) ||
+				!allowed(indexes[i], indexes[j]) || !allowed(indexes[j^1], indexes[i+1]) {
+				continue
+			}
+			indexes[i+1], indexes[j] = indexes[j], indexes[i+1]
+			repaired = true
+			break
+		}
+		if !repaired {
+			unpaired = append(unpaired, i)
+		}
+	}
+	return unpaired
+}
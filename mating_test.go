@@ -0,0 +1,97 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestMatingRestriction(t *testing.T) {
+	s := genetics.NewSpecies(4, 1)
+	for _, test := range []struct {
+		tag      string
+		pop      []genetics.Chromosome
+		selected fixedSelection
+		pairs    int
+	}{
+		{
+			tag:      "re-paired",
+			pop:      []genetics.Chromosome{s.New(0, 0, 0, 0), s.New(0, 0, 0, 0), s.New(1, 1, 0, 0), s.New(1, 1, 1, 1)},
+			selected: fixedSelection{0, 1, 2, 3},
+			pairs:    2,
+		}, {
+			tag:      "too similar to mate",
+			pop:      []genetics.Chromosome{s.New(0, 0, 0, 0), s.New(0, 0, 0, 1), s.New(0, 0, 0, 0), s.New(0, 0, 0, 1)},
+			selected: fixedSelection{0, 1, 2, 3},
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			rng := rand.New()
+			rng.Seed(42)
+			for run := 0; run < 10; run++ {
+				pop := make([]genetics.Chromosome, len(test.pop))
+				for i, c := range test.pop {
+					pop[i] = s.New(c.Genes...)
+				}
+				var pairs [][2]genetics.Gene
+				evolver := genetics.Evolver{
+					ReplacementCount:  len(test.selected),
+					CrossoverRate:     1,
+					Selector:          test.selected,
+					Crossover:         pairRecorder{&pairs},
+					MatingRestriction: &genetics.MatingRestriction{Threshold: 2},
+				}
+				evolver.Evolve(rng, pop, make([]genetics.Fitness, len(pop)))
+				if len(pairs) != test.pairs {
+					t.Fatalf("Evolve() recombined %d pairs; want %d", len(pairs), test.pairs)
+				}
+			}
+		})
+	}
+}
+
+// meanDistance is the mean Hamming distance between the members of pop.
+func meanDistance(pop *genetics.Population) float64 {
+	total, pairs := 0.0, 0
+	for i := range pop.Chromosomes {
+		for j := i + 1; j < len(pop.Chromosomes); j++ {
+			total += genetics.HammingDistance(pop.Chromosomes[i], pop.Chromosomes[j])
+			pairs++
+		}
+	}
+	return total / float64(pairs)
+}
+
+func TestMatingRestrictionRetainsDiversity(t *testing.T) {
+	diversity := func(restriction *genetics.MatingRestriction) float64 {
+		rng := rand.New()
+		rng.Seed(42)
+		total := 0.0
+		for run := 0; run < 10; run++ {
+			pop := newBinaryPopulation(t, rng, 40, 20)
+			engine := genetics.Engine{
+				Evolver: genetics.Evolver{
+					ReplacementCount:  10,
+					CrossoverRate:     1,
+					MutationRate:      0.05,
+					Selector:          genetics.TournamentSelection{Size: 3},
+					Crossover:         genetics.MultiPointCrossover{Points: 2},
+					Mutator:           genetics.RandomResettingMutation{},
+					MatingRestriction: restriction,
+				},
+				Evaluator: genetics.FitnessFunc(oneMax),
+			}
+			if err := engine.Run(rng, pop, 30); err != nil {
+				t.Fatalf("Run(); err=%s", err)
+			}
+			total += meanDistance(pop)
+		}
+		return total / 10
+	}
+	restricted, unrestricted := diversity(&genetics.MatingRestriction{Threshold: 6}), diversity(nil)
+	if restricted <= unrestricted {
+		t.Errorf("mean distance with MatingRestriction=%g; want more than %g without", restricted, unrestricted)
+	}
+}
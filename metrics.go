@@ -0,0 +1,125 @@
+package genetics
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// statsColumns are the headers of the columns written by a StatsWriter, in order.
+var statsColumns = []string{
+	"generation", "best", "worst", "mean", "evaluations", "failures",
+	"offspring", "improvements", "cache_lookups", "cache_hits",
+}
+
+// jsonStats is the JSON lines encoding of Stats; its keys match statsColumns.
+type jsonStats struct {
+	Generation   int     `json:"generation"`
+	Best         Fitness `json:"best"`
+	Worst        Fitness `json:"worst"`
+	Mean         float64 `json:"mean"`
+	Evaluations  int     `json:"evaluations"`
+	Failures     int     `json:"failures"`
+	Offspring    int     `json:"offspring"`
+	Improvements int     `json:"improvements"`
+	CacheLookups int     `json:"cache_lookups"`
+	CacheHits    int     `json:"cache_hits"`
+	// Operators and Loci are only written as JSON.
+	Operators map[string]OperatorStats `json:"operators,omitempty"`
+	Loci      []LocusStats             `json:"loci,omitempty"`
+}
+
+// StatsWriter streams the Stats of each generation to an io.Writer as CSV, TSV, or
+// JSON lines so that runs can be plotted in external tools. It is both an Observer,
+// which flushes when an Engine's run terminates, and a StatsObserver for other
+// drivers, whose callers should call Flush when the run is done. Writing stops at
+// the first error, which is reported by Flush.
+type StatsWriter struct {
+	NopObserver
+	csv  *csv.Writer
+	buf  *bufio.Writer
+	json *json.Encoder
+	row  []string
+	err  error
+}
+
+// NewCSVStatsWriter writes Stats to w as CSV with a header row.
+func NewCSVStatsWriter(w io.Writer) *StatsWriter {
+	return &StatsWriter{csv: csv.NewWriter(w)}
+}
+
+// NewTSVStatsWriter writes Stats to w as tab-separated values with a header row.
+func NewTSVStatsWriter(w io.Writer) *StatsWriter {
+	out := csv.NewWriter(w)
+	out.Comma = '\t'
+	return &StatsWriter{csv: out}
+}
+
+// NewJSONStatsWriter writes Stats to w as JSON lines, one object per generation.
+func NewJSONStatsWriter(w io.Writer) *StatsWriter {
+	buf := bufio.NewWriter(w)
+	return &StatsWriter{buf: buf, json: json.NewEncoder(buf)}
+}
+
+// OnStats implements StatsObserver
+func (w *StatsWriter) OnStats(s Stats) {
+	if w.err != nil {
+		return
+	}
+	if w.json != nil {
+		w.err = w.json.Encode(jsonStats{
+			Generation:   s.Generation,
+			Best:         s.Best,
+			Worst:        s.Worst,
+			Mean:         s.Mean,
+			Evaluations:  s.Evaluations,
+			Failures:     s.Failures,
+			Offspring:    s.Offspring,
+			Improvements: s.Improvements,
+			CacheLookups: s.CacheLookups,
+			CacheHits:    s.CacheHits,
+			Operators:    s.Operators,
+			Loci:         s.Loci,
+		})
+		return
+	}
+	if w.row == nil {
+		w.row = make([]string, len(statsColumns))
+		if w.err = w.csv.Write(statsColumns); w.err != nil {
+			return
+		}
+	}
+	w.row[0] = strconv.Itoa(s.Generation)
+	w.row[1] = strconv.FormatFloat(float64(s.Best), 'g', -1, 64)
+	w.row[2] = strconv.FormatFloat(float64(s.Worst), 'g', -1, 64)
+	w.row[3] = strconv.FormatFloat(s.Mean, 'g', -1, 64)
+	w.row[4] = strconv.Itoa(s.Evaluations)
+	w.row[5] = strconv.Itoa(s.Failures)
+	w.row[6] = strconv.Itoa(s.Offspring)
+	w.row[7] = strconv.Itoa(s.Improvements)
+	w.row[8] = strconv.Itoa(s.CacheLookups)
+	w.row[9] = strconv.Itoa(s.CacheHits)
+	w.err = w.csv.Write(w.row)
+}
+
+// OnTermination implements Observer
+func (w *StatsWriter) OnTermination(e *Engine, err error) {
+	w.Flush()
+}
+
+// Flush writes any buffered Stats and returns the first error encountered.
+func (w *StatsWriter) Flush() error {
+	if w.json != nil {
+		if err := w.buf.Flush(); w.err == nil {
+			w.err = err
+		}
+		return w.err
+	}
+	w.csv.Flush()
+	if err := w.csv.Error(); w.err == nil {
+		w.err = err
+	}
+	return w.err
+}
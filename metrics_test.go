@@ -0,0 +1,137 @@
+package genetics_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+const statsHeader = "generation,best,worst,mean,evaluations,failures,offspring,improvements,cache_lookups,cache_hits"
+
+func TestStatsWriter(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		new    func(buf *bytes.Buffer) *genetics.StatsWriter
+		header string
+	}{
+		{name: "CSV", new: func(buf *bytes.Buffer) *genetics.StatsWriter { return genetics.NewCSVStatsWriter(buf) }, header: statsHeader},
+		{name: "TSV", new: func(buf *bytes.Buffer) *genetics.StatsWriter { return genetics.NewTSVStatsWriter(buf) }, header: strings.ReplaceAll(statsHeader, ",", "\t")},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			rng := rand.New()
+			rng.Seed(42)
+			var buf bytes.Buffer
+			recorder := &statsObserver{}
+			engine := genetics.Engine{
+				Evolver: genetics.Evolver{
+					ReplacementCount: 4,
+					CrossoverRate:    1,
+					Selector:         genetics.TournamentSelection{Size: 2},
+					Crossover:        genetics.MultiPointCrossover{Points: 1},
+					Mutator:          genetics.SwapMutation{},
+				},
+				Evaluator: genetics.FitnessFunc(oneMax),
+				Observers: []genetics.Observer{test.new(&buf), recorder},
+			}
+			if err := engine.Run(rng, newBinaryPopulation(t, rng, 8, 10), 3); err != nil {
+				t.Fatalf("Run(); err=%s", err)
+			}
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(lines) != 5 || lines[0] != test.header {
+				t.Fatalf("StatsWriter wrote:\n%s", buf.String())
+			}
+			s := recorder.stats[3]
+			sep := test.header[len("generation")]
+			want := strings.Join([]string{
+				"3",
+				strconv.FormatInt(int64(s.Best), 10),
+				strconv.FormatInt(int64(s.Worst), 10),
+				strconv.FormatFloat(s.Mean, 'g', -1, 64),
+				"4", "0", "4",
+				strconv.Itoa(s.Improvements),
+				"0", "0",
+			}, string(sep))
+			if lines[4] != want {
+				t.Errorf("StatsWriter wrote %q for the final generation; want %q", lines[4], want)
+			}
+		})
+	}
+}
+
+func TestJSONStatsWriter(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	var buf bytes.Buffer
+	w := genetics.NewJSONStatsWriter(&buf)
+	recorder := &statsRecorder{}
+	de := &genetics.DifferentialEvolution{
+		Species:   genetics.NewUniformRealSpecies(2, -5, 5),
+		Evaluator: genetics.RealFitnessFunc(sphere),
+		Size:      10,
+		Observers: []genetics.StatsObserver{w, recorder},
+	}
+	if _, _, err := de.Run(rng, 5); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if buf.Len() != 0 {
+		t.Error("NewJSONStatsWriter() should buffer until Flush")
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush(); err=%s", err)
+	}
+
+	var got []genetics.Stats
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var line struct {
+			Generation   int                               `json:"generation"`
+			Best         genetics.Fitness                  `json:"best"`
+			Worst        genetics.Fitness                  `json:"worst"`
+			Mean         float64                           `json:"mean"`
+			Evaluations  int                               `json:"evaluations"`
+			Offspring    int                               `json:"offspring"`
+			Improvements int                               `json:"improvements"`
+			Operators    map[string]genetics.OperatorStats `json:"operators"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("line %q is not JSON: %s", scanner.Text(), err)
+		}
+		got = append(got, genetics.Stats{
+			Generation:   line.Generation,
+			Best:         line.Best,
+			Worst:        line.Worst,
+			Mean:         line.Mean,
+			Evaluations:  line.Evaluations,
+			Offspring:    line.Offspring,
+			Improvements: line.Improvements,
+			Operators:    line.Operators,
+		})
+	}
+	if diff := cmp.Diff(recorder.stats, got); diff != "" {
+		t.Errorf("JSON lines differ from the Stats; -want +got:\n%s", diff)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestStatsWriterError(t *testing.T) {
+	w := genetics.NewCSVStatsWriter(failingWriter{})
+	w.OnStats(genetics.Stats{})
+	w.OnStats(genetics.Stats{Generation: 1})
+	if err := w.Flush(); err == nil || err.Error() != "disk full" {
+		t.Errorf("Flush()=%v; want disk full", err)
+	}
+}
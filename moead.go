@@ -0,0 +1,336 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/inlined/rand"
+)
+
+// Decomposition chooses how MOEAD scalarizes the Objectives of a subproblem.
+type Decomposition int
+
+const (
+	// Tchebycheff minimizes the largest weighted shortfall of any objective from
+	// the best value seen for it. It reaches every point of the front, including
+	// those on concave regions, and is the usual default.
+	Tchebycheff Decomposition = iota
+	// WeightedSum maximizes the weighted sum of the objectives. It is cheaper to
+	// reason about but only reaches the convex regions of the front.
+	WeightedSum
+)
+
+func (d Decomposition) String() string {
+	switch d {
+	case Tchebycheff:
+		return "Tchebycheff"
+	case WeightedSum:
+		return "WeightedSum"
+	}
+	return fmt.Sprintf("Decomposition(%d)", int(d))
+}
+
+// MOEAD is the multi-objective evolutionary algorithm based on decomposition of
+// Zhang and Li. The multi-objective problem is split into single-objective
+// subproblems, one per member of the population, by weight vectors spread evenly
+// over the simplex, and each subproblem is evolved by mating only with the
+// subproblems of the nearest weights. Because it compares scalars rather than
+// sorting by dominance, MOEA/D scales to many more objectives than
+// dominance-based algorithms. Like Engine, a MOEAD is not goroutine safe.
+type MOEAD struct {
+	Species   *Species
+	Evaluator MultiEvaluator
+	// NumObjectives is the number of objectives Evaluator scores; at least 2.
+	NumObjectives int
+	// Permutation, if set, creates the initial population with Species.NewPerm
+	// rather than Species.NewRand.
+	Permutation bool
+
+	// Divisions is the number of steps into which each weight's range of [0, 1] is
+	// divided. The population has a member for every weight vector whose
+	// components are multiples of 1/Divisions summing to 1. Defaults to the fewest
+	// which give a population of at least 100.
+	Divisions     int
+	Decomposition Decomposition
+	// Neighbors is the number of nearest subproblems, including itself, with
+	// which a subproblem mates and shares children. Defaults to 20.
+	Neighbors int
+	// NeighborhoodRate is the probability that parents are chosen from the
+	// neighborhood rather than the whole population. Defaults to 0.9.
+	NeighborhoodRate float64
+	// MaxReplacements is the most subproblems a child may replace. Defaults to 2.
+	MaxReplacements int
+
+	// Crossover combines two parents into a child; only its first child is used.
+	Crossover Crossover
+	// Mutator, if set, mutates every child.
+	Mutator Mutator
+	// Archive, if set, is offered every Chromosome evaluated.
+	Archive *EpsilonArchive
+
+	// Terminate, if set, ends a Run early once a generation satisfies it.
+	Terminate Termination
+	// Observers are notified of the Stats of each generation. Stats summarize each
+	// subproblem's score: its negated Tchebycheff distance or its weighted sum.
+	Observers []StatsObserver
+
+	weights    [][]float64
+	neighbors  [][]int
+	pop        []Chromosome
+	objectives []Objectives
+	ideal      Objectives
+	stats      Stats
+}
+
+// Population returns the member of each subproblem and its Objectives.
+func (m *MOEAD) Population() ([]Chromosome, []Objectives) {
+	return m.pop, m.objectives
+}
+
+// Weights returns the weight vector of each subproblem.
+func (m *MOEAD) Weights() [][]float64 {
+	return m.weights
+}
+
+// Front returns the members of the population which no other member dominates.
+func (m *MOEAD) Front() ([]Chromosome, []Objectives) {
+	var cs []Chromosome
+	var objectives []Objectives
+member:
+	for i, o := range m.objectives {
+		for j, other := range m.objectives {
+			if Dominates(other, o) || (j < i && objectivesEqual(other, o)) {
+				continue member
+			}
+		}
+		cs, objectives = append(cs, m.pop[i]), append(objectives, o)
+	}
+	return cs, objectives
+}
+
+// Stats summarizes the most recent generation.
+func (m *MOEAD) Stats() Stats {
+	return m.stats
+}
+
+// Run evolves a random population for up to the given number of generations. The
+// result is read with Front or Population.
+func (m *MOEAD) Run(r rand.Rand, generations int) error {
+	switch {
+	case m.Species == nil || m.Evaluator == nil || m.Crossover == nil:
+		return fmt.Errorf("MOEAD.Run(); Species, Evaluator, and Crossover must be set")
+	case m.NumObjectives < 2:
+		return fmt.Errorf("MOEAD.Run(); NumObjectives %d must be at least 2", m.NumObjectives)
+	case m.Divisions < 0 || m.Neighbors < 0 || m.MaxReplacements < 0 || m.NeighborhoodRate < 0 || m.NeighborhoodRate > 1:
+		return fmt.Errorf("MOEAD.Run(); Divisions, Neighbors, and MaxReplacements must not be negative and NeighborhoodRate must be in [0, 1]")
+	}
+	divisions, neighbors, rate, replacements := m.Divisions, m.Neighbors, m.NeighborhoodRate, m.MaxReplacements
+	if divisions == 0 {
+		for divisions = 1; simplexPoints(divisions, m.NumObjectives) < 100; divisions++ {
+		}
+	}
+	if neighbors == 0 {
+		neighbors = 20
+	}
+	if rate == 0 {
+		rate = 0.9
+	}
+	if replacements == 0 {
+		replacements = 2
+	}
+	m.weights = simplexLattice(divisions, m.NumObjectives)
+	n := len(m.weights)
+	if n < 2 {
+		return fmt.Errorf("MOEAD.Run(); Divisions %d gives fewer than 2 subproblems", divisions)
+	}
+	if neighbors > n {
+		neighbors = n
+	}
+	if neighbors < 2 {
+		return fmt.Errorf("MOEAD.Run(); Neighbors %d must be at least 2", neighbors)
+	}
+	m.neighbors = nearestWeights(m.weights, neighbors)
+
+	m.pop = make([]Chromosome, n)
+	m.objectives = make([]Objectives, n)
+	m.ideal = nil
+	m.stats = Stats{}
+	for i := range m.pop {
+		var err error
+		if m.Permutation {
+			m.pop[i], err = m.Species.NewPerm(r)
+		} else {
+			m.pop[i], err = m.Species.NewRand(r)
+		}
+		if err != nil {
+			return fmt.Errorf("MOEAD.Run(); err=%s", err)
+		}
+		if m.objectives[i], err = m.evaluate(m.pop[i]); err != nil {
+			return fmt.Errorf("MOEAD.Run(); cannot evaluate initial member %d: %s", i, err)
+		}
+	}
+
+	all := make([]int, n)
+	for i := range all {
+		all[i] = i
+	}
+	scores := make([]Fitness, n)
+	for gen := 0; gen < generations; gen++ {
+		improvements := 0
+		for _, i := range r.Perm(n) {
+			pool := all
+			if r.Float64() < rate {
+				pool = m.neighbors[i]
+			}
+			a := int(r.Int31n(int32(len(pool))))
+			b := int(r.Int31n(int32(len(pool) - 1)))
+			if b >= a {
+				b++
+			}
+			child, _ := m.Crossover.Crossover(r, m.pop[pool[a]], m.pop[pool[b]])
+			if m.Mutator != nil {
+				m.Mutator.Mutate(r, &child)
+			}
+			o, err := m.evaluate(child)
+			if err != nil {
+				return fmt.Errorf("MOEAD.Run(); generation %d: %s", gen, err)
+			}
+			replaced := 0
+			for _, k := range r.Perm(len(pool)) {
+				if replaced == replacements {
+					break
+				}
+				j := pool[k]
+				if m.score(o, m.weights[j]) > m.score(m.objectives[j], m.weights[j]) {
+					m.pop[j], m.objectives[j] = child.Clone(), o
+					replaced++
+				}
+			}
+			if replaced > 0 {
+				improvements++
+			}
+		}
+
+		for i, o := range m.objectives {
+			scores[i] = m.score(o, m.weights[i])
+		}
+		m.stats = Stats{
+			Generation:   gen,
+			Evaluations:  n,
+			Offspring:    n,
+			Improvements: improvements,
+		}
+		m.stats.summarize(scores)
+		for _, o := range m.Observers {
+			o.OnStats(m.stats)
+		}
+		if m.Terminate != nil && m.Terminate(m.stats) {
+			break
+		}
+	}
+	return nil
+}
+
+// evaluate scores c, updates the ideal point, and offers c to the Archive.
+func (m *MOEAD) evaluate(c Chromosome) (Objectives, error) {
+	o, err := m.Evaluator.EvaluateObjectives(c)
+	if err != nil {
+		return nil, err
+	}
+	if len(o) != m.NumObjectives {
+		return nil, fmt.Errorf("%d objectives; want %d", len(o), m.NumObjectives)
+	}
+	if m.ideal == nil {
+		m.ideal = append(Objectives(nil), o...)
+	}
+	for i, f := range o {
+		m.ideal[i] = Fitness(math.Max(float64(m.ideal[i]), float64(f)))
+	}
+	if m.Archive != nil {
+		if _, err := m.Archive.Add(c, o); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// score is the fitness of o on the subproblem with weights w; higher is fitter.
+func (m *MOEAD) score(o Objectives, w []float64) Fitness {
+	if m.Decomposition == WeightedSum {
+		sum := 0.0
+		for i, f := range o {
+			sum += w[i] * float64(f)
+		}
+		return Fitness(sum)
+	}
+	worst := 0.0
+	for i, f := range o {
+		// A zero weight would ignore an objective entirely
+		worst = math.Max(worst, math.Max(w[i], 1e-6)*float64(m.ideal[i]-f))
+	}
+	return Fitness(-worst)
+}
+
+// simplexPoints is the number of weight vectors of k components which are
+// multiples of 1/divisions summing to 1: divisions+k-1 choose k-1.
+func simplexPoints(divisions, k int) int {
+	n := 1
+	for i := 1; i < k; i++ {
+		n = n * (divisions + i) / i
+	}
+	return n
+}
+
+// simplexLattice returns every weight vector of k components which are multiples
+// of 1/divisions summing to 1.
+func simplexLattice(divisions, k int) [][]float64 {
+	var weights [][]float64
+	w := make([]int, k)
+	var fill func(i, left int)
+	fill = func(i, left int) {
+		if i == k-1 {
+			w[i] = left
+			v := make([]float64, k)
+			for j, x := range w {
+				v[j] = float64(x) / float64(divisions)
+			}
+			weights = append(weights, v)
+			return
+		}
+		for x := 0; x <= left; x++ {
+			w[i] = x
+			fill(i+1, left-x)
+		}
+	}
+	fill(0, divisions)
+	return weights
+}
+
+// nearestWeights returns, for each weight vector, the indexes of the t nearest
+// weight vectors by Euclidean distance, itself first.
+func nearestWeights(weights [][]float64, t int) [][]int {
+	nearest := make([][]int, len(weights))
+	for i, w := range weights {
+		dist := make([]float64, len(weights))
+		order := make([]int, len(weights))
+		for j, v := range weights {
+			order[j] = j
+			for k := range w {
+				dist[j] += (w[k] - v[k]) * (w[k] - v[k])
+			}
+		}
+		sort.SliceStable(order, func(a, b int) bool { return dist[order[a]] < dist[order[b]] })
+		nearest[i] = order[:t]
+	}
+	return nearest
+}
+
+func objectivesEqual(a, b Objectives) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
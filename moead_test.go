@@ -0,0 +1,103 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+// lotz is the leading-ones, trailing-zeroes problem, whose Pareto front is every
+// Chromosome of leading 1s followed by 0s.
+func lotz(c genetics.Chromosome) genetics.Objectives {
+	ones, zeroes := 0, 0
+	for ones < len(c.Genes) && c.Genes[ones] == 1 {
+		ones++
+	}
+	for zeroes < len(c.Genes) && c.Genes[len(c.Genes)-1-zeroes] == 0 {
+		zeroes++
+	}
+	return genetics.Objectives{genetics.Fitness(ones), genetics.Fitness(zeroes)}
+}
+
+func TestMOEAD(t *testing.T) {
+	const n = 10
+	for _, d := range []genetics.Decomposition{genetics.Tchebycheff, genetics.WeightedSum} {
+		t.Run(d.String(), func(t *testing.T) {
+			rng := rand.New()
+			rng.Seed(42)
+			archive := &genetics.EpsilonArchive{Epsilon: []float64{1, 1}}
+			m := &genetics.MOEAD{
+				Species:       genetics.NewSpecies(n, 1),
+				Evaluator:     genetics.MultiFitnessFunc(lotz),
+				NumObjectives: 2,
+				Divisions:     n,
+				Decomposition: d,
+				Neighbors:     3,
+				Crossover:     genetics.MultiPointCrossover{Points: 1},
+				Mutator:       flipMutator{},
+				Archive:       archive,
+			}
+			if err := m.Run(rng, 200); err != nil {
+				t.Fatalf("MOEAD.Run(); err=%s", err)
+			}
+			if got := len(m.Weights()); got != n+1 {
+				t.Errorf("MOEAD has %d subproblems; want %d", got, n+1)
+			}
+			_, front := m.Front()
+			found := map[genetics.Fitness]bool{}
+			for _, o := range front {
+				if o[0]+o[1] != n {
+					t.Errorf("Front() includes %v, which is not Pareto-optimal", o)
+				}
+				found[o[0]] = true
+			}
+			// The weighted sum of a linear front is the same everywhere, so it
+			// cannot be relied upon to cover the front.
+			if d == genetics.Tchebycheff && len(found) < n {
+				t.Errorf("Front() covers %d of the %d Pareto-optimal points", len(found), n+1)
+			}
+			if archive.Len() < len(found) {
+				t.Errorf("Archive has %d members; want at least the %d of the front", archive.Len(), len(found))
+			}
+			if s := m.Stats(); s.Generation != 199 || s.Evaluations != n+1 {
+				t.Errorf("Stats()=%+v; want generation 199 with %d evaluations", s, n+1)
+			}
+		})
+	}
+}
+
+func TestMOEADDefaults(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	m := &genetics.MOEAD{
+		Species:       genetics.NewSpecies(6, 1),
+		Evaluator:     genetics.MultiFitnessFunc(func(c genetics.Chromosome) genetics.Objectives { return genetics.Objectives{0, 0, 0} }),
+		NumObjectives: 3,
+		Crossover:     genetics.MultiPointCrossover{Points: 1},
+	}
+	if err := m.Run(rng, 1); err != nil {
+		t.Fatalf("MOEAD.Run(); err=%s", err)
+	}
+	// 13 divisions are the fewest which give 100 weight vectors of 3 objectives.
+	if got := len(m.Weights()); got != 105 {
+		t.Errorf("MOEAD has %d subproblems; want 105", got)
+	}
+	for _, w := range m.Weights() {
+		if sum := w[0] + w[1] + w[2]; sum < 1-1e-9 || sum > 1+1e-9 {
+			t.Errorf("weights %v sum to %g; want 1", w, sum)
+		}
+	}
+
+	for _, bad := range []*genetics.MOEAD{
+		{Species: m.Species, Evaluator: m.Evaluator, NumObjectives: 3},
+		{Species: m.Species, Evaluator: m.Evaluator, NumObjectives: 1, Crossover: m.Crossover},
+		{Species: m.Species, Evaluator: m.Evaluator, NumObjectives: 3, Crossover: m.Crossover, NeighborhoodRate: 2},
+		{Species: m.Species, Evaluator: m.Evaluator, NumObjectives: 2, Crossover: m.Crossover},
+	} {
+		if err := bad.Run(rng, 1); err == nil {
+			t.Errorf("MOEAD.Run() of %+v should fail", bad)
+		}
+	}
+}
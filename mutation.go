@@ -11,6 +11,10 @@ const (
 	swapMutation            = "SwapMutation"
 	scrambleMutation        = "ScrambleMutation"
 	inversionMutation       = "InversionMutation"
+	insertionMutation       = "InsertionMutation"
+
+	displacementMutation         = "DisplacementMutation"
+	invertedDisplacementMutation = "InvertedDisplacementMutation"
 )
 
 // Mutator introduces randomness to the population.
@@ -24,20 +28,25 @@ type Mutator interface {
 }
 
 // RandomResettingMutation (equivalent to Bit Flip Mutation for Species with a bitwidth of 1)
-// Will randomly set an allele to one of the acceptable values. Assumes Species' Genomes
-// accept values of any bit size. This is most useful for chromasomes where genes affect
-// independent behavior (e.g. not permutation-based algorithms).
+// Will randomly set an allele to one of the acceptable values, from 0 to MaxAllele
+// or within the gene's range for a ranged Species. This is most useful for chromasomes
+// where genes affect independent behavior (e.g. not permutation-based algorithms).
 type RandomResettingMutation struct{}
 
 func (RandomResettingMutation) String() string {
 	return randomResettingMutation
 }
 
+// ConcurrentSafe implements ConcurrentSafe
+func (RandomResettingMutation) ConcurrentSafe() bool {
+	return true
+}
+
 // Mutate implements the Mutator interface
 func (m RandomResettingMutation) Mutate(r rand.Rand, c *Chromosome) {
 	n := r.Int31n(int32(len(c.Genes)))
-	v := r.Int31n(int32(c.Species.MaxAllele))
-	c.Genes[n] = Gene(v)
+	min, max := c.Species.Range(int(n))
+	c.Genes[n] = min + Gene(r.Int31n(int32(max-min)+1))
 }
 
 // SwapMutation mutations swap the value of two genomes.
@@ -49,6 +58,11 @@ func (SwapMutation) String() string {
 	return swapMutation
 }
 
+// ConcurrentSafe implements ConcurrentSafe
+func (SwapMutation) ConcurrentSafe() bool {
+	return true
+}
+
 // Mutate implements the mutator interface
 func (m SwapMutation) Mutate(r rand.Rand, c *Chromosome) {
 	// To avoid worrying about a collision with the same index, we'll
@@ -72,6 +86,11 @@ func (ScrambleMutation) String() string {
 	return scrambleMutation
 }
 
+// ConcurrentSafe implements ConcurrentSafe
+func (ScrambleMutation) ConcurrentSafe() bool {
+	return true
+}
+
 // Mutate implements Mutator
 func (m ScrambleMutation) Mutate(r rand.Rand, c *Chromosome) {
 	s := c.Species
@@ -93,6 +112,11 @@ func (InversionMutation) String() string {
 	return inversionMutation
 }
 
+// ConcurrentSafe implements ConcurrentSafe
+func (InversionMutation) ConcurrentSafe() bool {
+	return true
+}
+
 // Mutate implements Mutator
 func (m InversionMutation) Mutate(r rand.Rand, c *Chromosome) {
 	s := c.Species
@@ -103,3 +127,114 @@ func (m InversionMutation) Mutate(r rand.Rand, c *Chromosome) {
 		c.Genes[l], c.Genes[u] = c.Genes[u], c.Genes[l]
 	}
 }
+
+// InsertionMutation removes a random gene and reinserts it at another position,
+// shifting the genes in between. Unlike ScrambleMutation, it preserves all but
+// three adjacencies, which makes it well suited to routing problems and other
+// permutation-encoded Genes.
+type InsertionMutation struct{}
+
+func (InsertionMutation) String() string {
+	return insertionMutation
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (InsertionMutation) ConcurrentSafe() bool {
+	return true
+}
+
+// Mutate implements Mutator
+func (m InsertionMutation) Mutate(r rand.Rand, c *Chromosome) {
+	n := int32(len(c.Genes))
+	// As in SwapMutation, the destination is an offset from the source so that
+	// the gene always moves.
+	from := int(r.Int31n(n))
+	to := (from + int(r.Int31n(n-1)) + 1) % int(n)
+	g := c.Genes[from]
+	if from < to {
+		copy(c.Genes[from:to], c.Genes[from+1:to+1])
+	} else {
+		copy(c.Genes[to+1:from+1], c.Genes[to:from])
+	}
+	c.Genes[to] = g
+}
+
+// DisplacementMutation picks two crossover points and moves the middle segment
+// to a random position among the remaining genes. It generalizes
+// InsertionMutation to segments and is most appropriate for permutation-encoded
+// Genes.
+type DisplacementMutation struct{}
+
+func (DisplacementMutation) String() string {
+	return displacementMutation
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (DisplacementMutation) ConcurrentSafe() bool {
+	return true
+}
+
+// Mutate implements Mutator
+func (m DisplacementMutation) Mutate(r rand.Rand, c *Chromosome) {
+	displace(r, c.Genes, false)
+}
+
+// InvertedDisplacementMutation is DisplacementMutation which also reverses the
+// moved segment, combining it with InversionMutation.
+type InvertedDisplacementMutation struct{}
+
+func (InvertedDisplacementMutation) String() string {
+	return invertedDisplacementMutation
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (InvertedDisplacementMutation) ConcurrentSafe() bool {
+	return true
+}
+
+// Mutate implements Mutator
+func (m InvertedDisplacementMutation) Mutate(r rand.Rand, c *Chromosome) {
+	displace(r, c.Genes, true)
+}
+
+// displace moves a random segment of genes, reversed if invert is set, to a
+// random new position. The segment is chosen as in InversionMutation; if it
+// spans every gene, it can only be inverted.
+func displace(r rand.Rand, genes []Gene, invert bool) {
+	n := int32(len(genes))
+	l := r.Int31n(n - 1)
+	d := r.Int31n(n-l-1) + 1
+	size := d + 1
+	if invert {
+		reverseGenes(genes[l : l+size])
+	}
+	rest := n - size
+	if rest == 0 {
+		return
+	}
+	// p is the index of the segment once moved; skip its current index
+	p := r.Int31n(rest)
+	if p >= l {
+		p++
+	}
+	// Moving the segment rotates the genes between its old and new positions
+	if p > l {
+		rotateGenes(genes[l:p+size], int(size))
+	} else {
+		rotateGenes(genes[p:l+size], int(l-p))
+	}
+}
+
+// reverseGenes reverses genes in place.
+func reverseGenes(genes []Gene) {
+	for i, j := 0, len(genes)-1; i < j; i, j = i+1, j-1 {
+		genes[i], genes[j] = genes[j], genes[i]
+	}
+}
+
+// rotateGenes rotates genes left by k in place.
+func rotateGenes(genes []Gene, k int) {
+	reverseGenes(genes[:k])
+	reverseGenes(genes[k:])
+	reverseGenes(genes)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/inlined/genetics"
 	"github.com/inlined/rand"
 	"github.com/inlined/xkcd"
@@ -118,3 +119,161 @@ func TestMutations(t *testing.T) {
 		})
 	}
 }
+
+func TestInsertionMutation(t *testing.T) {
+	s := genetics.NewSpecies(6, 5)
+	for _, test := range []struct {
+		tag  string
+		rand rand.Rand
+		want []genetics.Gene
+	}{
+		{
+			tag:  "move right",
+			rand: xkcd.Rand(1, 1), // Gene 1 to offset 1 + 1
+			want: []genetics.Gene{0, 2, 3, 1, 4, 5},
+		}, {
+			tag:  "move left",
+			rand: xkcd.Rand(4, 2), // Gene 4 to (4 + 2 + 1) % 6
+			want: []genetics.Gene{0, 4, 1, 2, 3, 5},
+		}, {
+			tag:  "move to end",
+			rand: xkcd.Rand(0, 4),
+			want: []genetics.Gene{1, 2, 3, 4, 5, 0},
+		}, {
+			tag:  "move to start",
+			rand: xkcd.Rand(5, 0),
+			want: []genetics.Gene{5, 0, 1, 2, 3, 4},
+		}, {
+			tag:  "adjacent",
+			rand: xkcd.Rand(2, 0),
+			want: []genetics.Gene{0, 1, 3, 2, 4, 5},
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			c := s.New(0, 1, 2, 3, 4, 5)
+			genetics.InsertionMutation{}.Mutate(test.rand, &c)
+			if diff := cmp.Diff(test.want, c.Genes); diff != "" {
+				t.Errorf("Mutate(); got=%v want=%v diff=%s", c.Genes, test.want, diff)
+			}
+		})
+	}
+}
+
+func TestDisplacementMutation(t *testing.T) {
+	s := genetics.NewSpecies(6, 5)
+	for _, test := range []struct {
+		tag     string
+		mutator genetics.Mutator
+		rand    rand.Rand
+		want    []genetics.Gene
+	}{
+		{
+			tag:     "move right",
+			mutator: genetics.DisplacementMutation{},
+			rand:    xkcd.Rand(1, 1, 2), // Genes [1, 3] to index 2 + 1
+			want:    []genetics.Gene{0, 4, 5, 1, 2, 3},
+		}, {
+			tag:     "move left",
+			mutator: genetics.DisplacementMutation{},
+			rand:    xkcd.Rand(3, 0, 1), // Genes [3, 4] to index 1
+			want:    []genetics.Gene{0, 3, 4, 1, 2, 5},
+		}, {
+			tag:     "move to start",
+			mutator: genetics.DisplacementMutation{},
+			rand:    xkcd.Rand(4, 0, 0),
+			want:    []genetics.Gene{4, 5, 0, 1, 2, 3},
+		}, {
+			tag:     "whole chromosome",
+			mutator: genetics.DisplacementMutation{},
+			rand:    xkcd.Rand(0, 4),
+			want:    []genetics.Gene{0, 1, 2, 3, 4, 5},
+		}, {
+			tag:     "invert and move right",
+			mutator: genetics.InvertedDisplacementMutation{},
+			rand:    xkcd.Rand(1, 1, 2),
+			want:    []genetics.Gene{0, 4, 5, 3, 2, 1},
+		}, {
+			tag:     "invert and move left",
+			mutator: genetics.InvertedDisplacementMutation{},
+			rand:    xkcd.Rand(3, 0, 1),
+			want:    []genetics.Gene{0, 4, 3, 1, 2, 5},
+		}, {
+			tag:     "invert whole chromosome",
+			mutator: genetics.InvertedDisplacementMutation{},
+			rand:    xkcd.Rand(0, 4),
+			want:    []genetics.Gene{5, 4, 3, 2, 1, 0},
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			c := s.New(0, 1, 2, 3, 4, 5)
+			test.mutator.Mutate(test.rand, &c)
+			if diff := cmp.Diff(test.want, c.Genes); diff != "" {
+				t.Errorf("Mutate(); got=%v want=%v diff=%s", c.Genes, test.want, diff)
+			}
+		})
+	}
+
+	// Displacement always yields a permutation
+	rng := rand.New()
+	rng.Seed(42)
+	p, _ := genetics.NewSpecies(20, 19).NewPerm(rng)
+	for i := 0; i < 1000; i++ {
+		genetics.InvertedDisplacementMutation{}.Mutate(rng, &p)
+		if err := p.Species.ValidatePermutation(p); err != nil {
+			t.Fatalf("InvertedDisplacementMutation produced %v; err=%s", p.Genes, err)
+		}
+	}
+}
+
+func TestRandomResettingMutation(t *testing.T) {
+	ranged, err := genetics.NewRangedSpecies([]genetics.Gene{0, 10}, []genetics.Gene{1, 12})
+	if err != nil {
+		t.Fatalf("NewRangedSpecies(); err=%s", err)
+	}
+	for _, test := range []struct {
+		tag     string
+		species *genetics.Species
+		want    [][]genetics.Gene
+	}{
+		{
+			tag:     "unranged",
+			species: genetics.NewSpecies(2, 1),
+			want:    [][]genetics.Gene{{0, 1}, {0, 1}},
+		}, {
+			tag:     "ranged",
+			species: ranged,
+			want:    [][]genetics.Gene{{0, 1}, {10, 11, 12}},
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			rng := rand.New()
+			rng.Seed(42)
+			seen := make([]map[genetics.Gene]bool, test.species.NumGenes)
+			for i := range seen {
+				seen[i] = map[genetics.Gene]bool{}
+			}
+			for i := 0; i < 1000; i++ {
+				var genes []genetics.Gene
+				for j := 0; j < test.species.NumGenes; j++ {
+					min, _ := test.species.Range(j)
+					genes = append(genes, min)
+				}
+				c := test.species.New(genes...)
+				genetics.RandomResettingMutation{}.Mutate(rng, &c)
+				if err := test.species.Validate(c); err != nil {
+					t.Fatalf("Mutate() = %v; err=%s", c.Genes, err)
+				}
+				for j, a := range c.Genes {
+					seen[j][a] = true
+				}
+			}
+			for j, alleles := range test.want {
+				for _, a := range alleles {
+					if !seen[j][a] {
+						t.Errorf("Mutate() never set gene %d to %d", j, a)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package genetics
+
+import "fmt"
+
+const (
+	maximize = "Maximize"
+	minimize = "Minimize"
+)
+
+// Objective is the direction in which Fitness improves. Set Population.Objective
+// to Minimize rather than negating costs (e.g. the length of a tour), so that
+// scores stay meaningful and proportional selection still works.
+//
+// NaturalSelection and the heap utilities always prefer higher scores; the Engine
+// converts a Population's Fitness with Objective.Scores before selection and
+// replacement. Stats, Population.Best, and the other rankings honor the Objective.
+type Objective int
+
+const (
+	// Maximize prefers higher Fitness. It is the zero value.
+	Maximize Objective = iota
+	// Minimize prefers lower Fitness.
+	Minimize
+)
+
+func (o Objective) String() string {
+	if o == Minimize {
+		return minimize
+	}
+	return maximize
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (o Objective) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (o *Objective) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case maximize:
+		*o = Maximize
+	case minimize:
+		*o = Minimize
+	default:
+		return fmt.Errorf("Objective.UnmarshalText(); unknown objective %q", b)
+	}
+	return nil
+}
+
+// Better reports whether a is strictly fitter than b.
+func (o Objective) Better(a, b Fitness) bool {
+	if o == Minimize {
+		return a < b
+	}
+	return a > b
+}
+
+// Scores returns fitness as scores which increase with fitness, as expected by
+// NaturalSelection and Evolver.Evolve. For Maximize it returns fitness itself;
+// for Minimize, a negated copy.
+func (o Objective) Scores(fitness []Fitness) []Fitness {
+	if o != Minimize {
+		return fitness
+	}
+	scores := make([]Fitness, len(fitness))
+	for i, f := range fitness {
+		scores[i] = o.score(f)
+	}
+	return scores
+}
+
+// score returns f as a score which increases with fitness.
+func (o Objective) score(f Fitness) Fitness {
+	if o == Minimize {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,66 @@
+package genetics_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/inlined/genetics"
+)
+
+func TestObjective(t *testing.T) {
+	if !genetics.Maximize.Better(2, 1) || genetics.Maximize.Better(1, 1) || !genetics.Minimize.Better(1, 2) {
+		t.Error("Better() does not follow the Objective")
+	}
+	fitness := []genetics.Fitness{3, -1.5}
+	if diff := cmp.Diff(fitness, genetics.Maximize.Scores(fitness)); diff != "" {
+		t.Errorf("Maximize.Scores() differs; -want +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]genetics.Fitness{-3, 1.5}, genetics.Minimize.Scores(fitness)); diff != "" {
+		t.Errorf("Minimize.Scores() differs; -want +got:\n%s", diff)
+	}
+	if fitness[0] != 3 {
+		t.Error("Minimize.Scores() modified its argument")
+	}
+}
+
+func TestPopulationMinimize(t *testing.T) {
+	s := genetics.NewSpecies(2, 9)
+	pop := genetics.Population{
+		Species:     s,
+		Chromosomes: []genetics.Chromosome{s.New(1, 1), s.New(2, 2), s.New(3, 3), s.New(4, 4)},
+		Fitness:     []genetics.Fitness{5, 1, 9, 1},
+		Objective:   genetics.Minimize,
+	}
+	if best, f := pop.Best(); f != 1 || best.Genes[0] != 2 {
+		t.Errorf("Best()=%v,%g; want the first chromosome with fitness 1", best.Genes, f)
+	}
+	if worst, f := pop.Worst(); f != 9 || worst.Genes[0] != 3 {
+		t.Errorf("Worst()=%v,%g; want [3 3],9", worst.Genes, f)
+	}
+	if clone := pop.Clone(); clone.Objective != genetics.Minimize {
+		t.Error("Clone() lost the Objective")
+	}
+
+	b, err := json.Marshal(pop)
+	if err != nil {
+		t.Fatalf("json.Marshal(); err=%s", err)
+	}
+	var got genetics.Population
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal(%s); err=%s", b, err)
+	}
+	if got.Objective != genetics.Minimize {
+		t.Errorf("json round trip of %s lost the Objective", b)
+	}
+
+	pop.Sort()
+	var order []genetics.Gene
+	for _, c := range pop.Chromosomes {
+		order = append(order, c.Genes[0])
+	}
+	if diff := cmp.Diff([]genetics.Gene{2, 4, 1, 3}, order); diff != "" {
+		t.Errorf("Sort() ordered chromosomes %v; diff=%s", order, diff)
+	}
+}
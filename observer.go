@@ -0,0 +1,40 @@
+package genetics
+
+// Observer is notified of an Engine's progress so that users can log, checkpoint,
+// adapt parameters, or stream metrics without forking the evolution loop.
+// Observers may adjust the Engine (e.g. its Evolver's MutationRate) but must not
+// call Step or Run.
+type Observer interface {
+	// OnGenerationStart is called before a generation is evaluated and evolved.
+	OnGenerationStart(e *Engine, generation int)
+	// OnEvaluate is called after each chromosome is scored.
+	OnEvaluate(e *Engine, c Chromosome, fitness Fitness)
+	// OnReplacement is called after a child replaces the member at index of the population.
+	OnReplacement(e *Engine, index int, child Chromosome)
+	// OnTermination is called when Run finishes with the error which ended the run, if any.
+	OnTermination(e *Engine, err error)
+}
+
+// NopObserver implements every Observer method as a no-op. Embed it in observers
+// which only care about some events.
+type NopObserver struct{}
+
+// OnGenerationStart implements Observer
+func (NopObserver) OnGenerationStart(e *Engine, generation int) {}
+
+// OnEvaluate implements Observer
+func (NopObserver) OnEvaluate(e *Engine, c Chromosome, fitness Fitness) {}
+
+// OnReplacement implements Observer
+func (NopObserver) OnReplacement(e *Engine, index int, child Chromosome) {}
+
+// OnTermination implements Observer
+func (NopObserver) OnTermination(e *Engine, err error) {}
+
+// StatsObserver is notified of the Stats of each scored generation. Unlike
+// Observer it does not depend on Engine, so it can be shared by every driver
+// (Engine, CMAES, ...). An Engine notifies any of its Observers which also
+// implement StatsObserver.
+type StatsObserver interface {
+	OnStats(s Stats)
+}
@@ -0,0 +1,85 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/rand"
+)
+
+type countingObserver struct {
+	genetics.NopObserver
+	generations  []int
+	evaluations  int
+	replacements int
+	mismatches   int
+	terminated   bool
+}
+
+func (o *countingObserver) OnGenerationStart(e *genetics.Engine, generation int) {
+	o.generations = append(o.generations, generation)
+}
+
+func (o *countingObserver) OnEvaluate(e *genetics.Engine, c genetics.Chromosome, fitness genetics.Fitness) {
+	o.evaluations++
+}
+
+func (o *countingObserver) OnReplacement(e *genetics.Engine, index int, child genetics.Chromosome) {
+	o.replacements++
+	if pop := e.Population(); !cmp.Equal(pop.Chromosomes[index], child) {
+		o.mismatches++
+	}
+}
+
+func (o *countingObserver) OnTermination(e *genetics.Engine, err error) {
+	o.terminated = true
+}
+
+// mutationRamp adapts the Engine from within the loop.
+type mutationRamp struct {
+	genetics.NopObserver
+}
+
+func (mutationRamp) OnGenerationStart(e *genetics.Engine, generation int) {
+	e.Evolver.MutationRate = float32(generation) / 10
+}
+
+func TestObservers(t *testing.T) {
+	rng := rand.New()
+	counter := &countingObserver{}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 4,
+			CrossoverRate:    1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator:          genetics.RandomResettingMutation{},
+		},
+		Evaluator: genetics.FitnessFunc(oneMax),
+		Observers: []genetics.Observer{mutationRamp{}, counter},
+	}
+	if err := engine.Run(rng, newBinaryPopulation(t, rng, 6, 8), 5); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+
+	if diff := cmp.Diff([]int{0, 1, 2, 3, 4}, counter.generations); diff != "" {
+		t.Errorf("OnGenerationStart() called for generations %v; diff=%s", counter.generations, diff)
+	}
+	if want := 8 + 5*4; counter.evaluations != want {
+		t.Errorf("OnEvaluate() called %d times; want %d", counter.evaluations, want)
+	}
+	if want := 5 * 4; counter.replacements != want {
+		t.Errorf("OnReplacement() called %d times; want %d", counter.replacements, want)
+	}
+	if counter.mismatches != 0 {
+		t.Errorf("OnReplacement() was called with %d children missing from the population", counter.mismatches)
+	}
+	if !counter.terminated {
+		t.Error("OnTermination() was not called")
+	}
+	if engine.Evolver.MutationRate != 0.4 {
+		t.Errorf("Observer could not adapt MutationRate; got=%f want=0.4", engine.Evolver.MutationRate)
+	}
+}
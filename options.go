@@ -0,0 +1,105 @@
+package genetics
+
+// DefaultCrossoverRate is the CrossoverRate of NewEvolver, of RegisterFlags, and
+// of an EvolverConfig decoded without one.
+const DefaultCrossoverRate = 0.9
+
+// Option configures an Evolver created by NewEvolver.
+type Option func(e *Evolver)
+
+// NewEvolver returns an Evolver with sane defaults, changed by opts:
+// StochasticUniversalSampling, MultiPointCrossover(2) at a CrossoverRate of 0.9,
+// SwapMutation at a MutationRate of 0.01, and a ReplacementCount of 2 (a
+// steady-state GA). The result is an ordinary Evolver whose fields may still be
+// set directly; use Evolver.Validate to check it against a population size.
+func NewEvolver(opts ...Option) Evolver {
+	e := Evolver{
+		ReplacementCount: 2,
+		CrossoverRate:    DefaultCrossoverRate,
+		MutationRate:     0.01,
+		Selector:         StochasticUniversalSampling{},
+		Crossover:        MultiPointCrossover{Points: 2},
+		Mutator:          SwapMutation{},
+	}
+	for _, opt := range opts {
+		opt(&e)
+	}
+	return e
+}
+
+// WithSelector sets Evolver.Selector.
+func WithSelector(s NaturalSelection) Option {
+	return func(e *Evolver) {
+		e.Selector = s
+	}
+}
+
+// WithCrossover sets Evolver.Crossover.
+func WithCrossover(c Crossover) Option {
+	return func(e *Evolver) {
+		e.Crossover = c
+	}
+}
+
+// WithCrossoverRate sets Evolver.CrossoverRate. A rate of 0 never recombines.
+func WithCrossoverRate(rate float32) Option {
+	return func(e *Evolver) {
+		e.CrossoverRate = rate
+	}
+}
+
+// WithMutator sets Evolver.Mutator.
+func WithMutator(m Mutator) Option {
+	return func(e *Evolver) {
+		e.Mutator = m
+	}
+}
+
+// WithMutationRate sets Evolver.MutationRate.
+func WithMutationRate(rate float32) Option {
+	return func(e *Evolver) {
+		e.MutationRate = rate
+	}
+}
+
+// WithReplacementCount sets Evolver.ReplacementCount.
+func WithReplacementCount(n int) Option {
+	return func(e *Evolver) {
+		e.ReplacementCount = n
+	}
+}
+
+// WithRepairer sets Evolver.Repairer.
+func WithRepairer(r Repairer) Option {
+	return func(e *Evolver) {
+		e.Repairer = r
+	}
+}
+
+// WithDuplicateRetries sets Evolver.DuplicateRetries.
+func WithDuplicateRetries(n int) Option {
+	return func(e *Evolver) {
+		e.DuplicateRetries = n
+	}
+}
+
+// WithDistinctParents sets Evolver.DistinctParents.
+func WithDistinctParents() Option {
+	return func(e *Evolver) {
+		e.DistinctParents = true
+	}
+}
+
+// WithMatingRestriction sets Evolver.MatingRestriction.
+func WithMatingRestriction(r MatingRestriction) Option {
+	return func(e *Evolver) {
+		e.MatingRestriction = &r
+	}
+}
+
+// WithValidator sets Evolver.Validator.
+func WithValidator(v func(c Chromosome) error) Option {
+	return func(e *Evolver) {
+		e.Validator = v
+	}
+}
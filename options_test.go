@@ -0,0 +1,101 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestNewEvolver(t *testing.T) {
+	for _, test := range []struct {
+		tag  string
+		opts []genetics.Option
+		want genetics.Evolver
+	}{
+		{
+			tag: "defaults",
+			want: genetics.Evolver{
+				ReplacementCount: 2,
+				CrossoverRate:    0.9,
+				MutationRate:     0.01,
+				Selector:         genetics.StochasticUniversalSampling{},
+				Crossover:        genetics.MultiPointCrossover{Points: 2},
+				Mutator:          genetics.SwapMutation{},
+			},
+		}, {
+			tag: "options",
+			opts: []genetics.Option{
+				genetics.WithSelector(genetics.TournamentSelection{Size: 3}),
+				genetics.WithCrossover(genetics.DavisOrderCrossover{}),
+				genetics.WithCrossoverRate(0.7),
+				genetics.WithMutator(genetics.InversionMutation{}),
+				genetics.WithMutationRate(0.2),
+				genetics.WithReplacementCount(10),
+				genetics.WithDuplicateRetries(3),
+				genetics.WithDistinctParents(),
+			},
+			want: genetics.Evolver{
+				ReplacementCount: 10,
+				CrossoverRate:    0.7,
+				MutationRate:     0.2,
+				Selector:         genetics.TournamentSelection{Size: 3},
+				Crossover:        genetics.DavisOrderCrossover{},
+				Mutator:          genetics.InversionMutation{},
+				DuplicateRetries: 3,
+				DistinctParents:  true,
+			},
+		}, {
+			tag:  "mutation only",
+			opts: []genetics.Option{genetics.WithCrossoverRate(0)},
+			want: genetics.Evolver{
+				ReplacementCount: 2,
+				MutationRate:     0.01,
+				Selector:         genetics.StochasticUniversalSampling{},
+				Crossover:        genetics.MultiPointCrossover{Points: 2},
+				Mutator:          genetics.SwapMutation{},
+			},
+		}, {
+			tag: "later options win",
+			opts: []genetics.Option{
+				genetics.WithMutationRate(0.2),
+				genetics.WithMutationRate(0.3),
+			},
+			want: genetics.Evolver{
+				ReplacementCount: 2,
+				CrossoverRate:    0.9,
+				MutationRate:     0.3,
+				Selector:         genetics.StochasticUniversalSampling{},
+				Crossover:        genetics.MultiPointCrossover{Points: 2},
+				Mutator:          genetics.SwapMutation{},
+			},
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			got := genetics.NewEvolver(test.opts...)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("NewEvolver() returned an unexpected Evolver; diff=%s", diff)
+			}
+			if err := got.Validate(20); err != nil {
+				t.Errorf("NewEvolver() should be valid; err=%s", err)
+			}
+		})
+	}
+}
+
+func TestNewEvolverRuns(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	r := genetics.MatingRestriction{Distance: genetics.HammingDistance, Threshold: 1}
+	e := genetics.NewEvolver(genetics.WithMatingRestriction(r), genetics.WithReplacementCount(10))
+	if e.MatingRestriction == nil || e.MatingRestriction.Threshold != 1 {
+		t.Fatalf("WithMatingRestriction() did not set the MatingRestriction; got=%v", e.MatingRestriction)
+	}
+	pop := newBinaryPopulation(t, rng, 10, 20)
+	engine := genetics.Engine{Evolver: e, Evaluator: genetics.FitnessFunc(oneMax)}
+	if err := engine.Run(rng, pop, 20); err != nil {
+		t.Fatalf("Engine.Run(); err=%s", err)
+	}
+}
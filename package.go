@@ -1,6 +1,10 @@
 // Package genetics implements swappable components designed for a variety of genetics
 // algorithms. They were designed as a learning exercise and are based on the instructions
 // in tutorialspoint.com/genetic_algorithms.
+//
+// Runs are reproducible for a given random source. Wherever chromosomes with equal
+// fitness must be ordered (in selection, replacement, and Population queries), the
+// chromosome with the lower index in the population is treated as the fitter one.
 package genetics
 
 // TODO: Incremental vs generational offspring
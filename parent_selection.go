@@ -9,13 +9,16 @@ import (
 
 const (
 	stochasticUniversalSampling = "StochasticUniversalSampling"
+	rouletteWheelSelection      = "RouletteWheelSelection"
 	rankedSelection             = "RankedSelection"
+	exponentialRankedSelection  = "ExponentialRankedSelection"
 	tournamentSelection         = "TournamentSelection"
 )
 
 // NaturalSelection is an interface to pick the selection method.
-// A NaturalSelection MAY NOT BE GOROUTINE SAFE. It may only be used in one Evolve function at a time.
-// This helps avoid the lock incurred by the top-level rand functions.
+// A NaturalSelection MAY NOT BE GOROUTINE SAFE unless it is ConcurrentSafe, as every
+// built-in NaturalSelection is. Random numbers come from the caller's rand.Rand, which
+// avoids the lock incurred by the top-level rand functions.
 // TODO: consider nested interfaces (NaturalSelection has a Seed() function to return a Selector
 // that implements SelectParents). This would avoid re-generating the roulette wheel in
 // StochasticUniversalSampling
@@ -26,7 +29,8 @@ type NaturalSelection interface {
 
 // StochasticUniversalSampling creates a "roulette" wheel where each parent
 // gets a slice in proportion to their fitness. We then spin the wheel with
-// two fixed points to select which parents win.
+// two fixed points to select which parents win. If any fitness is negative, the
+// wheel is windowed so that the least fit parent gets no slice.
 // If src is nill, a new source is created with the current time.
 type StochasticUniversalSampling struct{}
 
@@ -34,60 +38,138 @@ func (s StochasticUniversalSampling) String() string {
 	return stochasticUniversalSampling
 }
 
+// ConcurrentSafe implements ConcurrentSafe
+func (StochasticUniversalSampling) ConcurrentSafe() bool {
+	return true
+}
+
 // SelectParents implements the NaturalSelection interface.
 func (s StochasticUniversalSampling) SelectParents(rand rand.Rand, numParents int, fitness []Fitness) (indexes []int) {
-	totalFitness := Fitness(0)
-	for _, f := range fitness {
-		totalFitness += f
-	}
+	w := newWheel(fitness)
 
 	// Use a fixed distance (uniform distribution) across the wheel.
-	// Note: we choose here to use integer arithmetic instead of a float distribution.
-	// This uses faster ALUs but introduces the possibility of error when totalFitness !>> numParents
-	distance := totalFitness / Fitness(numParents)
+	distance := w.total() / Fitness(numParents)
 	// Spin the wheel up to distance (equivalent to spinning the wheel randomly and then taking the modulo
 	// of the size)
-	pos := Fitness(rand.Int63n(int64(distance)))
+	pos := Fitness(rand.Float64()) * distance
 
-	// Iterate through the fitness scores as if it were a roulete wheel (e.g. incrementing f by
-	// fitness[n] rather than one) and remember the indexes which contain any pointers P.
 	// In edge cases, a position may hit the same parent multiple times; in this case, the parent
-	// is selected repeatedly.
-	// TODO: Should this be instead selected with a weight to avoid a parent mating with itself?
-	indexes = make([]int, 0, numParents)
+	// is selected repeatedly. Set Evolver.DistinctParents to keep it from mating with itself.
+	indexes = make([]int, numParents)
+	for n := range indexes {
+		indexes[n] = w.slice(pos)
+		pos += distance
+	}
+
+	return indexes
+}
+
+// RouletteWheelSelection is classic fitness proportionate selection. Like
+// StochasticUniversalSampling, each parent gets a slice of a "roulette" wheel in
+// proportion to their fitness, but the wheel is spun independently for each parent.
+// This has higher variance than StochasticUniversalSampling: a parent may be
+// selected any number of times regardless of its fitness.
+type RouletteWheelSelection struct{}
+
+func (s RouletteWheelSelection) String() string {
+	return rouletteWheelSelection
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (RouletteWheelSelection) ConcurrentSafe() bool {
+	return true
+}
+
+// SelectParents implements the NaturalSelection interface.
+func (s RouletteWheelSelection) SelectParents(rand rand.Rand, numParents int, fitness []Fitness) (indexes []int) {
+	w := newWheel(fitness)
+	indexes = make([]int, numParents)
+	for n := range indexes {
+		indexes[n] = w.slice(Fitness(rand.Float64()) * w.total())
+	}
+	return indexes
+}
+
+// wheel is a "roulette" wheel for fitness proportionate selection. Slice n of the
+// wheel spans [wheel[n-1], wheel[n]).
+type wheel []Fitness
+
+// newWheel sizes each slice in proportion to fitness. If any fitness is negative,
+// e.g. the negated scores of a minimizing population, every slice is first shrunk
+// by the least fitness (windowing) so that the least fit gets no slice. If no
+// chromosome gets a slice, all slices are equal.
+func newWheel(fitness []Fitness) wheel {
+	offset := Fitness(0)
+	for _, f := range fitness {
+		if f < offset {
+			offset = f
+		}
+	}
+	w := make(wheel, len(fitness))
 	accumFitness := Fitness(0)
-	for n := 0; len(indexes) < numParents; n++ {
-		accumFitness += fitness[n]
-		for ; pos < accumFitness; pos += distance {
-			indexes = append(indexes, n)
+	for n, f := range fitness {
+		accumFitness += f - offset
+		w[n] = accumFitness
+	}
+	if accumFitness == 0 {
+		for n := range w {
+			w[n] = Fitness(n + 1)
 		}
 	}
+	return w
+}
 
-	return indexes
+func (w wheel) total() Fitness {
+	return w[len(w)-1]
+}
+
+// slice returns the slice of the wheel which contains pos.
+func (w wheel) slice(pos Fitness) int {
+	n := sort.Search(len(w), func(n int) bool {
+		return pos < w[n]
+	})
+	// Rounding may carry pos onto the end of the wheel.
+	if n == len(w) {
+		n--
+	}
+	return n
 }
 
 // RankedSelection gives each chromosome odds of reproduction not based on its proportional
-// fitness, but its rank in overall fitness. This ensures that populations trend towards
+// fitness, but its rank in overall fitness. Equal fitness is ranked by index. This ensures that populations trend towards
 // optimal solutions still as the problem is converging.
-type RankedSelection struct{}
+// Pressure, if set, is the selective pressure of linear ranking in [1, 2]: the fittest
+// chromosome is expected to be selected Pressure times as often as the median and the
+// least fit 2-Pressure times as often. A Pressure of 1 is a uniform random choice.
+type RankedSelection struct {
+	Pressure float64
+}
 
 func (s RankedSelection) String() string {
-	return rankedSelection
+	if s.Pressure == 0 {
+		return rankedSelection
+	}
+	return fmt.Sprintf("%s(%g)", rankedSelection, s.Pressure)
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (RankedSelection) ConcurrentSafe() bool {
+	return true
 }
 
 // SelectParents selects parents in proportion to their fitness' rank.
 func (s RankedSelection) SelectParents(rand rand.Rand, numParents int, fitness []Fitness) (indexes []int) {
-	// Lazy version of a Schwartzian transform; may be memory wasteful
-	zipped := make([]tie, len(fitness))
-	for n, f := range fitness {
-		zipped[n] = tie{index: n, fitness: f}
-	}
-	sort.Slice(zipped, func(i, j int) bool {
-		return zipped[i].fitness > zipped[j].fitness
-	})
-	rankedIndexes := make([]int, len(fitness))
-	for n, t := range zipped {
-		rankedIndexes[n] = t.index
+	rankedIndexes := rankIndexes(fitness)
+	if s.Pressure != 0 {
+		n := float64(len(fitness))
+		weights := make([]float64, len(fitness))
+		for i := range weights {
+			weights[i] = 2 - s.Pressure
+			if n > 1 {
+				weights[i] += 2 * (s.Pressure - 1) * (n - 1 - float64(i)) / (n - 1)
+			}
+		}
+		return spinRankedWheel(rand, numParents, weights, rankedIndexes)
 	}
 
 	// Edited version of SUS. Should we waste the cycles trying to use a universal internal
@@ -105,8 +187,7 @@ func (s RankedSelection) SelectParents(rand rand.Rand, numParents int, fitness [
 	// Iterate through the fitness scores as if it were a roulete wheel (e.g. incrementing f by
 	// fitness[n] rather than one) and remember the indexes which contain any pointers P.
 	// In edge cases, a position may hit the same parent multiple times; in this case, the parent
-	// is selected repeatedly.
-	// TODO: Should this be instead selected with a weight to avoid a parent mating with itself?
+	// is selected repeatedly. Set Evolver.DistinctParents to keep it from mating with itself.
 	indexes = make([]int, 0, numParents)
 	accumRank := 0
 	for n := 0; len(indexes) < numParents; n++ {
@@ -119,22 +200,98 @@ func (s RankedSelection) SelectParents(rand rand.Rand, numParents int, fitness [
 	return indexes
 }
 
+// ExponentialRankedSelection selects parents in proportion to Base^rank, where the
+// fittest chromosome has rank 0. Base is in (0, 1); smaller bases favor the fittest
+// chromosomes more strongly than linear ranking can.
+type ExponentialRankedSelection struct {
+	Base float64
+}
+
+func (s ExponentialRankedSelection) String() string {
+	return fmt.Sprintf("%s(%g)", exponentialRankedSelection, s.Base)
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (ExponentialRankedSelection) ConcurrentSafe() bool {
+	return true
+}
+
+// SelectParents implements NaturalSelection
+func (s ExponentialRankedSelection) SelectParents(rand rand.Rand, numParents int, fitness []Fitness) (indexes []int) {
+	weights := make([]float64, len(fitness))
+	w := 1.0
+	for i := range weights {
+		weights[i] = w
+		w *= s.Base
+	}
+	return spinRankedWheel(rand, numParents, weights, rankIndexes(fitness))
+}
+
+// spinRankedWheel selects numParents with stochastic universal sampling on a wheel
+// where rankedIndexes[i] has a slice of size weights[i].
+func spinRankedWheel(rand rand.Rand, numParents int, weights []float64, rankedIndexes []int) []int {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	distance := total / float64(numParents)
+	pos := rand.Float64() * distance
+
+	indexes := make([]int, 0, numParents)
+	accum := 0.0
+	for n := 0; len(indexes) < numParents; n++ {
+		// Guard against rounding error on the last slice
+		if n == len(weights)-1 {
+			accum = total + distance
+		} else {
+			accum += weights[n]
+		}
+		for ; pos < accum && len(indexes) < numParents; pos += distance {
+			indexes = append(indexes, rankedIndexes[n])
+		}
+	}
+	return indexes
+}
+
 // TournamentSelection picks each parent by picking Size candidates from a fitness list
-// at random and selecting the parent with the greatest fitness.
+// at random and selecting the parent with the greatest fitness. Ties go to the lowest index.
+// If P is set, tournaments are probabilistic: the fittest candidate wins with probability
+// P, the second fittest with probability P(1-P), and so on, which lowers the selection
+// pressure of small populations. P of 0 or 1 is a deterministic tournament.
 type TournamentSelection struct {
 	Size int
+	P    float64
 }
 
 func (s TournamentSelection) String() string {
-	return fmt.Sprintf("%s(%d)", tournamentSelection, s.Size)
+	if s.P == 0 {
+		return fmt.Sprintf("%s(%d)", tournamentSelection, s.Size)
+	}
+	return fmt.Sprintf("%s(%d,%g)", tournamentSelection, s.Size, s.P)
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (TournamentSelection) ConcurrentSafe() bool {
+	return true
 }
 
 func (s TournamentSelection) selectOneParent(r rand.Rand, fitness []Fitness) int {
 	indexes := rand.Deal(r, len(fitness), s.Size)
+	if s.P != 0 && s.P < 1 {
+		sort.Slice(indexes, func(i, j int) bool {
+			return tie{index: indexes[i], fitness: fitness[indexes[i]]}.fitterThan(tie{index: indexes[j], fitness: fitness[indexes[j]]})
+		})
+		for _, index := range indexes[:s.Size-1] {
+			if r.Float64() < s.P {
+				return index
+			}
+		}
+		return indexes[s.Size-1]
+	}
 	maxFitness := fitness[indexes[0]]
 	maxIndex := indexes[0]
 	for n := 1; n < s.Size; n++ {
-		if fitness[indexes[n]] >= maxFitness {
+		if fitness[indexes[n]] > maxFitness || (fitness[indexes[n]] == maxFitness && indexes[n] < maxIndex) {
 			maxFitness = fitness[indexes[n]]
 			maxIndex = indexes[n]
 		}
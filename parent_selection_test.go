@@ -23,15 +23,15 @@ func TestParentSelection(t *testing.T) {
 			tag:             "SUS pick every other (even)",
 			strategy:        genetics.StochasticUniversalSampling{},
 			numSelected:     3,
-			fitness:         []genetics.Fitness{2, 2, 2, 2, 2, 2},
-			rand:            xkcd.Rand(1),
+			fitness:         []genetics.Fitness{2, 2, 2, 2, 2, 2}, // d = 12 / 3 = 4
+			rand:            xkcd.Rand(0.25),                      // pos = 1, 5, 9
 			expectedParents: []int{0, 2, 4},
 		}, {
 			tag:             "SUS pick every other (odd)",
 			strategy:        genetics.StochasticUniversalSampling{},
 			numSelected:     3,
-			fitness:         []genetics.Fitness{2, 2, 2, 2, 2, 2},
-			rand:            xkcd.Rand(3),
+			fitness:         []genetics.Fitness{2, 2, 2, 2, 2, 2}, // d = 12 / 3 = 4
+			rand:            xkcd.Rand(0.75),                      // pos = 3, 7, 11
 			expectedParents: []int{1, 3, 5},
 		}, {
 			// This is an edge case and a major sign to switch the selection mechanism to ranked scoring
@@ -39,15 +39,15 @@ func TestParentSelection(t *testing.T) {
 			tag:             "SUS top-exclusively",
 			strategy:        genetics.StochasticUniversalSampling{},
 			numSelected:     3,
-			fitness:         []genetics.Fitness{10, 1, 1},
-			rand:            xkcd.Rand(1),
+			fitness:         []genetics.Fitness{10, 1, 1}, // d = 12 / 3 = 4
+			rand:            xkcd.Rand(0.25),              // pos = 1, 5, 9
 			expectedParents: []int{0, 0, 0},
 		}, {
 			tag:             "SUS redundant picks",
 			strategy:        genetics.StochasticUniversalSampling{},
 			numSelected:     3,
-			fitness:         []genetics.Fitness{10, 1, 1},
-			rand:            xkcd.Rand(2),
+			fitness:         []genetics.Fitness{10, 1, 1}, // d = 12 / 3 = 4
+			rand:            xkcd.Rand(0.5),               // pos = 2, 6, 10
 			expectedParents: []int{0, 0, 1},
 		}, {
 			tag:             "Ranked wheel begin",
@@ -84,6 +84,83 @@ func TestParentSelection(t *testing.T) {
 			fitness:         []genetics.Fitness{4, 20, 16, 3}, // Ranked weights: 2, 4, 3, 1
 			rand:            xkcd.Rand(3, 2, 1, 2),            // deal {3, 2}, {1, 2}
 			expectedParents: []int{2 /* winner of 3 vs 2 */, 1 /* winner of 1 vs 2 */},
+		}, {
+			tag:             "Tournament tie goes to lower index",
+			strategy:        genetics.TournamentSelection{Size: 2},
+			numSelected:     2,
+			fitness:         []genetics.Fitness{7, 7, 7, 7},
+			rand:            xkcd.Rand(3, 1, 0, 2), // deal {3, 1}, {0, 2}
+			expectedParents: []int{1, 0},
+		}, {
+			tag:             "Probabilistic tournament",
+			strategy:        genetics.TournamentSelection{Size: 3, P: 0.5},
+			numSelected:     2,
+			fitness:         []genetics.Fitness{4, 20, 16, 3},
+			rand:            xkcd.Rand(0, 1, 2, 0.7, 0.2, 3, 1, 2, 0.9, 0.9), // deal {0, 1, 2}, {3, 1, 2}
+			expectedParents: []int{2 /* second fittest wins */, 3 /* least fit wins the remainder */},
+		}, {
+			tag:             "Roulette wheel spins independently",
+			strategy:        genetics.RouletteWheelSelection{},
+			numSelected:     3,
+			fitness:         []genetics.Fitness{4, 20, 16, 3}, // Wheel: [0, 4), [4, 24), [24, 40), [40, 43)
+			rand:            xkcd.Rand(5.0/43, 23.0/43, 42.0/43),
+			expectedParents: []int{1, 1, 3},
+		}, {
+			tag:             "SUS fractional fitness",
+			strategy:        genetics.StochasticUniversalSampling{},
+			numSelected:     2,
+			fitness:         []genetics.Fitness{0.25, 0.5, 0.75, 0.5}, // d = 2 / 2 = 1
+			rand:            xkcd.Rand(0.5),                           // pos = 0.5, 1.5
+			expectedParents: []int{1, 3},
+		}, {
+			tag:             "SUS negative fitness is windowed",
+			strategy:        genetics.StochasticUniversalSampling{},
+			numSelected:     2,
+			fitness:         []genetics.Fitness{-4, -2, -1, -3}, // Weights: 0, 2, 3, 1; d = 6 / 2 = 3
+			rand:            xkcd.Rand(0.5),                     // pos = 1.5, 4.5
+			expectedParents: []int{1, 2},
+		}, {
+			tag:             "SUS equal fitness without weight",
+			strategy:        genetics.StochasticUniversalSampling{},
+			numSelected:     2,
+			fitness:         []genetics.Fitness{0, 0, 0, 0}, // Weights: 1, 1, 1, 1; d = 4 / 2 = 2
+			rand:            xkcd.Rand(0.25),                // pos = 0.5, 2.5
+			expectedParents: []int{0, 2},
+		}, {
+			tag:             "Roulette wheel fractional fitness",
+			strategy:        genetics.RouletteWheelSelection{},
+			numSelected:     3,
+			fitness:         []genetics.Fitness{0.1, 0.2, 0.3, 0.4}, // Wheel: [0, 0.1), [0.1, 0.3), [0.3, 0.6), [0.6, 1)
+			rand:            xkcd.Rand(0.05, 0.5, 0.95),
+			expectedParents: []int{0, 2, 3},
+		}, {
+			tag:             "Linear ranking with maximum pressure",
+			strategy:        genetics.RankedSelection{Pressure: 2},
+			numSelected:     2,                                // d = 4 / 2 = 2
+			fitness:         []genetics.Fitness{4, 20, 16, 3}, // Ranked weights: 2/3, 2, 4/3, 0
+			rand:            xkcd.Rand(0.25),                  // pos = 0.5, 2.5
+			expectedParents: []int{1, 2},
+		}, {
+			tag:             "Linear ranking without pressure",
+			strategy:        genetics.RankedSelection{Pressure: 1},
+			numSelected:     2,                                // d = 4 / 2 = 2
+			fitness:         []genetics.Fitness{4, 20, 16, 3}, // Ranked weights: 1, 1, 1, 1
+			rand:            xkcd.Rand(0.25),                  // pos = 0.5, 2.5
+			expectedParents: []int{1, 0},
+		}, {
+			tag:             "Exponential ranking",
+			strategy:        genetics.ExponentialRankedSelection{Base: 0.5},
+			numSelected:     3,                                // d = 1.875 / 3 = 0.625
+			fitness:         []genetics.Fitness{4, 20, 16, 3}, // Ranked weights: 1/4, 1, 1/2, 1/8
+			rand:            xkcd.Rand(0.16),                  // pos = 0.1, 0.725, 1.35
+			expectedParents: []int{1, 1, 2},
+		}, {
+			tag:             "Ranked ties rank by index",
+			strategy:        genetics.RankedSelection{},
+			numSelected:     3,                           // d=6/3=2
+			fitness:         []genetics.Fitness{5, 5, 5}, // Ranked weights: 3 2 1
+			rand:            xkcd.Rand(1),
+			expectedParents: []int{0, 1, 2},
 		},
 	} {
 		t.Run(test.tag, func(t *testing.T) {
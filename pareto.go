@@ -0,0 +1,192 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+)
+
+// Objectives scores a Chromosome on several competing objectives at once. Like
+// Fitness, each objective is maximized; negate objectives which should be
+// minimized.
+type Objectives []Fitness
+
+// MultiEvaluator scores Chromosomes on several objectives.
+type MultiEvaluator interface {
+	EvaluateObjectives(c Chromosome) (Objectives, error)
+}
+
+// MultiFitnessFunc is a MultiEvaluator which cannot fail.
+type MultiFitnessFunc func(c Chromosome) Objectives
+
+// EvaluateObjectives implements MultiEvaluator
+func (f MultiFitnessFunc) EvaluateObjectives(c Chromosome) (Objectives, error) {
+	return f(c), nil
+}
+
+// Dominates reports whether a Pareto-dominates b: a is at least as fit as b in
+// every objective and fitter in at least one.
+func Dominates(a, b Objectives) bool {
+	better := false
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return false
+		case a[i] > b[i]:
+			better = true
+		}
+	}
+	return better
+}
+
+// EpsilonArchive is an elitist archive of the non-dominated Chromosomes found by a
+// multi-objective run, kept apart from the population so that the approximation
+// of the Pareto front it holds is not limited by the population's size.
+//
+// Following Laumanns, Thiele, Deb, and Zitzler, objective space is divided into
+// boxes of Epsilon[i] in objective i, and the archive keeps at most one member
+// per box and no member whose box is dominated by another's. Members are thus
+// spread along the front at least Epsilon apart. Within a box, a member is
+// replaced by one which dominates it or, if neither dominates, by one nearer to
+// the box's fittest corner.
+//
+// If MaxSize is set and the archive outgrows it, Epsilon is doubled until the
+// members fit, coarsening the front evenly rather than discarding its extremes.
+// An EpsilonArchive is not goroutine safe.
+type EpsilonArchive struct {
+	// Epsilon is the positive size of a box in each objective.
+	Epsilon []float64
+	// MaxSize, if positive, is the most members the archive holds.
+	MaxSize int
+
+	members []archiveMember
+	added   int
+}
+
+// archiveMember is a Chromosome held by an EpsilonArchive.
+type archiveMember struct {
+	c          Chromosome
+	objectives Objectives
+	box        []float64
+	// id orders the members by when they were added.
+	id int
+}
+
+// Add offers a copy of c, scored o, to the archive and reports whether it was
+// kept.
+func (a *EpsilonArchive) Add(c Chromosome, o Objectives) (bool, error) {
+	if len(o) == 0 || len(o) != len(a.Epsilon) {
+		return false, fmt.Errorf("EpsilonArchive.Add(); %d objectives for %d Epsilons", len(o), len(a.Epsilon))
+	}
+	for i, eps := range a.Epsilon {
+		if !(eps > 0) {
+			return false, fmt.Errorf("EpsilonArchive.Add(); Epsilon[%d] %g must be positive", i, eps)
+		}
+		if math.IsNaN(float64(o[i])) {
+			return false, fmt.Errorf("EpsilonArchive.Add(); objective %d is NaN", i)
+		}
+	}
+	a.added++
+	m := archiveMember{c: c.Clone(), objectives: append(Objectives(nil), o...), id: a.added}
+	if !a.insert(m) {
+		return false, nil
+	}
+	for a.MaxSize > 0 && len(a.members) > a.MaxSize {
+		a.coarsen()
+	}
+	// Coarsening may have merged the new member away
+	for _, kept := range a.members {
+		if kept.id == m.id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Len returns the number of members of the archive.
+func (a *EpsilonArchive) Len() int {
+	return len(a.members)
+}
+
+// Front returns the members of the archive and their Objectives, in the order in
+// which they were added. The Chromosomes and Objectives must not be modified.
+func (a *EpsilonArchive) Front() ([]Chromosome, []Objectives) {
+	cs := make([]Chromosome, len(a.members))
+	objectives := make([]Objectives, len(a.members))
+	for i, m := range a.members {
+		cs[i], objectives[i] = m.c, m.objectives
+	}
+	return cs, objectives
+}
+
+// insert adds m unless its box is dominated or its box's member is preferred, and
+// drops the members whose boxes m's dominates. It reports whether m was added.
+func (a *EpsilonArchive) insert(m archiveMember) bool {
+	m.box = make([]float64, len(m.objectives))
+	for i, f := range m.objectives {
+		m.box[i] = math.Floor(float64(f) / a.Epsilon[i])
+	}
+	kept := a.members[:0:0]
+	for n, old := range a.members {
+		switch {
+		case boxDominates(old.box, m.box):
+			return false
+		case boxEqual(old.box, m.box):
+			if !Dominates(m.objectives, old.objectives) &&
+				(Dominates(old.objectives, m.objectives) || a.cornerDistance(old) <= a.cornerDistance(m)) {
+				return false
+			}
+		case !boxDominates(m.box, old.box):
+			kept = append(kept, a.members[n])
+		}
+	}
+	a.members = append(kept, m)
+	return true
+}
+
+// coarsen doubles Epsilon and reinserts every member.
+func (a *EpsilonArchive) coarsen() {
+	eps := make([]float64, len(a.Epsilon))
+	for i := range eps {
+		eps[i] = 2 * a.Epsilon[i]
+	}
+	a.Epsilon = eps
+	members := a.members
+	a.members = nil
+	for _, m := range members {
+		a.insert(m)
+	}
+}
+
+// cornerDistance is the distance from m to the fittest corner of its box, in
+// units of Epsilon.
+func (a *EpsilonArchive) cornerDistance(m archiveMember) float64 {
+	d := 0.0
+	for i, f := range m.objectives {
+		x := m.box[i] + 1 - float64(f)/a.Epsilon[i]
+		d += x * x
+	}
+	return d
+}
+
+// boxDominates reports whether box a Pareto-dominates box b.
+func boxDominates(a, b []float64) bool {
+	better := false
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return false
+		case a[i] > b[i]:
+			better = true
+		}
+	}
+	return better
+}
+
+func boxEqual(a, b []float64) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package genetics_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestDominates(t *testing.T) {
+	for _, test := range []struct {
+		a, b genetics.Objectives
+		want bool
+	}{
+		{a: genetics.Objectives{2, 2}, b: genetics.Objectives{1, 1}, want: true},
+		{a: genetics.Objectives{2, 1}, b: genetics.Objectives{1, 1}, want: true},
+		{a: genetics.Objectives{1, 1}, b: genetics.Objectives{1, 1}, want: false},
+		{a: genetics.Objectives{2, 0}, b: genetics.Objectives{1, 1}, want: false},
+		{a: genetics.Objectives{1, 1}, b: genetics.Objectives{2, 1}, want: false},
+	} {
+		if got := genetics.Dominates(test.a, test.b); got != test.want {
+			t.Errorf("Dominates(%v, %v)=%t; want %t", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestEpsilonArchive(t *testing.T) {
+	s := genetics.NewSpecies(1, 100)
+	a := &genetics.EpsilonArchive{Epsilon: []float64{1, 1}}
+	for _, test := range []struct {
+		name string
+		o    genetics.Objectives
+		want bool
+		// front is the Objectives of the archive afterwards.
+		front []genetics.Objectives
+	}{
+		{name: "first", o: genetics.Objectives{1.5, 3.5}, want: true,
+			front: []genetics.Objectives{{1.5, 3.5}}},
+		{name: "non-dominated box", o: genetics.Objectives{3.5, 1.5}, want: true,
+			front: []genetics.Objectives{{1.5, 3.5}, {3.5, 1.5}}},
+		{name: "dominated box", o: genetics.Objectives{1.9, 1.9}, want: false,
+			front: []genetics.Objectives{{1.5, 3.5}, {3.5, 1.5}}},
+		{name: "same box, farther from the corner", o: genetics.Objectives{1.2, 3.9}, want: false,
+			front: []genetics.Objectives{{1.5, 3.5}, {3.5, 1.5}}},
+		{name: "same box, nearer the corner", o: genetics.Objectives{1.8, 3.6}, want: true,
+			front: []genetics.Objectives{{3.5, 1.5}, {1.8, 3.6}}},
+		{name: "same box, dominating", o: genetics.Objectives{3.6, 1.6}, want: true,
+			front: []genetics.Objectives{{1.8, 3.6}, {3.6, 1.6}}},
+		{name: "same box, dominated", o: genetics.Objectives{3.55, 1.55}, want: false,
+			front: []genetics.Objectives{{1.8, 3.6}, {3.6, 1.6}}},
+		{name: "dominating box", o: genetics.Objectives{4.1, 2.1}, want: true,
+			front: []genetics.Objectives{{1.8, 3.6}, {4.1, 2.1}}},
+		{name: "dominating every box", o: genetics.Objectives{5, 5}, want: true,
+			front: []genetics.Objectives{{5, 5}}},
+	} {
+		got, err := a.Add(s.New(a.Len()), test.o)
+		if err != nil {
+			t.Fatalf("%s: Add(); err=%s", test.name, err)
+		}
+		if got != test.want {
+			t.Errorf("%s: Add(%v)=%t; want %t", test.name, test.o, got, test.want)
+		}
+		if _, front := a.Front(); !cmp.Equal(front, test.front) {
+			t.Errorf("%s: Front()=%v; want %v", test.name, front, test.front)
+		}
+	}
+
+	for _, bad := range []*genetics.EpsilonArchive{
+		{Epsilon: []float64{1}},
+		{Epsilon: []float64{1, 0}},
+		{Epsilon: []float64{1, math.NaN()}},
+	} {
+		if _, err := bad.Add(s.New(0), genetics.Objectives{1, 1}); err == nil {
+			t.Errorf("Add() to an archive with Epsilon %v should fail", bad.Epsilon)
+		}
+	}
+	if _, err := a.Add(s.New(0), genetics.Objectives{1, genetics.Fitness(math.NaN())}); err == nil {
+		t.Error("Add() of a NaN objective should fail")
+	}
+}
+
+// TestEpsilonArchiveApproximation checks that the archive is an epsilon-
+// approximation of every point added to it: no member dominates another and every
+// point is within Epsilon of a member in every objective.
+func TestEpsilonArchiveApproximation(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	s := genetics.NewSpecies(1, 1)
+	for _, maxSize := range []int{0, 8} {
+		a := &genetics.EpsilonArchive{Epsilon: []float64{0.05, 0.05, 0.05}, MaxSize: maxSize}
+		var points []genetics.Objectives
+		for k := 0; k < 2000; k++ {
+			// Points near the surface of the positive octant of the unit sphere
+			x, y, z := rng.Float64(), rng.Float64(), rng.Float64()
+			r := math.Sqrt(x*x+y*y+z*z) * (1 + rng.Float64()/2)
+			o := genetics.Objectives{genetics.Fitness(x / r), genetics.Fitness(y / r), genetics.Fitness(z / r)}
+			points = append(points, o)
+			if _, err := a.Add(s.New(), o); err != nil {
+				t.Fatalf("Add(); err=%s", err)
+			}
+		}
+		_, front := a.Front()
+		if maxSize > 0 && (len(front) > maxSize || a.Epsilon[0] <= 0.05) {
+			t.Errorf("MaxSize %d: archive of %d members with Epsilon %v; want it coarsened to fit", maxSize, len(front), a.Epsilon)
+		}
+		if len(front) < 3 {
+			t.Errorf("MaxSize %d: archive of %d members is too sparse", maxSize, len(front))
+		}
+		for i, p := range front {
+			for j, q := range front {
+				if i != j && genetics.Dominates(p, q) {
+					t.Errorf("MaxSize %d: member %v dominates member %v", maxSize, p, q)
+				}
+			}
+		}
+	point:
+		for _, p := range points {
+			for _, q := range front {
+				if near(q, p, a.Epsilon) {
+					continue point
+				}
+			}
+			t.Errorf("MaxSize %d: no member is within %v of %v", maxSize, a.Epsilon, p)
+		}
+	}
+}
+
+// near reports whether a is within eps of being at least as fit as b in every objective.
+func near(a, b genetics.Objectives, eps []float64) bool {
+	for i := range a {
+		if float64(a[i])+eps[i] < float64(b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,196 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// PenaltySchedule sets how heavily a PenaltyEvaluator penalizes violating
+// Constraints as a run progresses.
+type PenaltySchedule interface {
+	// Coefficient returns the penalty per unit of Violation for generation, given
+	// the fraction of the population which is feasible at its start.
+	Coefficient(generation int, feasible float64) float64
+}
+
+// StaticPenalty is a PenaltySchedule which penalizes every generation alike.
+type StaticPenalty float64
+
+func (p StaticPenalty) String() string {
+	return fmt.Sprintf("StaticPenalty(%g)", float64(p))
+}
+
+// Coefficient implements PenaltySchedule
+func (p StaticPenalty) Coefficient(generation int, feasible float64) float64 {
+	return float64(p)
+}
+
+// DynamicPenalty is the PenaltySchedule of Joines and Houck, which penalizes
+// generation t by (C*(t+1))^Alpha. Early generations may explore infeasible
+// regions cheaply, while later generations are driven into the feasible region.
+type DynamicPenalty struct {
+	// C scales the generation. Defaults to 0.5.
+	C float64
+	// Alpha is the exponent of the scaled generation. Defaults to 2.
+	Alpha float64
+}
+
+func (p DynamicPenalty) String() string {
+	return fmt.Sprintf("DynamicPenalty(%g,%g)", p.C, p.Alpha)
+}
+
+// Coefficient implements PenaltySchedule
+func (p DynamicPenalty) Coefficient(generation int, feasible float64) float64 {
+	c, alpha := p.C, p.Alpha
+	if c == 0 {
+		c = 0.5
+	}
+	if alpha == 0 {
+		alpha = 2
+	}
+	return math.Pow(c*float64(generation+1), alpha)
+}
+
+// AdaptivePenalty is a PenaltySchedule which steers the population toward a
+// Target fraction of feasible members: each generation the coefficient is
+// multiplied by Factor if fewer members are feasible than Target and divided by
+// Factor if more are. The search thus hugs the boundary of the feasible region,
+// where constrained optima usually lie. It is restarted by generation 0 and is not
+// goroutine safe, so each Engine needs its own.
+type AdaptivePenalty struct {
+	// Initial is the coefficient of generation 0. Defaults to 1.
+	Initial float64
+	// Target is the fraction of the population which should be feasible, in
+	// (0, 1). Defaults to 0.5.
+	Target float64
+	// Factor, greater than 1, is how much the coefficient changes each generation.
+	// Defaults to 2.
+	Factor float64
+
+	coefficient float64
+}
+
+func (p *AdaptivePenalty) String() string {
+	return fmt.Sprintf("AdaptivePenalty(%g,%g,%g)", p.Initial, p.Target, p.Factor)
+}
+
+// Coefficient implements PenaltySchedule
+func (p *AdaptivePenalty) Coefficient(generation int, feasible float64) float64 {
+	target, factor := p.Target, p.Factor
+	if target == 0 {
+		target = 0.5
+	}
+	if factor == 0 {
+		factor = 2
+	}
+	switch {
+	case generation == 0 || p.coefficient == 0:
+		p.coefficient = p.Initial
+		if p.coefficient == 0 {
+			p.coefficient = 1
+		}
+	case feasible < target:
+		p.coefficient *= factor
+	case feasible > target:
+		p.coefficient /= factor
+	}
+	return p.coefficient
+}
+
+// PenaltyEvaluator is an Evaluator which blends the Violation of Constraints into
+// the Fitness of the wrapped Evaluator, so that constrained problems can be solved
+// by the unconstrained machinery: the Violation, weighted by the Schedule's
+// coefficient, is subtracted from the Fitness, or added to it if the population
+// is minimized. Unlike ConstrainedTournamentSelection, infeasible Chromosomes
+// which score well enough still compete with feasible ones.
+//
+// A PenaltyEvaluator follows the Engine's generation counter as an Observer, so
+// it must also be one of the Engine's Observers. Until then it uses the
+// coefficient of generation 0. It is goroutine safe if Evaluator is.
+type PenaltyEvaluator struct {
+	NopObserver
+
+	Evaluator   Evaluator
+	Constraints Constraints
+	// Schedule sets the coefficient of each generation. Defaults to
+	// StaticPenalty(1).
+	Schedule PenaltySchedule
+	// When the coefficient changes, the whole population is rescored so that
+	// members which survive a generation are compared by the current penalty.
+	// KeepScores, if set, skips this to save evaluations, at the risk that an
+	// infeasible member scored under a lighter penalty outranks feasible ones.
+	// Either way, the Engine's Cache is cleared when the coefficient changes.
+	KeepScores bool
+
+	mu          sync.RWMutex
+	started     bool
+	coefficient float64
+	objective   Objective
+}
+
+// Coefficient returns the penalty per unit of Violation of the current generation.
+func (p *PenaltyEvaluator) Coefficient() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.started {
+		p.coefficient = p.schedule().Coefficient(0, 1)
+		p.started = true
+	}
+	return p.coefficient
+}
+
+// Evaluate implements Evaluator
+func (p *PenaltyEvaluator) Evaluate(c Chromosome) (Fitness, error) {
+	f, err := p.Evaluator.Evaluate(c)
+	if err != nil {
+		return 0, err
+	}
+	violation := p.Constraints.Violation(c)
+	if violation == 0 {
+		return f, nil
+	}
+	penalty := Fitness(p.Coefficient() * violation)
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.objective == Minimize {
+		return f + penalty, nil
+	}
+	return f - penalty, nil
+}
+
+// OnGenerationStart implements Observer by moving to the coefficient of
+// generation.
+func (p *PenaltyEvaluator) OnGenerationStart(e *Engine, generation int) {
+	pop := e.Population()
+	feasible := 0
+	for _, c := range pop.Chromosomes {
+		if p.Constraints.Violation(c) == 0 {
+			feasible++
+		}
+	}
+	ratio := 1.0
+	if len(pop.Chromosomes) != 0 {
+		ratio = float64(feasible) / float64(len(pop.Chromosomes))
+	}
+	coefficient := p.schedule().Coefficient(generation, ratio)
+
+	p.mu.Lock()
+	changed := p.started && coefficient != p.coefficient
+	p.started, p.coefficient, p.objective = true, coefficient, pop.Objective
+	p.mu.Unlock()
+	switch {
+	case !changed || generation == 0:
+	case !p.KeepScores:
+		e.FitnessChanged(nil)
+	case e.Cache != nil:
+		e.Cache.Clear()
+	}
+}
+
+func (p *PenaltyEvaluator) schedule() PenaltySchedule {
+	if p.Schedule == nil {
+		return StaticPenalty(1)
+	}
+	return p.Schedule
+}
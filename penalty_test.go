@@ -0,0 +1,170 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestPenaltySchedules(t *testing.T) {
+	type generation struct {
+		feasible float64
+		want     float64
+	}
+	for _, test := range []struct {
+		schedule    genetics.PenaltySchedule
+		generations []generation
+	}{
+		{
+			schedule:    genetics.StaticPenalty(3),
+			generations: []generation{{0, 3}, {1, 3}, {0.5, 3}},
+		}, {
+			schedule:    genetics.DynamicPenalty{},
+			generations: []generation{{0, 0.25}, {0, 1}, {1, 2.25}, {1, 4}},
+		}, {
+			schedule:    genetics.DynamicPenalty{C: 1, Alpha: 1},
+			generations: []generation{{0, 1}, {0, 2}, {0, 3}},
+		}, {
+			schedule:    &genetics.AdaptivePenalty{},
+			generations: []generation{{0, 1}, {0.2, 2}, {0.1, 4}, {0.5, 4}, {0.9, 2}},
+		}, {
+			schedule:    &genetics.AdaptivePenalty{Initial: 10, Target: 0.8, Factor: 10},
+			generations: []generation{{1, 10}, {0.9, 1}, {0.5, 10}},
+		},
+	} {
+		var got, want []float64
+		for gen, g := range test.generations {
+			got = append(got, test.schedule.Coefficient(gen, g.feasible))
+			want = append(want, g.want)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("%s.Coefficient() got=%v diff=%s", test.schedule, got, diff)
+		}
+	}
+
+	// Generation 0 restarts an AdaptivePenalty
+	p := &genetics.AdaptivePenalty{}
+	p.Coefficient(0, 0)
+	p.Coefficient(1, 0)
+	if got := p.Coefficient(0, 0); got != 1 {
+		t.Errorf("AdaptivePenalty.Coefficient(0) after a run=%g; want 1", got)
+	}
+}
+
+func TestPenaltyEvaluator(t *testing.T) {
+	s := genetics.NewSpecies(2, 5)
+	sum := genetics.FitnessFunc(func(c genetics.Chromosome) genetics.Fitness {
+		return genetics.Fitness(c.Genes[0] + c.Genes[1])
+	})
+	p := &genetics.PenaltyEvaluator{
+		Evaluator:   sum,
+		Constraints: genetics.Constraints{genetics.SumAtMost{Loci: []int{0, 1}, Max: 4}},
+		Schedule:    genetics.StaticPenalty(3),
+	}
+	for _, test := range []struct {
+		genes []genetics.Gene
+		want  genetics.Fitness
+	}{
+		{genes: []genetics.Gene{1, 2}, want: 3},
+		{genes: []genetics.Gene{2, 2}, want: 4},
+		{genes: []genetics.Gene{3, 3}, want: 0},
+		{genes: []genetics.Gene{5, 5}, want: -8},
+	} {
+		if got, err := p.Evaluate(s.New(test.genes...)); err != nil || got != test.want {
+			t.Errorf("Evaluate(%v)=%g, %v; want %g", test.genes, got, err, test.want)
+		}
+	}
+}
+
+func TestPenaltyEvaluatorEngine(t *testing.T) {
+	const numGenes, limit = 8, 12
+	s := genetics.NewSpecies(numGenes, 5)
+	constraints := genetics.Constraints{genetics.SumAtMost{Loci: []int{0, 1, 2, 3, 4, 5, 6, 7}, Max: limit}}
+	// The sum of the genes rewards violating the constraint, or satisfying it
+	// when minimizing the negated sum.
+	sum := func(c genetics.Chromosome) genetics.Fitness {
+		sum := 0
+		for _, g := range c.Genes {
+			sum += g
+		}
+		return genetics.Fitness(sum)
+	}
+	for _, test := range []struct {
+		name      string
+		schedule  genetics.PenaltySchedule
+		keep      bool
+		objective genetics.Objective
+		// evaluations, if set, is how often the Evaluator should be called.
+		evaluations int
+		// slack is how far short of the limit the best sum may fall.
+		slack genetics.Fitness
+	}{
+		{name: "static", schedule: genetics.StaticPenalty(2)},
+		// Survivors keep stale scores, so only the evaluations are checked
+		{name: "dynamic keeping scores", schedule: genetics.DynamicPenalty{}, keep: true, evaluations: 40 + 49*20 + 20},
+		{name: "dynamic", schedule: genetics.DynamicPenalty{}, evaluations: 40 + 49*40 + 20},
+		{name: "adaptive", schedule: &genetics.AdaptivePenalty{}, slack: 1},
+		{name: "adaptive minimized", schedule: &genetics.AdaptivePenalty{}, objective: genetics.Minimize, slack: 1},
+	} {
+		rng := rand.New()
+		rng.Seed(42)
+		pop, err := s.NewRandPopulation(rng, 40)
+		if err != nil {
+			t.Fatalf("NewRandPopulation(); err=%s", err)
+		}
+		pop.Objective = test.objective
+		evaluations := 0
+		fitness := genetics.FitnessFunc(func(c genetics.Chromosome) genetics.Fitness {
+			evaluations++
+			if test.objective == genetics.Minimize {
+				return -sum(c)
+			}
+			return sum(c)
+		})
+		p := &genetics.PenaltyEvaluator{
+			Evaluator:   fitness,
+			Constraints: constraints,
+			Schedule:    test.schedule,
+			KeepScores:  test.keep,
+		}
+		engine := genetics.Engine{
+			Evolver: genetics.Evolver{
+				ReplacementCount: 20,
+				CrossoverRate:    0.9,
+				MutationRate:     0.5,
+				Selector:         genetics.TournamentSelection{Size: 2},
+				Crossover:        genetics.MultiPointCrossover{Points: 1},
+				Mutator:          genetics.RandomResettingMutation{},
+			},
+			Evaluator: p,
+			Observers: []genetics.Observer{p},
+		}
+		if err := engine.Run(rng, pop, 50); err != nil {
+			t.Fatalf("%s: Run(); err=%s", test.name, err)
+		}
+		if test.evaluations != 0 && evaluations != test.evaluations {
+			t.Errorf("%s: Run() made %d evaluations; want %d", test.name, evaluations, test.evaluations)
+		}
+		if test.keep {
+			continue
+		}
+		best, _ := pop.Best()
+		if _, ok := test.schedule.(*genetics.AdaptivePenalty); ok {
+			// The population straddles the boundary, so the best may be infeasible
+			best = genetics.Chromosome{}
+			for _, c := range pop.Chromosomes {
+				if constraints.Violation(c) == 0 && (best.Genes == nil || sum(c) > sum(best)) {
+					best = c
+				}
+			}
+		}
+		if best.Genes == nil {
+			t.Errorf("%s: Run() found no feasible Chromosome", test.name)
+		} else if err := constraints.Validate(best); err != nil || sum(best) < limit-test.slack {
+			t.Errorf("%s: Run() found %v with sum %g, err=%v; want a feasible sum within %g of %d", test.name, best.Genes, sum(best), err, test.slack, limit)
+		}
+	}
+}
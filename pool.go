@@ -0,0 +1,65 @@
+package genetics
+
+import "sync"
+
+var (
+	// genePools holds a sync.Pool of released Genes for each length of Genes, so
+	// that every Species with the same NumGenes shares one pool. Pools are emptied
+	// by the garbage collector, so released Genes are not leaked.
+	genePoolsMu sync.RWMutex
+	genePools   = map[int]*sync.Pool{}
+)
+
+// genePool returns the pool of released Genes of length n.
+func genePool(n int) *sync.Pool {
+	genePoolsMu.RLock()
+	p, ok := genePools[n]
+	genePoolsMu.RUnlock()
+	if ok {
+		return p
+	}
+	genePoolsMu.Lock()
+	defer genePoolsMu.Unlock()
+	if p, ok = genePools[n]; !ok {
+		p = &sync.Pool{}
+		genePools[n] = p
+	}
+	return p
+}
+
+// alloc returns a Chromosome of s whose Genes are unspecified, reusing Genes
+// released to s if possible.
+func (s *Species) alloc() Chromosome {
+	if g, ok := genePool(s.NumGenes).Get().(*[]Gene); ok {
+		return Chromosome{Species: s, Genes: *g}
+	}
+	return Chromosome{Species: s, Genes: make([]Gene, s.NumGenes)}
+}
+
+// Clone returns a copy of c, a Chromosome of s, which does not share Genes with
+// c. Its Genes are reused from a Chromosome passed to Release if possible, which
+// lets very large populations be evolved without allocating every child.
+func (s *Species) Clone(c Chromosome) Chromosome {
+	if len(c.Genes) != s.NumGenes {
+		return Chromosome{Species: s, Genes: append([]Gene(nil), c.Genes...)}
+	}
+	x := s.alloc()
+	copy(x.Genes, c.Genes)
+	return x
+}
+
+// Release returns the Genes of c, a Chromosome of s, to be reused by a later
+// Clone. Neither c nor any Chromosome sharing its Genes may be used afterwards.
+// Chromosomes which are never released are simply garbage collected.
+func (s *Species) Release(c Chromosome) {
+	if len(c.Genes) != s.NumGenes || cap(c.Genes) != len(c.Genes) {
+		return
+	}
+	g := c.Genes
+	genePool(s.NumGenes).Put(&g)
+}
+
+// sharesGenes reports whether a and b have the same Genes.
+func sharesGenes(a, b Chromosome) bool {
+	return len(a.Genes) != 0 && len(b.Genes) != 0 && &a.Genes[0] == &b.Genes[0]
+}
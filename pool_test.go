@@ -0,0 +1,57 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestSpeciesCloneRelease(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	s := genetics.NewSpecies(16, 9)
+	for i := 0; i < 100; i++ {
+		c, err := s.NewRand(rng)
+		if err != nil {
+			t.Fatalf("NewRand(); err=%s", err)
+		}
+		want := append([]genetics.Gene(nil), c.Genes...)
+		x := s.Clone(c)
+		if diff := cmp.Diff(c, x); diff != "" {
+			t.Fatalf("Species.Clone() differs from the original; diff=%s", diff)
+		}
+		x.Genes[0]++
+		if diff := cmp.Diff(want, c.Genes); diff != "" {
+			t.Fatalf("Species.Clone() shares Genes with the original; diff=%s", diff)
+		}
+		// Released Genes, including those of other lengths, must not corrupt later clones.
+		s.Release(x)
+		s.Release(genetics.NewSpecies(4, 9).New())
+	}
+}
+
+func TestEngineReplaceReuseBuffers(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	pop := newBinaryPopulation(t, rng, 8, 4)
+	engine := genetics.Engine{
+		Evolver:      genetics.NewEvolver(),
+		Evaluator:    genetics.FitnessFunc(oneMax),
+		ReuseBuffers: true,
+	}
+	engine.Reset(pop)
+	c := pop.Chromosomes[0]
+	c.Genes[0] = 1 - c.Genes[0]
+	// Replacing a member with itself must not release its Genes.
+	engine.Replace(0, c, "Edit")
+	want := append([]genetics.Gene(nil), c.Genes...)
+	for i := 0; i < 10; i++ {
+		c.Species.Clone(c).Genes[1] = 7
+	}
+	if diff := cmp.Diff(want, pop.Chromosomes[0].Genes); diff != "" {
+		t.Errorf("Engine.Replace() released the Genes it put in the population; diff=%s", diff)
+	}
+}
@@ -0,0 +1,156 @@
+package genetics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/inlined/rand"
+)
+
+// Population is a generation of Chromosomes of a single Species. Fitness, if set,
+// holds the score of the Chromosome at the same index, and Objective is the
+// direction in which it improves.
+// Population implements sort.Interface, ordering fitter Chromosomes first.
+type Population struct {
+	Species     *Species
+	Chromosomes []Chromosome
+	Fitness     []Fitness
+	Objective   Objective
+}
+
+// Len implements sort.Interface
+func (p Population) Len() int { return len(p.Chromosomes) }
+
+// Less implements sort.Interface; fitter Chromosomes sort first.
+func (p Population) Less(i, j int) bool { return p.Objective.Better(p.Fitness[i], p.Fitness[j]) }
+
+// Swap implements sort.Interface, keeping Chromosomes and Fitness aligned.
+func (p Population) Swap(i, j int) {
+	p.Chromosomes[i], p.Chromosomes[j] = p.Chromosomes[j], p.Chromosomes[i]
+	p.Fitness[i], p.Fitness[j] = p.Fitness[j], p.Fitness[i]
+}
+
+// Sort orders p from most to least fit. Chromosomes with equal fitness keep
+// their relative order.
+func (p Population) Sort() {
+	sort.Stable(p)
+}
+
+// Best returns the fittest member of a non-empty Population. Ties go to the
+// lowest index.
+func (p Population) Best() (Chromosome, Fitness) {
+	best := 0
+	for i := 1; i < len(p.Fitness); i++ {
+		if p.Objective.Better(p.Fitness[i], p.Fitness[best]) {
+			best = i
+		}
+	}
+	return p.Chromosomes[best], p.Fitness[best]
+}
+
+// Worst returns the least fit member of a non-empty Population. Ties go to the
+// highest index.
+func (p Population) Worst() (Chromosome, Fitness) {
+	worst := 0
+	for i := 1; i < len(p.Fitness); i++ {
+		if !p.Objective.Better(p.Fitness[i], p.Fitness[worst]) {
+			worst = i
+		}
+	}
+	return p.Chromosomes[worst], p.Fitness[worst]
+}
+
+// Clone returns a deep copy of p which shares only its Species.
+func (p Population) Clone() Population {
+	c := Population{
+		Species:     p.Species,
+		Chromosomes: make([]Chromosome, len(p.Chromosomes)),
+		Objective:   p.Objective,
+	}
+	for n, chromosome := range p.Chromosomes {
+		c.Chromosomes[n] = chromosome.Clone()
+	}
+	if p.Fitness != nil {
+		c.Fitness = append([]Fitness(nil), p.Fitness...)
+	}
+	return c
+}
+
+// Slice returns the sub-population [i, j). Like a Go slice, the sub-population
+// shares memory with p; use Clone to detach it.
+func (p Population) Slice(i, j int) Population {
+	s := Population{
+		Species:     p.Species,
+		Chromosomes: p.Chromosomes[i:j],
+		Objective:   p.Objective,
+	}
+	if p.Fitness != nil {
+		s.Fitness = p.Fitness[i:j]
+	}
+	return s
+}
+
+// scores returns p's Fitness as scores which increase with fitness; see Objective.Scores.
+func (p Population) scores() []Fitness {
+	return p.Objective.Scores(p.Fitness)
+}
+
+// NewRandPopulation creates a Population of size Chromosomes initialized with NewRand.
+func (s *Species) NewRandPopulation(r rand.Rand, size int) (*Population, error) {
+	return s.NewPopulation(r, size, 1, s.NewRand)
+}
+
+// NewPermPopulation creates a Population of size permutations initialized with NewPerm.
+func (s *Species) NewPermPopulation(r rand.Rand, size int) (*Population, error) {
+	return s.NewPopulation(r, size, 1, s.NewPerm)
+}
+
+// NewPopulation creates a Population of size Chromosomes initialized by init, e.g.
+// with a problem-specific heuristic. With more than one worker, Chromosomes are
+// initialized concurrently and each worker draws from its own random stream split
+// from r, so the Population is reproducible for a given seed and number of
+// workers. If any Chromosomes cannot be initialized, the error lists each failure.
+func (s *Species) NewPopulation(r rand.Rand, size, workers int, init func(r rand.Rand) (Chromosome, error)) (*Population, error) {
+	if size < 0 {
+		return nil, fmt.Errorf("Species.NewPopulation(); size %d must not be negative", size)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > size {
+		workers = size
+	}
+	pop := &Population{Species: s, Chromosomes: make([]Chromosome, size)}
+	errs := make([]error, size)
+	fill := func(r rand.Rand, from, to int) {
+		for i := from; i < to; i++ {
+			c, err := init(r)
+			if err != nil {
+				errs[i] = fmt.Errorf("chromosome %d: %s", i, err)
+				continue
+			}
+			pop.Chromosomes[i] = c
+		}
+	}
+	if workers <= 1 {
+		fill(r, 0, size)
+	} else {
+		streams := NewSeedSplitter(r.Int63n(math.MaxInt64)).Streams(workers)
+		var wg sync.WaitGroup
+		for w, stream := range streams {
+			wg.Add(1)
+			go func(r rand.Rand, from, to int) {
+				defer wg.Done()
+				fill(r, from, to)
+			}(stream, w*size/workers, (w+1)*size/workers)
+		}
+		wg.Wait()
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("Species.NewPopulation(); err=%s", err)
+	}
+	return pop, nil
+}
@@ -0,0 +1,127 @@
+package genetics_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestPopulation(t *testing.T) {
+	s := genetics.NewSpecies(2, 9)
+	pop := genetics.Population{
+		Species:     s,
+		Chromosomes: []genetics.Chromosome{s.New(1, 1), s.New(2, 2), s.New(3, 3), s.New(4, 4)},
+		Fitness:     []genetics.Fitness{5, 9, 1, 9},
+	}
+
+	if best, f := pop.Best(); f != 9 || best.Genes[0] != 2 {
+		t.Errorf("Best()=%v,%g; want the first chromosome with fitness 9", best.Genes, f)
+	}
+	if worst, f := pop.Worst(); f != 1 || worst.Genes[0] != 3 {
+		t.Errorf("Worst()=%v,%g; want [3 3],1", worst.Genes, f)
+	}
+
+	clone := pop.Clone()
+	clone.Chromosomes[0].Genes[0] = 0
+	clone.Fitness[0] = 0
+	if pop.Chromosomes[0].Genes[0] != 1 || pop.Fitness[0] != 5 {
+		t.Error("Clone() shares memory with the original Population")
+	}
+
+	sub := pop.Slice(1, 3)
+	if sub.Len() != 2 || sub.Fitness[0] != 9 || sub.Species != s {
+		t.Errorf("Slice(1, 3) = %+v", sub)
+	}
+
+	pop.Sort()
+	var order []genetics.Gene
+	for _, c := range pop.Chromosomes {
+		order = append(order, c.Genes[0])
+	}
+	if diff := cmp.Diff([]genetics.Gene{2, 4, 1, 3}, order); diff != "" {
+		t.Errorf("Sort() ordered chromosomes %v; diff=%s", order, diff)
+	}
+	if diff := cmp.Diff([]genetics.Fitness{9, 9, 5, 1}, pop.Fitness); diff != "" {
+		t.Errorf("Sort() ordered fitness %v; diff=%s", pop.Fitness, diff)
+	}
+}
+
+func TestNewPopulation(t *testing.T) {
+	s := genetics.NewSpecies(8, 7)
+	rng := rand.New()
+	rng.Seed(42)
+	pop, err := s.NewPermPopulation(rng, 10)
+	if err != nil {
+		t.Fatalf("NewPermPopulation(); err=%s", err)
+	}
+	if pop.Species != s || len(pop.Chromosomes) != 10 {
+		t.Fatalf("NewPermPopulation() created %d chromosomes of %v; want 10 of %v", len(pop.Chromosomes), pop.Species, s)
+	}
+	for _, c := range pop.Chromosomes {
+		if err := s.ValidatePermutation(c); err != nil {
+			t.Errorf("ValidatePermutation(%v); err=%s", c.Genes, err)
+		}
+	}
+
+	pop, err = s.NewRandPopulation(rng, 10)
+	if err != nil {
+		t.Fatalf("NewRandPopulation(); err=%s", err)
+	}
+	for _, c := range pop.Chromosomes {
+		if err := s.Validate(c); err != nil {
+			t.Errorf("Validate(%v); err=%s", c.Genes, err)
+		}
+	}
+
+	if _, err := genetics.NewSpecies(8, 3).NewPermPopulation(rng, 10); err == nil {
+		t.Error("NewPermPopulation() should fail when permutations do not fit in MaxAllele")
+	}
+}
+
+func TestNewPopulationParallel(t *testing.T) {
+	s := genetics.NewSpecies(16, 15)
+	create := func() *genetics.Population {
+		rng := rand.New()
+		rng.Seed(42)
+		pop, err := s.NewPopulation(rng, 100, 4, s.NewPerm)
+		if err != nil {
+			t.Fatalf("NewPopulation(); err=%s", err)
+		}
+		return pop
+	}
+	pop := create()
+	for i, c := range pop.Chromosomes {
+		if err := s.ValidatePermutation(c); err != nil {
+			t.Fatalf("chromosome %d is not a permutation; err=%s", i, err)
+		}
+	}
+	if diff := cmp.Diff(pop, create()); diff != "" {
+		t.Errorf("NewPopulation() is not reproducible; -first +second:\n%s", diff)
+	}
+}
+
+func TestNewPopulationErrors(t *testing.T) {
+	s := genetics.NewSpecies(2, 1)
+	calls := 0
+	init := func(r rand.Rand) (genetics.Chromosome, error) {
+		calls++
+		if calls%3 == 0 {
+			return genetics.Chromosome{}, errors.New("unlucky")
+		}
+		return s.New(), nil
+	}
+	_, err := s.NewPopulation(rand.New(), 7, 1, init)
+	if err == nil {
+		t.Fatal("NewPopulation() should fail when init fails")
+	}
+	for _, want := range []string{"chromosome 2: unlucky", "chromosome 5: unlucky"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("NewPopulation(); err=%s; want it to report %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// Package problems collects canonical benchmark problems so that operator
+// comparisons and regression tests share ground truth. Each problem exposes a
+// Species factory and a Fitness method which can be adapted with
+// genetics.FitnessFunc or genetics.RealFitnessFunc. Problems which are naturally
+// minimized are negated, so fitter solutions always score higher.
+package problems
+
+import (
+	"math"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/rand"
+)
+
+// OneMax counts the genes set to 1 in a binary Chromosome of N genes.
+type OneMax struct {
+	N int
+}
+
+// Species returns a binary Species of N genes.
+func (p OneMax) Species() *genetics.Species {
+	return genetics.NewSpecies(p.N, 1)
+}
+
+// Fitness implements genetics.FitnessFunc
+func (p OneMax) Fitness(c genetics.Chromosome) genetics.Fitness {
+	f := genetics.Fitness(0)
+	for _, g := range c.Genes {
+		if g == 1 {
+			f++
+		}
+	}
+	return f
+}
+
+// Optimum is the fitness of the all-ones Chromosome.
+func (p OneMax) Optimum() genetics.Fitness {
+	return genetics.Fitness(p.N)
+}
+
+// Knapsack is the 0-1 knapsack problem. Gene i of a binary Chromosome chooses
+// item i; items are packed in order and any item which would exceed Capacity is
+// skipped, so every Chromosome is feasible. Fitness is the total value packed.
+type Knapsack struct {
+	Capacity int
+	Weights  []int
+	Values   []int
+}
+
+// NewKnapsack generates a random instance of n items whose weights and values are
+// uniform in [1, maxItem].
+func NewKnapsack(r rand.Rand, n, capacity, maxItem int) Knapsack {
+	k := Knapsack{
+		Capacity: capacity,
+		Weights:  make([]int, n),
+		Values:   make([]int, n),
+	}
+	for i := 0; i < n; i++ {
+		k.Weights[i] = 1 + int(r.Int31n(int32(maxItem)))
+		k.Values[i] = 1 + int(r.Int31n(int32(maxItem)))
+	}
+	return k
+}
+
+// Species returns a binary Species with a gene per item.
+func (k Knapsack) Species() *genetics.Species {
+	return genetics.NewSpecies(len(k.Weights), 1)
+}
+
+// Fitness implements genetics.FitnessFunc
+func (k Knapsack) Fitness(c genetics.Chromosome) genetics.Fitness {
+	weight, value := 0, 0
+	for i, g := range c.Genes {
+		if g == 0 || weight+k.Weights[i] > k.Capacity {
+			continue
+		}
+		weight += k.Weights[i]
+		value += k.Values[i]
+	}
+	return genetics.Fitness(value)
+}
+
+// NQueens places N queens on an N×N board. Chromosomes are permutations whose gene
+// i is the row of the queen in column i, so queens never share a row or column.
+// Fitness is the negated number of pairs of queens which share a diagonal; a
+// solution scores 0.
+type NQueens struct {
+	N int
+}
+
+// Species returns a permutation Species; use NewPerm to create Chromosomes.
+func (p NQueens) Species() *genetics.Species {
+	return genetics.NewSpecies(p.N, genetics.Gene(p.N-1))
+}
+
+// Fitness implements genetics.FitnessFunc. Pairs sharing a row are also counted,
+// so Chromosomes which are not permutations are still scored fairly.
+func (p NQueens) Fitness(c genetics.Chromosome) genetics.Fitness {
+	attacks := genetics.Fitness(0)
+	for i := range c.Genes {
+		for j := i + 1; j < len(c.Genes); j++ {
+			d := c.Genes[j] - c.Genes[i]
+			if d == 0 || d == j-i || d == i-j {
+				attacks++
+			}
+		}
+	}
+	return -attacks
+}
+
+// Continuous is a continuous benchmark function of N variables which is minimized
+// at 0. Fitness negates the function value.
+type Continuous struct {
+	N int
+
+	min, max float64
+	f        func(x []float64) float64
+}
+
+// Rastrigin is highly multimodal with a regular grid of local minima. Its domain is
+// [-5.12, 5.12] and its minimum is at the origin.
+func Rastrigin(n int) Continuous {
+	return Continuous{N: n, min: -5.12, max: 5.12, f: func(x []float64) float64 {
+		total := 10 * float64(len(x))
+		for _, v := range x {
+			total += v*v - 10*math.Cos(2*math.Pi*v)
+		}
+		return total
+	}}
+}
+
+// Rosenbrock is unimodal but its minimum at (1, 1, ..., 1) lies in a long curved
+// valley. Its domain is [-5, 10].
+func Rosenbrock(n int) Continuous {
+	return Continuous{N: n, min: -5, max: 10, f: func(x []float64) float64 {
+		total := 0.0
+		for i := 0; i+1 < len(x); i++ {
+			a, b := x[i+1]-x[i]*x[i], 1-x[i]
+			total += 100*a*a + b*b
+		}
+		return total
+	}}
+}
+
+// Ackley has a nearly flat outer region around a deep minimum at the origin. Its
+// domain is [-32.768, 32.768].
+func Ackley(n int) Continuous {
+	return Continuous{N: n, min: -32.768, max: 32.768, f: func(x []float64) float64 {
+		sumSq, sumCos := 0.0, 0.0
+		for _, v := range x {
+			sumSq += v * v
+			sumCos += math.Cos(2 * math.Pi * v)
+		}
+		d := float64(len(x))
+		return -20*math.Exp(-0.2*math.Sqrt(sumSq/d)) - math.Exp(sumCos/d) + 20 + math.E
+	}}
+}
+
+// Species returns a RealSpecies of N genes bounded by the function's domain.
+func (p Continuous) Species() *genetics.RealSpecies {
+	return genetics.NewUniformRealSpecies(p.N, p.min, p.max)
+}
+
+// Value is the function value at x.
+func (p Continuous) Value(x []float64) float64 {
+	return p.f(x)
+}
+
+// Fitness implements genetics.RealFitnessFunc
+func (p Continuous) Fitness(c genetics.RealChromosome) genetics.Fitness {
+	return -genetics.Fitness(p.f(c.Genes))
+}
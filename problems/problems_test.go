@@ -0,0 +1,171 @@
+package problems_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/genetics/problems"
+)
+
+func TestOneMax(t *testing.T) {
+	p := problems.OneMax{N: 4}
+	s := p.Species()
+	if got := p.Fitness(s.New(1, 0, 1, 1)); got != 3 {
+		t.Errorf("Fitness()=%g; want 3", got)
+	}
+	if got := p.Fitness(s.New(1, 1, 1, 1)); got != p.Optimum() {
+		t.Errorf("Fitness()=%g; want Optimum()=%g", got, p.Optimum())
+	}
+}
+
+func TestKnapsack(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	k := problems.NewKnapsack(rng, 20, 100, 30)
+	if len(k.Weights) != 20 || len(k.Values) != 20 || k.Capacity != 100 {
+		t.Fatalf("NewKnapsack() generated %+v", k)
+	}
+	for i := range k.Weights {
+		if k.Weights[i] < 1 || k.Weights[i] > 30 || k.Values[i] < 1 || k.Values[i] > 30 {
+			t.Errorf("item %d has weight %d and value %d; want [1, 30]", i, k.Weights[i], k.Values[i])
+		}
+	}
+
+	k = problems.Knapsack{Capacity: 10, Weights: []int{6, 5, 4}, Values: []int{1, 2, 3}}
+	// The second item no longer fits once the first is packed
+	if got := k.Fitness(k.Species().New(1, 1, 1)); got != 4 {
+		t.Errorf("Fitness()=%g; want 4", got)
+	}
+	if got := k.Fitness(k.Species().New(0, 1, 1)); got != 5 {
+		t.Errorf("Fitness()=%g; want 5", got)
+	}
+}
+
+func TestNQueens(t *testing.T) {
+	p := problems.NQueens{N: 4}
+	s := p.Species()
+	for _, test := range []struct {
+		genes []genetics.Gene
+		want  genetics.Fitness
+	}{
+		{genes: []genetics.Gene{1, 3, 0, 2}, want: 0},
+		{genes: []genetics.Gene{0, 1, 2, 3}, want: -6},
+		{genes: []genetics.Gene{0, 0, 3, 3}, want: -3},
+	} {
+		if got := p.Fitness(s.New(test.genes...)); got != test.want {
+			t.Errorf("Fitness(%v)=%g; want %g", test.genes, got, test.want)
+		}
+	}
+}
+
+func TestContinuous(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		p       problems.Continuous
+		optimum []float64
+		bound   float64
+	}{
+		{name: "Rastrigin", p: problems.Rastrigin(3), optimum: []float64{0, 0, 0}, bound: 5.12},
+		{name: "Rosenbrock", p: problems.Rosenbrock(3), optimum: []float64{1, 1, 1}, bound: 10},
+		{name: "Ackley", p: problems.Ackley(3), optimum: []float64{0, 0, 0}, bound: 32.768},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			s := test.p.Species()
+			if s.NumGenes() != 3 || s.Max[0] != test.bound {
+				t.Errorf("Species() has %d genes bounded by %g; want 3 bounded by %g", s.NumGenes(), s.Max[0], test.bound)
+			}
+			if v := test.p.Value(test.optimum); math.Abs(v) > 1e-12 {
+				t.Errorf("Value(%v)=%g; want 0", test.optimum, v)
+			}
+			if f := test.p.Fitness(s.New(test.optimum...)); math.Abs(float64(f)) > 1e-12 {
+				t.Errorf("Fitness(%v)=%g; want 0", test.optimum, f)
+			}
+			away := s.New(2, 2, 2)
+			if f := test.p.Fitness(away); f >= 0 || f != -genetics.Fitness(test.p.Value(away.Genes)) {
+				t.Errorf("Fitness(%v)=%g; want the negated value", away.Genes, f)
+			}
+		})
+	}
+}
+
+const euc2D = `NAME : square
+COMMENT : four corners of a 3x4 rectangle
+TYPE : TSP
+DIMENSION : 4
+EDGE_WEIGHT_TYPE : EUC_2D
+NODE_COORD_SECTION
+1 0 0
+2 3 0
+3 3 4
+4 0 4
+EOF
+`
+
+const upperRow = `NAME: explicit
+TYPE: TSP
+DIMENSION: 4
+EDGE_WEIGHT_TYPE: EXPLICIT
+EDGE_WEIGHT_FORMAT: UPPER_ROW
+EDGE_WEIGHT_SECTION
+ 3 5 4
+ 4 5
+ 3
+EOF
+`
+
+func TestLoadTSPLIB(t *testing.T) {
+	want := [][]int{
+		{0, 3, 5, 4},
+		{3, 0, 4, 5},
+		{5, 4, 0, 3},
+		{4, 5, 3, 0},
+	}
+	for _, test := range []struct {
+		name   string
+		data   string
+		coords [][2]float64
+	}{
+		{name: "square", data: euc2D, coords: [][2]float64{{0, 0}, {3, 0}, {3, 4}, {0, 4}}},
+		{name: "explicit", data: upperRow},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			tsp, err := problems.LoadTSPLIB(strings.NewReader(test.data))
+			if err != nil {
+				t.Fatalf("LoadTSPLIB(); err=%s", err)
+			}
+			if tsp.Name != test.name {
+				t.Errorf("Name=%q; want %q", tsp.Name, test.name)
+			}
+			if diff := cmp.Diff(want, tsp.Distances); diff != "" {
+				t.Errorf("Distances differ; -want +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(test.coords, tsp.Coords); diff != "" {
+				t.Errorf("Coords differ; -want +got:\n%s", diff)
+			}
+			s := tsp.Species()
+			if got := tsp.Fitness(s.New(0, 1, 2, 3)); got != -14 {
+				t.Errorf("Fitness() of the perimeter=%g; want -14", got)
+			}
+			if got := tsp.Fitness(s.New(0, 2, 1, 3)); got != -18 {
+				t.Errorf("Fitness() of a crossing tour=%g; want -18", got)
+			}
+		})
+	}
+
+	for _, data := range []string{
+		"TYPE: ATSP\n",
+		"NODE_COORD_SECTION\n1 0 0\n",
+		"DIMENSION: 2\nEDGE_WEIGHT_TYPE: EUC_3D\nNODE_COORD_SECTION\n1 0 0\n2 1 1\n",
+		"DIMENSION: 3\nEDGE_WEIGHT_TYPE: EUC_2D\nNODE_COORD_SECTION\n1 0 0\n2 1 1\n",
+		"DIMENSION: 3\nEDGE_WEIGHT_TYPE: EXPLICIT\nEDGE_WEIGHT_FORMAT: UPPER_ROW\nEDGE_WEIGHT_SECTION\n1 2\n",
+	} {
+		if _, err := problems.LoadTSPLIB(strings.NewReader(data)); err == nil {
+			t.Errorf("LoadTSPLIB(%q) should fail", data)
+		}
+	}
+}
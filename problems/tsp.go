@@ -0,0 +1,237 @@
+package problems
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/inlined/genetics"
+)
+
+// TSP is the symmetric travelling salesperson problem. Chromosomes are
+// permutations of the cities; Fitness is the negated length of the closed tour.
+type TSP struct {
+	Name string
+	// Distances[i][j] is the distance from city i to city j.
+	Distances [][]int
+	// Coords, if known, is the position of each city, e.g. for plotting tours.
+	// GEO instances hold each city's latitude and longitude.
+	Coords [][2]float64
+}
+
+// Species returns a permutation Species; use NewPerm to create Chromosomes.
+func (t TSP) Species() *genetics.Species {
+	return genetics.NewSpecies(len(t.Distances), genetics.Gene(len(t.Distances)-1))
+}
+
+// Fitness implements genetics.FitnessFunc
+func (t TSP) Fitness(c genetics.Chromosome) genetics.Fitness {
+	return -genetics.Fitness(t.Length(c.Genes))
+}
+
+// Length is the length of the closed tour which visits cities in order.
+func (t TSP) Length(tour []genetics.Gene) int {
+	length := 0
+	for i, from := range tour {
+		length += t.Distances[from][tour[(i+1)%len(tour)]]
+	}
+	return length
+}
+
+// LoadTSPLIB reads a symmetric TSP instance in the TSPLIB format. The EUC_2D,
+// CEIL_2D, ATT, and GEO edge weight types are supported, as are EXPLICIT weights
+// in the FULL_MATRIX, UPPER_ROW, LOWER_ROW, UPPER_DIAG_ROW, and LOWER_DIAG_ROW
+// formats.
+func LoadTSPLIB(r io.Reader) (TSP, error) {
+	var (
+		t            TSP
+		dimension    int
+		weightType   string
+		weightFormat string
+		coords       [][2]float64
+		weights      []int
+		section      string
+	)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if line == "EOF" {
+			break
+		}
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			section = ""
+			switch key {
+			case "NAME":
+				t.Name = value
+			case "TYPE":
+				if value != "TSP" {
+					return TSP{}, fmt.Errorf("LoadTSPLIB(); unsupported TYPE %s", value)
+				}
+			case "DIMENSION":
+				n, err := strconv.Atoi(value)
+				if err != nil || n < 2 {
+					return TSP{}, fmt.Errorf("LoadTSPLIB(); invalid DIMENSION %s", value)
+				}
+				dimension = n
+			case "EDGE_WEIGHT_TYPE":
+				weightType = value
+			case "EDGE_WEIGHT_FORMAT":
+				weightFormat = value
+			}
+			continue
+		}
+		switch line {
+		case "NODE_COORD_SECTION", "EDGE_WEIGHT_SECTION", "DISPLAY_DATA_SECTION", "TOUR_SECTION":
+			if dimension == 0 {
+				return TSP{}, fmt.Errorf("LoadTSPLIB(); %s precedes DIMENSION", line)
+			}
+			section = line
+			continue
+		}
+
+		fields := strings.Fields(line)
+		switch section {
+		case "NODE_COORD_SECTION":
+			if len(fields) != 3 {
+				return TSP{}, fmt.Errorf("LoadTSPLIB(); malformed node %q", line)
+			}
+			var c [2]float64
+			for i := range c {
+				v, err := strconv.ParseFloat(fields[i+1], 64)
+				if err != nil {
+					return TSP{}, fmt.Errorf("LoadTSPLIB(); malformed node %q", line)
+				}
+				c[i] = v
+			}
+			coords = append(coords, c)
+		case "EDGE_WEIGHT_SECTION":
+			for _, f := range fields {
+				v, err := strconv.ParseFloat(f, 64)
+				if err != nil {
+					return TSP{}, fmt.Errorf("LoadTSPLIB(); malformed edge weight %q", f)
+				}
+				weights = append(weights, int(v))
+			}
+		case "DISPLAY_DATA_SECTION", "TOUR_SECTION":
+			// Not needed to score tours.
+		default:
+			return TSP{}, fmt.Errorf("LoadTSPLIB(); unexpected line %q", line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return TSP{}, fmt.Errorf("LoadTSPLIB(); err=%s", err)
+	}
+	if dimension == 0 {
+		return TSP{}, fmt.Errorf("LoadTSPLIB(); missing DIMENSION")
+	}
+
+	t.Distances = make([][]int, dimension)
+	for i := range t.Distances {
+		t.Distances[i] = make([]int, dimension)
+	}
+	if weightType == "EXPLICIT" {
+		if err := t.fillExplicit(weightFormat, weights); err != nil {
+			return TSP{}, err
+		}
+		return t, nil
+	}
+
+	if len(coords) != dimension {
+		return TSP{}, fmt.Errorf("LoadTSPLIB(); expected %d nodes, got %d", dimension, len(coords))
+	}
+	var dist func(a, b [2]float64) int
+	switch weightType {
+	case "EUC_2D":
+		dist = func(a, b [2]float64) int {
+			return int(math.Round(math.Hypot(a[0]-b[0], a[1]-b[1])))
+		}
+	case "CEIL_2D":
+		dist = func(a, b [2]float64) int {
+			return int(math.Ceil(math.Hypot(a[0]-b[0], a[1]-b[1])))
+		}
+	case "ATT":
+		dist = func(a, b [2]float64) int {
+			r := math.Sqrt((math.Pow(a[0]-b[0], 2) + math.Pow(a[1]-b[1], 2)) / 10)
+			d := math.Round(r)
+			if d < r {
+				d++
+			}
+			return int(d)
+		}
+	case "GEO":
+		dist = geoDistance
+	default:
+		return TSP{}, fmt.Errorf("LoadTSPLIB(); unsupported EDGE_WEIGHT_TYPE %s", weightType)
+	}
+	for i := range coords {
+		for j := i + 1; j < dimension; j++ {
+			d := dist(coords[i], coords[j])
+			t.Distances[i][j], t.Distances[j][i] = d, d
+		}
+	}
+	t.Coords = coords
+	return t, nil
+}
+
+func (t TSP) fillExplicit(format string, weights []int) error {
+	n := len(t.Distances)
+	// Each format visits the cells it lists in order.
+	var cells [][2]int
+	for i := 0; i < n; i++ {
+		switch format {
+		case "FULL_MATRIX":
+			for j := 0; j < n; j++ {
+				cells = append(cells, [2]int{i, j})
+			}
+		case "UPPER_ROW":
+			for j := i + 1; j < n; j++ {
+				cells = append(cells, [2]int{i, j})
+			}
+		case "UPPER_DIAG_ROW":
+			for j := i; j < n; j++ {
+				cells = append(cells, [2]int{i, j})
+			}
+		case "LOWER_ROW":
+			for j := 0; j < i; j++ {
+				cells = append(cells, [2]int{i, j})
+			}
+		case "LOWER_DIAG_ROW":
+			for j := 0; j <= i; j++ {
+				cells = append(cells, [2]int{i, j})
+			}
+		default:
+			return fmt.Errorf("LoadTSPLIB(); unsupported EDGE_WEIGHT_FORMAT %s", format)
+		}
+	}
+	if len(weights) != len(cells) {
+		return fmt.Errorf("LoadTSPLIB(); expected %d edge weights for %s, got %d", len(cells), format, len(weights))
+	}
+	for k, c := range cells {
+		t.Distances[c[0]][c[1]] = weights[k]
+		t.Distances[c[1]][c[0]] = weights[k]
+	}
+	return nil
+}
+
+// geoDistance is the TSPLIB distance between two (latitude, longitude) nodes given
+// in DDD.MM degrees and minutes.
+func geoDistance(a, b [2]float64) int {
+	radians := func(x float64) float64 {
+		deg := math.Trunc(x)
+		return math.Pi * (deg + 5*(x-deg)/3) / 180
+	}
+	const rrr = 6378.388
+	latA, lonA := radians(a[0]), radians(a[1])
+	latB, lonB := radians(b[0]), radians(b[1])
+	q1 := math.Cos(lonA - lonB)
+	q2 := math.Cos(latA - latB)
+	q3 := math.Cos(latA + latB)
+	return int(rrr*math.Acos(0.5*((1+q1)*q2-(1-q1)*q3)) + 1)
+}
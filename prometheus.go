@@ -0,0 +1,117 @@
+package genetics
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// PrometheusExporter is an Observer which exposes the progress of a long-running
+// evolution as Prometheus metrics in the text exposition format. Register it with
+// an http.ServeMux (e.g. at /metrics) and scrape it. It is also a StatsObserver, so
+// it can export any driver's progress; only an Engine reports diversity.
+//
+// The exported metrics, prefixed by Namespace, are:
+//
+//	_generation                  gauge   the most recently scored generation
+//	_best_fitness                gauge   the best fitness in that generation
+//	_mean_fitness                gauge   its mean fitness
+//	_worst_fitness               gauge   its worst fitness
+//	_evaluations_total           counter chromosomes scored
+//	_evaluation_failures_total   counter evaluations which failed
+//	_evaluations_per_second      gauge   the evaluation rate of the last generation
+//	_diversity                   gauge   the mean pairwise Distance of the population
+//
+// Unlike the rest of the package, a PrometheusExporter is goroutine safe so that it
+// can be scraped while the Engine runs.
+type PrometheusExporter struct {
+	NopObserver
+	// Namespace prefixes every metric. Defaults to "genetics".
+	Namespace string
+	// Distance, if set, measures the diversity of an Engine's population at the
+	// start of each generation. It is called for every pair of chromosomes.
+	Distance DistanceFunc
+
+	mu           sync.Mutex
+	stats        Stats
+	scored       bool
+	evaluations  int
+	failures     int
+	rate         float64
+	diversity    float64
+	hasDiversity bool
+	last         time.Time
+}
+
+// OnGenerationStart implements Observer
+func (p *PrometheusExporter) OnGenerationStart(e *Engine, generation int) {
+	if p.Distance == nil {
+		return
+	}
+	pop := e.Population().Chromosomes
+	total, pairs := 0.0, 0
+	for i := range pop {
+		for j := i + 1; j < len(pop); j++ {
+			total += p.Distance(pop[i], pop[j])
+			pairs++
+		}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if pairs > 0 {
+		p.diversity = total / float64(pairs)
+	}
+	p.hasDiversity = true
+}
+
+// OnStats implements StatsObserver
+func (p *PrometheusExporter) OnStats(s Stats) {
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.last.IsZero() {
+		if elapsed := now.Sub(p.last).Seconds(); elapsed > 0 {
+			p.rate = float64(s.Evaluations) / elapsed
+		}
+	}
+	p.last = now
+	p.stats = s
+	p.scored = true
+	p.evaluations += s.Evaluations
+	p.failures += s.Failures
+}
+
+// ServeHTTP implements http.Handler
+func (p *PrometheusExporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ns := p.Namespace
+	if ns == "" {
+		ns = "genetics"
+	}
+	p.mu.Lock()
+	metrics := []struct {
+		name, kind, help string
+		value            float64
+		present          bool
+	}{
+		{"generation", "gauge", "The most recently scored generation.", float64(p.stats.Generation), p.scored},
+		{"best_fitness", "gauge", "The best fitness in the most recently scored generation.", float64(p.stats.Best), p.scored},
+		{"mean_fitness", "gauge", "The mean fitness of the most recently scored generation.", p.stats.Mean, p.scored},
+		{"worst_fitness", "gauge", "The worst fitness in the most recently scored generation.", float64(p.stats.Worst), p.scored},
+		{"evaluations_total", "counter", "The number of chromosomes scored.", float64(p.evaluations), true},
+		{"evaluation_failures_total", "counter", "The number of evaluations which failed.", float64(p.failures), true},
+		{"evaluations_per_second", "gauge", "The evaluation rate of the most recent generation.", p.rate, p.scored},
+		{"diversity", "gauge", "The mean pairwise distance between members of the population.", p.diversity, p.hasDiversity},
+	}
+	p.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	for _, m := range metrics {
+		if !m.present {
+			continue
+		}
+		name := ns + "_" + m.name
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %s\n", name, m.help, name, m.kind, name, strconv.FormatFloat(m.value, 'g', -1, 64))
+	}
+}
@@ -0,0 +1,76 @@
+package genetics_test
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func scrape(t *testing.T, p *genetics.PrometheusExporter) map[string]string {
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type=%q; want the Prometheus text format", ct)
+	}
+	metrics := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(rec.Body.String()), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			t.Fatalf("malformed sample %q", line)
+		}
+		metrics[fields[0]] = fields[1]
+	}
+	return metrics
+}
+
+func TestPrometheusExporter(t *testing.T) {
+	p := &genetics.PrometheusExporter{Namespace: "onemax", Distance: genetics.HammingDistance}
+	if got := scrape(t, p); len(got) != 2 || got["onemax_evaluations_total"] != "0" {
+		t.Errorf("scrape before a run=%v; want only zeroed counters", got)
+	}
+
+	rng := rand.New()
+	rng.Seed(42)
+	recorder := &statsObserver{}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 4,
+			CrossoverRate:    1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator:          genetics.SwapMutation{},
+		},
+		Evaluator: genetics.FitnessFunc(oneMax),
+		Observers: []genetics.Observer{p, recorder},
+	}
+	if err := engine.Run(rng, newBinaryPopulation(t, rng, 8, 10), 3); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+
+	got := scrape(t, p)
+	last := recorder.stats[len(recorder.stats)-1]
+	for name, want := range map[string]string{
+		"onemax_generation":                "3",
+		"onemax_best_fitness":              strconv.Itoa(int(last.Best)),
+		"onemax_worst_fitness":             strconv.Itoa(int(last.Worst)),
+		"onemax_evaluations_total":         "22",
+		"onemax_evaluation_failures_total": "0",
+	} {
+		if got[name] != want {
+			t.Errorf("%s=%q; want %q", name, got[name], want)
+		}
+	}
+	for _, name := range []string{"onemax_mean_fitness", "onemax_evaluations_per_second", "onemax_diversity"} {
+		if _, ok := got[name]; !ok {
+			t.Errorf("scrape is missing %s", name)
+		}
+	}
+}
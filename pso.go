@@ -0,0 +1,151 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/inlined/rand"
+)
+
+// ParticleSwarm optimizes RealSpecies problems with global-best particle swarm
+// optimization. Each particle is pulled towards the best position it has seen
+// (with weight Cognitive) and the best position the swarm has seen (with weight
+// Social), while Inertia preserves part of its previous velocity. Particles which
+// leave the species' bounds stop at the bound and lose that component of their
+// velocity. Best means highest Fitness; minimize a cost by negating it in the
+// Evaluator. Like Engine, a ParticleSwarm is not goroutine safe.
+type ParticleSwarm struct {
+	Species   *RealSpecies
+	Evaluator RealEvaluator
+
+	// Size is the number of particles; at least 2.
+	Size int
+	// Inertia, Cognitive, and Social default to the constriction coefficients
+	// 0.7298, 1.49618, and 1.49618.
+	Inertia   float64
+	Cognitive float64
+	Social    float64
+	// MaxVelocity limits each velocity component to this fraction of the gene's
+	// range. Defaults to 0.2.
+	MaxVelocity float64
+
+	// Terminate, if set, ends a Run early once a generation satisfies it.
+	Terminate Termination
+	// Observers are notified of the Stats of each generation.
+	Observers []StatsObserver
+
+	positions   []RealChromosome
+	velocities  [][]float64
+	personal    []RealChromosome
+	personalFit []Fitness
+	best        int
+	stats       Stats
+}
+
+// Best returns the best position found by the swarm and its fitness.
+func (p *ParticleSwarm) Best() (RealChromosome, Fitness) {
+	if p.personal == nil {
+		return RealChromosome{}, 0
+	}
+	return p.personal[p.best], p.personalFit[p.best]
+}
+
+// Stats summarizes the most recent generation. Best, Worst, and Mean describe the
+// particles' personal bests; Improvements counts the particles which improved them.
+func (p *ParticleSwarm) Stats() Stats {
+	return p.stats
+}
+
+// Run flies a randomly initialized swarm for up to the given number of generations
+// and returns the best position found.
+func (p *ParticleSwarm) Run(r rand.Rand, generations int) (RealChromosome, Fitness, error) {
+	if p.Size < 2 {
+		return RealChromosome{}, 0, fmt.Errorf("ParticleSwarm.Run(); Size %d must be at least 2", p.Size)
+	}
+	w, c1, c2, vmax := p.Inertia, p.Cognitive, p.Social, p.MaxVelocity
+	if w == 0 {
+		w = 0.7298
+	}
+	if c1 == 0 {
+		c1 = 1.49618
+	}
+	if c2 == 0 {
+		c2 = 1.49618
+	}
+	if vmax == 0 {
+		vmax = 0.2
+	}
+
+	s := p.Species
+	n := s.NumGenes()
+	p.positions = make([]RealChromosome, p.Size)
+	p.velocities = make([][]float64, p.Size)
+	p.personal = make([]RealChromosome, p.Size)
+	p.personalFit = make([]Fitness, p.Size)
+	p.stats = Stats{}
+	for i := range p.positions {
+		p.positions[i] = s.NewRand(r)
+		p.velocities[i] = make([]float64, n)
+		for j := range p.velocities[i] {
+			span := s.Max[j] - s.Min[j]
+			p.velocities[i][j] = (2*r.Float64() - 1) * vmax * span
+		}
+		score, err := p.Evaluator.Evaluate(p.positions[i])
+		if err != nil {
+			return RealChromosome{}, 0, fmt.Errorf("ParticleSwarm.Run(); cannot evaluate initial particle %d: %s", i, err)
+		}
+		p.personal[i] = s.New(p.positions[i].Genes...)
+		p.personalFit[i] = score
+	}
+	p.best = rankIndexes(p.personalFit)[0]
+
+	for gen := 0; gen < generations; gen++ {
+		improvements := 0
+		global := p.personal[p.best]
+		for i, x := range p.positions {
+			v := p.velocities[i]
+			for j := range x.Genes {
+				limit := vmax * (s.Max[j] - s.Min[j])
+				v[j] = w*v[j] +
+					c1*r.Float64()*(p.personal[i].Genes[j]-x.Genes[j]) +
+					c2*r.Float64()*(global.Genes[j]-x.Genes[j])
+				v[j] = math.Max(-limit, math.Min(limit, v[j]))
+				x.Genes[j] += v[j]
+				if x.Genes[j] < s.Min[j] || x.Genes[j] > s.Max[j] {
+					v[j] = 0
+				}
+			}
+			s.clamp(&x)
+
+			score, err := p.Evaluator.Evaluate(x)
+			if err != nil {
+				best, f := p.Best()
+				return best, f, fmt.Errorf("ParticleSwarm.Run(); generation %d: %s", gen, err)
+			}
+			if score > p.personalFit[i] {
+				improvements++
+				p.personal[i] = s.New(x.Genes...)
+				p.personalFit[i] = score
+			}
+		}
+		// The swarm shares its best position once per generation (synchronous
+		// updates), so the order of particles does not bias the search.
+		p.best = rankIndexes(p.personalFit)[0]
+
+		p.stats = Stats{
+			Generation:   gen,
+			Evaluations:  p.Size,
+			Offspring:    p.Size,
+			Improvements: improvements,
+		}
+		p.stats.summarize(p.personalFit)
+		for _, o := range p.Observers {
+			o.OnStats(p.stats)
+		}
+		if p.Terminate != nil && p.Terminate(p.stats) {
+			break
+		}
+	}
+	best, f := p.Best()
+	return best, f, nil
+}
@@ -0,0 +1,69 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestParticleSwarm(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	recorder := &statsRecorder{}
+	pso := &genetics.ParticleSwarm{
+		Species:   genetics.NewUniformRealSpecies(3, -5.12, 5.12),
+		Evaluator: genetics.RealFitnessFunc(sphere),
+		Size:      20,
+		Observers: []genetics.StatsObserver{recorder},
+	}
+	best, f, err := pso.Run(rng, 200)
+	if err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if f < -1000 {
+		t.Errorf("ParticleSwarm did not converge; best %v scored %g", best.Genes, f)
+	}
+	if got, gotF := pso.Best(); gotF != f || len(got.Genes) != 3 {
+		t.Errorf("Best()=%v,%g; want %v,%g", got.Genes, gotF, best.Genes, f)
+	}
+	if len(recorder.stats) != 200 {
+		t.Fatalf("got %d Stats; want 200", len(recorder.stats))
+	}
+	for n := 1; n < len(recorder.stats); n++ {
+		if recorder.stats[n].Best < recorder.stats[n-1].Best {
+			t.Errorf("best personal fitness regressed from %g to %g in generation %d", recorder.stats[n-1].Best, recorder.stats[n].Best, n)
+		}
+	}
+	for _, g := range best.Genes {
+		if g < -5.12 || g > 5.12 {
+			t.Errorf("best %v is out of bounds", best.Genes)
+		}
+	}
+}
+
+func TestParticleSwarmTerminate(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	recorder := &statsRecorder{}
+	pso := &genetics.ParticleSwarm{
+		Species:   genetics.NewUniformRealSpecies(2, -5.12, 5.12),
+		Evaluator: genetics.RealFitnessFunc(sphere),
+		Size:      20,
+		Terminate: genetics.TargetFitness(-1e4),
+		Observers: []genetics.StatsObserver{recorder},
+	}
+	if _, f, err := pso.Run(rng, 1000); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	} else if f < -1e4 {
+		t.Errorf("Run() stopped at %g before reaching the target", f)
+	}
+	if n := len(recorder.stats); n == 1000 {
+		t.Errorf("Run() did not stop when it reached the target; generations=%d", n)
+	}
+
+	if _, _, err := (&genetics.ParticleSwarm{Species: pso.Species, Evaluator: pso.Evaluator, Size: 1}).Run(rng, 1); err == nil {
+		t.Error("Run() should reject swarms smaller than 2")
+	}
+}
@@ -0,0 +1,297 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/inlined/rand"
+)
+
+const (
+	arithmeticCrossover = "ArithmeticCrossover"
+	blendCrossover      = "BlendCrossover"
+	gaussianMutation    = "GaussianMutation"
+	polynomialMutation  = "PolynomialMutation"
+)
+
+// RealSpecies is a factory for real-coded Chromosomes, whose genes are float64s
+// bounded per gene by [Min[i], Max[i]]. Real-coded species suit continuous
+// problems (e.g. Rastrigin or Rosenbrock functions) without scaling integer genes.
+type RealSpecies struct {
+	Min []float64
+	Max []float64
+}
+
+// NewRealSpecies initializes a RealSpecies with per-gene bounds. min and max are
+// copied, so the caller may reuse them.
+func NewRealSpecies(min, max []float64) (*RealSpecies, error) {
+	if len(min) != len(max) {
+		return nil, fmt.Errorf("NewRealSpecies(); %d minimums but %d maximums", len(min), len(max))
+	}
+	for i := range min {
+		if min[i] > max[i] {
+			return nil, fmt.Errorf("NewRealSpecies(); gene %d has min %f greater than max %f", i, min[i], max[i])
+		}
+	}
+	return &RealSpecies{
+		Min: append([]float64(nil), min...),
+		Max: append([]float64(nil), max...),
+	}, nil
+}
+
+// NewUniformRealSpecies initializes a RealSpecies whose genes all share the bounds [min, max].
+func NewUniformRealSpecies(numGenes int, min, max float64) *RealSpecies {
+	s := &RealSpecies{
+		Min: make([]float64, numGenes),
+		Max: make([]float64, numGenes),
+	}
+	for i := 0; i < numGenes; i++ {
+		s.Min[i], s.Max[i] = min, max
+	}
+	return s
+}
+
+// NumGenes is the number of genes in each RealChromosome of the species.
+func (s *RealSpecies) NumGenes() int {
+	return len(s.Min)
+}
+
+// RealChromosome represents a single real-coded strategy for a RealSpecies.
+type RealChromosome struct {
+	Species *RealSpecies
+	Genes   []float64
+}
+
+// New creates a RealChromosome of the species. Any passed genes are initialized
+// starting at index 0 and clamped to the species' bounds; missing genes take
+// their minimum value.
+func (s *RealSpecies) New(g ...float64) RealChromosome {
+	c := RealChromosome{
+		Species: s,
+		Genes:   make([]float64, s.NumGenes()),
+	}
+	copy(c.Genes, s.Min)
+	copy(c.Genes, g)
+	s.clamp(&c)
+	return c
+}
+
+// NewRand creates a RealChromosome with each gene uniformly distributed within its bounds.
+func (s *RealSpecies) NewRand(r rand.Rand) RealChromosome {
+	c := s.New()
+	for i := range c.Genes {
+		c.Genes[i] = s.Min[i] + r.Float64()*(s.Max[i]-s.Min[i])
+	}
+	return c
+}
+
+func (s *RealSpecies) clamp(c *RealChromosome) {
+	for i, g := range c.Genes {
+		c.Genes[i] = math.Max(s.Min[i], math.Min(s.Max[i], g))
+	}
+}
+
+// RealEvaluator scores a RealChromosome.
+type RealEvaluator interface {
+	Evaluate(c RealChromosome) (Fitness, error)
+}
+
+// RealFitnessFunc adapts a fitness function which cannot fail into a RealEvaluator.
+type RealFitnessFunc func(c RealChromosome) Fitness
+
+// Evaluate implements RealEvaluator
+func (f RealFitnessFunc) Evaluate(c RealChromosome) (Fitness, error) {
+	return f(c), nil
+}
+
+// RealCrossover is a strategy for generating two real-coded children from two parents.
+type RealCrossover interface {
+	fmt.Stringer
+	Crossover(r rand.Rand, a, b RealChromosome) (x, y RealChromosome)
+}
+
+// RealMutator introduces randomness to a real-coded population.
+type RealMutator interface {
+	fmt.Stringer
+	Mutate(r rand.Rand, c *RealChromosome)
+}
+
+// ArithmeticCrossover picks a random weight from 0-1; the children are weighted
+// averages of the parents with inverse weights. It is the real-coded counterpart of
+// WholeArithmeticRecombination.
+type ArithmeticCrossover struct{}
+
+func (ArithmeticCrossover) String() string {
+	return arithmeticCrossover
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (ArithmeticCrossover) ConcurrentSafe() bool {
+	return true
+}
+
+// Crossover implements RealCrossover
+func (ArithmeticCrossover) Crossover(r rand.Rand, a, b RealChromosome) (x, y RealChromosome) {
+	f := r.Float64()
+	s := a.Species
+	x, y = s.New(), s.New()
+	for i := range x.Genes {
+		x.Genes[i] = f*a.Genes[i] + (1-f)*b.Genes[i]
+		y.Genes[i] = (1-f)*a.Genes[i] + f*b.Genes[i]
+	}
+	return x, y
+}
+
+// BlendCrossover (BLX-α) draws each child gene uniformly from the parents' interval
+// extended by Alpha times its width on either side, letting children explore
+// slightly beyond their parents. Alpha is typically 0.5.
+type BlendCrossover struct {
+	Alpha float64
+}
+
+func (c BlendCrossover) String() string {
+	return fmt.Sprintf("%s(%g)", blendCrossover, c.Alpha)
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (BlendCrossover) ConcurrentSafe() bool {
+	return true
+}
+
+// Crossover implements RealCrossover
+func (c BlendCrossover) Crossover(r rand.Rand, a, b RealChromosome) (x, y RealChromosome) {
+	s := a.Species
+	x, y = s.New(), s.New()
+	for i := range x.Genes {
+		lo, hi := math.Min(a.Genes[i], b.Genes[i]), math.Max(a.Genes[i], b.Genes[i])
+		d := c.Alpha * (hi - lo)
+		x.Genes[i] = lo - d + r.Float64()*(hi-lo+2*d)
+		y.Genes[i] = lo - d + r.Float64()*(hi-lo+2*d)
+	}
+	s.clamp(&x)
+	s.clamp(&y)
+	return x, y
+}
+
+// GaussianMutation adds normally distributed noise to genes. Sigma is the standard
+// deviation as a fraction of each gene's range. Each gene mutates with probability
+// Rate; a Rate of 0 mutates one gene on average.
+type GaussianMutation struct {
+	Sigma float64
+	Rate  float64
+}
+
+func (m GaussianMutation) String() string {
+	return fmt.Sprintf("%s(%g,%g)", gaussianMutation, m.Sigma, m.Rate)
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (GaussianMutation) ConcurrentSafe() bool {
+	return true
+}
+
+// Mutate implements RealMutator
+func (m GaussianMutation) Mutate(r rand.Rand, c *RealChromosome) {
+	s := c.Species
+	rate := geneRate(m.Rate, len(c.Genes))
+	for i := range c.Genes {
+		if r.Float64() < rate {
+			c.Genes[i] += normFloat64(r) * m.Sigma * (s.Max[i] - s.Min[i])
+		}
+	}
+	s.clamp(c)
+}
+
+// PolynomialMutation is Deb's polynomial mutation, which perturbs genes with a
+// polynomial distribution whose spread shrinks as the distribution index Eta grows
+// (typically 20). Each gene mutates with probability Rate; a Rate of 0 mutates
+// one gene on average.
+type PolynomialMutation struct {
+	Eta  float64
+	Rate float64
+}
+
+func (m PolynomialMutation) String() string {
+	return fmt.Sprintf("%s(%g,%g)", polynomialMutation, m.Eta, m.Rate)
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (PolynomialMutation) ConcurrentSafe() bool {
+	return true
+}
+
+// Mutate implements RealMutator
+func (m PolynomialMutation) Mutate(r rand.Rand, c *RealChromosome) {
+	s := c.Species
+	rate := geneRate(m.Rate, len(c.Genes))
+	for i := range c.Genes {
+		if r.Float64() >= rate {
+			continue
+		}
+		u := r.Float64()
+		var delta float64
+		if u < 0.5 {
+			delta = math.Pow(2*u, 1/(m.Eta+1)) - 1
+		} else {
+			delta = 1 - math.Pow(2*(1-u), 1/(m.Eta+1))
+		}
+		c.Genes[i] += delta * (s.Max[i] - s.Min[i])
+	}
+	s.clamp(c)
+}
+
+// geneRate defaults a per-gene mutation probability to mutating one gene on average.
+func geneRate(rate float64, numGenes int) float64 {
+	if rate == 0 {
+		return 1 / float64(numGenes)
+	}
+	return rate
+}
+
+// RealEvolver is the Evolver for real-coded populations. It shares selection and
+// replacement with Evolver.
+type RealEvolver struct {
+	ReplacementCount int
+	CrossoverRate    float32
+	MutationRate     float32
+	Selector         NaturalSelection
+	Crossover        RealCrossover
+	Mutator          RealMutator
+}
+
+// Validate reports the configuration errors that Evolver.Validate does, other
+// than those of a MatingRestriction.
+func (e RealEvolver) Validate(populationSize int) error {
+	if err := validateEvolver("RealEvolver", populationSize, e.ReplacementCount, e.CrossoverRate, e.MutationRate, e.Selector); err != nil {
+		return err
+	}
+	switch {
+	case e.Crossover == nil && e.CrossoverRate > 0:
+		return fmt.Errorf("RealEvolver.Validate(); Crossover is nil but CrossoverRate is %g", e.CrossoverRate)
+	case e.Mutator == nil && e.MutationRate > 0:
+		return fmt.Errorf("RealEvolver.Validate(); Mutator is nil but MutationRate is %g", e.MutationRate)
+	}
+	return nil
+}
+
+// Evolve replaces a handful of the population with the next generation. It
+// panics if the RealEvolver is invalid for pop; see Validate.
+func (e RealEvolver) Evolve(rand rand.Rand, pop []RealChromosome, scores []Fitness) {
+	if err := e.Validate(len(pop)); err != nil {
+		panic(err.Error())
+	}
+	breed(rand, pop, scores, e.ReplacementCount, e.Selector, func(a, b RealChromosome) (x, y RealChromosome) {
+		if rand.Float32() < e.CrossoverRate {
+			x, y = e.Crossover.Crossover(rand, a, b)
+		} else {
+			x, y = a.Species.New(a.Genes...), b.Species.New(b.Genes...)
+		}
+		if rand.Float32() < e.MutationRate {
+			e.Mutator.Mutate(rand, &x)
+		}
+		if rand.Float32() < e.MutationRate {
+			e.Mutator.Mutate(rand, &y)
+		}
+		return x, y
+	})
+}
@@ -0,0 +1,171 @@
+package genetics_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+	"github.com/inlined/xkcd"
+
+	"github.com/inlined/genetics"
+)
+
+func TestNewRealSpecies(t *testing.T) {
+	if _, err := genetics.NewRealSpecies([]float64{0, 1}, []float64{1}); err == nil {
+		t.Error("NewRealSpecies() should reject mismatched bounds")
+	}
+	if _, err := genetics.NewRealSpecies([]float64{2}, []float64{1}); err == nil {
+		t.Error("NewRealSpecies() should reject min > max")
+	}
+	min, max := []float64{0, -5}, []float64{1, 5}
+	s, err := genetics.NewRealSpecies(min, max)
+	if err != nil {
+		t.Fatalf("NewRealSpecies(); err=%s", err)
+	}
+	min[0], max[0] = 100, 200
+	if s.Min[0] != 0 || s.Max[0] != 1 {
+		t.Errorf("NewRealSpecies() should copy its bounds; got Min=%v Max=%v", s.Min, s.Max)
+	}
+	if diff := cmp.Diff([]float64{1, -5}, s.New(3).Genes); diff != "" {
+		t.Errorf("New() should clamp and default to the minimum; diff=%s", diff)
+	}
+	rng := rand.New()
+	for run := 0; run < 100; run++ {
+		c := s.NewRand(rng)
+		if c.Genes[0] < 0 || c.Genes[0] > 1 || c.Genes[1] < -5 || c.Genes[1] > 5 {
+			t.Fatalf("NewRand() = %v is out of bounds", c.Genes)
+		}
+	}
+}
+
+func TestRealOperators(t *testing.T) {
+	s := genetics.NewUniformRealSpecies(2, -10, 10)
+	x, y := genetics.ArithmeticCrossover{}.Crossover(xkcd.Rand(0.25), s.New(0, 4), s.New(8, 0))
+	if diff := cmp.Diff([]float64{6, 1}, x.Genes); diff != "" {
+		t.Errorf("ArithmeticCrossover x=%v; diff=%s", x.Genes, diff)
+	}
+	if diff := cmp.Diff([]float64{2, 3}, y.Genes); diff != "" {
+		t.Errorf("ArithmeticCrossover y=%v; diff=%s", y.Genes, diff)
+	}
+
+	rng := rand.New()
+	for run := 0; run < 100; run++ {
+		x, y := genetics.BlendCrossover{Alpha: 0.5}.Crossover(rng, s.New(0, 4), s.New(2, 8))
+		for _, c := range []genetics.RealChromosome{x, y} {
+			if c.Genes[0] < -1 || c.Genes[0] > 3 || c.Genes[1] < 2 || c.Genes[1] > 10 {
+				t.Fatalf("BlendCrossover(0.5) child %v is outside of the extended parent interval", c.Genes)
+			}
+		}
+	}
+
+	for _, mutator := range []genetics.RealMutator{
+		genetics.GaussianMutation{Sigma: 0.5, Rate: 1},
+		genetics.PolynomialMutation{Eta: 1, Rate: 1},
+	} {
+		t.Run(mutator.String(), func(t *testing.T) {
+			changed := false
+			for run := 0; run < 100; run++ {
+				c := s.New(9, -9)
+				mutator.Mutate(rng, &c)
+				for _, g := range c.Genes {
+					if g < -10 || g > 10 {
+						t.Fatalf("Mutate() = %v is out of bounds", c.Genes)
+					}
+				}
+				changed = changed || c.Genes[0] != 9 || c.Genes[1] != -9
+			}
+			if !changed {
+				t.Error("Mutate() never changed a gene")
+			}
+		})
+	}
+}
+
+// Minimizes the sphere function; fitness is scaled to keep three decimal places.
+func TestRealEvolverSphere(t *testing.T) {
+	sphere := func(c genetics.RealChromosome) genetics.Fitness {
+		sum := 0.0
+		for _, g := range c.Genes {
+			sum += g * g
+		}
+		return genetics.Fitness(-math.Round(sum * 1000))
+	}
+	rng := rand.New()
+	s := genetics.NewUniformRealSpecies(5, -5, 5)
+	pop := make([]genetics.RealChromosome, 40)
+	scores := make([]genetics.Fitness, len(pop))
+	for i := range pop {
+		pop[i] = s.NewRand(rng)
+	}
+	evolver := genetics.RealEvolver{
+		ReplacementCount: 20,
+		CrossoverRate:    0.9,
+		MutationRate:     0.2,
+		Selector:         genetics.TournamentSelection{Size: 3},
+		Crossover:        genetics.BlendCrossover{Alpha: 0.5},
+		Mutator:          genetics.GaussianMutation{Sigma: 0.05},
+	}
+	best := genetics.Fitness(math.Inf(-1))
+	for gen := 0; gen < 100; gen++ {
+		for i, c := range pop {
+			scores[i] = sphere(c)
+			if scores[i] > best {
+				best = scores[i]
+			}
+		}
+		evolver.Evolve(rng, pop, scores)
+	}
+	if best < -500 {
+		t.Errorf("RealEvolver did not approach the sphere's minimum; best=%g", best)
+	}
+}
+
+func TestRealEvolverValidate(t *testing.T) {
+	valid := genetics.RealEvolver{
+		ReplacementCount: 2,
+		CrossoverRate:    0.9,
+		MutationRate:     0.1,
+		Selector:         genetics.TournamentSelection{Size: 2},
+		Crossover:        genetics.ArithmeticCrossover{},
+		Mutator:          genetics.GaussianMutation{Sigma: 0.1},
+	}
+	for _, test := range []struct {
+		tag    string
+		modify func(e *genetics.RealEvolver)
+		valid  bool
+	}{
+		{tag: "valid", modify: func(e *genetics.RealEvolver) {}, valid: true},
+		{tag: "crossover only", modify: func(e *genetics.RealEvolver) { e.Mutator, e.MutationRate = nil, 0 }, valid: true},
+		{tag: "nil Selector", modify: func(e *genetics.RealEvolver) { e.Selector = nil }},
+		{tag: "nil Crossover", modify: func(e *genetics.RealEvolver) { e.Crossover = nil }},
+		{tag: "nil Mutator", modify: func(e *genetics.RealEvolver) { e.Mutator = nil }},
+		{tag: "odd ReplacementCount", modify: func(e *genetics.RealEvolver) { e.ReplacementCount = 3 }},
+		{tag: "ReplacementCount too large", modify: func(e *genetics.RealEvolver) { e.ReplacementCount = 6 }},
+		{tag: "negative CrossoverRate", modify: func(e *genetics.RealEvolver) { e.CrossoverRate = -1 }},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			e := valid
+			test.modify(&e)
+			if err := e.Validate(4); test.valid != (err == nil) {
+				t.Errorf("Validate(4); valid=%t err=%v", test.valid, err)
+			}
+		})
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Evolve() should panic with an odd ReplacementCount")
+		}
+	}()
+	rng := rand.New()
+	rng.Seed(42)
+	s := genetics.NewUniformRealSpecies(2, -1, 1)
+	pop := make([]genetics.RealChromosome, 4)
+	for i := range pop {
+		pop[i] = s.NewRand(rng)
+	}
+	e := valid
+	e.ReplacementCount = 3
+	e.Evolve(rng, pop, make([]genetics.Fitness, len(pop)))
+}
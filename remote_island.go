@@ -0,0 +1,144 @@
+package genetics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/inlined/rand"
+)
+
+// MigrationTransport carries migrants between Islands which live in different
+// processes or machines. Islands are connected in a ring: each sends migrants to
+// the next and receives them from the previous.
+type MigrationTransport interface {
+	// Send delivers migrants to the next Island.
+	Send(migrants []Chromosome) error
+	// Receive returns the migrants which have arrived since the last call. It must
+	// not block waiting for migrants.
+	Receive() ([]Chromosome, error)
+}
+
+// RemoteIsland evolves one Island of a distributed archipelago. Every
+// MigrationInterval generations it sends its Migrants fittest members through the
+// Transport, and any migrants which have arrived replace its least fit members.
+// Migration is asynchronous, so islands need not run in lockstep.
+type RemoteIsland struct {
+	Island            *Island
+	MigrationInterval int
+	Migrants          int
+	Transport         MigrationTransport
+}
+
+// Run evolves the Island for the given number of generations.
+func (ri *RemoteIsland) Run(r rand.Rand, generations int) error {
+	island := ri.Island
+	island.Engine.Reset(island.Population)
+	for gen := 0; gen < generations; gen++ {
+		if err := island.Engine.Step(r); err != nil {
+			return fmt.Errorf("island %s: %s", island.Name, err)
+		}
+		if ri.MigrationInterval > 0 && (gen+1)%ri.MigrationInterval == 0 {
+			if err := ri.migrate(); err != nil {
+				return fmt.Errorf("island %s: %s", island.Name, err)
+			}
+		}
+	}
+	if err := island.Engine.finish(r, nil); err != nil {
+		return fmt.Errorf("island %s: %s", island.Name, err)
+	}
+	return nil
+}
+
+// migrate sends the fittest members of the island and replaces its least fit
+// members with any migrants which have arrived, judged by their most recent scores.
+func (ri *RemoteIsland) migrate() error {
+	pop := ri.Island.Population
+	migrants := make([]Chromosome, 0, ri.Migrants)
+	for _, i := range TopK(pop.scores(), ri.Migrants) {
+		migrants = append(migrants, pop.Chromosomes[i].Clone())
+	}
+	if err := ri.Transport.Send(migrants); err != nil {
+		return fmt.Errorf("cannot send migrants: %s", err)
+	}
+
+	arrived, err := ri.Transport.Receive()
+	if err != nil {
+		return fmt.Errorf("cannot receive migrants: %s", err)
+	}
+	if len(arrived) > pop.Len() {
+		arrived = arrived[len(arrived)-pop.Len():]
+	}
+	worst := BottomK(pop.scores(), len(arrived))
+	for m, c := range arrived {
+		if err := pop.Species.validateGenes(c.Genes); err != nil {
+			return fmt.Errorf("received an invalid migrant: %s", err)
+		}
+		c.Species = pop.Species
+		ri.Island.Engine.Replace(worst[m], c, migrationOperator)
+	}
+	return nil
+}
+
+// HTTPTransport is a MigrationTransport over HTTP. Serve it (it is an http.Handler)
+// so that the previous Island can POST migrants to it; Send POSTs migrants to the
+// Peer's HTTPTransport. Migrants are encoded as a JSON array of Chromosomes.
+// HTTPTransport is goroutine safe.
+type HTTPTransport struct {
+	// Peer is the URL of the next Island's HTTPTransport.
+	Peer string
+	// Client is used to reach Peer. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	mu    sync.Mutex
+	inbox []Chromosome
+}
+
+// ServeHTTP implements http.Handler by queuing POSTed migrants for Receive.
+func (t *HTTPTransport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "expected POST", http.StatusMethodNotAllowed)
+		return
+	}
+	var migrants []Chromosome
+	if err := json.NewDecoder(r.Body).Decode(&migrants); err != nil {
+		http.Error(w, fmt.Sprintf("malformed migrants: %s", err), http.StatusBadRequest)
+		return
+	}
+	t.mu.Lock()
+	t.inbox = append(t.inbox, migrants...)
+	t.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Send implements MigrationTransport
+func (t *HTTPTransport) Send(migrants []Chromosome) error {
+	body, err := json.Marshal(migrants)
+	if err != nil {
+		return err
+	}
+	client := t.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Post(t.Peer, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s returned %s", t.Peer, resp.Status)
+	}
+	return nil
+}
+
+// Receive implements MigrationTransport
+func (t *HTTPTransport) Receive() ([]Chromosome, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	migrants := t.inbox
+	t.inbox = nil
+	return migrants, nil
+}
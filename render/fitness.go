@@ -0,0 +1,85 @@
+package render
+
+import (
+	"io"
+
+	"github.com/inlined/genetics"
+)
+
+// FitnessPlot is a genetics.Observer which charts the best and mean fitness of each
+// scored generation of a run. It also works as a StatsObserver of other drivers,
+// whose callers should call WriteFile when the run is done.
+type FitnessPlot struct {
+	genetics.NopObserver
+
+	// Path, if set, is the file written when an Engine's run terminates. Its
+	// extension, .svg or .png, chooses the format.
+	Path string
+	// Width and Height are the size of the image in pixels. Default to 640 by 400.
+	Width, Height int
+
+	best, mean [][2]float64
+	err        error
+}
+
+// OnGenerationStart implements genetics.Observer by starting a new chart at
+// generation 0.
+func (p *FitnessPlot) OnGenerationStart(e *genetics.Engine, generation int) {
+	if generation == 0 {
+		p.best, p.mean = nil, nil
+	}
+}
+
+// OnStats implements genetics.StatsObserver
+func (p *FitnessPlot) OnStats(s genetics.Stats) {
+	g := float64(s.Generation)
+	p.best = append(p.best, [2]float64{g, float64(s.Best)})
+	p.mean = append(p.mean, [2]float64{g, s.Mean})
+}
+
+// OnTermination implements genetics.Observer by writing Path, if set.
+func (p *FitnessPlot) OnTermination(e *genetics.Engine, err error) {
+	if p.Path != "" {
+		p.err = p.WriteFile(p.Path)
+	}
+}
+
+// Err returns the error of the last write made when a run terminated, if any.
+func (p *FitnessPlot) Err() error {
+	return p.err
+}
+
+// WriteSVG writes the chart as an SVG image.
+func (p *FitnessPlot) WriteSVG(w io.Writer) error {
+	return p.figure().writeSVG(w)
+}
+
+// WritePNG writes the chart as a PNG image.
+func (p *FitnessPlot) WritePNG(w io.Writer) error {
+	return p.figure().writePNG(w)
+}
+
+// WriteFile atomically replaces path with the chart in the format chosen by its
+// extension, .svg or .png.
+func (p *FitnessPlot) WriteFile(path string) error {
+	return p.figure().writeFile(path)
+}
+
+func (p *FitnessPlot) figure() *figure {
+	f := &figure{
+		width:  p.Width,
+		height: p.Height,
+		title:  "Fitness",
+		axes:   true,
+		xLabel: "Generation",
+		yLabel: "Fitness",
+		series: []series{
+			{name: "best", color: blue, points: p.best},
+			{name: "mean", color: orange, points: p.mean},
+		},
+	}
+	if f.width <= 0 || f.height <= 0 {
+		f.width, f.height = 640, 400
+	}
+	return f
+}
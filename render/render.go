@@ -0,0 +1,264 @@
+// Package render draws plots of runs as SVG or PNG images: FitnessPlot charts the
+// best and mean fitness of each generation, and TourPlot draws the best tour of
+// a permutation problem whose cities have 2D coordinates. Both are
+// genetics.Observers which write their image to a file when a run terminates.
+// SVG images are labelled; PNG images are drawn with the standard library alone
+// and so carry no text.
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	background = color.RGBA{255, 255, 255, 255}
+	axisColor  = color.RGBA{128, 128, 128, 255}
+	blue       = color.RGBA{31, 119, 180, 255}
+	orange     = color.RGBA{255, 127, 14, 255}
+	black      = color.RGBA{0, 0, 0, 255}
+)
+
+// series is a line through points in data coordinates.
+type series struct {
+	name   string
+	color  color.RGBA
+	points [][2]float64
+	closed bool
+}
+
+// figure is a plot of series and markers which can be written as SVG or PNG.
+type figure struct {
+	width, height int
+	title         string
+	// axes draws labelled axes and leaves margins for their labels.
+	axes           bool
+	xLabel, yLabel string
+	// equalAspect scales both dimensions alike, e.g. for maps.
+	equalAspect bool
+	series      []series
+	markers     [][2]float64
+}
+
+// bounds returns the range of the data in each dimension, widened where the data
+// is flat so that it can be scaled.
+func (f *figure) bounds() (min, max [2]float64) {
+	min = [2]float64{math.Inf(1), math.Inf(1)}
+	max = [2]float64{math.Inf(-1), math.Inf(-1)}
+	extend := func(p [2]float64) {
+		for d := range p {
+			min[d], max[d] = math.Min(min[d], p[d]), math.Max(max[d], p[d])
+		}
+	}
+	for _, s := range f.series {
+		for _, p := range s.points {
+			extend(p)
+		}
+	}
+	for _, p := range f.markers {
+		extend(p)
+	}
+	for d := range min {
+		switch {
+		case math.IsInf(min[d], 1):
+			min[d], max[d] = 0, 1
+		case min[d] == max[d]:
+			min[d], max[d] = min[d]-0.5, max[d]+0.5
+		}
+	}
+	return min, max
+}
+
+// margins returns the left, top, right, and bottom margins in pixels.
+func (f *figure) margins() (left, top, right, bottom float64) {
+	if f.axes {
+		return 70, 30, 20, 45
+	}
+	return 20, 20, 20, 20
+}
+
+// transform returns a function mapping data coordinates to pixels, with y
+// increasing upwards.
+func (f *figure) transform() func(p [2]float64) (float64, float64) {
+	min, max := f.bounds()
+	left, top, right, bottom := f.margins()
+	w, h := float64(f.width)-left-right, float64(f.height)-top-bottom
+	sx, sy := w/(max[0]-min[0]), h/(max[1]-min[1])
+	ox, oy := left, top
+	if f.equalAspect {
+		s := math.Min(sx, sy)
+		ox += (w - s*(max[0]-min[0])) / 2
+		oy += (h - s*(max[1]-min[1])) / 2
+		sx, sy = s, s
+		h = s * (max[1] - min[1])
+	}
+	return func(p [2]float64) (float64, float64) {
+		return ox + (p[0]-min[0])*sx, oy + h - (p[1]-min[1])*sy
+	}
+}
+
+// writeSVG writes f as an SVG image.
+func (f *figure) writeSVG(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	px := f.transform()
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", f.width, f.height, f.width, f.height)
+	fmt.Fprintf(b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hex(background))
+	if f.title != "" {
+		fmt.Fprintf(b, `<text x="%d" y="20" text-anchor="middle" font-family="sans-serif" font-size="14">%s</text>`+"\n", f.width/2, escape(f.title))
+	}
+	if f.axes {
+		min, max := f.bounds()
+		x0, y0 := px(min)
+		x1, y1 := px(max)
+		fmt.Fprintf(b, `<g stroke="%s" fill="none"><line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f"/><line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f"/></g>`+"\n",
+			hex(axisColor), x0, y0, x1, y0, x0, y0, x0, y1)
+		fmt.Fprintf(b, `<g font-family="sans-serif" font-size="11" fill="%s">`+"\n", hex(black))
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`+"\n", x0, y0+15, label(min[0]))
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`+"\n", x1, y0+15, label(max[0]))
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" text-anchor="end">%s</text>`+"\n", x0-5, y0, label(min[1]))
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" text-anchor="end">%s</text>`+"\n", x0-5, y1+4, label(max[1]))
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`+"\n", (x0+x1)/2, y0+35, escape(f.xLabel))
+		fmt.Fprintf(b, `<text x="15" y="%.1f" text-anchor="middle" transform="rotate(-90 15 %.1f)">%s</text>`+"\n", (y0+y1)/2, (y0+y1)/2, escape(f.yLabel))
+		for n, s := range f.series {
+			fmt.Fprintf(b, `<text x="%.1f" y="%.1f" fill="%s">%s</text>`+"\n", x0+10, y1+15*float64(n+1), hex(s.color), escape(s.name))
+		}
+		fmt.Fprintln(b, `</g>`)
+	}
+	for _, s := range f.series {
+		element := "polyline"
+		if s.closed {
+			element = "polygon"
+		}
+		points := make([]string, len(s.points))
+		for i, p := range s.points {
+			x, y := px(p)
+			points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+		}
+		fmt.Fprintf(b, `<%s points="%s" fill="none" stroke="%s" stroke-width="1.5"/>`+"\n", element, strings.Join(points, " "), hex(s.color))
+	}
+	for _, p := range f.markers {
+		x, y := px(p)
+		fmt.Fprintf(b, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"/>`+"\n", x, y, hex(black))
+	}
+	fmt.Fprintln(b, `</svg>`)
+	return b.Flush()
+}
+
+// writePNG writes f as a PNG image without text.
+func (f *figure) writePNG(w io.Writer) error {
+	img := image.NewRGBA(image.Rect(0, 0, f.width, f.height))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = background.R, background.G, background.B, background.A
+	}
+	px := f.transform()
+	if f.axes {
+		min, max := f.bounds()
+		x0, y0 := px(min)
+		x1, y1 := px(max)
+		drawLine(img, x0, y0, x1, y0, axisColor)
+		drawLine(img, x0, y0, x0, y1, axisColor)
+	}
+	for _, s := range f.series {
+		for i := 1; i < len(s.points); i++ {
+			ax, ay := px(s.points[i-1])
+			bx, by := px(s.points[i])
+			drawLine(img, ax, ay, bx, by, s.color)
+		}
+		if s.closed && len(s.points) > 2 {
+			ax, ay := px(s.points[len(s.points)-1])
+			bx, by := px(s.points[0])
+			drawLine(img, ax, ay, bx, by, s.color)
+		}
+	}
+	for _, p := range f.markers {
+		x, y := px(p)
+		for dx := -2; dx <= 2; dx++ {
+			for dy := -2; dy <= 2; dy++ {
+				img.SetRGBA(int(math.Round(x))+dx, int(math.Round(y))+dy, black)
+			}
+		}
+	}
+	return png.Encode(w, img)
+}
+
+// writeFile atomically replaces path with f in the format chosen by the
+// extension of path, .svg or .png.
+func (f *figure) writeFile(path string) error {
+	write := f.writeSVG
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".svg":
+	case ".png":
+		write = f.writePNG
+	default:
+		return fmt.Errorf("%s is neither .svg nor .png", path)
+	}
+	tmp := path + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := write(out); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// drawLine draws a line between two pixels with Bresenham's algorithm.
+func drawLine(img *image.RGBA, fx0, fy0, fx1, fy1 float64, c color.RGBA) {
+	x0, y0 := int(math.Round(fx0)), int(math.Round(fy0))
+	x1, y1 := int(math.Round(fx1)), int(math.Round(fy1))
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	for e := dx + dy; ; {
+		img.SetRGBA(x0, y0, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func label(v float64) string {
+	return fmt.Sprintf("%.4g", v)
+}
+
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
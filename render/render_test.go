@@ -0,0 +1,130 @@
+package render_test
+
+import (
+	"bytes"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/genetics/problems"
+	"github.com/inlined/genetics/render"
+)
+
+// circle is a TSP of n cities evenly spaced around a circle, whose best tour
+// visits them in order.
+func circle(n int) problems.TSP {
+	t := problems.TSP{Name: "circle", Distances: make([][]int, n), Coords: make([][2]float64, n)}
+	for i := range t.Coords {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		t.Coords[i] = [2]float64{100 * math.Cos(a), 100 * math.Sin(a)}
+	}
+	for i := range t.Distances {
+		t.Distances[i] = make([]int, n)
+		for j := range t.Distances[i] {
+			t.Distances[i][j] = int(math.Round(math.Hypot(t.Coords[i][0]-t.Coords[j][0], t.Coords[i][1]-t.Coords[j][1])))
+		}
+	}
+	return t
+}
+
+func TestPlots(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	tsp := circle(8)
+	s := tsp.Species()
+	pop, err := s.NewPermPopulation(rng, 30)
+	if err != nil {
+		t.Fatalf("NewPermPopulation(); err=%s", err)
+	}
+	dir := t.TempDir()
+	fitness := &render.FitnessPlot{Path: filepath.Join(dir, "fitness.svg")}
+	tour := &render.TourPlot{Coords: tsp.Coords, Path: filepath.Join(dir, "tour.png")}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 20,
+			CrossoverRate:    0.9,
+			MutationRate:     0.2,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.PartiallyMappedCrossover{},
+			Mutator:          genetics.SwapMutation{},
+		},
+		Evaluator: genetics.FitnessFunc(tsp.Fitness),
+		Observers: []genetics.Observer{fitness, tour},
+	}
+	if err := engine.Run(rng, pop, 20); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if fitness.Err() != nil || tour.Err() != nil {
+		t.Fatalf("writing the plots; errs=%v, %v", fitness.Err(), tour.Err())
+	}
+
+	svg, err := os.ReadFile(fitness.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"<svg", "Generation", ">best<", ">mean<", ">20<"} {
+		if !strings.Contains(string(svg), want) {
+			t.Errorf("FitnessPlot SVG lacks %q:\n%s", want, svg)
+		}
+	}
+	if got := strings.Count(string(svg), "<polyline"); got != 2 {
+		t.Errorf("FitnessPlot SVG has %d lines; want 2 for best and mean", got)
+	}
+
+	b, err := os.ReadFile(tour.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("TourPlot wrote an invalid PNG; err=%s", err)
+	}
+	if size := img.Bounds().Size(); size.X != 600 || size.Y != 600 {
+		t.Errorf("TourPlot PNG is %v; want 600x600", size)
+	}
+	// The cities are drawn over the tour
+	if r, g, b, _ := img.At(300+int(math.Round(100*560/200.0)), 300).RGBA(); r|g|b != 0 {
+		t.Errorf("TourPlot PNG has no city at the right of the circle")
+	}
+
+	best, f := tour.Best()
+	if _, want := pop.Best(); f != want || len(best.Genes) != 8 {
+		t.Errorf("TourPlot.Best()=%v, %g; want a tour of 8 cities with the population's best fitness %g", best.Genes, f, want)
+	}
+	var buf bytes.Buffer
+	if err := tour.WriteSVG(&buf); err != nil {
+		t.Fatalf("TourPlot.WriteSVG(); err=%s", err)
+	}
+	if got := strings.Count(buf.String(), "<circle"); got != 8 || !strings.Contains(buf.String(), "<polygon") {
+		t.Errorf("TourPlot SVG has %d cities; want 8 and a closed tour:\n%s", got, buf.String())
+	}
+}
+
+func TestPlotErrors(t *testing.T) {
+	p := &render.FitnessPlot{}
+	if err := p.WriteFile(filepath.Join(t.TempDir(), "fitness.gif")); err == nil {
+		t.Error("WriteFile() should reject formats other than SVG and PNG")
+	}
+	// An empty chart is still an image
+	var buf bytes.Buffer
+	if err := p.WritePNG(&buf); err != nil {
+		t.Errorf("WritePNG() of an empty chart; err=%s", err)
+	}
+
+	tour := &render.TourPlot{Coords: [][2]float64{{0, 0}, {1, 1}}}
+	s := genetics.NewSpecies(3, 2)
+	pop := &genetics.Population{Species: s, Chromosomes: []genetics.Chromosome{s.New(0, 1, 2)}}
+	var engine genetics.Engine
+	engine.Reset(pop)
+	tour.OnGenerationStart(&engine, 0)
+	tour.OnStats(genetics.Stats{})
+	if err := tour.WriteSVG(&buf); err == nil {
+		t.Error("WriteSVG() should reject a tour visiting a city without Coords")
+	}
+}
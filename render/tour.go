@@ -0,0 +1,119 @@
+package render
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/inlined/genetics"
+)
+
+// TourPlot is a genetics.Observer which draws the best tour found by a run of a
+// permutation problem whose cities have 2D coordinates, e.g. the Coords of a
+// problems.TSP. Each allele of a tour is the index of a city in Coords.
+type TourPlot struct {
+	genetics.NopObserver
+
+	// Coords is the position of each city.
+	Coords [][2]float64
+	// Path, if set, is the file written when an Engine's run terminates. Its
+	// extension, .svg or .png, chooses the format.
+	Path string
+	// Width and Height are the size of the image in pixels. Default to 600 by 600.
+	Width, Height int
+
+	engine      *genetics.Engine
+	best        genetics.Chromosome
+	bestFitness genetics.Fitness
+	err         error
+}
+
+// OnGenerationStart implements genetics.Observer by forgetting the best tour at
+// generation 0.
+func (p *TourPlot) OnGenerationStart(e *genetics.Engine, generation int) {
+	p.engine = e
+	if generation == 0 {
+		p.best = genetics.Chromosome{}
+	}
+}
+
+// OnStats implements genetics.StatsObserver by keeping the fittest tour of the
+// population.
+func (p *TourPlot) OnStats(s genetics.Stats) {
+	if p.engine == nil {
+		return
+	}
+	pop := p.engine.Population()
+	best, f := pop.Best()
+	if p.best.Genes == nil || pop.Objective.Better(f, p.bestFitness) {
+		p.best, p.bestFitness = best.Clone(), f
+	}
+}
+
+// OnTermination implements genetics.Observer by writing Path, if set.
+func (p *TourPlot) OnTermination(e *genetics.Engine, err error) {
+	if p.Path != "" {
+		p.err = p.WriteFile(p.Path)
+	}
+}
+
+// Err returns the error of the last write made when a run terminated, if any.
+func (p *TourPlot) Err() error {
+	return p.err
+}
+
+// Best returns the best tour seen and its Fitness.
+func (p *TourPlot) Best() (genetics.Chromosome, genetics.Fitness) {
+	return p.best, p.bestFitness
+}
+
+// WriteSVG writes the best tour as an SVG image.
+func (p *TourPlot) WriteSVG(w io.Writer) error {
+	f, err := p.figure()
+	if err != nil {
+		return err
+	}
+	return f.writeSVG(w)
+}
+
+// WritePNG writes the best tour as a PNG image.
+func (p *TourPlot) WritePNG(w io.Writer) error {
+	f, err := p.figure()
+	if err != nil {
+		return err
+	}
+	return f.writePNG(w)
+}
+
+// WriteFile atomically replaces path with the best tour in the format chosen by
+// its extension, .svg or .png.
+func (p *TourPlot) WriteFile(path string) error {
+	f, err := p.figure()
+	if err != nil {
+		return err
+	}
+	return f.writeFile(path)
+}
+
+func (p *TourPlot) figure() (*figure, error) {
+	points := make([][2]float64, len(p.best.Genes))
+	for i, city := range p.best.Genes {
+		if city < 0 || city >= len(p.Coords) {
+			return nil, fmt.Errorf("TourPlot.figure(); city %d has no Coords", city)
+		}
+		points[i] = p.Coords[city]
+	}
+	f := &figure{
+		width:       p.Width,
+		height:      p.Height,
+		equalAspect: true,
+		series:      []series{{color: blue, points: points, closed: true}},
+		markers:     p.Coords,
+	}
+	if p.best.Genes != nil {
+		f.title = fmt.Sprintf("Fitness %g", float64(p.bestFitness))
+	}
+	if f.width <= 0 || f.height <= 0 {
+		f.width, f.height = 600, 600
+	}
+	return f, nil
+}
@@ -0,0 +1,55 @@
+package genetics
+
+import (
+	"fmt"
+
+	"github.com/inlined/rand"
+)
+
+const permutationRepair = "PermutationRepair"
+
+// Repairer restores invariants of a Chromosome which variation operators may break,
+// e.g. custom crossovers which duplicate alleles in a permutation. Constraints are
+// also Repairers.
+type Repairer interface {
+	fmt.Stringer
+	Repair(r rand.Rand, c *Chromosome)
+}
+
+// PermutationRepair restores Chromosomes which should be permutations of
+// [0, NumGenes). The first occurrence of each allele is kept and duplicate or
+// out-of-range alleles are replaced, in order, by the missing alleles in
+// ascending order.
+type PermutationRepair struct{}
+
+func (PermutationRepair) String() string {
+	return permutationRepair
+}
+
+// ConcurrentSafe implements ConcurrentSafe
+func (PermutationRepair) ConcurrentSafe() bool {
+	return true
+}
+
+// Repair implements Repairer
+func (PermutationRepair) Repair(r rand.Rand, c *Chromosome) {
+	n := len(c.Genes)
+	seen := make([]bool, n)
+	var invalid []int
+	for i, g := range c.Genes {
+		if g < 0 || g >= n || seen[g] {
+			invalid = append(invalid, i)
+			continue
+		}
+		seen[g] = true
+	}
+
+	missing := 0
+	for _, i := range invalid {
+		for seen[missing] {
+			missing++
+		}
+		c.Genes[i] = Gene(missing)
+		seen[missing] = true
+	}
+}
@@ -0,0 +1,86 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestPermutationRepair(t *testing.T) {
+	s := genetics.NewSpecies(5, 10)
+	for _, test := range []struct {
+		tag   string
+		genes []genetics.Gene
+		want  []genetics.Gene
+	}{
+		{
+			tag:   "valid",
+			genes: []genetics.Gene{4, 2, 0, 1, 3},
+			want:  []genetics.Gene{4, 2, 0, 1, 3},
+		}, {
+			tag:   "duplicate",
+			genes: []genetics.Gene{4, 2, 4, 1, 3},
+			want:  []genetics.Gene{4, 2, 0, 1, 3},
+		}, {
+			tag:   "out of range",
+			genes: []genetics.Gene{4, 9, 2, 1, 3},
+			want:  []genetics.Gene{4, 0, 2, 1, 3},
+		}, {
+			tag:   "many",
+			genes: []genetics.Gene{1, 1, 1, 1, 1},
+			want:  []genetics.Gene{1, 0, 2, 3, 4},
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			c := s.New(test.genes...)
+			genetics.PermutationRepair{}.Repair(rand.New(), &c)
+			if diff := cmp.Diff(test.want, c.Genes); diff != "" {
+				t.Errorf("Repair(%v) = %v; diff=%s", test.genes, c.Genes, diff)
+			}
+		})
+	}
+}
+
+// duplicatingCrossover is a broken permutation crossover which copies a's first gene over b's.
+type duplicatingCrossover struct{}
+
+func (duplicatingCrossover) String() string { return "DuplicatingCrossover" }
+
+func (duplicatingCrossover) Crossover(r rand.Rand, a, b genetics.Chromosome) (x, y genetics.Chromosome) {
+	x, y = a.Species.New(a.Genes...), b.Species.New(b.Genes...)
+	y.Genes[0] = a.Genes[0]
+	return x, y
+}
+
+func TestEvolverRepairer(t *testing.T) {
+	rng := rand.New()
+	s := genetics.NewSpecies(6, 5)
+	pop := make([]genetics.Chromosome, 6)
+	scores := make([]genetics.Fitness, len(pop))
+	for i := range pop {
+		pop[i], _ = s.NewPerm(rng)
+	}
+	evolver := genetics.Evolver{
+		ReplacementCount: 4,
+		CrossoverRate:    1,
+		Selector:         genetics.TournamentSelection{Size: 2},
+		Crossover:        duplicatingCrossover{},
+		Mutator:          genetics.SwapMutation{},
+		Repairer:         genetics.PermutationRepair{},
+	}
+	for run := 0; run < 10; run++ {
+		evolver.Evolve(rng, pop, scores)
+	}
+	for n, c := range pop {
+		seen := map[genetics.Gene]bool{}
+		for _, g := range c.Genes {
+			seen[g] = true
+		}
+		if len(seen) != len(c.Genes) {
+			t.Errorf("chromosome %d = %v is not a permutation", n, c.Genes)
+		}
+	}
+}
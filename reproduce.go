@@ -0,0 +1,225 @@
+package genetics
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/inlined/rand"
+)
+
+// modulePath is used to find the package's version in the build info
+const modulePath = "github.com/inlined/genetics"
+
+// EvolverConfig is the serializable configuration of an Evolver. Operators are
+// recorded by name in the syntax of NaturalSelectionFlag, CrossoverFlag, and
+// MutationFlag. An empty Crossover or Mutator records an Evolver without one. As for Evolver,
+// a crossoverRate of 0 never recombines; callers decoding user-written configs
+// (e.g. package config) default an omitted crossoverRate to DefaultCrossoverRate.
+type EvolverConfig struct {
+	ReplacementCount int     `json:"replacementCount"`
+	CrossoverRate    float32 `json:"crossoverRate"`
+	MutationRate     float32 `json:"mutationRate"`
+	Selector         string  `json:"selector"`
+	Crossover        string  `json:"crossover"`
+	Mutator          string  `json:"mutator"`
+	DuplicateRetries int     `json:"duplicateRetries,omitempty"`
+	DistinctParents  bool    `json:"distinctParents,omitempty"`
+	// MatingThreshold, if positive, records a MatingRestriction whose Distance is
+	// the DistanceFunc registered as MatingDistance, or the default if it is empty.
+	MatingDistance  string  `json:"matingDistance,omitempty"`
+	MatingThreshold float64 `json:"matingThreshold,omitempty"`
+}
+
+// RunManifest captures everything needed to reproduce an Engine run apart from the
+// fitness function: the seed, the Species, how the initial population is created,
+// the Evolver's configuration, and the versions of the package and Go. After Run,
+// it also records the best Chromosome so that Replay can confirm a reproduction.
+// RunManifests encode to JSON. Engine features other than the Evolver (e.g. a
+// LocalSearch or MutationControl) are not captured, and NewRunManifest refuses
+// Evolvers with features it cannot record.
+type RunManifest struct {
+	Version   string `json:"version"`
+	GoVersion string `json:"goVersion"`
+
+	Seed    int64         `json:"seed"`
+	Species *Species      `json:"species"`
+	Evolver EvolverConfig `json:"evolver"`
+	// Permutation creates the initial population with NewPerm instead of NewRand.
+	Permutation    bool `json:"permutation,omitempty"`
+	PopulationSize int  `json:"populationSize"`
+	Generations    int  `json:"generations"`
+	// Objective is the initial population's Objective.
+	Objective Objective `json:"objective,omitempty"`
+
+	Best        []Gene  `json:"best,omitempty"`
+	BestFitness Fitness `json:"bestFitness,omitempty"`
+}
+
+// NewRunManifest describes a run of e. It fails if any of e's operators cannot be
+// restored from its name, if e has a Repairer or Validator, which have no names,
+// or if its MatingRestriction measures Distance with an unregistered DistanceFunc.
+func NewRunManifest(seed int64, s *Species, e Evolver, populationSize, generations int) (*RunManifest, error) {
+	switch {
+	case e.Repairer != nil:
+		return nil, fmt.Errorf("NewRunManifest(); Repairer %s cannot be recorded", e.Repairer)
+	case e.Validator != nil:
+		return nil, fmt.Errorf("NewRunManifest(); a Validator cannot be recorded")
+	}
+	crossover, mutator := "", ""
+	if e.Crossover != nil {
+		crossover = e.Crossover.String()
+	}
+	if e.Mutator != nil {
+		mutator = e.Mutator.String()
+	}
+	var matingDistance string
+	var matingThreshold float64
+	if r := e.MatingRestriction; r != nil {
+		if r.Distance != nil {
+			var ok bool
+			if matingDistance, ok = registeredName(r.Distance); !ok {
+				return nil, fmt.Errorf("NewRunManifest(); the MatingRestriction Distance is not a registered DistanceFunc")
+			}
+		}
+		matingThreshold = r.Threshold
+	}
+	m := &RunManifest{
+		Version:   packageVersion(),
+		GoVersion: runtime.Version(),
+		Seed:      seed,
+		Species:   s,
+		Evolver: EvolverConfig{
+			ReplacementCount: e.ReplacementCount,
+			CrossoverRate:    e.CrossoverRate,
+			MutationRate:     e.MutationRate,
+			Selector:         e.Selector.String(),
+			Crossover:        crossover,
+			Mutator:          mutator,
+			DuplicateRetries: e.DuplicateRetries,
+			DistinctParents:  e.DistinctParents,
+			MatingDistance:   matingDistance,
+			MatingThreshold:  matingThreshold,
+		},
+		PopulationSize: populationSize,
+		Generations:    generations,
+	}
+	if _, err := m.Evolver.Evolver(); err != nil {
+		return nil, fmt.Errorf("NewRunManifest(); err=%s", err)
+	}
+	return m, nil
+}
+
+// Evolver restores the Evolver described by c.
+func (c EvolverConfig) Evolver() (Evolver, error) {
+	sel, err := ParseSelection(c.Selector)
+	if err != nil {
+		return Evolver{}, err
+	}
+	var x Crossover
+	if c.Crossover != "" {
+		if x, err = ParseCrossover(c.Crossover); err != nil {
+			return Evolver{}, err
+		}
+	}
+	var mut Mutator
+	if c.Mutator != "" {
+		if mut, err = ParseMutator(c.Mutator); err != nil {
+			return Evolver{}, err
+		}
+	}
+	var restriction *MatingRestriction
+	if c.MatingThreshold != 0 {
+		restriction = &MatingRestriction{Threshold: c.MatingThreshold}
+		if c.MatingDistance != "" {
+			if restriction.Distance, err = LookupDistance(c.MatingDistance); err != nil {
+				return Evolver{}, err
+			}
+		}
+	}
+	return Evolver{
+		ReplacementCount:  c.ReplacementCount,
+		CrossoverRate:     c.CrossoverRate,
+		MutationRate:      c.MutationRate,
+		Selector:          sel,
+		Crossover:         x,
+		Mutator:           mut,
+		DuplicateRetries:  c.DuplicateRetries,
+		DistinctParents:   c.DistinctParents,
+		MatingRestriction: restriction,
+	}, nil
+}
+
+// Run performs the run described by m with eval, records its best Chromosome, and
+// returns the final population.
+func (m *RunManifest) Run(eval Evaluator) (*Population, error) {
+	pop, err := m.run(eval)
+	if err != nil {
+		return nil, err
+	}
+	best, f := pop.Best()
+	m.Best, m.BestFitness = best.Genes, f
+	return pop, nil
+}
+
+// Replay repeats the run described by m with eval and fails unless it finds the
+// same best Chromosome that Run recorded.
+func (m *RunManifest) Replay(eval Evaluator) (*Population, error) {
+	if m.Best == nil {
+		return nil, fmt.Errorf("RunManifest.Replay(); the manifest has no recorded result")
+	}
+	pop, err := m.run(eval)
+	if err != nil {
+		return nil, err
+	}
+	best, f := pop.Best()
+	if f != m.BestFitness || !genesEqual(best.Genes, m.Best) {
+		return pop, fmt.Errorf("RunManifest.Replay(); found %v scoring %g; recorded %v scoring %g (recorded with %s and %s; replayed with %s and %s)",
+			best.Genes, f, m.Best, m.BestFitness, m.Version, m.GoVersion, packageVersion(), runtime.Version())
+	}
+	return pop, nil
+}
+
+func (m *RunManifest) run(eval Evaluator) (*Population, error) {
+	e, err := m.Evolver.Evolver()
+	if err != nil {
+		return nil, fmt.Errorf("RunManifest.Run(); err=%s", err)
+	}
+	r := rand.New()
+	r.Seed(m.Seed)
+	var pop *Population
+	if m.Permutation {
+		pop, err = m.Species.NewPermPopulation(r, m.PopulationSize)
+	} else {
+		pop, err = m.Species.NewRandPopulation(r, m.PopulationSize)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("RunManifest.Run(); err=%s", err)
+	}
+	pop.Objective = m.Objective
+	engine := Engine{Evolver: e, Evaluator: eval}
+	if err := engine.Run(r, pop, m.Generations); err != nil {
+		return nil, fmt.Errorf("RunManifest.Run(); err=%s", err)
+	}
+	return pop, nil
+}
+
+// packageVersion is the version of this package in the running binary's build info.
+func packageVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "unknown"
+}
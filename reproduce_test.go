@@ -0,0 +1,180 @@
+package genetics_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/inlined/genetics"
+)
+
+func TestRunManifestReplay(t *testing.T) {
+	m, err := genetics.NewRunManifest(42, genetics.NewSpecies(12, 11), genetics.Evolver{
+		ReplacementCount: 6,
+		CrossoverRate:    0.9,
+		MutationRate:     0.2,
+		Selector:         genetics.TournamentSelection{Size: 3},
+		Crossover:        genetics.DavisOrderCrossover{},
+		Mutator:          genetics.SwapMutation{},
+	}, 20, 15)
+	if err != nil {
+		t.Fatalf("NewRunManifest(); err=%s", err)
+	}
+	m.Permutation = true
+	eval := genetics.FitnessFunc(inPlace)
+	if _, err := m.Replay(eval); err == nil {
+		t.Error("Replay() should fail before Run records a result")
+	}
+	pop, err := m.Run(eval)
+	if err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if _, f := pop.Best(); f != m.BestFitness || m.Best == nil {
+		t.Errorf("Run() recorded %v scoring %g; want the best of the population scoring %g", m.Best, m.BestFitness, f)
+	}
+	if m.GoVersion == "" || m.Version == "" {
+		t.Errorf("manifest is missing versions: %+v", m)
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal(); err=%s", err)
+	}
+	var decoded genetics.RunManifest
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("Unmarshal(%s); err=%s", b, err)
+	}
+	if diff := cmp.Diff(m, &decoded); diff != "" {
+		t.Errorf("manifest did not survive JSON; -want +got:\n%s", diff)
+	}
+	replayed, err := decoded.Replay(eval)
+	if err != nil {
+		t.Fatalf("Replay(); err=%s", err)
+	}
+	if diff := cmp.Diff(pop.Chromosomes, replayed.Chromosomes); diff != "" {
+		t.Errorf("Replay() evolved a different population; -want +got:\n%s", diff)
+	}
+
+	decoded.Best = []genetics.Gene{11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1, 0}
+	if _, err := decoded.Replay(eval); err == nil {
+		t.Error("Replay() should fail when the result differs from the recording")
+	}
+}
+
+type unnamedMutator struct{ genetics.SwapMutation }
+
+func (unnamedMutator) String() string { return "Custom" }
+
+func TestRunManifestUnrecordable(t *testing.T) {
+	s := genetics.NewSpecies(4, 3)
+	for _, test := range []struct {
+		name   string
+		modify func(e *genetics.Evolver)
+	}{
+		{name: "unknown operator", modify: func(e *genetics.Evolver) { e.Mutator = unnamedMutator{} }},
+		{name: "repairer", modify: func(e *genetics.Evolver) { e.Repairer = genetics.PermutationRepair{} }},
+		{name: "validator", modify: func(e *genetics.Evolver) { e.Validator = s.ValidatePermutation }},
+		{name: "unregistered distance", modify: func(e *genetics.Evolver) {
+			e.MatingRestriction = &genetics.MatingRestriction{
+				Distance:  func(a, b genetics.Chromosome) float64 { return 1 },
+				Threshold: 1,
+			}
+		}},
+	} {
+		e := genetics.Evolver{
+			ReplacementCount: 2,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator:          genetics.SwapMutation{},
+		}
+		test.modify(&e)
+		if _, err := genetics.NewRunManifest(1, s, e, 4, 1); err == nil {
+			t.Errorf("%s: NewRunManifest() should reject an Evolver which it cannot record", test.name)
+		}
+	}
+
+}
+
+func TestRunManifestWithoutOperator(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		evolver genetics.Evolver
+	}{
+		{
+			name: "mutation only",
+			evolver: genetics.Evolver{
+				ReplacementCount: 2,
+				MutationRate:     1,
+				Selector:         genetics.TournamentSelection{Size: 2},
+				Mutator:          genetics.SwapMutation{},
+			},
+		}, {
+			name: "crossover only",
+			evolver: genetics.Evolver{
+				ReplacementCount: 2,
+				CrossoverRate:    1,
+				Selector:         genetics.TournamentSelection{Size: 2},
+				Crossover:        genetics.MultiPointCrossover{Points: 1},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			m, err := genetics.NewRunManifest(1, genetics.NewSpecies(4, 3), test.evolver, 4, 2)
+			if err != nil {
+				t.Fatalf("NewRunManifest(); err=%s", err)
+			}
+			b, err := json.Marshal(m)
+			if err != nil {
+				t.Fatalf("Marshal(); err=%s", err)
+			}
+			var decoded genetics.RunManifest
+			if err := json.Unmarshal(b, &decoded); err != nil {
+				t.Fatalf("Unmarshal(%s); err=%s", b, err)
+			}
+			e, err := decoded.Evolver.Evolver()
+			if err != nil {
+				t.Fatalf("EvolverConfig.Evolver(); err=%s", err)
+			}
+			if fmt.Sprint(e.Crossover, e.Mutator) != fmt.Sprint(test.evolver.Crossover, test.evolver.Mutator) {
+				t.Errorf("EvolverConfig.Evolver() restored %v and %v; want %v and %v", e.Crossover, e.Mutator, test.evolver.Crossover, test.evolver.Mutator)
+			}
+			if _, err := decoded.Run(genetics.FitnessFunc(inPlace)); err != nil {
+				t.Errorf("Run(); err=%s", err)
+			}
+		})
+	}
+}
+
+func TestRunManifestMatingRestriction(t *testing.T) {
+	for _, restriction := range []*genetics.MatingRestriction{
+		{Threshold: 2},
+		{Distance: genetics.EuclideanDistance, Threshold: 3},
+	} {
+		m, err := genetics.NewRunManifest(1, genetics.NewSpecies(4, 3), genetics.Evolver{
+			ReplacementCount:  2,
+			Selector:          genetics.TournamentSelection{Size: 2},
+			Crossover:         genetics.MultiPointCrossover{Points: 1},
+			Mutator:           genetics.SwapMutation{},
+			MatingRestriction: restriction,
+		}, 4, 1)
+		if err != nil {
+			t.Fatalf("NewRunManifest(); err=%s", err)
+		}
+		e, err := m.Evolver.Evolver()
+		if err != nil {
+			t.Fatalf("EvolverConfig.Evolver(); err=%s", err)
+		}
+		got := e.MatingRestriction
+		if got == nil || got.Threshold != restriction.Threshold || (got.Distance == nil) != (restriction.Distance == nil) {
+			t.Errorf("EvolverConfig.Evolver() restored MatingRestriction %+v; want %+v", got, restriction)
+		} else if got.Distance != nil {
+			s := genetics.NewSpecies(2, 4)
+			a, b := s.New(0, 0), s.New(3, 4)
+			if got.Distance(a, b) != 5 {
+				t.Errorf("restored Distance=%g; want the Euclidean distance 5", got.Distance(a, b))
+			}
+		}
+	}
+}
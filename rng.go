@@ -0,0 +1,136 @@
+package genetics
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math"
+	mrand "math/rand/v2"
+
+	"github.com/inlined/rand"
+)
+
+// RNG is the small set of random methods the package relies on. Downstream users
+// can bring any source which implements it (or use the MathRand and CryptoRand
+// adapters) and convert it with AdaptRNG wherever the package takes a rand.Rand,
+// without depending on github.com/inlined/rand themselves.
+type RNG interface {
+	Int63n(n int64) int64
+	Float64() float64
+	Perm(n int) []int
+	Shuffle(n int, swap func(i, j int))
+	Read(p []byte) (int, error)
+}
+
+// AdaptRNG returns a rand.Rand backed by r. The methods RNG lacks are derived from
+// Int63n and Float64. Seed is ignored unless r also has a Seed(int64) method.
+func AdaptRNG(r RNG) rand.Rand {
+	return adaptedRNG{r}
+}
+
+type adaptedRNG struct {
+	RNG
+}
+
+func (a adaptedRNG) Int31n(n int32) int32 { return int32(a.Int63n(int64(n))) }
+func (a adaptedRNG) Intn(n int) int       { return int(a.Int63n(int64(n))) }
+func (a adaptedRNG) Int63() int64         { return a.Int63n(math.MaxInt64) }
+func (a adaptedRNG) Int31() int32         { return int32(a.Int63n(math.MaxInt32)) }
+func (a adaptedRNG) Int() int             { return int(a.Int63()) }
+func (a adaptedRNG) Uint32() uint32       { return uint32(a.Int63() >> 31) }
+func (a adaptedRNG) Uint64() uint64       { return uint64(a.Int63())>>31 | uint64(a.Int63())<<32 }
+func (a adaptedRNG) NormFloat64() float64 { return normFloat64(a) }
+
+func (a adaptedRNG) Float32() float32 {
+	// Float64 may round up to 1 when converted; resample as math/rand does.
+	for {
+		if f := float32(a.Float64()); f < 1 {
+			return f
+		}
+	}
+}
+
+func (a adaptedRNG) ExpFloat64() float64 {
+	return -math.Log(1 - a.Float64())
+}
+
+func (a adaptedRNG) Seed(seed int64) {
+	if s, ok := a.RNG.(interface{ Seed(int64) }); ok {
+		s.Seed(seed)
+	}
+}
+
+// MathRand adapts a math/rand/v2 generator, e.g.
+// mrand.New(mrand.NewPCG(seed1, seed2)), into an RNG. Read fills p from Uint64.
+func MathRand(r *mrand.Rand) RNG {
+	return mathRNG{r}
+}
+
+type mathRNG struct {
+	r *mrand.Rand
+}
+
+func (m mathRNG) Int63n(n int64) int64               { return m.r.Int64N(n) }
+func (m mathRNG) Float64() float64                   { return m.r.Float64() }
+func (m mathRNG) Perm(n int) []int                   { return m.r.Perm(n) }
+func (m mathRNG) Shuffle(n int, swap func(i, j int)) { m.r.Shuffle(n, swap) }
+
+func (m mathRNG) Read(p []byte) (int, error) {
+	var buf [8]byte
+	for i := 0; i < len(p); i += 8 {
+		binary.LittleEndian.PutUint64(buf[:], m.r.Uint64())
+		copy(p[i:], buf[:])
+	}
+	return len(p), nil
+}
+
+// CryptoRand is an RNG backed by crypto/rand. It cannot be seeded, so runs which
+// use it are not reproducible, and it is much slower than MathRand.
+func CryptoRand() RNG {
+	return cryptoRNG{}
+}
+
+type cryptoRNG struct{}
+
+func (cryptoRNG) uint64() uint64 {
+	var buf [8]byte
+	if _, err := crand.Read(buf[:]); err != nil {
+		panic("genetics: crypto/rand failed: " + err.Error())
+	}
+	return binary.LittleEndian.Uint64(buf[:])
+}
+
+func (c cryptoRNG) Int63n(n int64) int64 {
+	if n <= 0 {
+		panic("genetics: invalid argument to Int63n")
+	}
+	// Reject the top of the range so that every residue is equally likely.
+	max := math.MaxUint64 - math.MaxUint64%uint64(n)
+	for {
+		if v := c.uint64(); v < max {
+			return int64(v % uint64(n))
+		}
+	}
+}
+
+func (c cryptoRNG) Float64() float64 {
+	return float64(c.uint64()>>11) / (1 << 53)
+}
+
+func (c cryptoRNG) Perm(n int) []int {
+	p := make([]int, n)
+	for i := range p {
+		p[i] = i
+	}
+	c.Shuffle(n, func(i, j int) { p[i], p[j] = p[j], p[i] })
+	return p
+}
+
+func (c cryptoRNG) Shuffle(n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		swap(i, int(c.Int63n(int64(i+1))))
+	}
+}
+
+func (cryptoRNG) Read(p []byte) (int, error) {
+	return crand.Read(p)
+}
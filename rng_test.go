@@ -0,0 +1,82 @@
+package genetics_test
+
+import (
+	mrand "math/rand/v2"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/inlined/genetics"
+)
+
+func TestRNGAdapters(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		rng  genetics.RNG
+	}{
+		{name: "MathRand", rng: genetics.MathRand(mrand.New(mrand.NewPCG(1, 2)))},
+		{name: "CryptoRand", rng: genetics.CryptoRand()},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			r := genetics.AdaptRNG(test.rng)
+			for i := 0; i < 1000; i++ {
+				if v := r.Int31n(7); v < 0 || v >= 7 {
+					t.Fatalf("Int31n(7)=%d", v)
+				}
+				if v := r.Int63n(1 << 40); v < 0 || v >= 1<<40 {
+					t.Fatalf("Int63n(1<<40)=%d", v)
+				}
+				if f := r.Float32(); f < 0 || f >= 1 {
+					t.Fatalf("Float32()=%g", f)
+				}
+				if f := r.Float64(); f < 0 || f >= 1 {
+					t.Fatalf("Float64()=%g", f)
+				}
+			}
+			perm := r.Perm(10)
+			seen := make([]bool, 10)
+			for _, p := range perm {
+				seen[p] = true
+			}
+			for i, ok := range seen {
+				if !ok {
+					t.Errorf("Perm(10)=%v is missing %d", perm, i)
+				}
+			}
+			buf := make([]byte, 13)
+			if n, err := r.Read(buf); n != 13 || err != nil {
+				t.Errorf("Read()=%d,%v; want 13,nil", n, err)
+			}
+
+			s := genetics.NewSpecies(8, 7)
+			if _, err := s.NewPerm(r); err != nil {
+				t.Errorf("NewPerm(); err=%s", err)
+			}
+		})
+	}
+}
+
+func TestMathRandReproducible(t *testing.T) {
+	run := func() []genetics.Chromosome {
+		r := genetics.AdaptRNG(genetics.MathRand(mrand.New(mrand.NewPCG(42, 0))))
+		pop := newBinaryPopulation(t, r, 8, 10)
+		engine := genetics.Engine{
+			Evolver: genetics.Evolver{
+				ReplacementCount: 4,
+				CrossoverRate:    1,
+				MutationRate:     0.5,
+				Selector:         genetics.TournamentSelection{Size: 2},
+				Crossover:        genetics.MultiPointCrossover{Points: 1},
+				Mutator:          genetics.SwapMutation{},
+			},
+			Evaluator: genetics.FitnessFunc(oneMax),
+		}
+		if err := engine.Run(r, pop, 5); err != nil {
+			t.Fatalf("Run(); err=%s", err)
+		}
+		return pop.Chromosomes
+	}
+	if diff := cmp.Diff(run(), run()); diff != "" {
+		t.Errorf("runs with the same seed differ; -first +second:\n%s", diff)
+	}
+}
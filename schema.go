@@ -0,0 +1,202 @@
+package genetics
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// wildcard is the symbol of a Schema for a locus which matches any allele
+const wildcard = "*"
+
+// Schema is a pattern of genes in which some loci are fixed to an allele and the
+// rest are wildcards, e.g. 1**0*. Holland's schema theorem predicts that short,
+// low-order schemata fitter than the population's average (building blocks) grow
+// in frequency unless crossover and mutation disrupt them.
+type Schema struct {
+	// Alleles holds the allele of each locus which Fixed reports is fixed. The
+	// remaining loci are wildcards.
+	Alleles []Gene
+	Fixed   []bool
+}
+
+// ParseSchema parses a Schema written with one character per locus, e.g. 1**0*,
+// or with comma-separated loci for alleles above 9, e.g. 12,*,*,0,*. A * is a
+// wildcard.
+func ParseSchema(pattern string) (Schema, error) {
+	loci := strings.Split(pattern, ",")
+	if !strings.Contains(pattern, ",") {
+		loci = strings.Split(pattern, "")
+	}
+	s := Schema{Alleles: make([]Gene, len(loci)), Fixed: make([]bool, len(loci))}
+	for i, l := range loci {
+		l = strings.TrimSpace(l)
+		if l == wildcard {
+			continue
+		}
+		a, err := strconv.Atoi(l)
+		if err != nil {
+			return Schema{}, fmt.Errorf("ParseSchema(%s); locus %d: %q is neither an allele nor %s", pattern, i, l, wildcard)
+		}
+		s.Alleles[i], s.Fixed[i] = a, true
+	}
+	return s, nil
+}
+
+// String writes s in the format read by ParseSchema, using commas only if an
+// allele has more than one digit.
+func (s Schema) String() string {
+	loci := make([]string, len(s.Fixed))
+	sep := ""
+	for i, fixed := range s.Fixed {
+		loci[i] = wildcard
+		if fixed {
+			loci[i] = strconv.Itoa(s.Alleles[i])
+			if len(loci[i]) != 1 {
+				sep = ","
+			}
+		}
+	}
+	return strings.Join(loci, sep)
+}
+
+// Matches reports whether c is an instance of s: c holds the allele of s at each
+// of its fixed loci.
+func (s Schema) Matches(c Chromosome) bool {
+	if len(c.Genes) != len(s.Fixed) {
+		return false
+	}
+	for i, fixed := range s.Fixed {
+		if fixed && c.Genes[i] != s.Alleles[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Order is the number of fixed loci of s. Mutation disrupts schemata of higher
+// order more often.
+func (s Schema) Order() int {
+	order := 0
+	for _, fixed := range s.Fixed {
+		if fixed {
+			order++
+		}
+	}
+	return order
+}
+
+// DefiningLength is the distance between the first and last fixed loci of s.
+// One-point crossover disrupts schemata of greater defining length more often.
+func (s Schema) DefiningLength() int {
+	first, last := -1, -1
+	for i, fixed := range s.Fixed {
+		if fixed {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		return 0
+	}
+	return last - first
+}
+
+// SchemaStats measures a Schema in one generation of a population.
+type SchemaStats struct {
+	Generation int
+	// Count is the number of members of the population which match the Schema.
+	Count int
+	// Frequency is the fraction of the population which matches the Schema.
+	Frequency float64
+	// MeanFitness is the mean Fitness of the matching members, or 0 if there are
+	// none, and PopulationMean is the mean Fitness of the whole population.
+	MeanFitness    float64
+	PopulationMean float64
+}
+
+// SchemaTracker is an Observer which follows the frequency and mean fitness of
+// Schemata through each scored generation of a run, e.g. to teach the schema
+// theorem or to diagnose operators which disrupt building blocks: a schema fitter
+// than the population whose frequency still falls is being broken up faster than
+// selection can spread it. Add a *SchemaTracker to Engine.Observers, or call
+// Record directly for other drivers.
+type SchemaTracker struct {
+	NopObserver
+
+	Schemata []Schema
+
+	engine  *Engine
+	history [][]SchemaStats
+}
+
+// OnGenerationStart implements Observer by starting a new history at generation 0.
+func (t *SchemaTracker) OnGenerationStart(e *Engine, generation int) {
+	t.engine = e
+	if generation == 0 {
+		t.history = nil
+	}
+}
+
+// OnStats implements StatsObserver
+func (t *SchemaTracker) OnStats(s Stats) {
+	if t.engine != nil {
+		t.Record(s.Generation, t.engine.Population())
+	}
+}
+
+// Record measures each of the Schemata in the scored pop.
+func (t *SchemaTracker) Record(generation int, pop *Population) {
+	if len(t.history) != len(t.Schemata) {
+		t.history = make([][]SchemaStats, len(t.Schemata))
+	}
+	total := 0.0
+	for _, f := range pop.Fitness {
+		total += float64(f)
+	}
+	mean := 0.0
+	if len(pop.Fitness) != 0 {
+		mean = total / float64(len(pop.Fitness))
+	}
+	for n, schema := range t.Schemata {
+		s := SchemaStats{Generation: generation, PopulationMean: mean}
+		sum := 0.0
+		for i, c := range pop.Chromosomes {
+			if schema.Matches(c) {
+				s.Count++
+				sum += float64(pop.Fitness[i])
+			}
+		}
+		if s.Count != 0 {
+			s.Frequency = float64(s.Count) / float64(len(pop.Chromosomes))
+			s.MeanFitness = sum / float64(s.Count)
+		}
+		t.history[n] = append(t.history[n], s)
+	}
+}
+
+// History returns the SchemaStats of Schemata[n] for each recorded generation, in
+// order.
+func (t *SchemaTracker) History(n int) []SchemaStats {
+	if n >= len(t.history) {
+		return nil
+	}
+	return t.history[n]
+}
+
+// WriteTable writes the recorded SchemaStats as an aligned text table, grouped by
+// Schema.
+func (t *SchemaTracker) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "Schema\tGeneration\tCount\tFrequency\tMeanFitness\tPopulationMean")
+	for n, history := range t.history {
+		for _, s := range history {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%.3f\t%.3g\t%.3g\n", t.Schemata[n], s.Generation, s.Count, s.Frequency, s.MeanFitness, s.PopulationMean)
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,147 @@
+package genetics_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestParseSchema(t *testing.T) {
+	for _, test := range []struct {
+		pattern        string
+		want           string
+		order, defined int
+	}{
+		{pattern: "1**0*", want: "1**0*", order: 2, defined: 3},
+		{pattern: "*****", want: "*****"},
+		{pattern: "12,*,*,0,*", want: "12,*,*,0,*", order: 2, defined: 3},
+		{pattern: "1, *, 0", want: "1*0", order: 2, defined: 2},
+		{pattern: "**7*", want: "**7*", order: 1},
+	} {
+		s, err := genetics.ParseSchema(test.pattern)
+		if err != nil {
+			t.Errorf("ParseSchema(%s); err=%s", test.pattern, err)
+			continue
+		}
+		if got := s.String(); got != test.want {
+			t.Errorf("ParseSchema(%s).String()=%s; want %s", test.pattern, got, test.want)
+		}
+		if s.Order() != test.order || s.DefiningLength() != test.defined {
+			t.Errorf("ParseSchema(%s) has order %d and defining length %d; want %d and %d", test.pattern, s.Order(), s.DefiningLength(), test.order, test.defined)
+		}
+	}
+	for _, pattern := range []string{"1x0", "1,,0"} {
+		if _, err := genetics.ParseSchema(pattern); err == nil {
+			t.Errorf("ParseSchema(%s) should fail", pattern)
+		}
+	}
+
+	sp := genetics.NewSpecies(5, 1)
+	s, _ := genetics.ParseSchema("1**0*")
+	for _, test := range []struct {
+		genes []genetics.Gene
+		want  bool
+	}{
+		{genes: []genetics.Gene{1, 0, 1, 0, 1}, want: true},
+		{genes: []genetics.Gene{1, 1, 1, 0, 0}, want: true},
+		{genes: []genetics.Gene{0, 0, 1, 0, 1}},
+		{genes: []genetics.Gene{1, 0, 1, 1, 1}},
+	} {
+		if got := s.Matches(sp.New(test.genes...)); got != test.want {
+			t.Errorf("%s.Matches(%v)=%t; want %t", s, test.genes, got, test.want)
+		}
+	}
+}
+
+func TestSchemaTracker(t *testing.T) {
+	s := genetics.NewSpecies(4, 1)
+	schema := func(pattern string) genetics.Schema {
+		sc, err := genetics.ParseSchema(pattern)
+		if err != nil {
+			t.Fatalf("ParseSchema(%s); err=%s", pattern, err)
+		}
+		return sc
+	}
+	tracker := &genetics.SchemaTracker{Schemata: []genetics.Schema{schema("11**"), schema("0***")}}
+	pop := &genetics.Population{
+		Species:     s,
+		Chromosomes: []genetics.Chromosome{s.New(1, 1, 0, 0), s.New(1, 1, 1, 1), s.New(0, 0, 0, 0), s.New(1, 0, 1, 0)},
+		Fitness:     []genetics.Fitness{2, 4, 0, 2},
+	}
+	tracker.Record(7, pop)
+	want := [][]genetics.SchemaStats{
+		{{Generation: 7, Count: 2, Frequency: 0.5, MeanFitness: 3, PopulationMean: 2}},
+		{{Generation: 7, Count: 1, Frequency: 0.25, MeanFitness: 0, PopulationMean: 2}},
+	}
+	for n := range want {
+		if diff := cmp.Diff(want[n], tracker.History(n)); diff != "" {
+			t.Errorf("History(%d) diff=%s", n, diff)
+		}
+	}
+	var b strings.Builder
+	if err := tracker.WriteTable(&b); err != nil {
+		t.Fatalf("WriteTable(); err=%s", err)
+	}
+	wantTable := `Schema  Generation  Count  Frequency  MeanFitness  PopulationMean
+11**    7           2      0.500      3            2
+0***    7           1      0.250      0            2
+`
+	if diff := cmp.Diff(wantTable, b.String()); diff != "" {
+		t.Errorf("WriteTable() diff=%s", diff)
+	}
+}
+
+func TestSchemaTrackerEngine(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	s := genetics.NewSpecies(10, 1)
+	pop, err := s.NewRandPopulation(rng, 40)
+	if err != nil {
+		t.Fatalf("NewRandPopulation(); err=%s", err)
+	}
+	// A building block of OneMax
+	block, _ := genetics.ParseSchema("11********")
+	tracker := &genetics.SchemaTracker{Schemata: []genetics.Schema{block}}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 20,
+			CrossoverRate:    0.9,
+			MutationRate:     0.1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator:          genetics.RandomResettingMutation{},
+		},
+		// OneMax
+		Evaluator: genetics.FitnessFunc(func(c genetics.Chromosome) genetics.Fitness {
+			sum := 0
+			for _, g := range c.Genes {
+				sum += g
+			}
+			return genetics.Fitness(sum)
+		}),
+		Observers: []genetics.Observer{tracker},
+	}
+	if err := engine.Run(rng, pop, 20); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	history := tracker.History(0)
+	if len(history) != 21 {
+		t.Fatalf("History() recorded %d generations; want the 21 scored", len(history))
+	}
+	first, last := history[0], history[len(history)-1]
+	if !(last.Frequency > first.Frequency) || last.Generation != 20 {
+		t.Errorf("%s grew from %.2f to %.2f by generation %d; want it to spread by generation 20", block, first.Frequency, last.Frequency, last.Generation)
+	}
+
+	// Each run starts a new history
+	if err := engine.Run(rng, pop, 2); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if got := len(tracker.History(0)); got != 3 {
+		t.Errorf("History() after a second run recorded %d generations; want 3", got)
+	}
+}
@@ -0,0 +1,45 @@
+package genetics
+
+import (
+	"github.com/inlined/rand"
+)
+
+// SeedSplitter derives independent child seeds from a single master seed with
+// SplitMix64, so that runs which use one random stream per goroutine (e.g. per
+// Island) are reproducible from the master seed alone, regardless of how the
+// goroutines are scheduled. Child seeds are well mixed even for consecutive
+// master seeds. A SeedSplitter is not goroutine safe; split all streams before
+// starting goroutines.
+type SeedSplitter struct {
+	state uint64
+}
+
+// NewSeedSplitter creates a SeedSplitter for master.
+func NewSeedSplitter(master int64) *SeedSplitter {
+	return &SeedSplitter{state: uint64(master)}
+}
+
+// Next returns the next child seed.
+func (s *SeedSplitter) Next() int64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
+}
+
+// Rand returns a new random stream seeded with the next child seed.
+func (s *SeedSplitter) Rand() rand.Rand {
+	r := rand.New()
+	r.Seed(s.Next())
+	return r
+}
+
+// Streams returns n random streams seeded with the next n child seeds.
+func (s *SeedSplitter) Streams(n int) []rand.Rand {
+	streams := make([]rand.Rand, n)
+	for i := range streams {
+		streams[i] = s.Rand()
+	}
+	return streams
+}
@@ -0,0 +1,34 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/inlined/genetics"
+)
+
+func TestSeedSplitter(t *testing.T) {
+	// The first SplitMix64 outputs for a seed of 0
+	s := genetics.NewSeedSplitter(0)
+	want := []uint64{0xe220a8397b1dcdaf, 0x6e789e6aa1b965f4, 0x06c45d188009454f}
+	for i, w := range want {
+		if got := uint64(s.Next()); got != w {
+			t.Errorf("Next() #%d=%#x; want %#x", i, got, w)
+		}
+	}
+
+	first := func(master int64) []int64 {
+		var seeds []int64
+		for _, r := range genetics.NewSeedSplitter(master).Streams(3) {
+			seeds = append(seeds, r.Int63n(1<<62))
+		}
+		return seeds
+	}
+	if diff := cmp.Diff(first(1), first(1)); diff != "" {
+		t.Errorf("Streams() are not reproducible; -first +second:\n%s", diff)
+	}
+	if a, b := first(1), first(2); a[0] == b[0] || a[0] == a[1] {
+		t.Errorf("Streams() are not independent: %v and %v", a, b)
+	}
+}
@@ -0,0 +1,406 @@
+// Package server runs optimization jobs behind a REST API, so that the library can
+// be embedded as an optimization microservice. A Server serves:
+//
+//	POST   /jobs           submits a Job and replies with its Status
+//	GET    /jobs           lists the Status of every job
+//	GET    /jobs/{id}      polls the Status of a job
+//	GET    /jobs/{id}/best fetches the fittest Chromosome found so far
+//	DELETE /jobs/{id}      cancels a job if it is running and forgets it
+//
+// Requests and replies are JSON. A Job is a config.Config together with the name
+// of one of the Server's Problems or, if it names none, the Config's Fitness,
+// which must be an expression unless the Server AllowPlugins. Jobs larger than
+// the Server's MaxPopulation or MaxGenerations, and request bodies larger than
+// MaxRequestBytes, are refused with 400 Bad Request. A Server with a Store
+// persists the history and result of every job.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/genetics/config"
+	"github.com/inlined/genetics/fitness"
+	"github.com/inlined/genetics/store"
+)
+
+// MaxRequestBytes is the largest request body that a Server reads.
+const MaxRequestBytes = 1 << 20
+
+// The limits on a job's size of a Server which does not set its own.
+const (
+	DefaultMaxPopulation  = 10000
+	DefaultMaxGenerations = 100000
+)
+
+// Job is the body of a request to submit a job.
+type Job struct {
+	Config config.Config `json:"config"`
+	// Problem, if set, names the Server Problem which scores the job. Otherwise the
+	// job is scored with Config.Fitness.
+	Problem string `json:"problem,omitempty"`
+}
+
+// State is the stage of a job's life. A job is Running until it reaches its
+// Termination (Done), its Evaluator fails (Failed), or it is cancelled.
+type State string
+
+// The States of a job.
+const (
+	Running   State = "running"
+	Done      State = "done"
+	Failed    State = "failed"
+	Cancelled State = "cancelled"
+)
+
+// Status is the progress of a job.
+type Status struct {
+	ID    string `json:"id"`
+	State State  `json:"state"`
+	// Error is the error which ended a Failed job.
+	Error string `json:"error,omitempty"`
+	// Generation is the number of generations scored so far, of the most
+	// Generations the job will run.
+	Generation  int              `json:"generation"`
+	Generations int              `json:"generations"`
+	BestFitness genetics.Fitness `json:"bestFitness"`
+	MeanFitness float64          `json:"meanFitness"`
+	Evaluations int              `json:"evaluations"`
+}
+
+// Best is the reply to a request for the fittest Chromosome of a job.
+type Best struct {
+	Genes   []genetics.Gene  `json:"genes"`
+	Fitness genetics.Fitness `json:"fitness"`
+}
+
+// Server runs jobs and serves the REST API. The zero Server is ready to use; its
+// fields must not be modified once it has served a request. It is goroutine safe.
+type Server struct {
+	// Problems are the Evaluators which jobs may select by name.
+	Problems map[string]genetics.Evaluator
+	// MaxJobs, if set, is the most jobs which may run at once. Further jobs are
+	// refused with 503 Service Unavailable.
+	MaxJobs int
+	// Store, if set, records a Snapshot of every generation and the Result of
+	// every job under the job's ID. IDs continue from the largest numeric run ID
+	// already in the Store, so that a restarted Server does not reuse them. A job
+	// which cannot be stored fails.
+	Store store.Store
+	// AllowPlugins lets a job's Config.Fitness name a Go plugin, which the Server
+	// then opens and runs. Any client could run code on the Server's host, so
+	// by default only expressions are accepted.
+	AllowPlugins bool
+	// MaxPopulation and MaxGenerations, if set, are the largest PopulationSize
+	// and Termination.Generations that a job may have. They default to
+	// DefaultMaxPopulation and DefaultMaxGenerations.
+	MaxPopulation  int
+	MaxGenerations int
+
+	once sync.Once
+	mux  *http.ServeMux
+
+	mu      sync.Mutex
+	jobs    map[string]*job
+	next    int
+	running int
+}
+
+// job is a submitted job and its progress.
+type job struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu     sync.Mutex
+	status Status
+	best   Best
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.once.Do(func() {
+		s.mux = http.NewServeMux()
+		s.mux.HandleFunc("POST /jobs", s.submit)
+		s.mux.HandleFunc("GET /jobs", s.list)
+		s.mux.HandleFunc("GET /jobs/{id}", s.status)
+		s.mux.HandleFunc("GET /jobs/{id}/best", s.best)
+		s.mux.HandleFunc("DELETE /jobs/{id}", s.delete)
+	})
+	s.mux.ServeHTTP(w, r)
+}
+
+// Close cancels every running job and waits for them to stop.
+func (s *Server) Close() {
+	s.mu.Lock()
+	jobs := make([]*job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, j)
+	}
+	s.mu.Unlock()
+	for _, j := range jobs {
+		j.cancel()
+		<-j.done
+	}
+}
+
+// Submit validates j and starts running it in a new goroutine.
+func (s *Server) Submit(j Job) (Status, error) {
+	if err := j.Config.Validate(); err != nil {
+		return Status{}, fmt.Errorf("Server.Submit(); err=%s", err)
+	}
+	if err := s.checkLimits(j.Config); err != nil {
+		return Status{}, fmt.Errorf("Server.Submit(); err=%s", err)
+	}
+	eval, err := s.evaluator(j)
+	if err != nil {
+		return Status{}, fmt.Errorf("Server.Submit(); err=%s", err)
+	}
+	c := j.Config
+	r := c.Rand()
+	pop, err := c.Population(r)
+	if err != nil {
+		return Status{}, fmt.Errorf("Server.Submit(); err=%s", err)
+	}
+	engine, err := c.Engine(eval)
+	if err != nil {
+		return Status{}, fmt.Errorf("Server.Submit(); err=%s", err)
+	}
+
+	s.mu.Lock()
+	if s.MaxJobs > 0 && s.running >= s.MaxJobs {
+		s.mu.Unlock()
+		return Status{}, errBusy
+	}
+	if s.jobs == nil {
+		if err := s.resume(); err != nil {
+			s.mu.Unlock()
+			return Status{}, fmt.Errorf("Server.Submit(); err=%s", err)
+		}
+		s.jobs = map[string]*job{}
+	}
+	s.next++
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &job{
+		cancel: cancel,
+		done:   make(chan struct{}),
+		status: Status{ID: strconv.Itoa(s.next), State: Running, Generations: c.Termination.Generations},
+	}
+	s.jobs[run.status.ID] = run
+	s.running++
+	s.mu.Unlock()
+
+	stream := engine.RunStream(ctx, r, pop, c.Termination.Generations)
+	go func() {
+		defer close(run.done)
+		id := run.status.ID
+		var storeErr error
+		for snap := range stream.C {
+			if s.Store != nil && storeErr == nil {
+				// Keep draining the stream until the cancelled run stops
+				if storeErr = s.Store.SaveSnapshot(id, store.NewSnapshot(snap)); storeErr != nil {
+					cancel()
+				}
+			}
+			run.mu.Lock()
+			run.status.Generation = snap.Stats.Generation + 1
+			run.status.BestFitness = snap.Stats.Best
+			run.status.MeanFitness = snap.Stats.Mean
+			run.status.Evaluations += snap.Stats.Evaluations
+			run.best = Best{Genes: snap.Best.Genes, Fitness: snap.BestFitness}
+			run.mu.Unlock()
+		}
+		err := stream.Err()
+		run.mu.Lock()
+		switch {
+		case storeErr != nil:
+			run.status.State, run.status.Error = Failed, storeErr.Error()
+		case err == nil:
+			run.status.State = Done
+		case ctx.Err() != nil:
+			run.status.State = Cancelled
+		default:
+			run.status.State, run.status.Error = Failed, err.Error()
+		}
+		result := store.Result{
+			Generations: run.status.Generation,
+			Best:        run.best.Genes,
+			BestFitness: run.best.Fitness,
+			Finished:    time.Now(),
+		}
+		run.mu.Unlock()
+		if s.Store != nil && storeErr == nil {
+			if err != nil {
+				result.Error = err.Error()
+			}
+			if err := s.Store.SaveResult(id, result); err != nil {
+				run.mu.Lock()
+				run.status.State, run.status.Error = Failed, err.Error()
+				run.mu.Unlock()
+			}
+		}
+		cancel()
+		s.mu.Lock()
+		s.running--
+		s.mu.Unlock()
+	}()
+	return run.snapshot(), nil
+}
+
+// errBusy is returned by Submit when MaxJobs are already running.
+var errBusy = fmt.Errorf("Server.Submit(); too many jobs are running")
+
+// resume continues job IDs from the largest numeric run ID in the Store.
+func (s *Server) resume() error {
+	if s.Store == nil {
+		return nil
+	}
+	runs, err := s.Store.Runs()
+	if err != nil {
+		return err
+	}
+	for _, run := range runs {
+		if n, err := strconv.Atoi(run); err == nil && n > s.next {
+			s.next = n
+		}
+	}
+	return nil
+}
+
+// checkLimits returns an error if c is larger than the Server runs.
+func (s *Server) checkLimits(c config.Config) error {
+	maxPopulation, maxGenerations := s.MaxPopulation, s.MaxGenerations
+	if maxPopulation == 0 {
+		maxPopulation = DefaultMaxPopulation
+	}
+	if maxGenerations == 0 {
+		maxGenerations = DefaultMaxGenerations
+	}
+	switch {
+	case c.PopulationSize > maxPopulation:
+		return fmt.Errorf("PopulationSize %d exceeds the Server's limit of %d", c.PopulationSize, maxPopulation)
+	case c.Termination.Generations > maxGenerations:
+		return fmt.Errorf("Termination.Generations %d exceeds the Server's limit of %d", c.Termination.Generations, maxGenerations)
+	}
+	return nil
+}
+
+// evaluator returns the Evaluator which scores j.
+func (s *Server) evaluator(j Job) (genetics.Evaluator, error) {
+	if j.Problem == "" {
+		if fitness.IsPlugin(j.Config.Fitness) && !s.AllowPlugins {
+			return nil, fmt.Errorf("the Server does not allow plugins; Config.Fitness must be an expression")
+		}
+		return j.Config.Evaluator()
+	}
+	if j.Config.Fitness != "" {
+		return nil, fmt.Errorf("a job may not set both Problem %q and Config.Fitness", j.Problem)
+	}
+	eval, ok := s.Problems[j.Problem]
+	if !ok {
+		return nil, fmt.Errorf("unknown problem %q", j.Problem)
+	}
+	return eval, nil
+}
+
+// snapshot returns a copy of the job's Status.
+func (j *job) snapshot() Status {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status
+}
+
+// lookup returns the job named by the request's path, or replies with 404 Not
+// Found and returns nil.
+func (s *Server) lookup(w http.ResponseWriter, r *http.Request) *job {
+	s.mu.Lock()
+	j := s.jobs[r.PathValue("id")]
+	s.mu.Unlock()
+	if j == nil {
+		http.Error(w, fmt.Sprintf("unknown job %q", r.PathValue("id")), http.StatusNotFound)
+	}
+	return j
+}
+
+func (s *Server) submit(w http.ResponseWriter, r *http.Request) {
+	j := Job{Config: config.Config{Evolver: genetics.EvolverConfig{CrossoverRate: genetics.DefaultCrossoverRate}}}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxRequestBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&j); err != nil {
+		http.Error(w, fmt.Sprintf("malformed job: %s", err), http.StatusBadRequest)
+		return
+	}
+	status, err := s.Submit(j)
+	switch {
+	case err == errBusy:
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Location", "/jobs/"+status.ID)
+	reply(w, http.StatusCreated, status)
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	statuses := make([]Status, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		statuses = append(statuses, j.snapshot())
+	}
+	s.mu.Unlock()
+	sort.Slice(statuses, func(a, b int) bool {
+		x, _ := strconv.Atoi(statuses[a].ID)
+		y, _ := strconv.Atoi(statuses[b].ID)
+		return x < y
+	})
+	reply(w, http.StatusOK, statuses)
+}
+
+func (s *Server) status(w http.ResponseWriter, r *http.Request) {
+	if j := s.lookup(w, r); j != nil {
+		reply(w, http.StatusOK, j.snapshot())
+	}
+}
+
+func (s *Server) best(w http.ResponseWriter, r *http.Request) {
+	j := s.lookup(w, r)
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	best := j.best
+	j.mu.Unlock()
+	if best.Genes == nil {
+		http.Error(w, "no generation has been scored yet", http.StatusConflict)
+		return
+	}
+	reply(w, http.StatusOK, best)
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request) {
+	j := s.lookup(w, r)
+	if j == nil {
+		return
+	}
+	j.cancel()
+	<-j.done
+	s.mu.Lock()
+	delete(s.jobs, r.PathValue("id"))
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// reply writes v as the JSON body of a reply with code.
+func reply(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,268 @@
+package server_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/genetics/problems"
+	"github.com/inlined/genetics/server"
+	"github.com/inlined/genetics/store"
+)
+
+const evolver = `{
+    "replacementCount": 24,
+    "crossoverRate": 0.9,
+    "mutationRate": 0.3,
+    "selector": "TournamentSelection(3)",
+    "crossover": "MultiPoint(2)",
+    "mutator": "Inversion"
+  }`
+
+// job returns a Job for a binary Species of 20 genes which runs for generations.
+func job(problem, fitness string, generations int) string {
+	return fmt.Sprintf(`{
+  "config": {
+    "species": {"numGenes": 20, "maxAllele": 1},
+    "populationSize": 50,
+    "evolver": %s,
+    "termination": {"generations": %d, "targetFitness": 20},
+    "fitness": %q,
+    "seed": 42
+  },
+  "problem": %q
+}`, evolver, generations, fitness, problem)
+}
+
+func newServer(t *testing.T, maxJobs int) *httptest.Server {
+	return serve(t, maxJobs, nil)
+}
+
+func serve(t *testing.T, maxJobs int, st store.Store) *httptest.Server {
+	slow := genetics.FitnessFunc(func(c genetics.Chromosome) genetics.Fitness {
+		time.Sleep(time.Millisecond)
+		return 0
+	})
+	s := &server.Server{
+		Problems: map[string]genetics.Evaluator{
+			"onemax": genetics.FitnessFunc(problems.OneMax{N: 20}.Fitness),
+			"slow":   slow,
+		},
+		MaxJobs: maxJobs,
+		Store:   st,
+	}
+	ts := httptest.NewServer(s)
+	t.Cleanup(func() {
+		s.Close()
+		ts.Close()
+	})
+	return ts
+}
+
+func do(t *testing.T, method, url, body string, v interface{}) int {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("http.NewRequest(); err=%s", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s; err=%s", method, url, err)
+	}
+	defer resp.Body.Close()
+	if v != nil && resp.StatusCode < 300 {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("%s %s; malformed reply: %s", method, url, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// wait polls the job until it is no longer running.
+func wait(t *testing.T, url string) server.Status {
+	t.Helper()
+	for {
+		var status server.Status
+		if code := do(t, "GET", url, "", &status); code != http.StatusOK {
+			t.Fatalf("GET %s=%d; want 200", url, code)
+		}
+		if status.State != server.Running {
+			return status
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestJobs(t *testing.T) {
+	ts := newServer(t, 0)
+	for _, test := range []struct {
+		name    string
+		problem string
+		fitness string
+	}{
+		{name: "problem", problem: "onemax"},
+		{name: "fitness", fitness: "sum(g == 1)"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var status server.Status
+			if code := do(t, "POST", ts.URL+"/jobs", job(test.problem, test.fitness, 200), &status); code != http.StatusCreated {
+				t.Fatalf("POST /jobs=%d; want 201", code)
+			}
+			url := ts.URL + "/jobs/" + status.ID
+			status = wait(t, url)
+			if status.State != server.Done || status.BestFitness != 20 || status.Generation == 0 || status.Evaluations == 0 {
+				t.Errorf("GET %s=%+v; want a Done job with best fitness 20", url, status)
+			}
+			var best server.Best
+			if code := do(t, "GET", url+"/best", "", &best); code != http.StatusOK {
+				t.Fatalf("GET %s/best=%d; want 200", url, code)
+			}
+			if best.Fitness != 20 || len(best.Genes) != 20 {
+				t.Errorf("GET %s/best=%+v; want 20 ones", url, best)
+			}
+		})
+	}
+
+	var statuses []server.Status
+	if code := do(t, "GET", ts.URL+"/jobs", "", &statuses); code != http.StatusOK || len(statuses) != 2 || statuses[0].ID != "1" {
+		t.Errorf("GET /jobs=%d, %+v; want both jobs in order", code, statuses)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	ts := newServer(t, 1)
+	var status server.Status
+	if code := do(t, "POST", ts.URL+"/jobs", job("slow", "", server.DefaultMaxGenerations), &status); code != http.StatusCreated {
+		t.Fatalf("POST /jobs=%d; want 201", code)
+	}
+	if code := do(t, "POST", ts.URL+"/jobs", job("onemax", "", 10), nil); code != http.StatusServiceUnavailable {
+		t.Errorf("POST /jobs beyond MaxJobs=%d; want 503", code)
+	}
+	url := ts.URL + "/jobs/" + status.ID
+	if code := do(t, "DELETE", url, "", nil); code != http.StatusNoContent {
+		t.Fatalf("DELETE %s=%d; want 204", url, code)
+	}
+	if code := do(t, "GET", url, "", nil); code != http.StatusNotFound {
+		t.Errorf("GET %s after DELETE=%d; want 404", url, code)
+	}
+	if code := do(t, "POST", ts.URL+"/jobs", job("onemax", "", 10), nil); code != http.StatusCreated {
+		t.Errorf("POST /jobs after DELETE=%d; want 201", code)
+	}
+}
+
+func TestStore(t *testing.T) {
+	st := &store.FileStore{Dir: t.TempDir()}
+	for _, want := range []string{"1", "2"} {
+		// Each Server continues from the IDs of the jobs stored by the last.
+		ts := serve(t, 0, st)
+		var status server.Status
+		if code := do(t, "POST", ts.URL+"/jobs", job("onemax", "", 200), &status); code != http.StatusCreated {
+			t.Fatalf("POST /jobs=%d; want 201", code)
+		}
+		if status.ID != want {
+			t.Errorf("POST /jobs ID=%s; want %s", status.ID, want)
+		}
+		status = wait(t, ts.URL+"/jobs/"+status.ID)
+
+		snaps, err := st.LoadSnapshots(status.ID)
+		if err != nil {
+			t.Fatalf("LoadSnapshots(); err=%s", err)
+		}
+		if len(snaps) != status.Generation || snaps[len(snaps)-1].BestFitness != 20 {
+			t.Errorf("LoadSnapshots() has %d Snapshots; want %d ending with fitness 20", len(snaps), status.Generation)
+		}
+		result, err := st.LoadResult(status.ID)
+		if err != nil {
+			t.Fatalf("LoadResult(); err=%s", err)
+		}
+		if result.BestFitness != 20 || result.Generations != status.Generation || result.Error != "" {
+			t.Errorf("LoadResult()=%+v; want fitness 20 after %d generations", result, status.Generation)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	ts := newServer(t, 0)
+	for _, test := range []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{name: "malformed job", method: "POST", path: "/jobs", body: "{", want: http.StatusBadRequest},
+		{name: "unknown field", method: "POST", path: "/jobs", body: `{"problme": "onemax"}`, want: http.StatusBadRequest},
+		{name: "invalid config", method: "POST", path: "/jobs", body: `{"problem": "onemax"}`, want: http.StatusBadRequest},
+		{name: "unknown problem", method: "POST", path: "/jobs", body: job("twomax", "", 10), want: http.StatusBadRequest},
+		{name: "problem and fitness", method: "POST", path: "/jobs", body: job("onemax", "sum(g)", 10), want: http.StatusBadRequest},
+		{name: "no fitness", method: "POST", path: "/jobs", body: job("", "", 10), want: http.StatusBadRequest},
+		{name: "plugin", method: "POST", path: "/jobs", body: job("", "plugin:/tmp/evil.so#Fitness", 10), want: http.StatusBadRequest},
+		{name: "too many generations", method: "POST", path: "/jobs", body: job("onemax", "", server.DefaultMaxGenerations+1), want: http.StatusBadRequest},
+		{name: "population too large", method: "POST", path: "/jobs", body: strings.Replace(job("onemax", "", 10), `"populationSize": 50`, fmt.Sprintf(`"populationSize": %d`, server.DefaultMaxPopulation+1), 1), want: http.StatusBadRequest},
+		{name: "body too large", method: "POST", path: "/jobs", body: "{" + strings.Repeat(" ", server.MaxRequestBytes) + job("onemax", "", 10)[1:], want: http.StatusBadRequest},
+		{name: "unknown job", method: "GET", path: "/jobs/7", want: http.StatusNotFound},
+		{name: "unknown best", method: "GET", path: "/jobs/7/best", want: http.StatusNotFound},
+		{name: "unknown delete", method: "DELETE", path: "/jobs/7", want: http.StatusNotFound},
+		{name: "wrong method", method: "PUT", path: "/jobs", want: http.StatusMethodNotAllowed},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if code := do(t, test.method, ts.URL+test.path, test.body, nil); code != test.want {
+				t.Errorf("%s %s=%d; want %d", test.method, test.path, code, test.want)
+			}
+		})
+	}
+}
+
+func TestPlugins(t *testing.T) {
+	var j server.Job
+	if err := json.Unmarshal([]byte(job("", "plugin:/tmp/evil.so#Fitness", 10)), &j); err != nil {
+		t.Fatalf("json.Unmarshal(); err=%s", err)
+	}
+	// The plugin must be refused before it is opened
+	s := &server.Server{}
+	if _, err := s.Submit(j); err == nil || !strings.Contains(err.Error(), "does not allow plugins") {
+		t.Errorf("Submit() of a plugin; err=%v, want plugins refused", err)
+	}
+	s = &server.Server{AllowPlugins: true}
+	if _, err := s.Submit(j); err == nil || !strings.Contains(err.Error(), "/tmp/evil.so") {
+		t.Errorf("Submit() of a plugin with AllowPlugins; err=%v, want the plugin opened", err)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	for _, test := range []struct {
+		name           string
+		maxPopulation  int
+		maxGenerations int
+		want           string
+	}{
+		{name: "within limits", maxPopulation: 50, maxGenerations: 10},
+		{name: "population too large", maxPopulation: 49, maxGenerations: 10, want: "PopulationSize 50 exceeds"},
+		{name: "too many generations", maxPopulation: 50, maxGenerations: 9, want: "Termination.Generations 10 exceeds"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var j server.Job
+			if err := json.Unmarshal([]byte(job("onemax", "", 10)), &j); err != nil {
+				t.Fatalf("json.Unmarshal(); err=%s", err)
+			}
+			s := &server.Server{
+				Problems:       map[string]genetics.Evaluator{"onemax": genetics.FitnessFunc(problems.OneMax{N: 20}.Fitness)},
+				MaxPopulation:  test.maxPopulation,
+				MaxGenerations: test.maxGenerations,
+			}
+			defer s.Close()
+			_, err := s.Submit(j)
+			if test.want == "" && err != nil {
+				t.Errorf("Submit(); err=%s", err)
+			}
+			if test.want != "" && (err == nil || !strings.Contains(err.Error(), test.want)) {
+				t.Errorf("Submit(); err=%v, want %q", err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+)
+
+// Niche is a species in the NEAT sense: a cluster of Chromosomes whose genotypes
+// are within a Speciation's Threshold of its Representative. (Species instead
+// describes the genome shared by every Chromosome.)
+type Niche struct {
+	ID             int
+	Representative Chromosome
+	// Members are the indexes of the Niche's Chromosomes in the population.
+	Members []int
+	// Born is the generation in which the Niche appeared.
+	Born int
+	// Best is the best fitness any member has scored, first in generation Improved.
+	Best     Fitness
+	Improved int
+	// Eliminated is set once the Niche has stagnated past its grace period.
+	Eliminated bool
+}
+
+// Speciation clusters the population into Niches by genotype distance so that
+// structural innovations have time to mature before they must compete with the
+// whole population. Set Engine.Speciation to select parents by their shared
+// fitness rather than their raw fitness:
+//
+// Fitness is shared within each Niche (explicit fitness sharing), so a Chromosome's
+// score above the population's worst is divided by the size of its Niche and large
+// Niches cannot take over the population. Niches whose best fitness has not
+// improved for StagnationLimit generations are eliminated, unless they are younger
+// than GracePeriod generations or hold the population's best Chromosome: their
+// members get the worst shared fitness and are the first to be replaced.
+type Speciation struct {
+	// Distance defaults to HammingDistance.
+	Distance DistanceFunc
+	// Threshold is the greatest distance from a Niche's Representative at which a
+	// Chromosome joins the Niche. It must be positive.
+	Threshold float64
+	// StagnationLimit, if positive, is the number of generations without
+	// improvement after which a Niche is eliminated.
+	StagnationLimit int
+	// GracePeriod is the number of generations for which new Niches are protected
+	// from elimination.
+	GracePeriod int
+
+	niches []*Niche
+	nextID int
+}
+
+// Niches returns the Niches found by the most recent call to Share.
+func (s *Speciation) Niches() []Niche {
+	niches := make([]Niche, len(s.niches))
+	for i, n := range s.niches {
+		niches[i] = *n
+		niches[i].Members = append([]int(nil), n.Members...)
+	}
+	return niches
+}
+
+// Share clusters pop, whose Fitness must be current, into Niches and returns the
+// shared fitness of each Chromosome as scores which increase with fitness (see
+// Objective.Scores). Niches persist between calls so that their age and
+// stagnation can be tracked; a generation of 0 starts afresh.
+func (s *Speciation) Share(generation int, pop *Population) []Fitness {
+	if generation == 0 {
+		s.niches, s.nextID = nil, 0
+	}
+	distance := s.Distance
+	if distance == nil {
+		distance = HammingDistance
+	}
+	for _, n := range s.niches {
+		n.Members = n.Members[:0]
+	}
+	for i, c := range pop.Chromosomes {
+		var niche *Niche
+		for _, n := range s.niches {
+			if distance(c, n.Representative) <= s.Threshold {
+				niche = n
+				break
+			}
+		}
+		if niche == nil {
+			niche = &Niche{ID: s.nextID, Representative: c, Born: generation, Best: pop.Fitness[i], Improved: generation}
+			s.nextID++
+			s.niches = append(s.niches, niche)
+		}
+		niche.Members = append(niche.Members, i)
+	}
+
+	live := s.niches[:0]
+	for _, n := range s.niches {
+		if len(n.Members) != 0 {
+			live = append(live, n)
+		}
+	}
+	s.niches = live
+
+	scores := pop.scores()
+	worst, best := minScore(scores), 0
+	for i, f := range scores {
+		if f > scores[best] {
+			best = i
+		}
+	}
+	shared := make([]Fitness, len(scores))
+	least := Fitness(math.Inf(1))
+	var eliminated []*Niche
+	for _, n := range s.niches {
+		fittest, holdsBest := n.Members[0], false
+		for _, i := range n.Members {
+			if scores[i] > scores[fittest] {
+				fittest = i
+			}
+			holdsBest = holdsBest || i == best
+			shared[i] = worst + (scores[i]-worst)/Fitness(len(n.Members))
+			if shared[i] < least {
+				least = shared[i]
+			}
+		}
+		// The fittest member represents the Niche in the next generation.
+		n.Representative = pop.Chromosomes[fittest].Clone()
+		if pop.Objective.Better(pop.Fitness[fittest], n.Best) {
+			n.Best, n.Improved = pop.Fitness[fittest], generation
+		}
+		n.Eliminated = s.StagnationLimit > 0 && !holdsBest &&
+			generation-n.Improved >= s.StagnationLimit && generation-n.Born >= s.GracePeriod
+		if n.Eliminated {
+			eliminated = append(eliminated, n)
+		}
+	}
+	for _, n := range eliminated {
+		for _, i := range n.Members {
+			shared[i] = least - 1
+		}
+	}
+	return shared
+}
+
+// eliminate returns pop's scores with the members of Niches eliminated by the
+// most recent call to Share scored below the worst.
+func (s *Speciation) eliminate(pop *Population) []Fitness {
+	scores := append([]Fitness(nil), pop.scores()...)
+	worst := minScore(scores)
+	for _, n := range s.niches {
+		if n.Eliminated {
+			for _, i := range n.Members {
+				scores[i] = worst - 1
+			}
+		}
+	}
+	return scores
+}
+
+// minScore returns the least of a non-empty slice of scores.
+func minScore(scores []Fitness) Fitness {
+	least := scores[0]
+	for _, f := range scores[1:] {
+		if f < least {
+			least = f
+		}
+	}
+	return least
+}
+
+// validate reports whether s can cluster a population.
+func (s *Speciation) validate() error {
+	if s.Threshold <= 0 {
+		return fmt.Errorf("Speciation; Threshold %g must be positive", s.Threshold)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestSpeciationShare(t *testing.T) {
+	s := genetics.NewSpecies(4, 1)
+	pop := &genetics.Population{
+		Species: s,
+		Chromosomes: []genetics.Chromosome{
+			s.New(0, 0, 0, 0),
+			s.New(0, 0, 0, 1),
+			s.New(1, 1, 1, 1),
+			s.New(0, 0, 1, 0),
+		},
+		Fitness: []genetics.Fitness{10, 16, 30, 4},
+	}
+	sp := &genetics.Speciation{Threshold: 1}
+	shared := sp.Share(0, pop)
+	// Three chromosomes near the origin share a niche; the worst score is 4.
+	if diff := cmp.Diff([]genetics.Fitness{6, 8, 30, 4}, shared); diff != "" {
+		t.Errorf("Share() returned unexpected scores; -want +got:\n%s", diff)
+	}
+	niches := sp.Niches()
+	if len(niches) != 2 {
+		t.Fatalf("Share() found %d niches; want 2", len(niches))
+	}
+	if diff := cmp.Diff([]int{0, 1, 3}, niches[0].Members); diff != "" {
+		t.Errorf("first niche has unexpected members; -want +got:\n%s", diff)
+	}
+	if got := niches[0].Representative.Genes; !cmp.Equal(got, []genetics.Gene{0, 0, 0, 1}) {
+		t.Errorf("first niche is represented by %v; want its fittest member", got)
+	}
+}
+
+func TestSpeciationStagnation(t *testing.T) {
+	s := genetics.NewSpecies(4, 1)
+	pop := &genetics.Population{
+		Species:     s,
+		Chromosomes: []genetics.Chromosome{s.New(0, 0, 0, 0), s.New(1, 1, 1, 1), s.New(1, 1, 0, 0)},
+		Fitness:     []genetics.Fitness{10, 20, 5},
+	}
+	sp := &genetics.Speciation{Threshold: 1, StagnationLimit: 2, GracePeriod: 3}
+	for gen := 0; gen < 3; gen++ {
+		if shared := sp.Share(gen, pop); shared[0] != 10 || shared[2] != 5 {
+			t.Fatalf("generation %d shared %v; young niches should not be eliminated", gen, shared)
+		}
+	}
+	shared := sp.Share(3, pop)
+	if shared[0] >= 5 || shared[2] >= 5 {
+		t.Errorf("Share()=%v; stagnant niches past their grace period should score worst", shared)
+	}
+	if shared[1] != 20 {
+		t.Errorf("Share()=%v; the niche holding the best chromosome should survive", shared)
+	}
+	for _, n := range sp.Niches() {
+		if want := n.Members[0] != 1; n.Eliminated != want {
+			t.Errorf("niche %d with members %v has Eliminated=%t; want %t", n.ID, n.Members, n.Eliminated, want)
+		}
+	}
+}
+
+func TestEngineSpeciation(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	sp := &genetics.Speciation{Threshold: 4, StagnationLimit: 15, GracePeriod: 5}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 10,
+			CrossoverRate:    0.5,
+			MutationRate:     1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.DavisOrderCrossover{},
+			Mutator:          genetics.SwapMutation{},
+		},
+		Evaluator:  genetics.FitnessFunc(inPlace),
+		Speciation: sp,
+	}
+	pop, err := genetics.NewSpecies(12, 11).NewPermPopulation(rng, 30)
+	if err != nil {
+		t.Fatalf("NewPermPopulation(); err=%s", err)
+	}
+	if err := engine.Run(rng, pop, 100); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if _, f := pop.Best(); f < 8 {
+		t.Errorf("Run() with Speciation found a best of %g; want at least 8", f)
+	}
+	if len(sp.Niches()) < 2 {
+		t.Errorf("Run() ended with %d niches; sharing should preserve several", len(sp.Niches()))
+	}
+	for i, c := range pop.Chromosomes {
+		if pop.Fitness[i] != inPlace(c) {
+			t.Fatalf("chromosome %d scored %g; Population scores should be raw", i, pop.Fitness[i])
+		}
+	}
+
+	engine.Speciation = &genetics.Speciation{}
+	if err := engine.Run(rng, pop, 1); err == nil {
+		t.Error("Run() should reject a Speciation without a Threshold")
+	}
+}
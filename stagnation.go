@@ -0,0 +1,123 @@
+package genetics
+
+import (
+	"fmt"
+
+	"github.com/inlined/rand"
+)
+
+// restartOperator is the provenance of chromosomes reinitialized by a StagnationPolicy
+const restartOperator = "Restart"
+
+// StagnationPolicy is an Observer which detects when an Engine's best fitness has
+// not improved for Generations generations and then reinitializes the least fit
+// RestartFraction of the population, temporarily raises the mutation rate
+// (triggered hypermutation), or both. Add a *StagnationPolicy to Engine.Observers.
+type StagnationPolicy struct {
+	NopObserver
+
+	// Generations without an improvement in best fitness before the policy triggers.
+	Generations int
+
+	// RestartFraction, if positive, is the fraction of the population, ranked by
+	// its most recent scores, which is replaced with New chromosomes.
+	RestartFraction float64
+	// New creates restarted chromosomes, e.g. species.NewPerm. Defaults to the
+	// population's Species.NewRand.
+	New func(r rand.Rand) (Chromosome, error)
+	// Rand is the source of randomness for New.
+	Rand rand.Rand
+
+	// HypermutationRate, if positive, replaces the Evolver's MutationRate for
+	// HypermutationGenerations generations. A MutationController may still adjust
+	// the boosted rate; the original rate is restored afterwards.
+	HypermutationRate        float32
+	HypermutationGenerations int
+
+	best      Fitness
+	stagnant  int
+	boosted   int
+	savedRate float32
+	triggers  int
+}
+
+// Triggers returns the number of times the policy has triggered since the Engine was reset.
+func (p *StagnationPolicy) Triggers() int {
+	return p.triggers
+}
+
+// OnGenerationStart implements Observer
+func (p *StagnationPolicy) OnGenerationStart(e *Engine, generation int) {
+	if generation == 0 {
+		p.stagnant, p.boosted, p.triggers = 0, 0, 0
+		return
+	}
+	if p.boosted > 0 {
+		p.boosted--
+		if p.boosted == 0 {
+			e.Evolver.MutationRate = p.savedRate
+		}
+	}
+
+	stats := e.Stats()
+	if generation == 1 || stats.Objective.Better(stats.Best, p.best) {
+		p.best, p.stagnant = stats.Best, 0
+		return
+	}
+	p.stagnant++
+	if p.stagnant < p.Generations {
+		return
+	}
+	p.Trigger(e)
+}
+
+// Trigger responds immediately as if stagnation had been detected, e.g. because
+// the fitness landscape changed. The policy must be one of e's Observers to
+// restore the mutation rate after hypermutation.
+func (p *StagnationPolicy) Trigger(e *Engine) {
+	p.stagnant = 0
+	p.triggers++
+	if p.RestartFraction > 0 {
+		p.restart(e)
+	}
+	if p.HypermutationRate > 0 && p.HypermutationGenerations > 0 {
+		if p.boosted == 0 {
+			p.savedRate = e.Evolver.MutationRate
+		}
+		e.Evolver.MutationRate = p.HypermutationRate
+		p.boosted = p.HypermutationGenerations
+	}
+}
+
+// OnTermination implements Observer
+func (p *StagnationPolicy) OnTermination(e *Engine, err error) {
+	if p.boosted > 0 {
+		e.Evolver.MutationRate = p.savedRate
+		p.boosted = 0
+	}
+}
+
+// restart replaces the least fit RestartFraction of e's population.
+func (p *StagnationPolicy) restart(e *Engine) {
+	if err := replaceWorst(e, p.RestartFraction, p.New, p.Rand, restartOperator); err != nil {
+		panic(fmt.Sprintf("StagnationPolicy: %s", err))
+	}
+}
+
+// replaceWorst replaces the least fit fraction of e's population, ranked by its
+// most recent scores, with chromosomes created by newFn, which defaults to the
+// population's Species.NewRand.
+func replaceWorst(e *Engine, fraction float64, newFn func(r rand.Rand) (Chromosome, error), r rand.Rand, operator string) error {
+	pop := e.Population()
+	if newFn == nil {
+		newFn = pop.Species.NewRand
+	}
+	for _, i := range BottomK(pop.scores(), int(fraction*float64(pop.Len()))) {
+		c, err := newFn(r)
+		if err != nil {
+			return fmt.Errorf("cannot replace chromosome %d: %s", i, err)
+		}
+		e.Replace(i, c, operator)
+	}
+	return nil
+}
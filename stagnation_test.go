@@ -0,0 +1,99 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/rand"
+)
+
+// rateRecorder records the Evolver's MutationRate at the start of each generation.
+type rateRecorder struct {
+	genetics.NopObserver
+	rates []float32
+}
+
+func (r *rateRecorder) OnGenerationStart(e *genetics.Engine, generation int) {
+	r.rates = append(r.rates, e.Evolver.MutationRate)
+}
+
+func TestStagnationPolicy(t *testing.T) {
+	rng := rand.New()
+	pop := newBinaryPopulation(t, rng, 8, 10)
+	restarts := 0
+	policy := &genetics.StagnationPolicy{
+		Generations:     3,
+		RestartFraction: 0.3,
+		New: func(r rand.Rand) (genetics.Chromosome, error) {
+			restarts++
+			return pop.Species.NewRand(r)
+		},
+		Rand:                     rng,
+		HypermutationRate:        0.9,
+		HypermutationGenerations: 2,
+	}
+	recorder := &rateRecorder{}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 4,
+			CrossoverRate:    1,
+			MutationRate:     0.1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator:          genetics.RandomResettingMutation{},
+		},
+		// A flat landscape never improves
+		Evaluator: genetics.FitnessFunc(func(genetics.Chromosome) genetics.Fitness { return 0 }),
+		Observers: []genetics.Observer{policy, recorder},
+	}
+	if err := engine.Run(rng, pop, 10); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+
+	// Generation 1 sets the best fitness; generations 4 and 7 are the third without improvement
+	if policy.Triggers() != 2 || restarts != 6 {
+		t.Errorf("policy triggered %d times and restarted %d chromosomes; want 2 and 6", policy.Triggers(), restarts)
+	}
+	want := []float32{0.1, 0.1, 0.1, 0.1, 0.9, 0.9, 0.1, 0.9, 0.9, 0.1}
+	if diff := cmp.Diff(want, recorder.rates); diff != "" {
+		t.Errorf("unexpected mutation rates by generation; diff=%s", diff)
+	}
+	if engine.Evolver.MutationRate != 0.1 {
+		t.Errorf("MutationRate = %f after Run; want the original 0.1", engine.Evolver.MutationRate)
+	}
+}
+
+func TestStagnationPolicyImprovement(t *testing.T) {
+	rng := rand.New()
+	policy := &genetics.StagnationPolicy{Generations: 2, HypermutationRate: 1, HypermutationGenerations: 1}
+	generation := 0
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 2,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator:          genetics.SwapMutation{},
+		},
+		// Every generation is fitter than the last
+		Evaluator: genetics.FitnessFunc(func(genetics.Chromosome) genetics.Fitness { return genetics.Fitness(generation) }),
+		Observers: []genetics.Observer{policy, &generationCounter{n: &generation}},
+	}
+	if err := engine.Run(rng, newBinaryPopulation(t, rng, 4, 6), 10); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	if policy.Triggers() != 0 {
+		t.Errorf("policy triggered %d times on an improving run", policy.Triggers())
+	}
+}
+
+// generationCounter exposes the current generation to fitness functions.
+type generationCounter struct {
+	genetics.NopObserver
+	n *int
+}
+
+func (g *generationCounter) OnGenerationStart(e *genetics.Engine, generation int) {
+	*g.n = generation
+}
@@ -0,0 +1,180 @@
+package genetics
+
+import (
+	"math"
+	"sort"
+)
+
+// Stats summarizes a single generation of an Engine's population.
+type Stats struct {
+	Generation int
+	// Objective is the direction in which Best and Worst were judged.
+	Objective Objective
+	Best      Fitness
+	Worst     Fitness
+	Mean      float64
+
+	// Evaluations is the number of chromosomes scored this generation, of
+	// which Failures could not be evaluated.
+	Evaluations int
+	Failures    int
+	// Offspring is the number of children scored this generation, of which
+	// Improvements were fitter than both of their parents.
+	Offspring    int
+	Improvements int
+	// CacheLookups is the number of evaluations, including those made by local
+	// search, which consulted the Engine's Cache, of which CacheHits were answered
+	// from the cache.
+	CacheLookups int
+	CacheHits    int
+	// Operators breaks Offspring and Improvements down by each operator named in
+	// the children's provenance, e.g. a crossover, a mutator, or Clone. A child
+	// counts towards every operator which helped produce it. Operators is nil if
+	// there were no Offspring.
+	Operators map[string]OperatorStats
+	// Loci holds the LocusStats of each gene of the population, in order, if the
+	// Engine's AnalyzeLoci is set, and is otherwise nil.
+	Loci []LocusStats
+}
+
+// OperatorStats measures how often an operator's children improve on their parents.
+type OperatorStats struct {
+	Offspring    int `json:"offspring"`
+	Improvements int `json:"improvements"`
+	// Gain is the total improvement of the Improvements over their fitter parents.
+	Gain Fitness `json:"gain"`
+}
+
+// SuccessRate is the fraction of Offspring which improved on their parents.
+func (o OperatorStats) SuccessRate() float64 {
+	if o.Offspring == 0 {
+		return 0
+	}
+	return float64(o.Improvements) / float64(o.Offspring)
+}
+
+// MeanGain is the mean improvement of the Offspring, counting those which did not improve as 0.
+func (o OperatorStats) MeanGain() float64 {
+	if o.Offspring == 0 {
+		return 0
+	}
+	return float64(o.Gain) / float64(o.Offspring)
+}
+
+// add accumulates the child of an operator which gained gain, or did not improve if gain is 0.
+func (o *OperatorStats) add(gain Fitness) {
+	o.Offspring++
+	if gain > 0 {
+		o.Improvements++
+		o.Gain += gain
+	}
+}
+
+// SuccessRate is the fraction of Offspring which improved on their parents.
+func (s Stats) SuccessRate() float64 {
+	if s.Offspring == 0 {
+		return 0
+	}
+	return float64(s.Improvements) / float64(s.Offspring)
+}
+
+// CacheHitRate is the fraction of CacheLookups which were answered by the cache.
+func (s Stats) CacheHitRate() float64 {
+	if s.CacheLookups == 0 {
+		return 0
+	}
+	return float64(s.CacheHits) / float64(s.CacheLookups)
+}
+
+func (s *Stats) summarize(fitness []Fitness) {
+	if len(fitness) == 0 {
+		return
+	}
+	s.Best, s.Worst = fitness[0], fitness[0]
+	total := 0.0
+	for _, f := range fitness {
+		if s.Objective.Better(f, s.Best) {
+			s.Best = f
+		}
+		if s.Objective.Better(s.Worst, f) {
+			s.Worst = f
+		}
+		total += float64(f)
+	}
+	s.Mean = total / float64(len(fitness))
+}
+
+// Hypervolume is the volume of objective space which points of front dominate and
+// which dominates reference, a point less fit than the front in every objective.
+// It measures both how close a front is to the Pareto front and how widely it
+// spreads along it; higher is better. Points which do not dominate reference
+// contribute nothing. It takes time exponential in the number of objectives, so
+// it suits fronts of a few objectives.
+func Hypervolume(front []Objectives, reference Objectives) float64 {
+	var points [][]float64
+point:
+	for _, o := range front {
+		p := make([]float64, len(reference))
+		for i := range reference {
+			if p[i] = float64(o[i] - reference[i]); p[i] <= 0 {
+				continue point
+			}
+		}
+		points = append(points, p)
+	}
+	return hypervolume(points, len(reference))
+}
+
+// hypervolume is the volume which points, whose coordinates are positive,
+// dominate in their first k dimensions, by slicing along dimension k.
+func hypervolume(points [][]float64, k int) float64 {
+	if len(points) == 0 {
+		return 0
+	}
+	if k == 1 {
+		max := 0.0
+		for _, p := range points {
+			max = math.Max(max, p[0])
+		}
+		return max
+	}
+	sorted := append([][]float64(nil), points...)
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a][k-1] > sorted[b][k-1] })
+	v := 0.0
+	for i, p := range sorted {
+		next := 0.0
+		if i+1 < len(sorted) {
+			next = sorted[i+1][k-1]
+		}
+		if depth := p[k-1] - next; depth > 0 {
+			v += depth * hypervolume(sorted[:i+1], k-1)
+		}
+	}
+	return v
+}
+
+// IGD is the inverted generational distance of front from reference, a sampling
+// of the true Pareto front: the mean Euclidean distance from each point of
+// reference to the nearest point of front. Lower is better, and it is 0 once
+// front covers reference. It is +Inf if front is empty.
+func IGD(front, reference []Objectives) float64 {
+	switch {
+	case len(front) == 0:
+		return math.Inf(1)
+	case len(reference) == 0:
+		return 0
+	}
+	total := 0.0
+	for _, r := range reference {
+		nearest := math.Inf(1)
+		for _, o := range front {
+			d := 0.0
+			for i := range r {
+				d += float64(o[i]-r[i]) * float64(o[i]-r[i])
+			}
+			nearest = math.Min(nearest, d)
+		}
+		total += math.Sqrt(nearest)
+	}
+	return total / float64(len(reference))
+}
@@ -0,0 +1,84 @@
+package genetics_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestHypervolume(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		front     []genetics.Objectives
+		reference genetics.Objectives
+		want      float64
+	}{
+		{name: "empty", reference: genetics.Objectives{0, 0}, want: 0},
+		{name: "one point", front: []genetics.Objectives{{2, 3}}, reference: genetics.Objectives{0, 0}, want: 6},
+		{name: "staircase", front: []genetics.Objectives{{1, 3}, {3, 1}, {2, 2}}, reference: genetics.Objectives{0, 0}, want: 6},
+		{name: "dominated and duplicate points", front: []genetics.Objectives{{1, 3}, {1, 1}, {1, 3}, {3, 1}, {2, 2}}, reference: genetics.Objectives{0, 0}, want: 6},
+		{name: "offset reference", front: []genetics.Objectives{{1, 3}, {3, 1}}, reference: genetics.Objectives{-1, -1}, want: 12},
+		{name: "beyond reference", front: []genetics.Objectives{{1, 3}, {3, -1}}, reference: genetics.Objectives{0, 0}, want: 3},
+		{name: "3 objectives", front: []genetics.Objectives{{2, 1, 1}, {1, 2, 1}}, reference: genetics.Objectives{0, 0, 0}, want: 3},
+	} {
+		if got := genetics.Hypervolume(test.front, test.reference); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("%s: Hypervolume()=%g; want %g", test.name, got, test.want)
+		}
+	}
+}
+
+// TestHypervolumeCells checks Hypervolume against a count of the unit cells which
+// random fronts of integer points dominate.
+func TestHypervolumeCells(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	const size = 6
+	for trial := 0; trial < 20; trial++ {
+		var front []genetics.Objectives
+		for k := 0; k < 1+int(rng.Int31n(8)); k++ {
+			front = append(front, genetics.Objectives{
+				genetics.Fitness(rng.Int31n(size + 1)),
+				genetics.Fitness(rng.Int31n(size + 1)),
+				genetics.Fitness(rng.Int31n(size + 1)),
+			})
+		}
+		cells := 0
+		for x := 0; x < size; x++ {
+			for y := 0; y < size; y++ {
+				for z := 0; z < size; z++ {
+					for _, o := range front {
+						if int(o[0]) > x && int(o[1]) > y && int(o[2]) > z {
+							cells++
+							break
+						}
+					}
+				}
+			}
+		}
+		if got := genetics.Hypervolume(front, genetics.Objectives{0, 0, 0}); got != float64(cells) {
+			t.Errorf("Hypervolume(%v)=%g; want %d", front, got, cells)
+		}
+	}
+}
+
+func TestIGD(t *testing.T) {
+	reference := []genetics.Objectives{{0, 4}, {2, 2}, {4, 0}}
+	for _, test := range []struct {
+		name  string
+		front []genetics.Objectives
+		want  float64
+	}{
+		{name: "exact", front: reference, want: 0},
+		{name: "superset", front: append([]genetics.Objectives{{1, 3}}, reference...), want: 0},
+		{name: "one point", front: []genetics.Objectives{{0, 0}}, want: (4 + math.Sqrt(8) + 4) / 3},
+		{name: "extremes", front: []genetics.Objectives{{0, 4}, {4, 0}}, want: math.Sqrt(8) / 3},
+		{name: "empty", want: math.Inf(1)},
+	} {
+		if got := genetics.IGD(test.front, reference); math.Abs(got-test.want) > 1e-9 && got != test.want {
+			t.Errorf("%s: IGD()=%g; want %g", test.name, got, test.want)
+		}
+	}
+}
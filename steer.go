@@ -0,0 +1,63 @@
+package genetics
+
+import (
+	"fmt"
+
+	"github.com/inlined/rand"
+)
+
+// injectedOperator is the provenance of chromosomes inserted by Engine.Inject
+const injectedOperator = "Injected"
+
+// Pause stops a run of the Engine in another goroutine (Run, RunFor, or RunStream)
+// before its next generation, waiting for the generation in progress to finish,
+// including the delivery of its Snapshot by RunStream. Until Resume, the caller
+// may inspect and steer the run: read its Population and Stats, Inject or Replace
+// members, or change its Evolver, e.g. its MutationRate. Pause must not be called
+// by an Observer or while the Engine is already paused.
+func (e *Engine) Pause() {
+	e.steering.Lock()
+}
+
+// Resume continues a run stopped by Pause.
+func (e *Engine) Resume() {
+	e.steering.Unlock()
+}
+
+// Inject replaces the least fit members of the population, ranked by their most
+// recent scores, with cs, e.g. solutions suggested by a person. They are evaluated
+// at the start of the next generation.
+func (e *Engine) Inject(cs ...Chromosome) error {
+	pop := e.Population()
+	if pop == nil {
+		return fmt.Errorf("Engine.Inject(); the Engine has no population until it is Reset")
+	}
+	if len(cs) > pop.Len() {
+		return fmt.Errorf("Engine.Inject(); cannot inject %d chromosomes into a population of %d", len(cs), pop.Len())
+	}
+	for n, i := range BottomK(pop.scores(), len(cs)) {
+		e.Replace(i, cs[n], injectedOperator)
+	}
+	return nil
+}
+
+// steeredReset is Reset while holding the lock which Pause takes.
+func (e *Engine) steeredReset(pop *Population) {
+	e.steering.Lock()
+	defer e.steering.Unlock()
+	e.Reset(pop)
+}
+
+// steeredStep evolves a generation, unless before returns false, while holding the
+// lock which Pause takes, and reports whether the run should stop.
+func (e *Engine) steeredStep(r rand.Rand, before func() bool) (stop bool, err error) {
+	e.steering.Lock()
+	defer e.steering.Unlock()
+	if before != nil && !before() {
+		return true, nil
+	}
+	if err := e.Step(r); err != nil {
+		return true, err
+	}
+	return e.Terminate != nil && e.Terminate(e.stats), nil
+}
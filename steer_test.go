@@ -0,0 +1,73 @@
+package genetics_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestEnginePause(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	pop := newBinaryPopulation(t, rng, 30, 20)
+	engine := newStreamEngine()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := engine.RunStream(ctx, rng, pop, 1000000)
+
+	// Keep receiving Snapshots from the first on until the run pauses
+	<-stream.C
+	paused := make(chan struct{})
+	go func() {
+		engine.Pause()
+		close(paused)
+	}()
+	for waiting := true; waiting; {
+		select {
+		case <-stream.C:
+		case <-paused:
+			waiting = false
+		}
+	}
+	generation := engine.Generation()
+	time.Sleep(10 * time.Millisecond)
+	if engine.Generation() != generation {
+		t.Fatalf("paused run evolved from generation %d to %d", generation, engine.Generation())
+	}
+	best := pop.Chromosomes[0].Species.New()
+	for i := range best.Genes {
+		best.Genes[i] = 1
+	}
+	if err := engine.Inject(best); err != nil {
+		t.Fatalf("Inject(); err=%s", err)
+	}
+	if err := engine.Inject(make([]genetics.Chromosome, 21)...); err == nil {
+		t.Error("Inject() should reject more chromosomes than the population holds")
+	}
+	if err := (&genetics.Engine{}).Inject(best); err == nil {
+		t.Error("Inject() should fail before the Engine has a population")
+	}
+	engine.Evolver.MutationRate = 0
+	engine.Resume()
+
+	s := <-stream.C
+	if s.Stats.Generation != generation || s.BestFitness != 30 {
+		t.Errorf("expected generation %d to score the injected chromosome; got generation %d scoring %g", generation, s.Stats.Generation, s.BestFitness)
+	}
+	if _, ok := s.Stats.Operators["Injected"]; !ok {
+		t.Errorf("generation %d has no Injected offspring; got %v", generation, s.Stats.Operators)
+	}
+	cancel()
+	for range stream.C {
+	}
+	if err := stream.Err(); err != context.Canceled {
+		t.Errorf("RunStream() ended with err=%v; want %s", err, context.Canceled)
+	}
+	if engine.Evolver.MutationRate != 0 {
+		t.Errorf("MutationRate=%g; want the 0 set while paused", engine.Evolver.MutationRate)
+	}
+}
@@ -0,0 +1,144 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// resultFile is the name of the file in which a FileStore keeps a run's Result.
+const resultFile = "result.json"
+
+// FileStore is a Store which keeps each run in its own directory of Dir: the
+// Snapshot of generation n in gen-<n>.json and the Result in result.json. Run IDs
+// must be usable as directory names.
+type FileStore struct {
+	Dir string
+
+	mu sync.Mutex
+}
+
+// SaveSnapshot implements Store
+func (f *FileStore) SaveSnapshot(run string, s Snapshot) error {
+	if err := validRun(run); err != nil {
+		return fmt.Errorf("FileStore.SaveSnapshot(%s); err=%s", run, err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	path := filepath.Join(f.Dir, run, fmt.Sprintf("gen-%d.json", s.Stats.Generation))
+	if err := writeJSON(path, s); err != nil {
+		return fmt.Errorf("FileStore.SaveSnapshot(%s); err=%s", run, err)
+	}
+	return nil
+}
+
+// LoadSnapshots implements Store
+func (f *FileStore) LoadSnapshots(run string) ([]Snapshot, error) {
+	if err := validRun(run); err != nil {
+		return nil, fmt.Errorf("FileStore.LoadSnapshots(%s); err=%s", run, err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	paths, err := filepath.Glob(filepath.Join(f.Dir, run, "gen-*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("FileStore.LoadSnapshots(%s); err=%s", run, err)
+	}
+	snapshots := make([]Snapshot, len(paths))
+	for i, path := range paths {
+		if err := readJSON(path, &snapshots[i]); err != nil {
+			return nil, fmt.Errorf("FileStore.LoadSnapshots(%s); err=%s", run, err)
+		}
+	}
+	sort.Slice(snapshots, func(a, b int) bool {
+		return snapshots[a].Stats.Generation < snapshots[b].Stats.Generation
+	})
+	return snapshots, nil
+}
+
+// SaveResult implements Store
+func (f *FileStore) SaveResult(run string, r Result) error {
+	if err := validRun(run); err != nil {
+		return fmt.Errorf("FileStore.SaveResult(%s); err=%s", run, err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := writeJSON(filepath.Join(f.Dir, run, resultFile), r); err != nil {
+		return fmt.Errorf("FileStore.SaveResult(%s); err=%s", run, err)
+	}
+	return nil
+}
+
+// LoadResult implements Store
+func (f *FileStore) LoadResult(run string) (Result, error) {
+	if err := validRun(run); err != nil {
+		return Result{}, fmt.Errorf("FileStore.LoadResult(%s); err=%s", run, err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var r Result
+	err := readJSON(filepath.Join(f.Dir, run, resultFile), &r)
+	switch {
+	case os.IsNotExist(err):
+		return Result{}, ErrNotFound
+	case err != nil:
+		return Result{}, fmt.Errorf("FileStore.LoadResult(%s); err=%s", run, err)
+	}
+	return r, nil
+}
+
+// Runs implements Store
+func (f *FileStore) Runs() ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	entries, err := os.ReadDir(f.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("FileStore.Runs(); err=%s", err)
+	}
+	var runs []string
+	for _, e := range entries {
+		if e.IsDir() {
+			runs = append(runs, e.Name())
+		}
+	}
+	return runs, nil
+}
+
+// validRun returns an error if run cannot name a directory of a FileStore.
+func validRun(run string) error {
+	if run == "" || run == "." || run == ".." || strings.ContainsAny(run, `/\`) {
+		return fmt.Errorf("invalid run ID %q", run)
+	}
+	return nil
+}
+
+// writeJSON atomically replaces path with the JSON encoding of v.
+func writeJSON(path string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// readJSON decodes the file at path into v.
+func readJSON(path string, v interface{}) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,147 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// sqliteSchema creates the tables of a SQLiteStore. The fitness and evaluation
+// columns duplicate parts of the JSON so that runs can be queried with SQL.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS snapshots (
+	run          TEXT    NOT NULL,
+	generation   INTEGER NOT NULL,
+	best_fitness REAL    NOT NULL,
+	mean_fitness REAL    NOT NULL,
+	evaluations  INTEGER NOT NULL,
+	snapshot     TEXT    NOT NULL,
+	PRIMARY KEY (run, generation)
+);
+CREATE TABLE IF NOT EXISTS results (
+	run          TEXT    NOT NULL PRIMARY KEY,
+	generations  INTEGER NOT NULL,
+	best_fitness REAL    NOT NULL,
+	error        TEXT    NOT NULL,
+	finished     TEXT    NOT NULL,
+	result       TEXT    NOT NULL
+);`
+
+// SQLiteStore is a Store which keeps runs in the snapshots and results tables of
+// a SQLite database. The package does not depend on a SQLite driver; open the
+// database with the driver of your choice, e.g.
+//
+//	db, err := sql.Open("sqlite", "runs.db")
+//	...
+//	s, err := store.NewSQLiteStore(db)
+//
+// Then, for example, the best run is
+//
+//	SELECT run FROM results ORDER BY best_fitness DESC LIMIT 1
+//
+// best_fitness is the raw Fitness, whichever the Objective of the run, so runs
+// which minimize are ordered ASC instead. The tables do not record the Objective;
+// it is the Objective of the stats in each snapshot's JSON.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore creates the tables of a SQLiteStore in db if they do not exist.
+func NewSQLiteStore(db *sql.DB) (*SQLiteStore, error) {
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return nil, fmt.Errorf("NewSQLiteStore(); err=%s", err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// SaveSnapshot implements Store
+func (s *SQLiteStore) SaveSnapshot(run string, snap Snapshot) error {
+	b, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("SQLiteStore.SaveSnapshot(%s); err=%s", run, err)
+	}
+	_, err = s.db.Exec(`INSERT OR REPLACE INTO snapshots VALUES (?, ?, ?, ?, ?, ?)`,
+		run, snap.Stats.Generation, float64(snap.BestFitness), snap.Stats.Mean, snap.Stats.Evaluations, string(b))
+	if err != nil {
+		return fmt.Errorf("SQLiteStore.SaveSnapshot(%s); err=%s", run, err)
+	}
+	return nil
+}
+
+// LoadSnapshots implements Store
+func (s *SQLiteStore) LoadSnapshots(run string) ([]Snapshot, error) {
+	rows, err := s.db.Query(`SELECT snapshot FROM snapshots WHERE run = ? ORDER BY generation`, run)
+	if err != nil {
+		return nil, fmt.Errorf("SQLiteStore.LoadSnapshots(%s); err=%s", run, err)
+	}
+	defer rows.Close()
+	var snapshots []Snapshot
+	for rows.Next() {
+		var b string
+		var snap Snapshot
+		if err := rows.Scan(&b); err != nil {
+			return nil, fmt.Errorf("SQLiteStore.LoadSnapshots(%s); err=%s", run, err)
+		}
+		if err := json.Unmarshal([]byte(b), &snap); err != nil {
+			return nil, fmt.Errorf("SQLiteStore.LoadSnapshots(%s); err=%s", run, err)
+		}
+		snapshots = append(snapshots, snap)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("SQLiteStore.LoadSnapshots(%s); err=%s", run, err)
+	}
+	return snapshots, nil
+}
+
+// SaveResult implements Store
+func (s *SQLiteStore) SaveResult(run string, r Result) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("SQLiteStore.SaveResult(%s); err=%s", run, err)
+	}
+	_, err = s.db.Exec(`INSERT OR REPLACE INTO results VALUES (?, ?, ?, ?, ?, ?)`,
+		run, r.Generations, float64(r.BestFitness), r.Error, r.Finished.UTC().Format(time.RFC3339Nano), string(b))
+	if err != nil {
+		return fmt.Errorf("SQLiteStore.SaveResult(%s); err=%s", run, err)
+	}
+	return nil
+}
+
+// LoadResult implements Store
+func (s *SQLiteStore) LoadResult(run string) (Result, error) {
+	var b string
+	err := s.db.QueryRow(`SELECT result FROM results WHERE run = ?`, run).Scan(&b)
+	switch {
+	case err == sql.ErrNoRows:
+		return Result{}, ErrNotFound
+	case err != nil:
+		return Result{}, fmt.Errorf("SQLiteStore.LoadResult(%s); err=%s", run, err)
+	}
+	var r Result
+	if err := json.Unmarshal([]byte(b), &r); err != nil {
+		return Result{}, fmt.Errorf("SQLiteStore.LoadResult(%s); err=%s", run, err)
+	}
+	return r, nil
+}
+
+// Runs implements Store
+func (s *SQLiteStore) Runs() ([]string, error) {
+	rows, err := s.db.Query(`SELECT run FROM snapshots UNION SELECT run FROM results ORDER BY run`)
+	if err != nil {
+		return nil, fmt.Errorf("SQLiteStore.Runs(); err=%s", err)
+	}
+	defer rows.Close()
+	var runs []string
+	for rows.Next() {
+		var run string
+		if err := rows.Scan(&run); err != nil {
+			return nil, fmt.Errorf("SQLiteStore.Runs(); err=%s", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("SQLiteStore.Runs(); err=%s", err)
+	}
+	return runs, nil
+}
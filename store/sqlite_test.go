@@ -0,0 +1,203 @@
+package store_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/inlined/genetics/store"
+)
+
+// fakeSQLite is a database/sql driver which executes exactly the statements of a
+// SQLiteStore against maps, so that SQLiteStore can be tested without a SQLite
+// driver. Any other statement is an error.
+type fakeSQLite struct {
+	mu sync.Mutex
+	// tables is false until the schema has been executed.
+	tables    bool
+	snapshots map[string]map[int64]string
+	results   map[string]string
+	// fail, if set, fails every statement.
+	fail error
+}
+
+func newFakeSQLite() *fakeSQLite {
+	return &fakeSQLite{snapshots: map[string]map[int64]string{}, results: map[string]string{}}
+}
+
+// Connect implements driver.Connector
+func (db *fakeSQLite) Connect(context.Context) (driver.Conn, error) {
+	return fakeConn{db}, nil
+}
+
+// Driver implements driver.Connector
+func (db *fakeSQLite) Driver() driver.Driver {
+	return db
+}
+
+// Open implements driver.Driver
+func (db *fakeSQLite) Open(string) (driver.Conn, error) {
+	return fakeConn{db}, nil
+}
+
+type fakeConn struct {
+	db *fakeSQLite
+}
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return fakeStmt{c.db, strings.Join(strings.Fields(query), " ")}, nil
+}
+
+func (fakeConn) Close() error {
+	return nil
+}
+
+func (fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("fakeSQLite does not support transactions")
+}
+
+type fakeStmt struct {
+	db    *fakeSQLite
+	query string
+}
+
+func (fakeStmt) Close() error {
+	return nil
+}
+
+func (s fakeStmt) NumInput() int {
+	return strings.Count(s.query, "?")
+}
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	db := s.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.fail != nil {
+		return nil, db.fail
+	}
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE IF NOT EXISTS snapshots"):
+		db.tables = true
+	case !db.tables:
+		return nil, fmt.Errorf("no such table in %q", s.query)
+	case s.query == "INSERT OR REPLACE INTO snapshots VALUES (?, ?, ?, ?, ?, ?)":
+		run, gen := args[0].(string), args[1].(int64)
+		if db.snapshots[run] == nil {
+			db.snapshots[run] = map[int64]string{}
+		}
+		db.snapshots[run][gen] = args[5].(string)
+	case s.query == "INSERT OR REPLACE INTO results VALUES (?, ?, ?, ?, ?, ?)":
+		db.results[args[0].(string)] = args[5].(string)
+	default:
+		return nil, fmt.Errorf("fakeSQLite cannot execute %q", s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	db := s.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.fail != nil {
+		return nil, db.fail
+	}
+	rows := &fakeRows{}
+	switch s.query {
+	case "SELECT snapshot FROM snapshots WHERE run = ? ORDER BY generation":
+		snaps := db.snapshots[args[0].(string)]
+		var gens []int64
+		for gen := range snaps {
+			gens = append(gens, gen)
+		}
+		sort.Slice(gens, func(i, j int) bool { return gens[i] < gens[j] })
+		for _, gen := range gens {
+			rows.values = append(rows.values, snaps[gen])
+		}
+	case "SELECT result FROM results WHERE run = ?":
+		if r, ok := db.results[args[0].(string)]; ok {
+			rows.values = append(rows.values, r)
+		}
+	case "SELECT run FROM snapshots UNION SELECT run FROM results ORDER BY run":
+		runs := map[string]bool{}
+		for run := range db.snapshots {
+			runs[run] = true
+		}
+		for run := range db.results {
+			runs[run] = true
+		}
+		for run := range runs {
+			rows.values = append(rows.values, run)
+		}
+		sort.Slice(rows.values, func(i, j int) bool { return rows.values[i] < rows.values[j] })
+	default:
+		return nil, fmt.Errorf("fakeSQLite cannot query %q", s.query)
+	}
+	return rows, nil
+}
+
+// fakeRows is a single column of TEXT.
+type fakeRows struct {
+	values []string
+}
+
+func (*fakeRows) Columns() []string {
+	return []string{"value"}
+}
+
+func (*fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0], r.values = r.values[0], r.values[1:]
+	return nil
+}
+
+func TestSQLiteStore(t *testing.T) {
+	fake := newFakeSQLite()
+	db := sql.OpenDB(fake)
+	defer db.Close()
+	s, err := store.NewSQLiteStore(db)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore(); err=%s", err)
+	}
+	testStore(t, s)
+
+	// A second Store shares the existing tables
+	again, err := store.NewSQLiteStore(db)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() of existing tables; err=%s", err)
+	}
+	if diff := cmp.Diff([]string{"a", "b"}, mustRuns(t, again)); diff != "" {
+		t.Errorf("Runs() of existing tables diff=%s", diff)
+	}
+
+	fake.mu.Lock()
+	fake.fail = errors.New("disk I/O error")
+	fake.mu.Unlock()
+	if _, err := store.NewSQLiteStore(db); err == nil {
+		t.Error("NewSQLiteStore() should fail when the schema cannot be created")
+	}
+	if err := s.SaveResult("c", store.Result{}); err == nil || !strings.Contains(err.Error(), "disk I/O error") {
+		t.Errorf("SaveResult(); err=%v, want the database's error", err)
+	}
+	if _, err := s.LoadResult("a"); err == nil || err == store.ErrNotFound {
+		t.Errorf("LoadResult(); err=%v, want the database's error", err)
+	}
+	if _, err := s.Runs(); err == nil {
+		t.Error("Runs() should fail when the database does")
+	}
+}
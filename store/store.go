@@ -0,0 +1,54 @@
+// Package store persists the histories and results of runs, so that experiment
+// runners and the server package can keep them beyond the life of a process and
+// query them later. A Store records a Snapshot of each scored generation of a run
+// and the Result of the finished run, keyed by a run ID which the caller chooses.
+// FileStore keeps them in JSON files and SQLiteStore in a SQLite database.
+package store
+
+import (
+	"errors"
+	"time"
+
+	"github.com/inlined/genetics"
+)
+
+// ErrNotFound is returned by Store.LoadResult for a run without a Result.
+var ErrNotFound = errors.New("store: not found")
+
+// Snapshot is the progress of a run when a generation has been scored.
+type Snapshot struct {
+	Stats       genetics.Stats   `json:"stats"`
+	Best        []genetics.Gene  `json:"best"`
+	BestFitness genetics.Fitness `json:"bestFitness"`
+}
+
+// NewSnapshot converts a Snapshot streamed by genetics.Engine.RunStream.
+func NewSnapshot(s genetics.Snapshot) Snapshot {
+	return Snapshot{Stats: s.Stats, Best: s.Best.Genes, BestFitness: s.BestFitness}
+}
+
+// Result is the outcome of a finished run.
+type Result struct {
+	// Generations is the number of generations the run evolved.
+	Generations int              `json:"generations"`
+	Best        []genetics.Gene  `json:"best"`
+	BestFitness genetics.Fitness `json:"bestFitness"`
+	// Error is the error which ended the run, if any.
+	Error    string    `json:"error,omitempty"`
+	Finished time.Time `json:"finished"`
+}
+
+// Store saves and loads the Snapshots and Results of runs. Implementations are
+// goroutine safe.
+type Store interface {
+	// SaveSnapshot records s for run, replacing any Snapshot of the same generation.
+	SaveSnapshot(run string, s Snapshot) error
+	// LoadSnapshots returns the Snapshots of run ordered by generation.
+	LoadSnapshots(run string) ([]Snapshot, error)
+	// SaveResult records r for run, replacing any earlier Result.
+	SaveResult(run string, r Result) error
+	// LoadResult returns the Result of run, or ErrNotFound if it has none.
+	LoadResult(run string) (Result, error)
+	// Runs lists the IDs of every run with a Snapshot or Result, sorted.
+	Runs() ([]string, error)
+}
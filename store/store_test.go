@@ -0,0 +1,107 @@
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/genetics/store"
+)
+
+// testStore exercises the contract of every Store on the empty store s.
+func testStore(t *testing.T, s store.Store) {
+	t.Helper()
+	if runs, err := s.Runs(); err != nil || len(runs) != 0 {
+		t.Fatalf("Runs() of an empty Store=%v, %v; want none", runs, err)
+	}
+	if _, err := s.LoadResult("a"); err != store.ErrNotFound {
+		t.Errorf("LoadResult() of a missing run; err=%v; want ErrNotFound", err)
+	}
+	if snaps, err := s.LoadSnapshots("a"); err != nil || len(snaps) != 0 {
+		t.Errorf("LoadSnapshots() of a missing run=%v, %v; want none", snaps, err)
+	}
+
+	var want []store.Snapshot
+	for gen := 2; gen >= 0; gen-- {
+		snap := store.Snapshot{
+			Stats: genetics.Stats{
+				Generation:  gen,
+				Objective:   genetics.Minimize,
+				Best:        genetics.Fitness(gen),
+				Mean:        float64(gen) + 0.5,
+				Evaluations: 10,
+				Offspring:   4,
+				Operators:   map[string]genetics.OperatorStats{"Swap": {Offspring: 4}},
+			},
+			Best:        []genetics.Gene{gen, 1, 0},
+			BestFitness: genetics.Fitness(gen),
+		}
+		if err := s.SaveSnapshot("b", snap); err != nil {
+			t.Fatalf("SaveSnapshot(); err=%s", err)
+		}
+		want = append([]store.Snapshot{snap}, want...)
+	}
+	// A second Snapshot of a generation replaces the first.
+	want[1].BestFitness = 7
+	if err := s.SaveSnapshot("b", want[1]); err != nil {
+		t.Fatalf("SaveSnapshot(); err=%s", err)
+	}
+	got, err := s.LoadSnapshots("b")
+	if err != nil {
+		t.Fatalf("LoadSnapshots(); err=%s", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("LoadSnapshots() diff=%s", diff)
+	}
+
+	result := store.Result{
+		Generations: 3,
+		Best:        []genetics.Gene{0, 1, 0},
+		BestFitness: 0,
+		Error:       "interrupted",
+		Finished:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	if err := s.SaveResult("a", result); err != nil {
+		t.Fatalf("SaveResult(); err=%s", err)
+	}
+	if got, err := s.LoadResult("a"); err != nil {
+		t.Errorf("LoadResult(); err=%s", err)
+	} else if diff := cmp.Diff(result, got, cmp.Comparer(time.Time.Equal)); diff != "" {
+		t.Errorf("LoadResult() diff=%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"a", "b"}, mustRuns(t, s)); diff != "" {
+		t.Errorf("Runs() diff=%s", diff)
+	}
+}
+
+func mustRuns(t *testing.T, s store.Store) []string {
+	t.Helper()
+	runs, err := s.Runs()
+	if err != nil {
+		t.Fatalf("Runs(); err=%s", err)
+	}
+	return runs
+}
+
+func TestFileStore(t *testing.T) {
+	testStore(t, &store.FileStore{Dir: t.TempDir()})
+
+	s := &store.FileStore{Dir: t.TempDir()}
+	for _, run := range []string{"", ".", "..", "a/b"} {
+		if err := s.SaveResult(run, store.Result{}); err == nil {
+			t.Errorf("SaveResult(%q) should fail", run)
+		}
+	}
+}
+
+func TestNewSnapshot(t *testing.T) {
+	best := genetics.NewSpecies(3, 1).New(1, 0, 1)
+	got := store.NewSnapshot(genetics.Snapshot{Stats: genetics.Stats{Generation: 4}, Best: best, BestFitness: 2})
+	want := store.Snapshot{Stats: genetics.Stats{Generation: 4}, Best: []genetics.Gene{1, 0, 1}, BestFitness: 2}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("NewSnapshot() diff=%s", diff)
+	}
+}
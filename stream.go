@@ -0,0 +1,69 @@
+package genetics
+
+import (
+	"context"
+
+	"github.com/inlined/rand"
+)
+
+// Snapshot is the progress of a run when a generation has been scored.
+type Snapshot struct {
+	Stats Stats
+	// Best is a copy of the fittest member of the population, which later
+	// generations do not modify.
+	Best        Chromosome
+	BestFitness Fitness
+}
+
+// Stream is a run started by Engine.RunStream.
+type Stream struct {
+	// C receives a Snapshot of every scored generation, including the final
+	// population, and is closed when the run ends.
+	C <-chan Snapshot
+
+	done chan struct{}
+	err  error
+}
+
+// Err waits for the run to end and returns the error which ended it, if any.
+func (s *Stream) Err() error {
+	<-s.done
+	return s.err
+}
+
+// RunStream is Run in a new goroutine, streaming a Snapshot of each generation so
+// that a UI can show progress without waiting for the whole run. Each generation
+// waits for its Snapshot to be received, so a slow reader slows the run rather
+// than missing Snapshots. Cancelling ctx ends the run before its next generation
+// with ctx.Err(). The Engine must not be used until C is closed.
+func (e *Engine) RunStream(ctx context.Context, r rand.Rand, pop *Population, generations int) *Stream {
+	c := make(chan Snapshot)
+	s := &Stream{C: c, done: make(chan struct{})}
+	observers := e.Observers
+	e.Observers = append(observers[:len(observers):len(observers)], &streamObserver{ctx: ctx, e: e, c: c})
+	go func() {
+		defer close(c)
+		s.err = e.run(ctx, r, pop, generations)
+		e.Observers = observers
+		close(s.done)
+	}()
+	return s
+}
+
+// streamObserver sends a Snapshot of its Engine's generations to c until ctx is
+// done.
+type streamObserver struct {
+	NopObserver
+	ctx context.Context
+	e   *Engine
+	c   chan<- Snapshot
+}
+
+// OnStats implements StatsObserver
+func (o *streamObserver) OnStats(s Stats) {
+	best, f := o.e.pop.Best()
+	select {
+	case o.c <- Snapshot{Stats: s, Best: best.Clone(), BestFitness: f}:
+	case <-o.ctx.Done():
+	}
+}
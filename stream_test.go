@@ -0,0 +1,78 @@
+package genetics_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func newStreamEngine() *genetics.Engine {
+	return &genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 10,
+			CrossoverRate:    1,
+			MutationRate:     0.1,
+			Selector:         genetics.TournamentSelection{Size: 3},
+			Crossover:        genetics.MultiPointCrossover{Points: 2},
+			Mutator:          genetics.RandomResettingMutation{},
+		},
+		Evaluator:    genetics.FitnessFunc(oneMax),
+		ReuseBuffers: true,
+	}
+}
+
+func TestEngineRunStream(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	pop := newBinaryPopulation(t, rng, 30, 20)
+	engine := newStreamEngine()
+	stream := engine.RunStream(context.Background(), rng, pop, 10)
+	var snapshots []genetics.Snapshot
+	for s := range stream.C {
+		snapshots = append(snapshots, s)
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("RunStream(); err=%s", err)
+	}
+
+	// One Snapshot per generation and one of the final population
+	if len(snapshots) != 11 {
+		t.Fatalf("RunStream() sent %d Snapshots; want 11", len(snapshots))
+	}
+	for n, s := range snapshots {
+		if s.Stats.Generation != n {
+			t.Errorf("Snapshot %d is of generation %d", n, s.Stats.Generation)
+		}
+		if s.Stats.Best != s.BestFitness || oneMax(s.Best) != s.BestFitness {
+			t.Errorf("Snapshot %d has Best %v scoring %g; want %g (Stats.Best=%g)", n, s.Best.Genes, s.BestFitness, oneMax(s.Best), s.Stats.Best)
+		}
+	}
+	if _, f := pop.Best(); snapshots[10].BestFitness != f {
+		t.Errorf("last Snapshot has BestFitness %g; want the final population's %g", snapshots[10].BestFitness, f)
+	}
+	if len(engine.Observers) != 0 {
+		t.Errorf("RunStream() left Observers %v", engine.Observers)
+	}
+}
+
+func TestEngineRunStreamCancel(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	pop := newBinaryPopulation(t, rng, 30, 20)
+	engine := newStreamEngine()
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := engine.RunStream(ctx, rng, pop, 1000)
+	<-stream.C
+	cancel()
+	for range stream.C {
+	}
+	if err := stream.Err(); err != context.Canceled {
+		t.Errorf("RunStream() ended with err=%v; want %s", err, context.Canceled)
+	}
+	if engine.Generation() >= 1000 {
+		t.Errorf("RunStream() ran all %d generations after being cancelled", engine.Generation())
+	}
+}
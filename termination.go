@@ -0,0 +1,26 @@
+package genetics
+
+// Termination decides whether a run should stop after a generation summarized by
+// s. Terminations are shared by every driver (Engine, CMAES, ...) so that runs can
+// be compared under identical stopping rules.
+type Termination func(s Stats) bool
+
+// TargetFitness stops a run once its best fitness reaches target in the
+// direction of its Objective.
+func TargetFitness(target Fitness) Termination {
+	return func(s Stats) bool {
+		return !s.Objective.Better(target, s.Best)
+	}
+}
+
+// AnyOf stops a run when any of terms would.
+func AnyOf(terms ...Termination) Termination {
+	return func(s Stats) bool {
+		for _, t := range terms {
+			if t(s) {
+				return true
+			}
+		}
+		return false
+	}
+}
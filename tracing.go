@@ -0,0 +1,141 @@
+package genetics
+
+import (
+	"context"
+
+	"github.com/inlined/rand"
+)
+
+// Span names recorded by an Engine's Tracer. Evaluate spans are children of the
+// Generation span; Select, Crossover, and Mutate spans are children of the Evolve
+// span, which is also a child of the Generation span.
+const (
+	GenerationSpan = "genetics.Generation"
+	EvaluateSpan   = "genetics.Evaluate"
+	EvolveSpan     = "genetics.Evolve"
+	SelectSpan     = "genetics.Select"
+	CrossoverSpan  = "genetics.Crossover"
+	MutateSpan     = "genetics.Mutate"
+)
+
+// Tracer starts spans around the phases of each generation so that services
+// embedding an Engine can see where time goes. It has the shape of OpenTelemetry's
+// trace.Tracer, which can be adapted without this package depending on OpenTelemetry:
+//
+//	type otelTracer struct{ trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string) (context.Context, genetics.Span) {
+//		ctx, span := t.Tracer.Start(ctx, name)
+//		return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ trace.Span }
+//
+//	func (s otelSpan) End() { s.Span.End() }
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a traced operation started by a Tracer.
+type Span interface {
+	End()
+}
+
+type nopSpan struct{}
+
+func (nopSpan) End() {}
+
+// startSpan starts a span if the Engine has a Tracer.
+func (e *Engine) startSpan(ctx context.Context, name string) (context.Context, Span) {
+	if e.Tracer == nil {
+		return ctx, nopSpan{}
+	}
+	return e.Tracer.Start(ctx, name)
+}
+
+// rootContext is the parent of the Engine's top-level spans.
+func (e *Engine) rootContext() context.Context {
+	if e.Context == nil {
+		return context.Background()
+	}
+	return e.Context
+}
+
+// traced returns a copy of ev whose operators record spans under ctx.
+func (e *Engine) traced(ctx context.Context, ev Evolver) Evolver {
+	if e.Tracer == nil {
+		return ev
+	}
+	ev.Selector = tracedSelection{ev.Selector, e.Tracer, ctx}
+	if ev.Crossover != nil {
+		ev.Crossover = tracedCrossover{ev.Crossover, e.Tracer, ctx}
+	}
+	if ev.Mutator != nil {
+		ev.Mutator = tracedMutator{ev.Mutator, e.Tracer, ctx}
+	}
+	return ev
+}
+
+type tracedSelection struct {
+	NaturalSelection
+	tracer Tracer
+	ctx    context.Context
+}
+
+func (s tracedSelection) SelectParents(rand rand.Rand, numParents int, fitness []Fitness) []int {
+	_, span := s.tracer.Start(s.ctx, SelectSpan)
+	defer span.End()
+	return s.NaturalSelection.SelectParents(rand, numParents, fitness)
+}
+
+type tracedCrossover struct {
+	crossover Crossover
+	tracer    Tracer
+	ctx       context.Context
+}
+
+func (c tracedCrossover) String() string {
+	return c.crossover.String()
+}
+
+func (c tracedCrossover) Crossover(r rand.Rand, a, b Chromosome) (x, y Chromosome) {
+	_, span := c.tracer.Start(c.ctx, CrossoverSpan)
+	defer span.End()
+	return c.crossover.Crossover(r, a, b)
+}
+
+func (c tracedCrossover) crossoverWith(r rand.Rand, a, b Chromosome) (x, y Chromosome, name string) {
+	_, span := c.tracer.Start(c.ctx, CrossoverSpan)
+	defer span.End()
+	return crossover(c.crossover, r, a, b)
+}
+
+// tracedCrossoverInto traces a tracedCrossover which can write children in place.
+type tracedCrossoverInto struct {
+	tracedCrossover
+	into CrossoverInto
+}
+
+func (c tracedCrossoverInto) CrossoverInto(r rand.Rand, a, b, x, y Chromosome) {
+	_, span := c.tracer.Start(c.ctx, CrossoverSpan)
+	defer span.End()
+	c.into.CrossoverInto(r, a, b, x, y)
+}
+
+type tracedMutator struct {
+	Mutator
+	tracer Tracer
+	ctx    context.Context
+}
+
+func (m tracedMutator) Mutate(r rand.Rand, c *Chromosome) {
+	_, span := m.tracer.Start(m.ctx, MutateSpan)
+	defer span.End()
+	m.Mutator.Mutate(r, c)
+}
+
+func (m tracedMutator) mutateWith(r rand.Rand, c *Chromosome) string {
+	_, span := m.tracer.Start(m.ctx, MutateSpan)
+	defer span.End()
+	return mutate(m.Mutator, r, c)
+}
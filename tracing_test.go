@@ -0,0 +1,83 @@
+package genetics_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+type spanKey struct{}
+
+// recordingTracer records each span as "parent>name".
+type recordingTracer struct {
+	spans []string
+	open  int
+}
+
+type recordedSpan struct{ t *recordingTracer }
+
+func (s recordedSpan) End() { s.t.open-- }
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, genetics.Span) {
+	parent, _ := ctx.Value(spanKey{}).(string)
+	t.spans = append(t.spans, parent+">"+name)
+	t.open++
+	return context.WithValue(ctx, spanKey{}, name), recordedSpan{t}
+}
+
+func TestEngineTracer(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	tracer := &recordingTracer{}
+	var audit bytes.Buffer
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 2,
+			CrossoverRate:    1,
+			MutationRate:     1,
+			Selector:         genetics.TournamentSelection{Size: 2},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+			Mutator:          genetics.SwapMutation{},
+		},
+		Evaluator: genetics.FitnessFunc(oneMax),
+		Tracer:    tracer,
+		Context:   context.WithValue(context.Background(), spanKey{}, "request"),
+		Audit:     genetics.NewAuditLog(&audit),
+	}
+	if err := engine.Run(rng, newBinaryPopulation(t, rng, 8, 4), 1); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+	want := []string{
+		"request>genetics.Generation",
+		"genetics.Generation>genetics.Evaluate",
+		"genetics.Generation>genetics.Evolve",
+		"genetics.Evolve>genetics.Select",
+		"genetics.Evolve>genetics.Crossover",
+		"genetics.Evolve>genetics.Mutate",
+		"genetics.Evolve>genetics.Mutate",
+		"request>genetics.Evaluate",
+	}
+	if diff := cmp.Diff(want, tracer.spans); diff != "" {
+		t.Errorf("spans differ; -want +got:\n%s", diff)
+	}
+	if tracer.open != 0 {
+		t.Errorf("%d spans were not ended", tracer.open)
+	}
+	// Tracing must not leak into the operator names recorded for provenance.
+	dec := json.NewDecoder(&audit)
+	for dec.More() {
+		var rec genetics.AuditRecord
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatalf("Decode(); err=%s", err)
+		}
+		if rec.Operator != "Initial" && rec.Operator != "MultiPointCrossover(1)+SwapMutation" {
+			t.Errorf("audit recorded operator %q", rec.Operator)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package genetics
+
+import (
+	"fmt"
+
+	"github.com/inlined/rand"
+)
+
+// TransferFunc maps a Chromosome of one Species onto a new Chromosome of another,
+// e.g. to seed a larger problem instance with solutions to a smaller one. dst
+// starts with 0-initialized Genes.
+type TransferFunc func(src Chromosome, dst *Chromosome)
+
+// TruncateOrPad copies the leading genes of src into dst, dropping genes which do
+// not fit and filling any missing genes with fill.
+func TruncateOrPad(fill Gene) TransferFunc {
+	return func(src Chromosome, dst *Chromosome) {
+		n := copy(dst.Genes, src.Genes)
+		for i := n; i < len(dst.Genes); i++ {
+			dst.Genes[i] = fill
+		}
+	}
+}
+
+// Transfer maps c onto a new Chromosome of s using fn. Alleles are clamped to
+// the range of their gene in s.
+func (s *Species) Transfer(c Chromosome, fn TransferFunc) Chromosome {
+	dst := s.New()
+	fn(c, &dst)
+	for i, a := range dst.Genes {
+		if min, max := s.Range(i); a < min {
+			dst.Genes[i] = min
+		} else if a > max {
+			dst.Genes[i] = max
+		}
+	}
+	return dst
+}
+
+// MixPopulation creates a Population of size Chromosomes of s. The population is
+// seeded with "warm" Chromosomes transferred from another Species with fn and
+// filled with "cold" random Chromosomes. If there are more than size warm
+// Chromosomes, only the first size are used. A negative size is an error.
+func (s *Species) MixPopulation(r rand.Rand, warm []Chromosome, fn TransferFunc, size int) (*Population, error) {
+	if size < 0 {
+		return nil, fmt.Errorf("MixPopulation(); size %d is negative", size)
+	}
+	pop := &Population{
+		Species:     s,
+		Chromosomes: make([]Chromosome, size),
+	}
+	for i := range pop.Chromosomes {
+		if i < len(warm) {
+			pop.Chromosomes[i] = s.Transfer(warm[i], fn)
+			continue
+		}
+		c, err := s.NewRand(r)
+		if err != nil {
+			return nil, fmt.Errorf("MixPopulation(); err=%s", err)
+		}
+		pop.Chromosomes[i] = c
+	}
+	return pop, nil
+}
@@ -0,0 +1,84 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func TestTransfer(t *testing.T) {
+	small := genetics.NewSpecies(3, 9)
+	for _, test := range []struct {
+		tag  string
+		to   *genetics.Species
+		fn   genetics.TransferFunc
+		want []genetics.Gene
+	}{
+		{
+			tag:  "pad",
+			to:   genetics.NewSpecies(5, 9),
+			fn:   genetics.TruncateOrPad(1),
+			want: []genetics.Gene{7, 2, 9, 1, 1},
+		}, {
+			tag:  "truncate",
+			to:   genetics.NewSpecies(2, 9),
+			fn:   genetics.TruncateOrPad(0),
+			want: []genetics.Gene{7, 2},
+		}, {
+			tag:  "clamp",
+			to:   genetics.NewSpecies(3, 5),
+			fn:   genetics.TruncateOrPad(0),
+			want: []genetics.Gene{5, 2, 5},
+		}, {
+			tag: "custom mapping",
+			to:  genetics.NewSpecies(6, 9),
+			fn: func(src genetics.Chromosome, dst *genetics.Chromosome) {
+				// Each gene of the small problem splits into two genes of the large problem
+				for i, g := range src.Genes {
+					dst.Genes[2*i], dst.Genes[2*i+1] = g/2, g-g/2
+				}
+			},
+			want: []genetics.Gene{3, 4, 1, 1, 4, 5},
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			got := test.to.Transfer(small.New(7, 2, 9), test.fn)
+			if got.Species != test.to {
+				t.Error("Transfer() did not create a chromosome of the target Species")
+			}
+			if diff := cmp.Diff(test.want, got.Genes); diff != "" {
+				t.Errorf("Transfer() got=%v want=%v diff=%s", got.Genes, test.want, diff)
+			}
+		})
+	}
+}
+
+func TestMixPopulation(t *testing.T) {
+	small := genetics.NewSpecies(2, 1)
+	large := genetics.NewSpecies(4, 1)
+	warm := []genetics.Chromosome{small.New(1, 1), small.New(0, 1)}
+	pop, err := large.MixPopulation(rand.New(), warm, genetics.TruncateOrPad(0), 5)
+	if err != nil {
+		t.Fatalf("MixPopulation(); err=%s", err)
+	}
+	if pop.Len() != 5 || pop.Species != large {
+		t.Fatalf("MixPopulation() created %+v; want 5 chromosomes of the large Species", pop)
+	}
+	if diff := cmp.Diff([]genetics.Gene{1, 1, 0, 0}, pop.Chromosomes[0].Genes); diff != "" {
+		t.Errorf("first chromosome was not transferred; diff=%s", diff)
+	}
+	if diff := cmp.Diff([]genetics.Gene{0, 1, 0, 0}, pop.Chromosomes[1].Genes); diff != "" {
+		t.Errorf("second chromosome was not transferred; diff=%s", diff)
+	}
+	for n, c := range pop.Chromosomes {
+		if len(c.Genes) != 4 {
+			t.Errorf("chromosome %d has %d genes; want 4", n, len(c.Genes))
+		}
+	}
+	if _, err := large.MixPopulation(rand.New(), warm, genetics.TruncateOrPad(0), -1); err == nil {
+		t.Error("MixPopulation() should reject a negative size")
+	}
+}
@@ -0,0 +1,192 @@
+// Package tui shows the progress of long runs in a terminal. Progress is a
+// genetics.Observer which redraws a few lines in place each generation: a
+// sparkline of the best fitness, a gauge of the population's diversity, and the
+// generation rate with the time remaining.
+package tui
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/inlined/genetics"
+)
+
+// sparks are the bars of a sparkline, from lowest to highest.
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+const (
+	// gaugeWidth is the number of cells of the diversity gauge.
+	gaugeWidth = 20
+	// clearLine erases the line under the cursor.
+	clearLine = "\x1b[2K"
+)
+
+// Progress is a genetics.Observer which draws a live display of an Engine's run
+// on a terminal. Redraws are throttled to one per Interval, and the final
+// generation is always drawn when the run terminates. The display uses ANSI
+// escape codes to redraw in place, so Out should be a terminal.
+type Progress struct {
+	genetics.NopObserver
+
+	// Out is the terminal to draw on. Defaults to os.Stderr.
+	Out io.Writer
+	// Generations, if set, is the length of the run, from which the time
+	// remaining is estimated.
+	Generations int
+	// Width is the number of generations shown by the sparkline. Defaults to 40.
+	Width int
+	// Interval is the least time between redraws. Defaults to 100ms.
+	Interval time.Duration
+
+	engine      *genetics.Engine
+	best        []float64
+	stats       genetics.Stats
+	start, last time.Time
+	// lines is the number of lines of the last frame, which the next overwrites.
+	lines int
+}
+
+// OnGenerationStart implements genetics.Observer by starting a new display at
+// generation 0.
+func (p *Progress) OnGenerationStart(e *genetics.Engine, generation int) {
+	p.engine = e
+	if generation == 0 {
+		p.best, p.lines = nil, 0
+		p.start, p.last = time.Now(), time.Time{}
+	}
+}
+
+// OnStats implements genetics.StatsObserver
+func (p *Progress) OnStats(s genetics.Stats) {
+	p.stats = s
+	p.best = append(p.best, float64(s.Best))
+	if width := p.width(); len(p.best) > width {
+		p.best = p.best[len(p.best)-width:]
+	}
+	interval := p.Interval
+	if interval == 0 {
+		interval = 100 * time.Millisecond
+	}
+	if now := time.Now(); now.Sub(p.last) >= interval {
+		p.last = now
+		p.draw()
+	}
+}
+
+// OnTermination implements genetics.Observer by drawing the final generation and
+// the error which ended the run, if any.
+func (p *Progress) OnTermination(e *genetics.Engine, err error) {
+	p.draw()
+	if err != nil {
+		fmt.Fprintf(p.out(), "%sStopped: %s\n", clearLine, err)
+	}
+	p.lines = 0
+}
+
+// draw overwrites the last frame with the current one.
+func (p *Progress) draw() {
+	var b strings.Builder
+	if p.lines > 0 {
+		fmt.Fprintf(&b, "\x1b[%dA", p.lines)
+	}
+	lines := p.frame()
+	for _, l := range lines {
+		b.WriteString(clearLine + l + "\n")
+	}
+	p.lines = len(lines)
+	io.WriteString(p.out(), b.String())
+}
+
+// frame returns the lines of the display.
+func (p *Progress) frame() []string {
+	s := p.stats
+	gen := fmt.Sprintf("Generation %d", s.Generation)
+	if p.Generations > 0 {
+		gen += fmt.Sprintf("/%d", p.Generations)
+	}
+	elapsed := time.Since(p.start)
+	rate := 0.0
+	if secs := elapsed.Seconds(); secs > 0 {
+		rate = float64(s.Generation+1) / secs
+	}
+	timing := fmt.Sprintf("%.1f gen/s  elapsed %s", rate, elapsed.Round(100*time.Millisecond))
+	if remaining := p.Generations - s.Generation - 1; p.Generations > 0 && rate > 0 {
+		if remaining < 0 {
+			remaining = 0
+		}
+		eta := time.Duration(float64(remaining) / rate * float64(time.Second))
+		timing += fmt.Sprintf("  ETA %s", eta.Round(100*time.Millisecond))
+	}
+	diversity := p.diversity()
+	filled := int(math.Round(diversity * gaugeWidth))
+	return []string{
+		fmt.Sprintf("%s  best %.6g  mean %.6g", gen, float64(s.Best), s.Mean),
+		"Best " + sparkline(p.best),
+		fmt.Sprintf("Diversity [%s%s] %3.0f%%", strings.Repeat("#", filled), strings.Repeat("-", gaugeWidth-filled), 100*diversity),
+		timing,
+	}
+}
+
+// diversity is the mean entropy of the loci of the population, each normalized
+// by the most entropy its alleles could have: 1 while every allele is evenly
+// mixed and 0 once the population has converged.
+func (p *Progress) diversity() float64 {
+	if p.engine == nil {
+		return 0
+	}
+	pop := p.engine.Population()
+	if pop == nil || len(pop.Chromosomes) < 2 {
+		return 0
+	}
+	total, loci := 0.0, 0
+	for i, l := range genetics.AnalyzePopulation(pop) {
+		min, max := pop.Species.Range(i)
+		alleles := math.Min(float64(max-min+1), float64(len(pop.Chromosomes)))
+		if alleles > 1 {
+			total += l.Entropy / math.Log2(alleles)
+			loci++
+		}
+	}
+	if loci == 0 {
+		return 0
+	}
+	return math.Min(total/float64(loci), 1)
+}
+
+func (p *Progress) out() io.Writer {
+	if p.Out == nil {
+		return os.Stderr
+	}
+	return p.Out
+}
+
+func (p *Progress) width() int {
+	if p.Width <= 0 {
+		return 40
+	}
+	return p.Width
+}
+
+// sparkline draws values as bars scaled from their minimum to their maximum.
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	line := make([]rune, len(values))
+	for i, v := range values {
+		n := 0
+		if hi > lo {
+			n = int((v - lo) / (hi - lo) * float64(len(sparks)-1))
+		}
+		line[i] = sparks[n]
+	}
+	return string(line)
+}
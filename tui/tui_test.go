@@ -0,0 +1,94 @@
+package tui_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/genetics/tui"
+)
+
+func TestProgress(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	s := genetics.NewSpecies(16, 1)
+	pop, err := s.NewRandPopulation(rng, 30)
+	if err != nil {
+		t.Fatalf("NewRandPopulation(); err=%s", err)
+	}
+	var out strings.Builder
+	// A negative Interval redraws every generation
+	progress := &tui.Progress{Out: &out, Generations: 10, Width: 5, Interval: -1}
+	engine := genetics.Engine{
+		Evolver: genetics.Evolver{
+			ReplacementCount: 20,
+			CrossoverRate:    0.9,
+			Selector:         genetics.TournamentSelection{Size: 3},
+			Crossover:        genetics.MultiPointCrossover{Points: 1},
+		},
+		// OneMax
+		Evaluator: genetics.FitnessFunc(func(c genetics.Chromosome) genetics.Fitness {
+			sum := 0
+			for _, g := range c.Genes {
+				sum += g
+			}
+			return genetics.Fitness(sum)
+		}),
+		Observers: []genetics.Observer{progress},
+	}
+	if err := engine.Run(rng, pop, 10); err != nil {
+		t.Fatalf("Run(); err=%s", err)
+	}
+
+	// Each generation's frame but the first moves up over the last
+	got := out.String()
+	if n := strings.Count(got, "\x1b[4A"); n != 11 {
+		t.Errorf("Progress redrew %d times; want 11 for the 10 generations and the final scores", n)
+	}
+	frames := strings.Split(got, "\x1b[4A")
+	first, last := frames[0], frames[len(frames)-1]
+	for _, want := range []string{"Generation 0/10", "Diversity [", "gen/s", "ETA"} {
+		if !strings.Contains(first, want) {
+			t.Errorf("first frame lacks %q:\n%s", want, first)
+		}
+	}
+	if !strings.Contains(last, "Generation 10/10") || !strings.Contains(last, "ETA 0s") {
+		t.Errorf("last frame should show the final generation and no time remaining:\n%s", last)
+	}
+	// The sparkline shows the last Width generations
+	for _, line := range strings.Split(last, "\n") {
+		if spark := strings.TrimPrefix(line, "\x1b[2KBest "); spark != line && len([]rune(spark)) != 5 {
+			t.Errorf("sparkline %q has %d bars; want 5", spark, len([]rune(spark)))
+		}
+	}
+	// Selection without mutation loses diversity
+	if !strings.Contains(first, "Diversity [#################") || strings.Contains(last, "Diversity [#################") {
+		t.Errorf("diversity should start high and fall:\n%s\n...\n%s", first, last)
+	}
+}
+
+func TestProgressThrottled(t *testing.T) {
+	var out strings.Builder
+	progress := &tui.Progress{Out: &out, Interval: time.Hour}
+	progress.OnGenerationStart(&genetics.Engine{}, 0)
+	for gen := 0; gen < 100; gen++ {
+		progress.OnStats(genetics.Stats{Generation: gen, Best: genetics.Fitness(gen)})
+	}
+	progress.OnTermination(nil, errors.New("interrupted"))
+	got := out.String()
+	if n := strings.Count(got, "Generation "); n != 2 {
+		t.Errorf("Progress drew %d frames within its Interval; want the first and the last", n)
+	}
+	if !strings.Contains(got, "Generation 99  best 99") || !strings.Contains(got, "Stopped: interrupted") {
+		t.Errorf("the final frame should show the last generation and the error:\n%s", got)
+	}
+	// The sparkline shows the last 40 generations by default
+	spark := strings.Split(strings.Split(got, "Best ")[2], "\n")[0]
+	if bars := []rune(spark); len(bars) != 40 || bars[0] != '▁' || bars[39] != '█' {
+		t.Errorf("the sparkline %q should rise over 40 bars with the best fitness", spark)
+	}
+}
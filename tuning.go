@@ -0,0 +1,202 @@
+package genetics
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/inlined/rand"
+)
+
+// errTuningBudget is returned by the meta-level Evaluator once a Tuner's Budget is spent.
+var errTuningBudget = errors.New("tuning budget spent")
+
+// Tuner is a meta-GA: a genetic algorithm whose chromosomes are Evolver
+// configurations. Each configuration sets the MutationRate, the Size of a
+// TournamentSelection, and the ReplacementCount of a copy of Base, and is scored by
+// the mean fitness of the best chromosome found in Trials runs of Generations
+// generations on the user's problem. All other Evolver fields are kept from Base.
+type Tuner struct {
+	Base      Evolver
+	Evaluator Evaluator
+	// NewPopulation creates the initial population of each trial run.
+	NewPopulation func(r rand.Rand) (*Population, error)
+	// Generations is the length of each trial run.
+	Generations int
+	// Trials is the number of runs averaged to score a configuration. Defaults to 1.
+	Trials int
+
+	// MaxTournamentSize bounds the tuned TournamentSelection. Defaults to 8, or the
+	// population size if it is smaller.
+	MaxTournamentSize int
+	// MaxReplacementCount bounds the tuned ReplacementCount, which is always even.
+	// Defaults to the population size.
+	MaxReplacementCount int
+
+	// MetaPopulation is the number of configurations in each meta-generation.
+	// Defaults to 10.
+	MetaPopulation int
+	// Tuning stops after MetaGenerations meta-generations or once Budget has
+	// elapsed, whichever comes first. At least one must be set. A configuration
+	// still being scored when the Budget runs out is abandoned.
+	MetaGenerations int
+	Budget          time.Duration
+}
+
+// TunedConfig is the result of Tune.
+type TunedConfig struct {
+	Evolver Evolver
+	// Fitness is the mean best fitness of the Evolver's trial runs.
+	Fitness Fitness
+	// Configurations is the number of configurations scored.
+	Configurations int
+}
+
+// configuration genes
+const (
+	tuneMutationRate = iota // per mille
+	tuneTournamentSize
+	tuneReplacementPairs
+)
+
+// configure returns a copy of Base with the configuration c.
+func (t Tuner) configure(c Chromosome) Evolver {
+	e := t.Base
+	e.MutationRate = float32(c.Genes[tuneMutationRate]) / 1000
+	e.Selector = TournamentSelection{Size: c.Genes[tuneTournamentSize]}
+	e.ReplacementCount = 2 * c.Genes[tuneReplacementPairs]
+	return e
+}
+
+// Tune searches for the Evolver configuration which performs best on the user's problem.
+func (t Tuner) Tune(r rand.Rand) (TunedConfig, error) {
+	if t.Generations <= 0 {
+		return TunedConfig{}, fmt.Errorf("Tuner.Tune(); Generations %d must be positive", t.Generations)
+	}
+	if t.MetaGenerations <= 0 && t.Budget <= 0 {
+		return TunedConfig{}, fmt.Errorf("Tuner.Tune(); one of MetaGenerations or Budget must be set")
+	}
+	pop, err := t.NewPopulation(r)
+	if err != nil {
+		return TunedConfig{}, fmt.Errorf("Tuner.Tune(); cannot create a population: %s", err)
+	}
+	size := len(pop.Chromosomes)
+	maxTournament, maxReplacement := t.MaxTournamentSize, t.MaxReplacementCount
+	if maxTournament == 0 {
+		maxTournament = 8
+	}
+	if maxTournament > size {
+		maxTournament = size
+	}
+	if maxReplacement == 0 || maxReplacement > size {
+		maxReplacement = size
+	}
+	if maxTournament < 2 || maxReplacement < 2 {
+		return TunedConfig{}, fmt.Errorf("Tuner.Tune(); populations of %d are too small to tune", size)
+	}
+	trials, metaSize := t.Trials, t.MetaPopulation
+	if trials == 0 {
+		trials = 1
+	}
+	if metaSize == 0 {
+		metaSize = 10
+	}
+
+	species, err := NewRangedSpecies(
+		[]Gene{tuneMutationRate: 0, tuneTournamentSize: 2, tuneReplacementPairs: 1},
+		[]Gene{tuneMutationRate: 1000, tuneTournamentSize: maxTournament, tuneReplacementPairs: maxReplacement / 2},
+	)
+	if err != nil {
+		return TunedConfig{}, fmt.Errorf("Tuner.Tune(); err=%s", err)
+	}
+
+	var (
+		deadline time.Time
+		best     TunedConfig
+		found    bool
+		trialErr error
+	)
+	if t.Budget > 0 {
+		deadline = time.Now().Add(t.Budget)
+	}
+	overBudget := func() bool {
+		return t.Budget > 0 && time.Now().After(deadline)
+	}
+	meta := Engine{
+		Evolver: Evolver{
+			ReplacementCount: 2 * (metaSize / 4),
+			CrossoverRate:    0.9,
+			MutationRate:     0.5,
+			Selector:         TournamentSelection{Size: 2},
+			Crossover:        MultiPointCrossover{Points: 1},
+			Mutator:          RandomResettingMutation{},
+		},
+		// Abandoned configurations fail; they must not abort the search.
+		MaxFailureRate: 1,
+		Terminate: func(s Stats) bool {
+			return trialErr != nil || overBudget()
+		},
+	}
+	if meta.Evolver.ReplacementCount == 0 {
+		meta.Evolver.ReplacementCount = 2
+	}
+	meta.Evaluator = evaluatorFunc(func(c Chromosome) (Fitness, error) {
+		e := t.configure(c)
+		var total Fitness
+		var objective Objective
+		for i := 0; i < trials; i++ {
+			if overBudget() {
+				return 0, errTuningBudget
+			}
+			pop, err := t.NewPopulation(r)
+			if err == nil {
+				trial := Engine{Evolver: e, Evaluator: t.Evaluator}
+				err = trial.Run(r, pop, t.Generations)
+			}
+			if err != nil {
+				if trialErr == nil {
+					trialErr = err
+				}
+				return 0, err
+			}
+			_, f := pop.Best()
+			total += f
+			objective = pop.Objective
+		}
+		f := total / Fitness(trials)
+		best.Configurations++
+		if !found || objective.Better(f, best.Fitness) {
+			best.Evolver, best.Fitness, found = e, f, true
+		}
+		// The meta population maximizes, whatever the trials' Objective.
+		return objective.score(f), nil
+	})
+
+	metaPop, err := species.NewRandPopulation(r, metaSize)
+	if err != nil {
+		return TunedConfig{}, fmt.Errorf("Tuner.Tune(); err=%s", err)
+	}
+	// Without MetaGenerations, only the Budget ends the run.
+	generations := t.MetaGenerations
+	if generations <= 0 {
+		generations = int(^uint(0) >> 1)
+	}
+	if err := meta.Run(r, metaPop, generations); err != nil {
+		return best, fmt.Errorf("Tuner.Tune(); err=%s", err)
+	}
+	if trialErr != nil {
+		return best, fmt.Errorf("Tuner.Tune(); trial run failed: %s", trialErr)
+	}
+	if !found {
+		return best, fmt.Errorf("Tuner.Tune(); Budget %s ended before any configuration was scored", t.Budget)
+	}
+	return best, nil
+}
+
+// evaluatorFunc adapts a function into an Evaluator.
+type evaluatorFunc func(c Chromosome) (Fitness, error)
+
+// Evaluate implements Evaluator
+func (f evaluatorFunc) Evaluate(c Chromosome) (Fitness, error) {
+	return f(c)
+}
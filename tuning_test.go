@@ -0,0 +1,99 @@
+package genetics_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/genetics"
+)
+
+func newTuner() genetics.Tuner {
+	return genetics.Tuner{
+		Base: genetics.Evolver{
+			CrossoverRate: 0.9,
+			Crossover:     genetics.MultiPointCrossover{Points: 1},
+			Mutator:       genetics.SwapMutation{},
+		},
+		Evaluator: genetics.FitnessFunc(inPlace),
+		NewPopulation: func(r rand.Rand) (*genetics.Population, error) {
+			return genetics.NewSpecies(12, 11).NewPermPopulation(r, 16)
+		},
+		Generations:       20,
+		Trials:            2,
+		MaxTournamentSize: 4,
+	}
+}
+
+func TestTuner(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	tuner := newTuner()
+	tuner.MetaGenerations = 5
+	got, err := tuner.Tune(rng)
+	if err != nil {
+		t.Fatalf("Tune(); err=%s", err)
+	}
+	e := got.Evolver
+	size := e.Selector.(genetics.TournamentSelection).Size
+	if e.MutationRate < 0 || e.MutationRate > 1 || size < 2 || size > 4 ||
+		e.ReplacementCount < 2 || e.ReplacementCount > 16 || e.ReplacementCount%2 != 0 {
+		t.Errorf("Tune() returned an Evolver outside the search space: %+v", e)
+	}
+	if e.Mutator != tuner.Base.Mutator || e.Crossover != tuner.Base.Crossover || e.CrossoverRate != tuner.Base.CrossoverRate {
+		t.Errorf("Tune() did not keep the untuned fields of Base: %+v", e)
+	}
+	if got.Configurations < 10 {
+		t.Errorf("Tune() scored %d configurations; want at least the 10 initial ones", got.Configurations)
+	}
+
+	// The tuned configuration should beat a poorly configured Evolver on average.
+	poor := tuner.Base
+	poor.MutationRate = 0
+	poor.ReplacementCount = 2
+	poor.Selector = genetics.TournamentSelection{Size: 2}
+	mean := func(e genetics.Evolver) genetics.Fitness {
+		total := genetics.Fitness(0)
+		for i := 0; i < 20; i++ {
+			pop, _ := tuner.NewPopulation(rng)
+			engine := genetics.Engine{Evolver: e, Evaluator: tuner.Evaluator}
+			if err := engine.Run(rng, pop, tuner.Generations); err != nil {
+				t.Fatalf("Run(); err=%s", err)
+			}
+			_, f := pop.Best()
+			total += f
+		}
+		return total / 20
+	}
+	if tuned, untuned := mean(e), mean(poor); tuned <= untuned {
+		t.Errorf("tuned Evolver %+v scored %g; want better than %g", e, tuned, untuned)
+	}
+}
+
+func TestTunerBudget(t *testing.T) {
+	rng := rand.New()
+	rng.Seed(42)
+	tuner := newTuner()
+	tuner.Budget = 50 * time.Millisecond
+	start := time.Now()
+	if _, err := tuner.Tune(rng); err != nil {
+		t.Fatalf("Tune(); err=%s", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Tune() took %s with a Budget of %s", elapsed, tuner.Budget)
+	}
+
+	tuner.Budget = 0
+	if _, err := tuner.Tune(rng); err == nil {
+		t.Error("Tune() should require MetaGenerations or a Budget")
+	}
+	tuner.MetaGenerations = 1
+	tuner.NewPopulation = func(r rand.Rand) (*genetics.Population, error) {
+		return nil, errors.New("no population")
+	}
+	if _, err := tuner.Tune(rng); err == nil {
+		t.Error("Tune() should fail when populations cannot be created")
+	}
+}
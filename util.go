@@ -1,22 +1,129 @@
 package genetics
 
+import (
+	"container/heap"
+	"encoding/binary"
+	"math"
+	"sort"
+
+	"github.com/inlined/rand"
+)
+
 type tie struct {
 	index   int
 	fitness Fitness
 }
 
-type maxTieHeap []tie
+// fitterThan breaks ties between equal fitness by index; see the package documentation.
+func (t tie) fitterThan(o tie) bool {
+	return t.fitness > o.fitness || (t.fitness == o.fitness && t.index < o.index)
+}
+
+// tieHeap is a fixed-size heap of ties whose root is the last by before.
+type tieHeap struct {
+	ties   []tie
+	before func(a, b tie) bool
+}
 
-func (h maxTieHeap) Len() int           { return len(h) }
-func (h maxTieHeap) Less(i, j int) bool { return h[i].fitness > h[j].fitness }
-func (h maxTieHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h tieHeap) Len() int           { return len(h.ties) }
+func (h tieHeap) Less(i, j int) bool { return h.before(h.ties[j], h.ties[i]) }
+func (h tieHeap) Swap(i, j int)      { h.ties[i], h.ties[j] = h.ties[j], h.ties[i] }
 
 // Push is unsupported in this pacakge
-func (h maxTieHeap) Push(x interface{}) {
-	panic("maxTieHeap.Push() unsupported")
+func (h tieHeap) Push(x interface{}) {
+	panic("tieHeap.Push() unsupported")
 }
 
 // Pop unsupported in this package
-func (h maxTieHeap) Pop() interface{} {
-	panic("maxTieHeap.Pop() unsupported")
+func (h tieHeap) Pop() interface{} {
+	panic("tieHeap.Pop() unsupported")
+}
+
+// TopK returns the indexes of the k highest scores, highest first. Equal scores
+// are ranked by index, so the lower index comes first. k is limited to
+// len(scores). Use Objective.Scores to rank the Fitness of a minimizing
+// Population.
+func TopK(scores []Fitness, k int) []int {
+	return selectK(scores, k, tie.fitterThan)
+}
+
+// BottomK returns the indexes of the k lowest scores, lowest first. Equal scores
+// are ranked by index, so the higher index comes first; BottomK(scores, n) is
+// TopK(scores, n) reversed. k is limited to len(scores).
+func BottomK(scores []Fitness, k int) []int {
+	return selectK(scores, k, func(a, b tie) bool { return b.fitterThan(a) })
+}
+
+// selectK returns the indexes of the k scores which come first by before, in
+// order, in O(n log k) time.
+func selectK(scores []Fitness, k int, before func(a, b tie) bool) []int {
+	if k > len(scores) {
+		k = len(scores)
+	}
+	if k <= 0 {
+		return []int{}
+	}
+	h := tieHeap{ties: make([]tie, k), before: before}
+	for i := range h.ties {
+		h.ties[i] = tie{index: i, fitness: scores[i]}
+	}
+	heap.Init(h)
+	for i := k; i < len(scores); i++ {
+		if t := (tie{index: i, fitness: scores[i]}); before(t, h.ties[0]) {
+			h.ties[0] = t
+			heap.Fix(h, 0)
+		}
+	}
+	sort.Slice(h.ties, func(i, j int) bool { return before(h.ties[i], h.ties[j]) })
+	res := make([]int, k)
+	for i, t := range h.ties {
+		res[i] = t.index
+	}
+	return res
+}
+
+// rankIndexes returns the indexes of fitness from most to least fit.
+func rankIndexes(fitness []Fitness) []int {
+	order := make([]int, len(fitness))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return tie{index: order[i], fitness: fitness[order[i]]}.fitterThan(tie{index: order[j], fitness: fitness[order[j]]})
+	})
+	return order
+}
+
+// normFloat64 draws from the standard normal distribution with the Box-Muller
+// transform, since rand.Rand only guarantees uniform sources.
+func normFloat64(r rand.Rand) float64 {
+	u1 := r.Float64()
+	for u1 == 0 {
+		u1 = r.Float64()
+	}
+	u2 := r.Float64()
+	return math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2)
+}
+
+// genesEqual reports whether a and b hold the same Genes.
+func genesEqual(a, b []Gene) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, g := range a {
+		if g != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// genesKey encodes genes as a string suitable for use as a map key.
+func genesKey(genes []Gene) string {
+	b := make([]byte, len(genes)*binary.MaxVarintLen64)
+	n := 0
+	for _, g := range genes {
+		n += binary.PutVarint(b[n:], int64(g))
+	}
+	return string(b[:n])
 }
@@ -0,0 +1,81 @@
+package genetics_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/inlined/genetics"
+)
+
+func TestTopKBottomK(t *testing.T) {
+	for _, test := range []struct {
+		tag    string
+		scores []genetics.Fitness
+		k      int
+		top    []int
+		bottom []int
+	}{
+		{
+			tag:    "distinct",
+			scores: []genetics.Fitness{4, 20, 16, 3, 7},
+			k:      2,
+			top:    []int{1, 2},
+			bottom: []int{3, 0},
+		}, {
+			tag:    "minima after the first k",
+			scores: []genetics.Fitness{9, 8, 7, 1, 2},
+			k:      2,
+			top:    []int{0, 1},
+			bottom: []int{3, 4},
+		}, {
+			tag:    "ties rank by index",
+			scores: []genetics.Fitness{5, 1, 5, 1, 5},
+			k:      3,
+			top:    []int{0, 2, 4},
+			bottom: []int{3, 1, 4},
+		}, {
+			tag:    "fractional",
+			scores: []genetics.Fitness{0.5, -0.25, 0.75},
+			k:      1,
+			top:    []int{2},
+			bottom: []int{1},
+		}, {
+			tag:    "k exceeds len",
+			scores: []genetics.Fitness{2, 1},
+			k:      5,
+			top:    []int{0, 1},
+			bottom: []int{1, 0},
+		}, {
+			tag:    "k is zero",
+			scores: []genetics.Fitness{2, 1},
+			k:      0,
+			top:    []int{},
+			bottom: []int{},
+		}, {
+			tag:    "empty",
+			k:      1,
+			top:    []int{},
+			bottom: []int{},
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			if diff := cmp.Diff(test.top, genetics.TopK(test.scores, test.k)); diff != "" {
+				t.Errorf("TopK(%v, %d) differs; -want +got:\n%s", test.scores, test.k, diff)
+			}
+			if diff := cmp.Diff(test.bottom, genetics.BottomK(test.scores, test.k)); diff != "" {
+				t.Errorf("BottomK(%v, %d) differs; -want +got:\n%s", test.scores, test.k, diff)
+			}
+		})
+	}
+}
+
+func TestBottomKIsReversedTopK(t *testing.T) {
+	scores := []genetics.Fitness{3, 1, 4, 1, 5, 9, 2, 6, 5, 3, 5}
+	top, bottom := genetics.TopK(scores, len(scores)), genetics.BottomK(scores, len(scores))
+	for i := range top {
+		if top[i] != bottom[len(bottom)-1-i] {
+			t.Fatalf("TopK()=%v is not BottomK()=%v reversed", top, bottom)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package genetics
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Normalization chooses how a WeightedSumEvaluator scales each objective before
+// weighting it, so that objectives measured on different scales are weighted
+// fairly.
+type Normalization int
+
+const (
+	// NoNormalization weights the objectives as they are.
+	NoNormalization Normalization = iota
+	// BoundsNormalization scales objective i from [Min[i], Max[i]] to [0, 1].
+	BoundsNormalization
+	// ObservedNormalization scales each objective from the range of values it has
+	// taken so far to [0, 1]. It needs no bounds, but a Chromosome's Fitness then
+	// depends on the Chromosomes scored before it, so it should not be combined
+	// with an Engine's Cache.
+	ObservedNormalization
+)
+
+func (n Normalization) String() string {
+	switch n {
+	case NoNormalization:
+		return "NoNormalization"
+	case BoundsNormalization:
+		return "BoundsNormalization"
+	case ObservedNormalization:
+		return "ObservedNormalization"
+	}
+	return fmt.Sprintf("Normalization(%d)", int(n))
+}
+
+// WeightedSumEvaluator is an Evaluator which scores a Chromosome by the weighted
+// sum of its Objectives, so that multi-objective problems can be solved with the
+// single-objective machinery of Engine. Each run finds one point of the Pareto
+// front, chosen by Weights, and only points on convex regions of the front can be
+// found at all; use MOEAD to approximate the whole front. It is goroutine safe if
+// Evaluator is.
+type WeightedSumEvaluator struct {
+	Evaluator MultiEvaluator
+	// Weights is the weight of each objective.
+	Weights       []float64
+	Normalization Normalization
+	// Min and Max bound each objective for BoundsNormalization. Objectives outside
+	// the bounds are scaled beyond [0, 1].
+	Min, Max Objectives
+
+	mu                       sync.Mutex
+	observedMin, observedMax Objectives
+}
+
+// Evaluate implements Evaluator
+func (w *WeightedSumEvaluator) Evaluate(c Chromosome) (Fitness, error) {
+	o, err := w.Evaluator.EvaluateObjectives(c)
+	if err != nil {
+		return 0, err
+	}
+	if len(o) != len(w.Weights) {
+		return 0, fmt.Errorf("WeightedSumEvaluator.Evaluate(); %d objectives for %d Weights", len(o), len(w.Weights))
+	}
+	lo, hi := w.Min, w.Max
+	switch w.Normalization {
+	case NoNormalization:
+	case BoundsNormalization:
+		if len(lo) != len(o) || len(hi) != len(o) {
+			return 0, fmt.Errorf("WeightedSumEvaluator.Evaluate(); BoundsNormalization needs Min and Max for each of %d objectives", len(o))
+		}
+		for i := range lo {
+			if !(hi[i] > lo[i]) {
+				return 0, fmt.Errorf("WeightedSumEvaluator.Evaluate(); Max[%d] %g must exceed Min[%d] %g", i, hi[i], i, lo[i])
+			}
+		}
+	case ObservedNormalization:
+		lo, hi = w.observe(o)
+	default:
+		return 0, fmt.Errorf("WeightedSumEvaluator.Evaluate(); unknown %s", w.Normalization)
+	}
+	sum := 0.0
+	for i, f := range o {
+		x := float64(f)
+		if w.Normalization != NoNormalization {
+			x = 0
+			if span := float64(hi[i] - lo[i]); span > 0 {
+				x = float64(f-lo[i]) / span
+			}
+		}
+		sum += w.Weights[i] * x
+	}
+	return Fitness(sum), nil
+}
+
+// observe widens the observed range of each objective to include o and returns
+// copies of its bounds.
+func (w *WeightedSumEvaluator) observe(o Objectives) (lo, hi Objectives) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.observedMin == nil {
+		w.observedMin = append(Objectives(nil), o...)
+		w.observedMax = append(Objectives(nil), o...)
+	}
+	for i, f := range o {
+		if f < w.observedMin[i] {
+			w.observedMin[i] = f
+		}
+		if f > w.observedMax[i] {
+			w.observedMax[i] = f
+		}
+	}
+	return append(Objectives(nil), w.observedMin...), append(Objectives(nil), w.observedMax...)
+}
@@ -0,0 +1,89 @@
+package genetics_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/inlined/genetics"
+)
+
+// genesObjectives scores a Chromosome with its genes as its Objectives.
+var genesObjectives = genetics.MultiFitnessFunc(func(c genetics.Chromosome) genetics.Objectives {
+	o := make(genetics.Objectives, len(c.Genes))
+	for i, g := range c.Genes {
+		o[i] = genetics.Fitness(g)
+	}
+	return o
+})
+
+func TestWeightedSumEvaluator(t *testing.T) {
+	s := genetics.NewSpecies(2, 100)
+	for _, test := range []struct {
+		name string
+		w    *genetics.WeightedSumEvaluator
+		// genes are scored in order; want is the Fitness of the last.
+		genes [][]genetics.Gene
+		want  genetics.Fitness
+	}{
+		{
+			name:  "raw",
+			w:     &genetics.WeightedSumEvaluator{Weights: []float64{1, 0.5}},
+			genes: [][]genetics.Gene{{10, 40}},
+			want:  30,
+		}, {
+			name: "bounds",
+			w: &genetics.WeightedSumEvaluator{
+				Weights:       []float64{1, 0.5},
+				Normalization: genetics.BoundsNormalization,
+				Min:           genetics.Objectives{0, 0},
+				Max:           genetics.Objectives{20, 100},
+			},
+			genes: [][]genetics.Gene{{10, 40}},
+			want:  0.5 + 0.2,
+		}, {
+			name: "bounds exceeded",
+			w: &genetics.WeightedSumEvaluator{
+				Weights:       []float64{1, 1},
+				Normalization: genetics.BoundsNormalization,
+				Min:           genetics.Objectives{10, 10},
+				Max:           genetics.Objectives{20, 20},
+			},
+			genes: [][]genetics.Gene{{30, 0}},
+			want:  2 - 1,
+		}, {
+			name:  "first observed",
+			w:     &genetics.WeightedSumEvaluator{Weights: []float64{1, 1}, Normalization: genetics.ObservedNormalization},
+			genes: [][]genetics.Gene{{10, 40}},
+			want:  0,
+		}, {
+			name:  "observed",
+			w:     &genetics.WeightedSumEvaluator{Weights: []float64{1, 2}, Normalization: genetics.ObservedNormalization},
+			genes: [][]genetics.Gene{{0, 50}, {40, 0}, {10, 40}},
+			want:  0.25 + 2*0.8,
+		},
+	} {
+		test.w.Evaluator = genesObjectives
+		var got genetics.Fitness
+		var err error
+		for _, g := range test.genes {
+			if got, err = test.w.Evaluate(s.New(g...)); err != nil {
+				t.Fatalf("%s: Evaluate(); err=%s", test.name, err)
+			}
+		}
+		if math.Abs(float64(got-test.want)) > 1e-9 {
+			t.Errorf("%s: Evaluate()=%g; want %g", test.name, got, test.want)
+		}
+	}
+
+	for _, bad := range []*genetics.WeightedSumEvaluator{
+		{Weights: []float64{1}},
+		{Weights: []float64{1, 1}, Normalization: genetics.BoundsNormalization},
+		{Weights: []float64{1, 1}, Normalization: genetics.BoundsNormalization, Min: genetics.Objectives{0, 5}, Max: genetics.Objectives{1, 5}},
+		{Weights: []float64{1, 1}, Normalization: genetics.Normalization(7)},
+	} {
+		bad.Evaluator = genesObjectives
+		if _, err := bad.Evaluate(s.New(1, 2)); err == nil {
+			t.Errorf("Evaluate() with %+v should fail", bad)
+		}
+	}
+}